@@ -13,8 +13,11 @@ import (
 	"educonnect/pkg/cache"
 	"educonnect/pkg/database"
 	"educonnect/pkg/livekit"
+	"educonnect/pkg/mailer"
 	"educonnect/pkg/messaging"
+	"educonnect/pkg/paygate"
 	"educonnect/pkg/search"
+	"educonnect/pkg/sms"
 	"educonnect/pkg/storage"
 
 	"github.com/joho/godotenv"
@@ -82,9 +85,28 @@ func main() {
 	slog.Info("connected to Meilisearch")
 
 	// ── LiveKit ─────────────────────────────────────────────────
-	lkClient := livekit.NewClient(cfg.LiveKit)
+	lkClient := livekit.NewClient(cfg.LiveKit, cfg.MinIO)
 	slog.Info("LiveKit client initialized")
 
+	// ── Mailer (SMTP) ───────────────────────────────────────────
+	mail := mailer.NewMailer(cfg.SMTP)
+
+	// ── SMS ─────────────────────────────────────────────────────
+	smsSender, err := sms.NewSender(cfg.SMS)
+	if err != nil {
+		slog.Error("failed to configure SMS provider", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("SMS provider configured", "provider", cfg.SMS.Provider)
+
+	// ── Payment gateways ────────────────────────────────────────
+	gateways, err := paygate.NewGateways(cfg.Payment)
+	if err != nil {
+		slog.Error("failed to configure payment gateways", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("payment gateways configured", "gateway", cfg.Payment.Gateway)
+
 	// ── Server ──────────────────────────────────────────────────
 	deps := &server.Dependencies{
 		Config:  cfg,
@@ -94,6 +116,9 @@ func main() {
 		Storage: store,
 		Search:  searchClient,
 		LiveKit: lkClient,
+		Mailer:  mail,
+		SMS:     smsSender,
+		Payment: gateways,
 	}
 
 	srv := server.New(deps)
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"educonnect/internal/access"
+	"educonnect/internal/events"
+	"educonnect/internal/invoice"
+	"educonnect/internal/ledger"
+	"educonnect/internal/notification"
+	"educonnect/internal/payment"
+	"educonnect/internal/server"
+	"educonnect/internal/session"
+	"educonnect/internal/sessionseries"
+	"educonnect/internal/wallet"
+	"educonnect/internal/worker"
+)
+
+// services holds the domain services the worker drives.
+type services struct {
+	notification *notification.Service
+	session      *session.Service
+	series       *sessionseries.Service
+	ledger       *ledger.Service
+	wallet       *wallet.Service
+	invoice      *invoice.Service
+	payment      *payment.Service
+}
+
+func newServices(deps *server.Dependencies, pub *events.Publisher) *services {
+	policy := access.NewPolicy(deps.DB)
+	walletSvc := wallet.NewService(deps.DB, deps.Storage, pub, deps.Config.Wallet)
+	return &services{
+		notification: notification.NewService(deps.DB),
+		session:      session.NewService(deps.DB, deps.LiveKit, deps.Storage, policy, pub),
+		series:       sessionseries.NewService(deps.DB, deps.LiveKit, walletSvc, policy, pub),
+		ledger:       ledger.NewService(deps.DB),
+		wallet:       walletSvc,
+		invoice:      invoice.NewService(deps.DB, deps.Storage, deps.Config.Invoice),
+		payment:      payment.NewService(deps.DB, deps.Payment, deps.Config.App.URL, deps.Config.Payment.ReturnURL),
+	}
+}
+
+// registerConsumers binds every domain-event handler. Durable names are
+// shared across worker replicas, so each event is handled once.
+func registerConsumers(r *events.Runner, svc *services) {
+	svc.notification.RegisterEventHandlers(r)
+	svc.session.RegisterEventHandlers(r)
+	svc.series.RegisterEventHandlers(r)
+	svc.invoice.RegisterEventHandlers(r)
+	svc.payment.RegisterEventHandlers(r)
+}
+
+// registerJobs declares the periodic jobs. Schedules are evaluated in the
+// worker timezone (WORKER_TIMEZONE).
+func registerJobs(s *worker.Scheduler, deps *server.Dependencies, svc *services) error {
+	jobs := []worker.Job{
+		{
+			Name:     "session-reminders",
+			Schedule: "*/5 * * * *",
+			Run: func(ctx context.Context) error {
+				n, err := svc.notification.SendSessionReminders(ctx, deps.Config.Worker.ReminderLead)
+				if n > 0 {
+					slog.Info("session reminders sent", "sessions", n)
+				}
+				return err
+			},
+		},
+		{
+			Name:     "session-lifecycle",
+			Schedule: "@every 1m",
+			Run: func(ctx context.Context) error {
+				res, err := svc.session.RunLifecycle(ctx)
+				if err != nil {
+					return err
+				}
+				if res.Started+res.Completed+res.NoShow > 0 {
+					slog.Info("session lifecycle sweep",
+						"started", res.Started, "completed", res.Completed, "no_show", res.NoShow)
+				}
+				return nil
+			},
+		},
+		{
+			Name:     "ledger-reconciliation",
+			Schedule: "30 2 * * *",
+			Run: func(ctx context.Context) error {
+				r, err := svc.ledger.Reconcile(ctx)
+				if err != nil {
+					return err
+				}
+				if !r.OK() {
+					slog.Error("ledger reconciliation failed",
+						"wallet_mismatches", len(r.WalletMismatches),
+						"unbalanced_journals", len(r.UnbalancedJournals),
+						"trial_balance", r.TrialBalance)
+					for _, m := range r.WalletMismatches {
+						slog.Error("wallet does not match ledger",
+							"teacher_id", m.TeacherID, "wallet", m.WalletBalance, "ledger", m.LedgerBalance)
+					}
+					return fmt.Errorf("ledger out of balance")
+				}
+				slog.Info("ledger reconciled", "wallets", r.WalletsChecked)
+				return nil
+			},
+		},
+		{
+			Name:     "wallet-purchase-expiry",
+			Schedule: "@every 1h",
+			Run: func(ctx context.Context) error {
+				n, err := svc.wallet.ExpireStalePurchases(ctx)
+				if n > 0 {
+					slog.Info("expired stale wallet purchases", "count", n)
+				}
+				return err
+			},
+		},
+		{
+			Name:     "package-expiry",
+			Schedule: "@every 1h",
+			Run: func(ctx context.Context) error {
+				n, err := svc.payment.ExpirePackagePurchases(ctx)
+				if n > 0 {
+					slog.Info("expired package purchases", "count", n)
+				}
+				return err
+			},
+		},
+		{
+			Name:     "subscription-lifecycle",
+			Schedule: "@every 1h",
+			Run: func(ctx context.Context) error {
+				res, err := svc.payment.RunSubscriptionLifecycle(ctx)
+				if err != nil {
+					return err
+				}
+				if res.Renewed+res.Expired+res.Checkouts > 0 {
+					slog.Info("subscription lifecycle sweep",
+						"renewed", res.Renewed, "expired", res.Expired, "checkouts", res.Checkouts)
+				}
+				return nil
+			},
+		},
+	}
+
+	for _, j := range jobs {
+		if err := s.Register(j); err != nil {
+			return err
+		}
+	}
+	return nil
+}
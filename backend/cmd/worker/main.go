@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"educonnect/internal/config"
+	"educonnect/internal/events"
+	"educonnect/internal/server"
+	"educonnect/internal/worker"
+	"educonnect/pkg/cache"
+	"educonnect/pkg/database"
+	"educonnect/pkg/livekit"
+	"educonnect/pkg/mailer"
+	"educonnect/pkg/messaging"
+	"educonnect/pkg/paygate"
+	"educonnect/pkg/sms"
+	"educonnect/pkg/storage"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	// ── Logger ──────────────────────────────────────────────────
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+	}))
+	slog.SetDefault(logger)
+
+	// ── Load .env (development only) ────────────────────────────
+	for _, p := range []string{"../../.env", "../.env", ".env"} {
+		if err := godotenv.Load(p); err == nil {
+			break
+		}
+	}
+
+	// ── Configuration ───────────────────────────────────────────
+	cfg, err := config.Load()
+	if err != nil {
+		slog.Error("failed to load config", "error", err)
+		os.Exit(1)
+	}
+
+	loc, err := time.LoadLocation(cfg.Worker.Timezone)
+	if err != nil {
+		slog.Error("invalid worker timezone", "timezone", cfg.Worker.Timezone, "error", err)
+		os.Exit(1)
+	}
+
+	// ── Database (PostgreSQL) ───────────────────────────────────
+	db, err := database.NewPostgres(cfg.Database)
+	if err != nil {
+		slog.Error("failed to connect to PostgreSQL", "error", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+	slog.Info("connected to PostgreSQL")
+
+	// ── Cache (Redis) ───────────────────────────────────────────
+	rdb, err := cache.NewRedis(cfg.Redis)
+	if err != nil {
+		slog.Error("failed to connect to Redis", "error", err)
+		os.Exit(1)
+	}
+	defer rdb.Close()
+	slog.Info("connected to Redis")
+
+	// ── Message Queue (NATS) ────────────────────────────────────
+	nc, err := messaging.NewNATS(cfg.NATS)
+	if err != nil {
+		slog.Error("failed to connect to NATS", "error", err)
+		os.Exit(1)
+	}
+	defer nc.Close()
+	slog.Info("connected to NATS")
+
+	// ── Object Storage (MinIO) ──────────────────────────────────
+	store, err := storage.NewMinIO(cfg.MinIO)
+	if err != nil {
+		slog.Error("failed to connect to MinIO", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("connected to MinIO")
+
+	// ── LiveKit ─────────────────────────────────────────────────
+	lkClient := livekit.NewClient(cfg.LiveKit, cfg.MinIO)
+
+	// ── Mailer / SMS ────────────────────────────────────────────
+	mail := mailer.NewMailer(cfg.SMTP)
+	smsSender, err := sms.NewSender(cfg.SMS)
+	if err != nil {
+		slog.Error("failed to configure SMS provider", "error", err)
+		os.Exit(1)
+	}
+
+	// ── Payment gateways (subscription renewals) ────────────────
+	gateways, err := paygate.NewGateways(cfg.Payment)
+	if err != nil {
+		slog.Error("failed to configure payment gateways", "error", err)
+		os.Exit(1)
+	}
+
+	deps := &server.Dependencies{
+		Config:  cfg,
+		DB:      db,
+		Cache:   rdb,
+		MQ:      nc,
+		Storage: store,
+		LiveKit: lkClient,
+		Mailer:  mail,
+		SMS:     smsSender,
+		Payment: gateways,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// ── Event consumers & outbox relay ──────────────────────────
+	publisher := events.NewPublisher(deps.MQ)
+	consumers := events.NewRunner(deps.MQ, deps.DB)
+	svc := newServices(deps, publisher)
+	registerConsumers(consumers, svc)
+
+	if err := consumers.Start(ctx); err != nil {
+		slog.Error("failed to start event consumers", "error", err)
+		os.Exit(1)
+	}
+	relay := events.NewRelay(deps.DB, publisher)
+	relayDone := make(chan struct{})
+	go func() {
+		defer close(relayDone)
+		relay.Run(ctx)
+	}()
+
+	// ── Scheduled jobs ──────────────────────────────────────────
+	scheduler := worker.NewScheduler(deps.Cache, loc, cfg.Worker.LeaderTTL)
+	if err := registerJobs(scheduler, deps, svc); err != nil {
+		slog.Error("failed to register jobs", "error", err)
+		os.Exit(1)
+	}
+	schedulerDone := make(chan struct{})
+	go func() {
+		defer close(schedulerDone)
+		scheduler.Run(ctx)
+	}()
+
+	slog.Info("worker started", "timezone", loc.String())
+
+	// ── Graceful shutdown ───────────────────────────────────────
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	slog.Info("shutting down worker...")
+
+	cancel()
+	consumers.Stop()
+
+	select {
+	case <-schedulerDone:
+	case <-time.After(30 * time.Second):
+		slog.Warn("scheduled jobs did not finish in time")
+	}
+	<-relayDone
+
+	slog.Info("worker stopped")
+}
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- ═══════════════════════════════════════════════════════════════
+-- Transactional Outbox
+-- ═══════════════════════════════════════════════════════════════
+-- Domain events are inserted in the same transaction as the state
+-- change that produced them. A background relay publishes pending
+-- rows to NATS; the row id doubles as the JetStream message id so
+-- retries are deduplicated by the broker and by consumers.
+-- ═══════════════════════════════════════════════════════════════
+
+CREATE TABLE event_outbox (
+    id               UUID PRIMARY KEY,              -- envelope id / idempotency key
+    event_type       VARCHAR(100) NOT NULL,
+    event_version    INT NOT NULL DEFAULT 1,
+    envelope         JSONB NOT NULL,
+    attempts         INT NOT NULL DEFAULT 0,
+    last_error       TEXT,
+    next_attempt_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    published_at     TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_event_outbox_pending ON event_outbox(next_attempt_at) WHERE published_at IS NULL;
+CREATE INDEX idx_event_outbox_published ON event_outbox(published_at) WHERE published_at IS NOT NULL;
+
+-- Consumer-side idempotency: one row per (durable consumer, event id)
+-- successfully handled, so redeliveries are acknowledged without re-running.
+CREATE TABLE processed_events (
+    consumer      VARCHAR(100) NOT NULL,
+    event_id      UUID NOT NULL,
+    processed_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (consumer, event_id)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS processed_events;
+DROP TABLE IF EXISTS event_outbox;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- ═══════════════════════════════════════════════════════════════
+-- Session Reminders
+-- ═══════════════════════════════════════════════════════════════
+-- The worker stamps reminder_sent_at when it notifies a session's
+-- teacher and students, so each session is reminded exactly once
+-- even if several sweeps overlap its reminder window.
+-- ═══════════════════════════════════════════════════════════════
+
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS reminder_sent_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_sessions_reminder_due
+    ON sessions(start_time)
+    WHERE status = 'scheduled' AND reminder_sent_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_sessions_reminder_due;
+ALTER TABLE sessions DROP COLUMN IF EXISTS reminder_sent_at;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+
+-- ═══════════════════════════════════════════════════════════════
+-- Session Lifecycle
+-- ═══════════════════════════════════════════════════════════════
+-- 'no_show' marks a scheduled session whose time window passed
+-- without anyone joining the LiveKit room. The worker's lifecycle
+-- sweep sets it; ALTER TYPE ... ADD VALUE cannot run in a
+-- transaction on older PostgreSQL, hence NO TRANSACTION.
+-- ═══════════════════════════════════════════════════════════════
+
+ALTER TYPE session_status ADD VALUE IF NOT EXISTS 'no_show';
+
+CREATE INDEX IF NOT EXISTS idx_sessions_lifecycle
+    ON sessions(end_time)
+    WHERE status IN ('scheduled', 'live');
+
+-- +goose Down
+-- Enum values cannot be dropped; fold no-shows back into cancellations.
+DROP INDEX IF EXISTS idx_sessions_lifecycle;
+UPDATE sessions SET status = 'cancelled', cancellation_reason = COALESCE(cancellation_reason, 'no_show')
+WHERE status = 'no_show';
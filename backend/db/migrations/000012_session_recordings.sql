@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- ═══════════════════════════════════════════════════════════════
+-- Session Recordings
+-- ═══════════════════════════════════════════════════════════════
+-- Recording-enabled sessions are captured by LiveKit Egress into the
+-- recordings bucket. recording_key is the object key; the file is
+-- only served once Egress reports it complete ('ready').
+-- ═══════════════════════════════════════════════════════════════
+
+ALTER TABLE sessions
+    ADD COLUMN IF NOT EXISTS recording_key       TEXT,
+    ADD COLUMN IF NOT EXISTS recording_egress_id VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS recording_status    VARCHAR(20)
+        CHECK (recording_status IN ('recording', 'ready', 'failed'));
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sessions_recording_egress
+    ON sessions(recording_egress_id) WHERE recording_egress_id IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_sessions_recording_egress;
+ALTER TABLE sessions
+    DROP COLUMN IF EXISTS recording_status,
+    DROP COLUMN IF EXISTS recording_egress_id,
+    DROP COLUMN IF EXISTS recording_key;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- ═══════════════════════════════════════════════════════════════
+-- Payout Workflow
+-- ═══════════════════════════════════════════════════════════════
+-- Teachers request payouts against their available net earnings;
+-- admins move them pending → processing → completed (with the
+-- transfer reference) or reject them (failed). account_number
+-- holds the normalized 20-digit RIP.
+-- ═══════════════════════════════════════════════════════════════
+
+ALTER TABLE payouts
+    ADD COLUMN IF NOT EXISTS admin_id    UUID REFERENCES users(id),
+    ADD COLUMN IF NOT EXISTS admin_notes TEXT,
+    ADD COLUMN IF NOT EXISTS updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+ALTER TABLE payouts
+    ADD CONSTRAINT chk_payouts_amount_positive CHECK (amount > 0);
+
+CREATE INDEX IF NOT EXISTS idx_payouts_teacher ON payouts(teacher_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_payouts_status  ON payouts(status);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_payouts_status;
+DROP INDEX IF EXISTS idx_payouts_teacher;
+ALTER TABLE payouts DROP CONSTRAINT IF EXISTS chk_payouts_amount_positive;
+ALTER TABLE payouts
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS admin_notes,
+    DROP COLUMN IF EXISTS admin_id;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- ═══════════════════════════════════════════════════════════════
+-- Retire Platform Fees
+-- ═══════════════════════════════════════════════════════════════
+-- Session access now follows the star model only (000008): the
+-- teacher pays one star per accepted enrollment and is never
+-- asked for a per-series fee. Nothing reads or writes
+-- platform_fees any more; the table is kept read-only under a
+-- legacy name so past BaridiMob payments stay auditable.
+-- ═══════════════════════════════════════════════════════════════
+
+-- Fees that were never paid are void under the new model.
+UPDATE platform_fees
+SET status = 'failed',
+    admin_notes = COALESCE(admin_notes || E'\n', '') || 'Voided: replaced by per-enrollment stars'
+WHERE status = 'pending';
+
+ALTER TABLE platform_fees RENAME TO legacy_platform_fees;
+ALTER INDEX idx_platform_fees_series  RENAME TO idx_legacy_platform_fees_series;
+ALTER INDEX idx_platform_fees_teacher RENAME TO idx_legacy_platform_fees_teacher;
+ALTER INDEX idx_platform_fees_status  RENAME TO idx_legacy_platform_fees_status;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER INDEX idx_legacy_platform_fees_status  RENAME TO idx_platform_fees_status;
+ALTER INDEX idx_legacy_platform_fees_teacher RENAME TO idx_platform_fees_teacher;
+ALTER INDEX idx_legacy_platform_fees_series  RENAME TO idx_platform_fees_series;
+ALTER TABLE legacy_platform_fees RENAME TO platform_fees;
+-- +goose StatementEnd
@@ -0,0 +1,200 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- ═══════════════════════════════════════════════════════════════
+-- Double-entry Ledger
+-- ═══════════════════════════════════════════════════════════════
+-- Every money movement (wallet purchases, star deductions and
+-- refunds, student payments, payouts) is recorded as a journal
+-- whose postings sum to zero. Postings are signed: positive is a
+-- debit, negative a credit. Journals and postings are immutable;
+-- mistakes are corrected with a reversing journal.
+--
+-- Accounts are keyed by code: system accounts use their kind
+-- ('cash', 'platform_revenue', …), per-teacher accounts use
+-- '<kind>:<teacher_id>'.
+-- ═══════════════════════════════════════════════════════════════
+
+CREATE TABLE ledger_accounts (
+    code        VARCHAR(100) PRIMARY KEY,
+    kind        VARCHAR(30)  NOT NULL CHECK (kind IN (
+                    'cash',               -- asset: platform CCP / BaridiMob account
+                    'pending_purchases',  -- liability: credit purchases awaiting admin approval
+                    'teacher_wallet',     -- liability: prepaid star credit owed to a teacher
+                    'teacher_earnings',   -- liability: student payments owed to a teacher
+                    'payouts_pending',    -- liability: requested payouts not yet transferred
+                    'platform_revenue',   -- revenue: stars consumed, commissions
+                    'refunds',            -- contra-revenue: stars refunded
+                    'bonus_expense',      -- expense: credit package bonuses
+                    'opening_balance'     -- equity: balances that predate the ledger
+                )),
+    owner_id    UUID,                     -- teacher for per-teacher accounts
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_ledger_accounts_kind ON ledger_accounts(kind, owner_id);
+
+CREATE TABLE ledger_journals (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    kind            VARCHAR(50)  NOT NULL,   -- e.g. star_deduction, purchase_approved
+    reference_type  VARCHAR(50)  NOT NULL,   -- e.g. wallet_transaction, payout
+    reference_id    UUID         NOT NULL,
+    description     TEXT NOT NULL DEFAULT '',
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    -- A business event is posted once.
+    CONSTRAINT uq_ledger_journal_reference UNIQUE (kind, reference_type, reference_id)
+);
+
+CREATE INDEX idx_ledger_journals_reference ON ledger_journals(reference_type, reference_id);
+CREATE INDEX idx_ledger_journals_created   ON ledger_journals(created_at DESC);
+
+CREATE TABLE ledger_postings (
+    id            BIGSERIAL PRIMARY KEY,
+    journal_id    UUID NOT NULL REFERENCES ledger_journals(id),
+    account_code  VARCHAR(100) NOT NULL REFERENCES ledger_accounts(code),
+    amount        DECIMAL(14,2) NOT NULL CHECK (amount <> 0)
+);
+
+CREATE INDEX idx_ledger_postings_journal ON ledger_postings(journal_id);
+CREATE INDEX idx_ledger_postings_account ON ledger_postings(account_code);
+
+-- Immutability: no updates or deletes, ever.
+CREATE OR REPLACE FUNCTION ledger_reject_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'ledger is append-only (% on %)', TG_OP, TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_ledger_journals_immutable
+    BEFORE UPDATE OR DELETE ON ledger_journals
+    FOR EACH ROW EXECUTE FUNCTION ledger_reject_change();
+CREATE TRIGGER trg_ledger_postings_immutable
+    BEFORE UPDATE OR DELETE ON ledger_postings
+    FOR EACH ROW EXECUTE FUNCTION ledger_reject_change();
+
+-- Balance: checked at commit so a journal's postings can be inserted
+-- one by one inside the posting transaction.
+CREATE OR REPLACE FUNCTION ledger_check_balanced()
+RETURNS TRIGGER AS $$
+DECLARE
+    total DECIMAL(14,2);
+BEGIN
+    SELECT COALESCE(SUM(amount), 0) INTO total FROM ledger_postings WHERE journal_id = NEW.journal_id;
+    IF total <> 0 THEN
+        RAISE EXCEPTION 'ledger journal % is unbalanced by %', NEW.journal_id, total;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER trg_ledger_postings_balanced
+    AFTER INSERT ON ledger_postings
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION ledger_check_balanced();
+
+-- ─── Opening balances ───────────────────────────────────────────
+-- Bring existing state into the ledger so reconciliation starts green.
+
+INSERT INTO ledger_accounts (code, kind) VALUES
+    ('cash', 'cash'),
+    ('pending_purchases', 'pending_purchases'),
+    ('platform_revenue', 'platform_revenue'),
+    ('refunds', 'refunds'),
+    ('bonus_expense', 'bonus_expense'),
+    ('payouts_pending', 'payouts_pending'),
+    ('opening_balance', 'opening_balance');
+
+-- 1. Wallet balances
+INSERT INTO ledger_accounts (code, kind, owner_id)
+SELECT 'teacher_wallet:' || teacher_id, 'teacher_wallet', teacher_id
+FROM teacher_wallets;
+
+INSERT INTO ledger_journals (kind, reference_type, reference_id, description)
+SELECT 'opening_balance', 'teacher_wallet', id, 'Solde d''ouverture du portefeuille'
+FROM teacher_wallets WHERE balance <> 0;
+
+INSERT INTO ledger_postings (journal_id, account_code, amount)
+SELECT j.id, x.code, x.amount
+FROM ledger_journals j
+JOIN teacher_wallets w ON w.id = j.reference_id
+CROSS JOIN LATERAL (VALUES
+    ('opening_balance', w.balance),
+    ('teacher_wallet:' || w.teacher_id, -w.balance)
+) AS x(code, amount)
+WHERE j.kind = 'opening_balance' AND j.reference_type = 'teacher_wallet';
+
+-- 2. Purchases still awaiting approval (the amount actually paid, bonus excluded)
+INSERT INTO ledger_journals (kind, reference_type, reference_id, description)
+SELECT 'purchase_submitted', 'wallet_transaction', wt.id, wt.description
+FROM wallet_transactions wt
+WHERE wt.type = 'purchase' AND wt.status = 'pending';
+
+INSERT INTO ledger_postings (journal_id, account_code, amount)
+SELECT j.id, x.code, x.amount
+FROM ledger_journals j
+JOIN wallet_transactions wt ON wt.id = j.reference_id
+LEFT JOIN credit_packages cp ON cp.id = wt.package_id
+CROSS JOIN LATERAL (VALUES
+    ('cash', wt.amount - COALESCE(cp.bonus, 0)),
+    ('pending_purchases', -(wt.amount - COALESCE(cp.bonus, 0)))
+) AS x(code, amount)
+WHERE j.kind = 'purchase_submitted';
+
+-- 3. Teacher earnings: net of refunds and of completed payouts
+INSERT INTO ledger_accounts (code, kind, owner_id)
+SELECT DISTINCT 'teacher_earnings:' || t, 'teacher_earnings', t
+FROM (
+    SELECT payee_id AS t FROM transactions WHERE status IN ('completed', 'refunded')
+    UNION SELECT teacher_id FROM payouts
+) owners
+ON CONFLICT (code) DO NOTHING;
+
+CREATE TEMP TABLE opening_earnings ON COMMIT DROP AS
+SELECT a.owner_id AS teacher_id,
+       COALESCE((SELECT SUM(net_amount - COALESCE(refund_amount, 0)) FROM transactions
+                 WHERE payee_id = a.owner_id AND status IN ('completed', 'refunded')), 0)
+     - COALESCE((SELECT SUM(amount) FROM payouts
+                 WHERE teacher_id = a.owner_id AND status = 'completed'), 0) AS amount
+FROM ledger_accounts a
+WHERE a.kind = 'teacher_earnings';
+
+INSERT INTO ledger_journals (kind, reference_type, reference_id, description)
+SELECT 'opening_balance', 'teacher_earnings', teacher_id, 'Solde d''ouverture des revenus'
+FROM opening_earnings WHERE amount <> 0;
+
+INSERT INTO ledger_postings (journal_id, account_code, amount)
+SELECT j.id, x.code, x.amount
+FROM ledger_journals j
+JOIN opening_earnings e ON e.teacher_id = j.reference_id
+CROSS JOIN LATERAL (VALUES
+    ('opening_balance', e.amount),
+    ('teacher_earnings:' || e.teacher_id, -e.amount)
+) AS x(code, amount)
+WHERE j.kind = 'opening_balance' AND j.reference_type = 'teacher_earnings';
+
+-- 4. Payouts requested but not yet transferred
+INSERT INTO ledger_journals (kind, reference_type, reference_id, description)
+SELECT 'payout_requested', 'payout', id, 'Demande de retrait'
+FROM payouts WHERE status IN ('pending', 'processing');
+
+INSERT INTO ledger_postings (journal_id, account_code, amount)
+SELECT j.id, x.code, x.amount
+FROM ledger_journals j
+JOIN payouts p ON p.id = j.reference_id
+CROSS JOIN LATERAL (VALUES
+    ('teacher_earnings:' || p.teacher_id, p.amount),
+    ('payouts_pending', -p.amount)
+) AS x(code, amount)
+WHERE j.kind = 'payout_requested';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS ledger_postings;
+DROP TABLE IF EXISTS ledger_journals;
+DROP TABLE IF EXISTS ledger_accounts;
+DROP FUNCTION IF EXISTS ledger_check_balanced();
+DROP FUNCTION IF EXISTS ledger_reject_change();
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- ═══════════════════════════════════════════════════════════════
+-- Payment Gateways
+-- ═══════════════════════════════════════════════════════════════
+-- Student payments go through a gateway checkout (SATIM for
+-- CIB/Edahabia cards, BaridiMob for CCP) and are completed only by
+-- the gateway's signed callback, never by the payer. A transaction
+-- moves pending → processing (checkout opened) → completed | failed.
+-- provider_reference is the gateway's order ID, unique per gateway.
+-- ═══════════════════════════════════════════════════════════════
+
+ALTER TYPE payment_method ADD VALUE IF NOT EXISTS 'cib';
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS gateway        VARCHAR(20),
+    ADD COLUMN IF NOT EXISTS checkout_url   TEXT,
+    ADD COLUMN IF NOT EXISTS failure_reason TEXT,
+    ADD COLUMN IF NOT EXISTS paid_at        TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_transactions_gateway_reference
+    ON transactions(gateway, provider_reference)
+    WHERE gateway IS NOT NULL AND provider_reference IS NOT NULL;
+
+-- Pending transactions from the self-confirmation flow can no longer
+-- be completed; close them so the payer starts a real checkout.
+UPDATE transactions
+SET status = 'failed', failure_reason = 'superseded by gateway checkout'
+WHERE status = 'pending' AND gateway IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- 'cib' stays in the payment_method enum: Postgres cannot drop enum values.
+DROP INDEX IF EXISTS uq_transactions_gateway_reference;
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS paid_at,
+    DROP COLUMN IF EXISTS failure_reason,
+    DROP COLUMN IF EXISTS checkout_url,
+    DROP COLUMN IF EXISTS gateway;
+-- +goose StatementEnd
@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- ═══════════════════════════════════════════════════════════════
+-- Purchase Receipts
+-- ═══════════════════════════════════════════════════════════════
+-- Teachers top up their wallet by CCP transfer and attach the
+-- receipt (image or PDF, stored in the documents bucket) to the
+-- pending purchase; admins approve against it. provider_ref is
+-- compared normalized (alphanumerics, upper case) so the same
+-- transfer cannot be claimed twice. Purchases left pending past
+-- the validation window are expired by the worker.
+-- ═══════════════════════════════════════════════════════════════
+
+ALTER TYPE wallet_tx_status ADD VALUE IF NOT EXISTS 'expired';
+
+ALTER TABLE wallet_transactions
+    ADD COLUMN IF NOT EXISTS receipt_key          TEXT,
+    ADD COLUMN IF NOT EXISTS receipt_content_type VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS receipt_uploaded_at  TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS provider_ref_key     VARCHAR(255)
+        GENERATED ALWAYS AS (upper(regexp_replace(provider_ref, '[^A-Za-z0-9]', '', 'g'))) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_wallet_tx_provider_ref
+    ON wallet_transactions(provider_ref_key)
+    WHERE type = 'purchase' AND provider_ref_key <> '';
+
+CREATE INDEX IF NOT EXISTS idx_wallet_tx_pending_purchases
+    ON wallet_transactions(created_at)
+    WHERE type = 'purchase' AND status = 'pending';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- 'expired' stays in the wallet_tx_status enum: Postgres cannot drop enum values.
+DROP INDEX IF EXISTS idx_wallet_tx_pending_purchases;
+DROP INDEX IF EXISTS idx_wallet_tx_provider_ref;
+ALTER TABLE wallet_transactions
+    DROP COLUMN IF EXISTS provider_ref_key,
+    DROP COLUMN IF EXISTS receipt_uploaded_at,
+    DROP COLUMN IF EXISTS receipt_content_type,
+    DROP COLUMN IF EXISTS receipt_key;
+-- +goose StatementEnd
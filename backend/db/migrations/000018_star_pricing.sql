@@ -0,0 +1,67 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- ═══════════════════════════════════════════════════════════════
+-- Star Pricing
+-- ═══════════════════════════════════════════════════════════════
+-- The star cost per accepted enrollment is configured by admins
+-- instead of being compiled in. Each row is one immutable price
+-- version for a session type, optionally narrowed to a level
+-- and/or a wilaya, valid from effective_from until effective_until
+-- (open-ended when NULL). A deduction uses the most specific row in
+-- force — level before wilaya, then the latest effective_from —
+-- and records it in wallet_transactions.star_price_id.
+-- ═══════════════════════════════════════════════════════════════
+
+CREATE TABLE IF NOT EXISTS star_prices (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    version         BIGINT GENERATED ALWAYS AS IDENTITY UNIQUE,
+    session_type    session_type NOT NULL,
+    level_id        UUID REFERENCES levels(id),       -- NULL = any level
+    wilaya          VARCHAR(100),                      -- NULL = any wilaya
+    price           DECIMAL(10,2) NOT NULL CHECK (price > 0),
+    effective_from  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    effective_until TIMESTAMPTZ,
+    notes           TEXT NOT NULL DEFAULT '',
+    created_by      UUID REFERENCES users(id),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (effective_until IS NULL OR effective_until > effective_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_star_prices_lookup
+    ON star_prices(session_type, effective_from DESC);
+
+-- Defaults in force since the wallet was introduced (50 / 70 DZD).
+INSERT INTO star_prices (session_type, price, effective_from, notes) VALUES
+    ('group',      50, '2000-01-01', 'initial group price'),
+    ('one_on_one', 70, '2000-01-01', 'initial private price');
+
+ALTER TABLE wallet_transactions
+    ADD COLUMN IF NOT EXISTS star_price_id UUID REFERENCES star_prices(id);
+
+-- Past deductions were charged the initial defaults.
+UPDATE wallet_transactions wt
+SET star_price_id = sp.id
+FROM session_series ss, star_prices sp
+WHERE wt.type = 'star_deduction' AND wt.star_price_id IS NULL
+  AND ss.id = wt.series_id
+  AND sp.session_type = ss.session_type AND sp.effective_from = '2000-01-01'
+  AND sp.level_id IS NULL AND sp.wilaya IS NULL;
+
+-- Packages are now edited by admins.
+ALTER TABLE credit_packages
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+CREATE TRIGGER trigger_credit_packages_updated
+    BEFORE UPDATE ON credit_packages
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS trigger_credit_packages_updated ON credit_packages;
+ALTER TABLE credit_packages DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE wallet_transactions DROP COLUMN IF EXISTS star_price_id;
+DROP TABLE IF EXISTS star_prices;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- ═══════════════════════════════════════════════════════════════
+-- Awaiting Credit
+-- ═══════════════════════════════════════════════════════════════
+-- An acceptance the teacher's wallet cannot pay for is parked as
+-- 'awaiting_credit' instead of failing. It is completed, and the
+-- star deducted, once an approved purchase raises the balance;
+-- parked enrollments are resumed oldest first.
+-- ═══════════════════════════════════════════════════════════════
+
+ALTER TYPE enrollment_status ADD VALUE IF NOT EXISTS 'awaiting_credit';
+
+ALTER TABLE session_enrollments
+    ADD COLUMN IF NOT EXISTS awaiting_credit_at TIMESTAMPTZ;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- 'awaiting_credit' stays in the enrollment_status enum: Postgres cannot drop enum values.
+ALTER TABLE session_enrollments DROP COLUMN IF EXISTS awaiting_credit_at;
+-- +goose StatementEnd
@@ -0,0 +1,60 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- ═══════════════════════════════════════════════════════════════
+-- Partial Star Refunds
+-- ═══════════════════════════════════════════════════════════════
+-- A student leaving a series no longer refunds all or nothing: the
+-- refund policy grants a full, grace or pro-rated share of the star,
+-- and admins may refund more. An enrollment can therefore carry
+-- several refund rows, each pointing at the deduction it returns
+-- (refund_of) and naming the rule that produced it. Their sum never
+-- exceeds the deduction.
+--
+-- Earlier refunds named no deduction, only the enrollment. Each is
+-- linked to the one deduction it can have returned; when several
+-- fit, it is left unlinked and keeps counting against the
+-- enrollment's latest deduction.
+-- ═══════════════════════════════════════════════════════════════
+
+ALTER TABLE wallet_transactions
+    ADD COLUMN IF NOT EXISTS refund_of   UUID REFERENCES wallet_transactions(id),
+    ADD COLUMN IF NOT EXISTS refund_rule VARCHAR(20);   -- before_start, grace, prorated, admin
+
+UPDATE wallet_transactions r
+SET refund_of = d.id, refund_rule = 'before_start'
+FROM wallet_transactions d
+WHERE r.type = 'refund' AND r.refund_of IS NULL
+  AND d.type = 'star_deduction' AND d.status = 'completed'
+  AND d.enrollment_id = r.enrollment_id AND d.wallet_id = r.wallet_id
+  AND d.amount = r.amount AND d.created_at <= r.created_at
+  AND NOT EXISTS (
+      SELECT 1 FROM wallet_transactions o
+      WHERE o.id <> d.id
+        AND o.type = 'star_deduction' AND o.status = 'completed'
+        AND o.enrollment_id = r.enrollment_id AND o.wallet_id = r.wallet_id
+        AND o.amount = r.amount AND o.created_at <= r.created_at
+  );
+
+CREATE INDEX IF NOT EXISTS idx_wallet_tx_refund_of
+    ON wallet_transactions(refund_of) WHERE refund_of IS NOT NULL;
+
+-- Who took the student out of the series: the teacher, or the
+-- student/parent withdrawing.
+ALTER TABLE session_enrollments
+    ADD COLUMN IF NOT EXISTS removed_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS removed_by VARCHAR(10)
+        CHECK (removed_by IN ('teacher', 'student'));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE session_enrollments
+    DROP COLUMN IF EXISTS removed_by,
+    DROP COLUMN IF EXISTS removed_at;
+DROP INDEX IF EXISTS idx_wallet_tx_refund_of;
+ALTER TABLE wallet_transactions
+    DROP COLUMN IF EXISTS refund_rule,
+    DROP COLUMN IF EXISTS refund_of;
+-- +goose StatementEnd
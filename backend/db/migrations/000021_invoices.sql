@@ -0,0 +1,63 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- ═══════════════════════════════════════════════════════════════
+-- Invoices & Credit Notes
+-- ═══════════════════════════════════════════════════════════════
+-- Every completed wallet purchase and student payment gets one
+-- invoice; refunds are documented by credit notes against it,
+-- never by editing the invoice. Numbers are gapless per kind and
+-- calendar year (FA-2026-000001, AV-2026-000001), drawn from
+-- invoice_sequences under a row lock in the issuing transaction.
+-- The parties and amounts are snapshotted on the row so the PDF
+-- (documents bucket, object_key) can be regenerated identically.
+-- ═══════════════════════════════════════════════════════════════
+
+CREATE TYPE invoice_kind AS ENUM ('invoice', 'credit_note');
+
+CREATE TABLE IF NOT EXISTS invoice_sequences (
+    kind        invoice_kind NOT NULL,
+    year        INT NOT NULL,
+    last_number INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (kind, year)
+);
+
+CREATE TABLE IF NOT EXISTS invoices (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    kind            invoice_kind NOT NULL,
+    number          VARCHAR(20) NOT NULL UNIQUE,
+    source_type     VARCHAR(20) NOT NULL CHECK (source_type IN ('wallet_purchase', 'payment')),
+    source_id       UUID NOT NULL,                       -- wallet_transactions / transactions
+    credit_note_of  UUID REFERENCES invoices(id),        -- credit notes only
+    customer_id     UUID NOT NULL REFERENCES users(id),  -- billed party
+    customer_name   TEXT NOT NULL,
+    customer_wilaya VARCHAR(100),
+    teacher_id      UUID NOT NULL REFERENCES users(id),  -- same as customer for wallet purchases
+    teacher_name    TEXT NOT NULL,
+    description     TEXT NOT NULL,
+    amount          DECIMAL(10,2) NOT NULL CHECK (amount > 0),
+    payment_method  VARCHAR(30),
+    reference       VARCHAR(255),                        -- gateway or CCP reference
+    reason          TEXT,                                -- credit notes: why
+    object_key      TEXT,                                -- NULL until the PDF is stored
+    generated_at    TIMESTAMPTZ,
+    issued_by       UUID REFERENCES users(id),           -- admin, when issued by hand
+    issued_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK ((kind = 'credit_note') = (credit_note_of IS NOT NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_invoices_source
+    ON invoices(source_type, source_id) WHERE kind = 'invoice';
+CREATE INDEX IF NOT EXISTS idx_invoices_customer ON invoices(customer_id, issued_at DESC);
+CREATE INDEX IF NOT EXISTS idx_invoices_teacher  ON invoices(teacher_id, issued_at DESC);
+CREATE INDEX IF NOT EXISTS idx_invoices_credit_note_of
+    ON invoices(credit_note_of) WHERE credit_note_of IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS invoices;
+DROP TABLE IF EXISTS invoice_sequences;
+DROP TYPE IF EXISTS invoice_kind;
+-- +goose StatementEnd
@@ -0,0 +1,59 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- ═══════════════════════════════════════════════════════════════
+-- Subscription Lifecycle
+-- ═══════════════════════════════════════════════════════════════
+-- A subscription runs in monthly periods (start_date..end_date).
+-- Each completed session the student attended with the teacher
+-- uses one of the period's sessions_per_month, recorded once in
+-- subscription_usages. When a period ends the worker expires the
+-- subscription or, with auto_renew, opens the next period together
+-- with a renewal transaction; a renewal left unpaid past the grace
+-- period expires it. Pausing freezes the period, and resuming
+-- pushes end_date back by the days spent paused.
+-- ═══════════════════════════════════════════════════════════════
+
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS period                 INT NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS renewal_method         payment_method NOT NULL DEFAULT 'ccp_baridimob',
+    ADD COLUMN IF NOT EXISTS renewal_transaction_id UUID REFERENCES transactions(id),
+    ADD COLUMN IF NOT EXISTS renewed_at             TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS paused_at              TIMESTAMPTZ,
+    ADD CONSTRAINT chk_subscriptions_sessions_used CHECK (sessions_used >= 0);
+
+ALTER TABLE transactions
+    ADD CONSTRAINT fk_transactions_subscription
+    FOREIGN KEY (subscription_id) REFERENCES subscriptions(id) NOT VALID;
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_due
+    ON subscriptions(end_date) WHERE status = 'active';
+
+CREATE TABLE IF NOT EXISTS subscription_usages (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    period          INT NOT NULL,
+    session_id      UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    student_id      UUID NOT NULL REFERENCES users(id),
+    used_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (session_id, student_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_usages_subscription
+    ON subscription_usages(subscription_id, period);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS subscription_usages;
+DROP INDEX IF EXISTS idx_subscriptions_due;
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS fk_transactions_subscription;
+ALTER TABLE subscriptions
+    DROP CONSTRAINT IF EXISTS chk_subscriptions_sessions_used,
+    DROP COLUMN IF EXISTS paused_at,
+    DROP COLUMN IF EXISTS renewed_at,
+    DROP COLUMN IF EXISTS renewal_transaction_id,
+    DROP COLUMN IF EXISTS renewal_method,
+    DROP COLUMN IF EXISTS period;
+-- +goose StatementEnd
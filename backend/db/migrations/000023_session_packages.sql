@@ -0,0 +1,85 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- ═══════════════════════════════════════════════════════════════
+-- Session Packages
+-- ═══════════════════════════════════════════════════════════════
+-- Teachers sell bundles of sessions for one of their offerings
+-- (packages). A student — or a parent for their child — buys one
+-- through a payment transaction: the purchase stays 'pending' until
+-- the gateway confirms it, then becomes 'active' for valid_days.
+-- Each booking the teacher accepts uses one session of the
+-- student's oldest active purchase with that teacher, recorded in
+-- booking_requests.package_purchase_id. Purchases left unused past
+-- expires_at are expired by the worker.
+-- ═══════════════════════════════════════════════════════════════
+
+CREATE TYPE package_purchase_status AS ENUM ('pending', 'active', 'expired', 'cancelled');
+
+ALTER TABLE packages
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD CONSTRAINT chk_packages_total_sessions CHECK (total_sessions > 0),
+    ADD CONSTRAINT chk_packages_price CHECK (price > 0),
+    ADD CONSTRAINT chk_packages_valid_days CHECK (valid_days > 0);
+
+CREATE INDEX IF NOT EXISTS idx_packages_teacher ON packages(teacher_id);
+
+CREATE TRIGGER trigger_packages_updated
+    BEFORE UPDATE ON packages
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+-- Rows written before purchases were paid through the gateway are
+-- taken as active; new purchases start pending.
+ALTER TABLE package_purchases
+    ADD COLUMN IF NOT EXISTS status         package_purchase_status NOT NULL DEFAULT 'active',
+    ADD COLUMN IF NOT EXISTS total_sessions INT,
+    ADD COLUMN IF NOT EXISTS purchased_by   UUID REFERENCES users(id),   -- parent buying for a child
+    ADD COLUMN IF NOT EXISTS activated_at   TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD CONSTRAINT chk_package_purchases_remaining CHECK (sessions_remaining >= 0);
+
+ALTER TABLE package_purchases ALTER COLUMN status SET DEFAULT 'pending';
+
+UPDATE package_purchases pp
+SET total_sessions = p.total_sessions, activated_at = pp.created_at
+FROM packages p
+WHERE p.id = pp.package_id AND pp.total_sessions IS NULL;
+
+ALTER TABLE package_purchases ALTER COLUMN total_sessions SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_package_purchases_balance
+    ON package_purchases(student_id, created_at) WHERE status = 'active';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_package_purchases_transaction
+    ON package_purchases(transaction_id);
+
+CREATE TRIGGER trigger_package_purchases_updated
+    BEFORE UPDATE ON package_purchases
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+ALTER TABLE booking_requests
+    ADD COLUMN IF NOT EXISTS package_purchase_id UUID REFERENCES package_purchases(id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE booking_requests DROP COLUMN IF EXISTS package_purchase_id;
+DROP TRIGGER IF EXISTS trigger_package_purchases_updated ON package_purchases;
+DROP INDEX IF EXISTS idx_package_purchases_transaction;
+DROP INDEX IF EXISTS idx_package_purchases_balance;
+ALTER TABLE package_purchases
+    DROP CONSTRAINT IF EXISTS chk_package_purchases_remaining,
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS activated_at,
+    DROP COLUMN IF EXISTS purchased_by,
+    DROP COLUMN IF EXISTS total_sessions,
+    DROP COLUMN IF EXISTS status;
+DROP TYPE IF EXISTS package_purchase_status;
+DROP TRIGGER IF EXISTS trigger_packages_updated ON packages;
+DROP INDEX IF EXISTS idx_packages_teacher;
+ALTER TABLE packages
+    DROP CONSTRAINT IF EXISTS chk_packages_valid_days,
+    DROP CONSTRAINT IF EXISTS chk_packages_price,
+    DROP CONSTRAINT IF EXISTS chk_packages_total_sessions,
+    DROP COLUMN IF EXISTS updated_at;
+-- +goose StatementEnd
@@ -0,0 +1,85 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- ═══════════════════════════════════════════════════════════════
+-- Promotions
+-- ═══════════════════════════════════════════════════════════════
+-- Teachers discount their own payments and packages. A promotion
+-- with a code is a coupon the student enters (at booking or when
+-- paying); one without is applied automatically. It is valid
+-- between start_date and end_date, up to max_usage uses overall
+-- and per_student_limit per student, and only for offering_id when
+-- set. Each use is a promotion_redemptions row tied to the
+-- transaction it discounted, which records promotion_id and
+-- discount_amount; a payment that fails gives its use back
+-- (released_at).
+-- ═══════════════════════════════════════════════════════════════
+
+ALTER TABLE promotions
+    ADD COLUMN IF NOT EXISTS code              VARCHAR(32),
+    ADD COLUMN IF NOT EXISTS offering_id       UUID REFERENCES offerings(id),
+    ADD COLUMN IF NOT EXISTS per_student_limit INT,
+    ADD COLUMN IF NOT EXISTS updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD CONSTRAINT chk_promotions_type CHECK (
+        (type = 'percentage' AND value > 0 AND value < 100) OR
+        (type = 'fixed' AND value > 0) OR
+        (type = 'buy_x_get_y' AND buy_quantity > 0 AND free_quantity > 0)),
+    ADD CONSTRAINT chk_promotions_window CHECK (end_date > start_date),
+    ADD CONSTRAINT chk_promotions_usage CHECK (usage_count >= 0),
+    ADD CONSTRAINT chk_promotions_per_student CHECK (per_student_limit IS NULL OR per_student_limit > 0);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_promotions_code
+    ON promotions(UPPER(code)) WHERE code IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_promotions_teacher ON promotions(teacher_id);
+
+CREATE TRIGGER trigger_promotions_updated
+    BEFORE UPDATE ON promotions
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS promotion_id    UUID REFERENCES promotions(id),
+    ADD COLUMN IF NOT EXISTS discount_amount DECIMAL(10,2) NOT NULL DEFAULT 0;
+
+-- The redemption is written before the transaction it discounts
+-- (the discount sets the amount), hence the deferred key.
+CREATE TABLE IF NOT EXISTS promotion_redemptions (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    promotion_id    UUID NOT NULL REFERENCES promotions(id) ON DELETE CASCADE,
+    student_id      UUID NOT NULL REFERENCES users(id),
+    transaction_id  UUID NOT NULL UNIQUE
+                    REFERENCES transactions(id) ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED,
+    discount_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    free_sessions   INT NOT NULL DEFAULT 0,
+    released_at     TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_promotion_redemptions_student
+    ON promotion_redemptions(promotion_id, student_id) WHERE released_at IS NULL;
+
+-- A code entered when booking is redeemed when the session is paid.
+ALTER TABLE booking_requests
+    ADD COLUMN IF NOT EXISTS promo_code VARCHAR(32);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE booking_requests DROP COLUMN IF EXISTS promo_code;
+DROP TABLE IF EXISTS promotion_redemptions;
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS discount_amount,
+    DROP COLUMN IF EXISTS promotion_id;
+DROP TRIGGER IF EXISTS trigger_promotions_updated ON promotions;
+DROP INDEX IF EXISTS idx_promotions_teacher;
+DROP INDEX IF EXISTS idx_promotions_code;
+ALTER TABLE promotions
+    DROP CONSTRAINT IF EXISTS chk_promotions_per_student,
+    DROP CONSTRAINT IF EXISTS chk_promotions_usage,
+    DROP CONSTRAINT IF EXISTS chk_promotions_window,
+    DROP CONSTRAINT IF EXISTS chk_promotions_type,
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS per_student_limit,
+    DROP COLUMN IF EXISTS offering_id,
+    DROP COLUMN IF EXISTS code;
+-- +goose StatementEnd
@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- ═══════════════════════════════════════════════════════════════
+-- Scheduling Time Zones
+-- ═══════════════════════════════════════════════════════════════
+-- Every user schedules in an IANA zone (users.timezone). A teacher's
+-- availability_slots and the requested_date/start_time/end_time of
+-- bookings made with them are wall-clock times in that zone; a
+-- booking keeps the zone it was made in (booking_requests.timezone)
+-- so accepting it later yields the instant the student asked for.
+--
+-- Sessions created from bookings used to read the wall clock as UTC,
+-- starting an hour late in Algiers; those still to come or under way
+-- and matching that reading are moved to the instant meant. Held and
+-- cancelled sessions keep the times they were recorded with.
+-- ═══════════════════════════════════════════════════════════════
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'Africa/Algiers';
+
+ALTER TABLE booking_requests
+    ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'Africa/Algiers';
+
+UPDATE sessions s
+SET start_time = (s.start_time AT TIME ZONE 'UTC') AT TIME ZONE 'Africa/Algiers',
+    end_time   = (s.end_time AT TIME ZONE 'UTC') AT TIME ZONE 'Africa/Algiers'
+WHERE s.status IN ('scheduled', 'live')
+  AND s.id IN (
+    SELECT br.session_id FROM booking_requests br
+    WHERE br.session_id IS NOT NULL
+      AND s.start_time = (br.requested_date + br.start_time) AT TIME ZONE 'UTC'
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Corrected session times are kept.
+ALTER TABLE booking_requests DROP COLUMN IF EXISTS timezone;
+ALTER TABLE users DROP COLUMN IF EXISTS timezone;
+-- +goose StatementEnd
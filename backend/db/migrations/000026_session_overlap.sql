@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- ═══════════════════════════════════════════════════════════════
+-- Session Overlap
+-- ═══════════════════════════════════════════════════════════════
+-- A teacher's scheduled and live sessions may not overlap in time
+-- (sessions touching end to start are fine). The application checks
+-- first to explain refusals; this constraint closes the races.
+--
+-- Sessions already overlapping, or ending before they start, when it
+-- arrived are marked overlap_exempt and left for the teacher to sort
+-- out: the constraint ignores them until they are rescheduled, the
+-- application check does not.
+-- ═══════════════════════════════════════════════════════════════
+
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+ALTER TABLE sessions
+    ADD COLUMN IF NOT EXISTS overlap_exempt BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE sessions s SET overlap_exempt = true
+WHERE s.status IN ('scheduled', 'live')
+  AND CASE WHEN s.end_time < s.start_time THEN true ELSE EXISTS (
+        SELECT 1 FROM sessions o
+        WHERE o.teacher_id = s.teacher_id
+          AND o.id <> s.id
+          AND o.status IN ('scheduled', 'live')
+          AND o.start_time < s.end_time
+          AND o.end_time > s.start_time) END;
+
+ALTER TABLE sessions
+    ADD CONSTRAINT sessions_no_overlap EXCLUDE USING gist (
+        teacher_id WITH =,
+        tstzrange(start_time, end_time) WITH &&
+    ) WHERE (status IN ('scheduled', 'live') AND NOT overlap_exempt);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE sessions DROP CONSTRAINT IF EXISTS sessions_no_overlap;
+ALTER TABLE sessions DROP COLUMN IF EXISTS overlap_exempt;
+-- +goose StatementEnd
@@ -0,0 +1,127 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- ═══════════════════════════════════════════════════════════════
+-- Availability Exceptions & Holidays
+-- ═══════════════════════════════════════════════════════════════
+-- A teacher's availability on a date is their weekly slots, less
+-- the days (or hours) they take off, plus one-off extra slots, both
+-- kept in availability_exceptions. Exceptions are on the teacher's
+-- wall clock, like the weekly slots.
+--
+-- holidays is the national calendar: Algerian public holidays and
+-- school holidays. Teachers opt into the kinds they observe through
+-- teacher_profiles.observed_holidays; an observed holiday closes the
+-- day unless the teacher adds an extra slot on it. Public holidays
+-- are seeded through 2030. Religious holidays follow the lunar
+-- calendar and school holidays the ministry's yearly calendar: their
+-- expected dates are seeded (school holidays for 2026-2027) and admins
+-- correct them once announced, and enter later school years as the
+-- ministry publishes them.
+-- ═══════════════════════════════════════════════════════════════
+
+UPDATE availability_exceptions SET is_available = false WHERE is_available IS NULL;
+
+-- Rows from before are left as they are; availability ignores extra
+-- slots without times and takes partial times as the whole day off.
+ALTER TABLE availability_exceptions
+    ALTER COLUMN is_available SET NOT NULL,
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD CONSTRAINT chk_availability_exceptions_times CHECK (
+        (start_time IS NULL AND end_time IS NULL AND NOT is_available)
+        OR (start_time IS NOT NULL AND end_time IS NOT NULL AND end_time > start_time)
+    ) NOT VALID;
+
+CREATE INDEX IF NOT EXISTS idx_availability_exceptions_teacher
+    ON availability_exceptions(teacher_id, exception_date);
+
+CREATE TRIGGER trigger_availability_exceptions_updated
+    BEFORE UPDATE ON availability_exceptions
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+CREATE TABLE holidays (
+    id          UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    kind        VARCHAR(10) NOT NULL,          -- public or school
+    name_fr     VARCHAR(255) NOT NULL,
+    name_ar     VARCHAR(255),
+    start_date  DATE NOT NULL,
+    end_date    DATE NOT NULL,                 -- inclusive
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_holidays_kind CHECK (kind IN ('public', 'school')),
+    CONSTRAINT chk_holidays_dates CHECK (end_date >= start_date)
+);
+
+CREATE INDEX idx_holidays_dates ON holidays(start_date, end_date);
+
+CREATE TRIGGER trigger_holidays_updated
+    BEFORE UPDATE ON holidays
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+ALTER TABLE teacher_profiles
+    ADD COLUMN IF NOT EXISTS observed_holidays TEXT[] NOT NULL DEFAULT '{}',
+    ADD CONSTRAINT chk_teacher_profiles_observed_holidays
+        CHECK (observed_holidays <@ ARRAY['public', 'school']::TEXT[]);
+
+-- Fixed public holidays
+INSERT INTO holidays (kind, name_fr, name_ar, start_date, end_date)
+SELECT 'public', f.name_fr, f.name_ar, make_date(y, f.month, f.day), make_date(y, f.month, f.day)
+FROM generate_series(2026, 2030) AS y,
+     (VALUES
+        (1, 1, 'Nouvel An', 'رأس السنة الميلادية'),
+        (1, 12, 'Yennayer', 'رأس السنة الأمازيغية'),
+        (5, 1, 'Fête du Travail', 'عيد العمال'),
+        (7, 5, 'Fête de l''Indépendance', 'عيد الاستقلال'),
+        (11, 1, 'Anniversaire de la Révolution', 'عيد الثورة')
+     ) AS f(month, day, name_fr, name_ar);
+
+-- Religious holidays, expected dates
+INSERT INTO holidays (kind, name_fr, name_ar, start_date, end_date) VALUES
+    ('public', 'Aïd el-Fitr', 'عيد الفطر', '2026-03-20', '2026-03-21'),
+    ('public', 'Aïd el-Adha', 'عيد الأضحى', '2026-05-27', '2026-05-28'),
+    ('public', 'Awal Moharram', 'رأس السنة الهجرية', '2026-06-16', '2026-06-16'),
+    ('public', 'Achoura', 'عاشوراء', '2026-06-25', '2026-06-25'),
+    ('public', 'Mawlid Ennabaoui', 'المولد النبوي', '2026-08-25', '2026-08-25'),
+    ('public', 'Aïd el-Fitr', 'عيد الفطر', '2027-03-10', '2027-03-11'),
+    ('public', 'Aïd el-Adha', 'عيد الأضحى', '2027-05-16', '2027-05-17'),
+    ('public', 'Awal Moharram', 'رأس السنة الهجرية', '2027-06-06', '2027-06-06'),
+    ('public', 'Achoura', 'عاشوراء', '2027-06-15', '2027-06-15'),
+    ('public', 'Mawlid Ennabaoui', 'المولد النبوي', '2027-08-15', '2027-08-15'),
+    ('public', 'Aïd el-Fitr', 'عيد الفطر', '2028-02-27', '2028-02-28'),
+    ('public', 'Aïd el-Adha', 'عيد الأضحى', '2028-05-05', '2028-05-06'),
+    ('public', 'Awal Moharram', 'رأس السنة الهجرية', '2028-05-25', '2028-05-25'),
+    ('public', 'Achoura', 'عاشوراء', '2028-06-03', '2028-06-03'),
+    ('public', 'Mawlid Ennabaoui', 'المولد النبوي', '2028-08-03', '2028-08-03'),
+    ('public', 'Aïd el-Fitr', 'عيد الفطر', '2029-02-15', '2029-02-16'),
+    ('public', 'Aïd el-Adha', 'عيد الأضحى', '2029-04-24', '2029-04-25'),
+    ('public', 'Awal Moharram', 'رأس السنة الهجرية', '2029-05-14', '2029-05-14'),
+    ('public', 'Achoura', 'عاشوراء', '2029-05-23', '2029-05-23'),
+    ('public', 'Mawlid Ennabaoui', 'المولد النبوي', '2029-07-24', '2029-07-24'),
+    ('public', 'Aïd el-Fitr', 'عيد الفطر', '2030-02-05', '2030-02-06'),
+    ('public', 'Aïd el-Adha', 'عيد الأضحى', '2030-04-13', '2030-04-14'),
+    ('public', 'Awal Moharram', 'رأس السنة الهجرية', '2030-05-03', '2030-05-03'),
+    ('public', 'Achoura', 'عاشوراء', '2030-05-12', '2030-05-12'),
+    ('public', 'Mawlid Ennabaoui', 'المولد النبوي', '2030-07-13', '2030-07-13');
+
+-- School holidays 2026-2027, expected dates
+INSERT INTO holidays (kind, name_fr, name_ar, start_date, end_date) VALUES
+    ('school', 'Vacances d''automne', 'عطلة الخريف', '2026-10-29', '2026-11-07'),
+    ('school', 'Vacances d''hiver', 'عطلة الشتاء', '2026-12-17', '2027-01-02'),
+    ('school', 'Vacances de printemps', 'عطلة الربيع', '2027-03-18', '2027-04-03'),
+    ('school', 'Vacances d''été', 'العطلة الصيفية', '2027-06-17', '2027-09-18');
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE teacher_profiles
+    DROP CONSTRAINT IF EXISTS chk_teacher_profiles_observed_holidays,
+    DROP COLUMN IF EXISTS observed_holidays;
+DROP TABLE IF EXISTS holidays;
+DROP TRIGGER IF EXISTS trigger_availability_exceptions_updated ON availability_exceptions;
+DROP INDEX IF EXISTS idx_availability_exceptions_teacher;
+ALTER TABLE availability_exceptions
+    DROP CONSTRAINT IF EXISTS chk_availability_exceptions_times,
+    DROP COLUMN IF EXISTS updated_at,
+    ALTER COLUMN is_available DROP NOT NULL;
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- ═══════════════════════════════════════════════════════════════
+-- Recurring Bookings
+-- ═══════════════════════════════════════════════════════════════
+-- A booking request may repeat weekly: on the weekdays in
+-- recurrence_days (0 = Sunday, as availability_slots.day_of_week),
+-- from requested_date until recurrence_until or for recurrence_count
+-- occurrences, always at start_time-end_time on the teacher's wall
+-- clock. The occurrences are derived from the rule, not stored;
+-- accepting the request creates one series with a session for each.
+-- ═══════════════════════════════════════════════════════════════
+
+ALTER TABLE booking_requests
+    ADD COLUMN IF NOT EXISTS recurrence_days SMALLINT[],
+    ADD COLUMN IF NOT EXISTS recurrence_until DATE,
+    ADD COLUMN IF NOT EXISTS recurrence_count INT,
+    ADD CONSTRAINT chk_booking_requests_recurrence CHECK (
+        (recurrence_days IS NULL AND recurrence_until IS NULL AND recurrence_count IS NULL)
+        OR (cardinality(recurrence_days) > 0
+            AND recurrence_days <@ ARRAY[0, 1, 2, 3, 4, 5, 6]::SMALLINT[]
+            AND (recurrence_until IS NULL) <> (recurrence_count IS NULL)
+            AND (recurrence_until IS NULL OR recurrence_until >= requested_date)
+            AND (recurrence_count IS NULL OR recurrence_count > 0))
+    );
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE booking_requests
+    DROP CONSTRAINT IF EXISTS chk_booking_requests_recurrence,
+    DROP COLUMN IF EXISTS recurrence_count,
+    DROP COLUMN IF EXISTS recurrence_until,
+    DROP COLUMN IF EXISTS recurrence_days;
+-- +goose StatementEnd
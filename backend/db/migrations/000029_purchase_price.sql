@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- ═══════════════════════════════════════════════════════════════
+-- Purchase Price
+-- ═══════════════════════════════════════════════════════════════
+-- A credit purchase is posted to the ledger when an admin approves
+-- it, not when the teacher submits it: until the receipt is checked
+-- nothing is known to have reached the platform's account, and a
+-- rejected or expired purchase leaves no entries to compensate.
+-- The price paid (bonus excluded) is kept on the purchase instead,
+-- as it stood on submission, since a package may be repriced before
+-- the review. Purchases submitted earlier carry it in their
+-- purchase_submitted journal; the ledger is append-only, so those
+-- journals stay and are settled on review as before.
+-- ═══════════════════════════════════════════════════════════════
+
+ALTER TABLE wallet_transactions
+    ADD COLUMN IF NOT EXISTS price DECIMAL(10,2) CHECK (price >= 0);
+
+UPDATE wallet_transactions wt
+SET price = p.amount
+FROM ledger_journals j
+JOIN ledger_postings p ON p.journal_id = j.id AND p.account_code = 'cash'
+WHERE j.kind = 'purchase_submitted'
+  AND j.reference_type = 'wallet_transaction'
+  AND j.reference_id = wt.id;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE wallet_transactions DROP COLUMN IF EXISTS price;
+-- +goose StatementEnd
@@ -0,0 +1,111 @@
+package access
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"educonnect/pkg/database"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrSessionNotFound = errors.New("session not found")
+	ErrNotJoinable     = errors.New("session is not open for joining")
+	ErrNotEnrolled     = errors.New("not enrolled in this session series")
+	ErrSeatReleased    = errors.New("enrollment star was refunded — seat released")
+)
+
+// ─── Join Policy ────────────────────────────────────────────────
+//
+// Who may enter a session's LiveKit room. Under the star model the teacher
+// pays one star per student when an enrollment is accepted, so the room is
+// never gated on a payment at join time:
+//
+//	teacher      always, for their own scheduled or live sessions
+//	series       students with an accepted enrollment whose star was not
+//	             refunded (bookings and pre-wallet enrollments carry no
+//	             star deduction and are let in on the enrollment alone)
+//	standalone   any signed-in user; these sessions have no roster
+
+// Policy evaluates the join rules against the database.
+type Policy struct {
+	db *database.Postgres
+}
+
+func NewPolicy(db *database.Postgres) *Policy {
+	return &Policy{db: db}
+}
+
+// Grant describes a session a user has been allowed to join.
+type Grant struct {
+	SessionID   uuid.UUID
+	TeacherID   uuid.UUID
+	SeriesID    *uuid.UUID
+	Status      string
+	RoomID      string
+	SessionType string
+	IsTeacher   bool
+}
+
+// facts is everything decide needs; kept separate so the rules are testable
+// without a database.
+type facts struct {
+	status       string
+	isTeacher    bool
+	inSeries     bool
+	enrollment   string // "" when the user has no enrollment in the series
+	starRefunded bool
+}
+
+func decide(f facts) error {
+	if f.status != "scheduled" && f.status != "live" {
+		return ErrNotJoinable
+	}
+	if f.isTeacher || !f.inSeries {
+		return nil
+	}
+	if f.enrollment != "accepted" {
+		return ErrNotEnrolled
+	}
+	if f.starRefunded {
+		return ErrSeatReleased
+	}
+	return nil
+}
+
+// CanJoin loads the session and the user's enrollment and applies the rules.
+func (p *Policy) CanJoin(ctx context.Context, sessionID, userID uuid.UUID) (*Grant, error) {
+	g := Grant{SessionID: sessionID}
+	var enrollment string
+	var refunded bool
+	err := p.db.Pool.QueryRow(ctx,
+		`SELECT s.teacher_id, s.series_id, s.status::text, COALESCE(s.livekit_room_id,''), s.session_type::text,
+		        COALESCE(e.status::text,''),
+		        EXISTS (SELECT 1 FROM wallet_transactions wt
+		                WHERE wt.enrollment_id = e.id AND wt.type = 'refund' AND wt.status = 'completed')
+		 FROM sessions s
+		 LEFT JOIN session_enrollments e ON e.series_id = s.series_id AND e.student_id = $2
+		 WHERE s.id = $1`, sessionID, userID,
+	).Scan(&g.TeacherID, &g.SeriesID, &g.Status, &g.RoomID, &g.SessionType, &enrollment, &refunded)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("load session access: %w", err)
+	}
+	g.IsTeacher = g.TeacherID == userID
+
+	if err := decide(facts{
+		status:       g.Status,
+		isTeacher:    g.IsTeacher,
+		inSeries:     g.SeriesID != nil,
+		enrollment:   enrollment,
+		starRefunded: refunded,
+	}); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
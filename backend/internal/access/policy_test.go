@@ -0,0 +1,29 @@
+package access
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecide(t *testing.T) {
+	cases := []struct {
+		name string
+		f    facts
+		want error
+	}{
+		{"teacher, scheduled", facts{status: "scheduled", isTeacher: true, inSeries: true}, nil},
+		{"teacher, completed", facts{status: "completed", isTeacher: true, inSeries: true}, ErrNotJoinable},
+		{"student, cancelled", facts{status: "cancelled", inSeries: true, enrollment: "accepted"}, ErrNotJoinable},
+		{"student, accepted", facts{status: "live", inSeries: true, enrollment: "accepted"}, nil},
+		{"student, invited only", facts{status: "live", inSeries: true, enrollment: "invited"}, ErrNotEnrolled},
+		{"student, no enrollment", facts{status: "live", inSeries: true}, ErrNotEnrolled},
+		{"student, star refunded", facts{status: "live", inSeries: true, enrollment: "accepted", starRefunded: true}, ErrSeatReleased},
+		{"student, standalone session", facts{status: "scheduled"}, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, decide(tc.f))
+		})
+	}
+}
@@ -121,3 +121,18 @@ type LevelInput struct {
 	Cycle string `json:"cycle" validate:"required"`
 	Order int    `json:"order" validate:"required,min=1,max=12"`
 }
+
+// UpdateHolidaysRequest adds holidays to the national calendar, or
+// corrects those given with an id (religious dates once announced).
+type UpdateHolidaysRequest struct {
+	Holidays []HolidayInput `json:"holidays" validate:"required,dive"`
+}
+
+type HolidayInput struct {
+	ID        *uuid.UUID `json:"id,omitempty"`
+	Kind      string     `json:"kind" validate:"required,oneof=public school"`
+	NameFr    string     `json:"name" validate:"required,max=255"`
+	NameAr    string     `json:"name_ar" validate:"max=255"`
+	StartDate string     `json:"start_date" validate:"required,datetime=2006-01-02"`
+	EndDate   string     `json:"end_date" validate:"required,datetime=2006-01-02"`
+}
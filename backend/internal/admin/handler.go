@@ -244,10 +244,43 @@ func (h *Handler) UpdateLevels(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"success": true, "data": levels})
 }
 
+// UpdateHolidays PUT /admin/config/holidays
+func (h *Handler) UpdateHolidays(c *gin.Context) {
+	var req UpdateHolidaysRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+		return
+	}
+
+	holidays, err := h.service.UpdateHolidays(c.Request.Context(), req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": holidays})
+}
+
+// DeleteHoliday DELETE /admin/config/holidays/:id
+func (h *Handler) DeleteHoliday(c *gin.Context) {
+	if err := h.service.DeleteHoliday(c.Request.Context(), c.Param("id")); err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"message": "holiday deleted"}})
+}
+
 func handleError(c *gin.Context, err error) {
 	switch {
-	case errors.Is(err, ErrUserNotFound), errors.Is(err, ErrDisputeNotFound), errors.Is(err, ErrVerifyNotFound):
+	case errors.Is(err, ErrUserNotFound), errors.Is(err, ErrDisputeNotFound), errors.Is(err, ErrVerifyNotFound),
+		errors.Is(err, ErrHolidayNotFound):
 		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+	case errors.Is(err, ErrInvalidHoliday):
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
 	default:
 		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"message": "internal server error"}})
 	}
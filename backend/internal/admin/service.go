@@ -16,6 +16,8 @@ var (
 	ErrUserNotFound    = errors.New("user not found")
 	ErrDisputeNotFound = errors.New("dispute not found")
 	ErrVerifyNotFound  = errors.New("verification not found")
+	ErrHolidayNotFound = errors.New("holiday not found")
+	ErrInvalidHoliday  = errors.New("invalid holiday")
 )
 
 type Service struct {
@@ -550,3 +552,83 @@ func (s *Service) UpdateLevels(ctx context.Context, req UpdateLevelsRequest) ([]
 
 	return result, nil
 }
+
+// ═══════════════════════════════════════════════════════════════
+// Config (Holidays)
+// ═══════════════════════════════════════════════════════════════
+
+type HolidayResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Kind      string    `json:"kind"`
+	NameFr    string    `json:"name"`
+	NameAr    string    `json:"name_ar"`
+	StartDate string    `json:"start_date"`
+	EndDate   string    `json:"end_date"`
+}
+
+// UpdateHolidays inserts the holidays without an id and rewrites those
+// with one, all or nothing.
+func (s *Service) UpdateHolidays(ctx context.Context, req UpdateHolidaysRequest) ([]HolidayResponse, error) {
+	for _, h := range req.Holidays {
+		if h.EndDate < h.StartDate {
+			return nil, fmt.Errorf("%w: %s ends before it starts", ErrInvalidHoliday, h.NameFr)
+		}
+	}
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result := []HolidayResponse{}
+	for _, h := range req.Holidays {
+		var resp HolidayResponse
+		var start, end time.Time
+		if h.ID != nil {
+			err = tx.QueryRow(ctx,
+				`UPDATE holidays SET kind = $2, name_fr = $3, name_ar = NULLIF($4, ''),
+				    start_date = $5::date, end_date = $6::date
+				 WHERE id = $1
+				 RETURNING id, kind, name_fr, COALESCE(name_ar, ''), start_date, end_date`,
+				*h.ID, h.Kind, h.NameFr, h.NameAr, h.StartDate, h.EndDate,
+			).Scan(&resp.ID, &resp.Kind, &resp.NameFr, &resp.NameAr, &start, &end)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrHolidayNotFound
+			}
+		} else {
+			err = tx.QueryRow(ctx,
+				`INSERT INTO holidays (kind, name_fr, name_ar, start_date, end_date)
+				 VALUES ($1, $2, NULLIF($3, ''), $4::date, $5::date)
+				 RETURNING id, kind, name_fr, COALESCE(name_ar, ''), start_date, end_date`,
+				h.Kind, h.NameFr, h.NameAr, h.StartDate, h.EndDate,
+			).Scan(&resp.ID, &resp.Kind, &resp.NameFr, &resp.NameAr, &start, &end)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("save holiday %s: %w", h.NameFr, err)
+		}
+		resp.StartDate = start.Format("2006-01-02")
+		resp.EndDate = end.Format("2006-01-02")
+		result = append(result, resp)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return result, nil
+}
+
+func (s *Service) DeleteHoliday(ctx context.Context, holidayID string) error {
+	id, err := uuid.Parse(holidayID)
+	if err != nil {
+		return ErrHolidayNotFound
+	}
+	tag, err := s.db.Pool.Exec(ctx, `DELETE FROM holidays WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete holiday: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrHolidayNotFound
+	}
+	return nil
+}
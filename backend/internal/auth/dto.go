@@ -71,7 +71,8 @@ type RefreshTokenRequest struct {
 }
 
 type ForgotPasswordRequest struct {
-	Email string `json:"email" binding:"required,email"`
+	Email string `json:"email" binding:"required_without=Phone,omitempty,email"`
+	Phone string `json:"phone" binding:"required_without=Email,omitempty,min=10,max=15"`
 }
 
 type ResetPasswordRequest struct {
@@ -112,3 +113,7 @@ type OTPResponse struct {
 	Message   string `json:"message"`
 	ExpiresIn int    `json:"expires_in"` // seconds
 }
+
+type MessageResponse struct {
+	Message string `json:"message"`
+}
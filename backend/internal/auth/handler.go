@@ -244,6 +244,53 @@ func (h *Handler) RefreshToken(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": resp})
 }
 
+// ForgotPassword godoc
+// @Summary Request a password reset link by email or SMS
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body ForgotPasswordRequest true "Email or phone"
+// @Success 200 {object} MessageResponse
+// @Router /auth/forgot-password [post]
+func (h *Handler) ForgotPassword(c *gin.Context) {
+	var req ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	resp, err := h.service.ForgotPassword(c.Request.Context(), req)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// ResetPassword godoc
+// @Summary Set a new password using a reset token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} MessageResponse
+// @Router /auth/reset-password [post]
+func (h *Handler) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	if err := h.service.ResetPassword(c.Request.Context(), req); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": MessageResponse{Message: "password has been reset"}})
+}
+
 // ─── Helpers ────────────────────────────────────────────────────
 
 func handleServiceError(c *gin.Context, err error) {
@@ -254,7 +301,7 @@ func handleServiceError(c *gin.Context, err error) {
 		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 	case errors.Is(err, ErrInvalidOTP):
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-	case errors.Is(err, ErrTooManyOTPAttempts):
+	case errors.Is(err, ErrTooManyOTPAttempts), errors.Is(err, ErrTooManyResetAttempts):
 		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
 	case errors.Is(err, ErrInvalidToken):
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
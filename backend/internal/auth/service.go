@@ -15,7 +15,9 @@ import (
 	"educonnect/internal/middleware"
 	"educonnect/pkg/cache"
 	"educonnect/pkg/database"
+	"educonnect/pkg/mailer"
 	"educonnect/pkg/search"
+	"educonnect/pkg/sms"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
@@ -24,25 +26,30 @@ import (
 )
 
 var (
-	ErrInvalidCredentials = errors.New("invalid email or password")
-	ErrUserExists         = errors.New("user with this email or phone already exists")
-	ErrInvalidOTP         = errors.New("invalid or expired OTP code")
-	ErrTooManyOTPAttempts = errors.New("too many OTP attempts, please try again later")
-	ErrInvalidToken       = errors.New("invalid or expired token")
-	ErrUserNotFound       = errors.New("user not found")
+	ErrInvalidCredentials   = errors.New("invalid email or password")
+	ErrUserExists           = errors.New("user with this email or phone already exists")
+	ErrInvalidOTP           = errors.New("invalid or expired OTP code")
+	ErrTooManyOTPAttempts   = errors.New("too many OTP attempts, please try again later")
+	ErrInvalidToken         = errors.New("invalid or expired token")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrTooManyResetAttempts = errors.New("too many password reset requests, please try again later")
 )
 
+const resetTokenExpiry = 30 * time.Minute
+
 // Service handles authentication business logic.
 type Service struct {
 	db     *database.Postgres
 	cache  *cache.Redis
 	cfg    *config.Config
 	search *search.Meilisearch
+	mailer *mailer.Mailer
+	sms    sms.SMSSender
 }
 
 // NewService creates a new auth service.
-func NewService(db *database.Postgres, cache *cache.Redis, cfg *config.Config, search *search.Meilisearch) *Service {
-	return &Service{db: db, cache: cache, cfg: cfg, search: search}
+func NewService(db *database.Postgres, cache *cache.Redis, cfg *config.Config, search *search.Meilisearch, mailer *mailer.Mailer, smsSender sms.SMSSender) *Service {
+	return &Service{db: db, cache: cache, cfg: cfg, search: search, mailer: mailer, sms: smsSender}
 }
 
 // ─── Registration ───────────────────────────────────────────────
@@ -310,8 +317,14 @@ func (s *Service) SendOTP(ctx context.Context, phone string) (*OTPResponse, erro
 		return nil, fmt.Errorf("store OTP: %w", err)
 	}
 
-	// TODO: Send OTP via SMS (ICOSNET/Twilio)
-	slog.Info("OTP generated", "phone", phone) // code intentionally NOT logged
+	if s.sms != nil {
+		msg := fmt.Sprintf("Votre code EduConnect : %s. Valable 5 minutes.", code)
+		if err := s.sms.Send(ctx, phone, msg); err != nil {
+			_ = s.cache.DeleteOTP(ctx, phone)
+			return nil, fmt.Errorf("send OTP: %w", err)
+		}
+	}
+	slog.Info("OTP sent", "phone", phone) // code intentionally NOT logged
 
 	return &OTPResponse{
 		Message:   "OTP sent successfully",
@@ -406,6 +419,116 @@ func (s *Service) RefreshToken(ctx context.Context, refreshToken string) (*AuthR
 	return s.generateAuthResponse(ctx, userID, email, role, firstName, lastName, wilaya, createdAt)
 }
 
+// ─── Password Reset ─────────────────────────────────────────────
+
+// ForgotPassword issues a single-use reset token and delivers it by email or SMS.
+// The response is identical whether or not the account exists, so it cannot be
+// used to enumerate users.
+func (s *Service) ForgotPassword(ctx context.Context, req ForgotPasswordRequest) (*MessageResponse, error) {
+	identifier := req.Email
+	if identifier == "" {
+		identifier = req.Phone
+	}
+
+	attempts, err := s.cache.IncrResetAttempts(ctx, identifier)
+	if err != nil {
+		slog.Error("reset rate check failed", "error", err)
+	}
+	if attempts > 3 {
+		return nil, ErrTooManyResetAttempts
+	}
+
+	resp := &MessageResponse{Message: "If an account exists, reset instructions have been sent"}
+
+	var (
+		userID uuid.UUID
+		email  string
+		phone  string
+	)
+	err = s.db.Pool.QueryRow(ctx,
+		`SELECT id, COALESCE(email, ''), COALESCE(phone, '')
+		 FROM users
+		 WHERE ((NULLIF($1, '') IS NOT NULL AND email = $1) OR (NULLIF($2, '') IS NOT NULL AND phone = $2))
+		   AND is_active = true`,
+		req.Email, req.Phone,
+	).Scan(&userID, &email, &phone)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return resp, nil
+		}
+		return nil, fmt.Errorf("query user: %w", err)
+	}
+
+	// From here on a failure is logged, not returned: an error only
+	// known accounts could produce would tell them apart.
+	token := generateRefreshToken()
+	if err := s.cache.SetResetToken(ctx, hashToken(token), userID.String(), resetTokenExpiry); err != nil {
+		slog.Error("store reset token failed", "user_id", userID, "error", err)
+		return resp, nil
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", s.cfg.App.URL, token)
+	if req.Email != "" {
+		body := fmt.Sprintf("Pour réinitialiser votre mot de passe EduConnect, ouvrez ce lien (valable 30 minutes) :\n\n%s\n\nSi vous n'êtes pas à l'origine de cette demande, ignorez ce message.", link)
+		if s.mailer != nil {
+			if err := s.mailer.Send(ctx, email, "Réinitialisation de votre mot de passe", body); err != nil {
+				slog.Error("send reset email failed", "user_id", userID, "error", err)
+			}
+		}
+	} else if s.sms != nil {
+		msg := fmt.Sprintf("EduConnect : réinitialisez votre mot de passe (valable 30 min) : %s", link)
+		if err := s.sms.Send(ctx, phone, msg); err != nil {
+			slog.Error("send reset sms failed", "user_id", userID, "error", err)
+		}
+	}
+
+	return resp, nil
+}
+
+// ResetPassword consumes a reset token, sets the new password and revokes
+// every refresh token of the user so existing sessions are logged out.
+func (s *Service) ResetPassword(ctx context.Context, req ResetPasswordRequest) error {
+	userIDStr, err := s.cache.ConsumeResetToken(ctx, hashToken(req.Token))
+	if err != nil {
+		return ErrInvalidToken
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return ErrInvalidToken
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), 12)
+	if err != nil {
+		return fmt.Errorf("hash password: %w", err)
+	}
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx,
+		`UPDATE users SET password_hash = $1, updated_at = NOW() WHERE id = $2 AND is_active = true`,
+		string(hash), userID,
+	)
+	if err != nil {
+		return fmt.Errorf("update password: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM refresh_tokens WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("revoke refresh tokens: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
 // ─── Helpers ────────────────────────────────────────────────────
 
 func (s *Service) generateAuthResponse(ctx context.Context, userID uuid.UUID, email, role, firstName, lastName string, wilaya *string, createdAt time.Time) (*AuthResponse, error) {
@@ -9,6 +9,7 @@ import (
 
 	"educonnect/internal/booking"
 	"educonnect/internal/config"
+	"educonnect/internal/scheduling"
 	"educonnect/pkg/database"
 
 	"github.com/google/uuid"
@@ -61,7 +62,7 @@ func TestMain(m *testing.M) {
 	}
 	defer testDB.Close()
 
-	testService = booking.NewService(testDB)
+	testService = booking.NewService(testDB, nil, nil)
 
 	// Run tests
 	code := m.Run()
@@ -910,6 +911,113 @@ func TestBookingService_TimeConflicts(t *testing.T) {
 	})
 }
 
+// ═══════════════════════════════════════════════════════════════
+// Test: Weekly Recurring Bookings
+// ═══════════════════════════════════════════════════════════════
+
+func TestBookingService_Recurring(t *testing.T) {
+	ctx := context.Background()
+
+	// Setup
+	teacher := createTeacherWithProfile(t, ctx, "RecurringTest", "Teacher")
+	defer cleanupTestUser(t, ctx, teacher.ID)
+
+	firstTuesday := getNextWeekday(time.Tuesday)
+	createTeacherAvailability(t, ctx, teacher.ID, 2, "08:00", "20:00")
+
+	parent := createParentWithProfile(t, ctx, "RecurringTest", "Parent")
+	defer cleanupTestUser(t, ctx, parent.ID)
+	child := createStudentWithProfile(t, ctx, "RecurringTest", "Child", &parent.ID)
+	defer cleanupTestUser(t, ctx, child.ID)
+
+	weekly := func(startTime, endTime string) booking.CreateBookingRequest {
+		return booking.CreateBookingRequest{
+			TeacherID:     teacher.ID.String(),
+			SessionType:   "individual",
+			RequestedDate: firstTuesday.Format("2006-01-02"),
+			StartTime:     startTime,
+			EndTime:       endTime,
+			Message:       "Every Tuesday",
+			ForChildID:    child.ID.String(),
+			Recurrence:    &booking.RecurrenceInput{Days: []int{2}, Count: 4},
+		}
+	}
+
+	// ─── A session on the third Tuesday is in the way ───────────
+	thirdTuesday := firstTuesday.AddDate(0, 0, 14).Format("2006-01-02")
+	created, err := testService.CreateBookingRequest(ctx, parent.ID.String(), "parent", booking.CreateBookingRequest{
+		TeacherID:     teacher.ID.String(),
+		SessionType:   "individual",
+		RequestedDate: thirdTuesday,
+		StartTime:     "17:00",
+		EndTime:       "18:00",
+		ForChildID:    child.ID.String(),
+	})
+	require.NoError(t, err)
+	_, err = testService.AcceptBookingRequest(ctx, created.ID, teacher.ID.String(), booking.AcceptBookingRequest{Price: 1500})
+	require.NoError(t, err)
+
+	t.Run("ConflictsReportedPerDate", func(t *testing.T) {
+		_, err := testService.CreateBookingRequest(ctx, parent.ID.String(), "parent", weekly("17:00", "18:00"))
+		require.ErrorIs(t, err, booking.ErrRecurrenceConflict)
+
+		var rc *booking.RecurrenceConflictError
+		require.ErrorAs(t, err, &rc)
+		require.Len(t, rc.Conflicts, 1)
+		assert.Equal(t, thirdTuesday, rc.Conflicts[0].Date)
+
+		t.Log("✓ Only the booked Tuesday is reported")
+	})
+
+	t.Run("FirstDateOffRule", func(t *testing.T) {
+		req := weekly("18:00", "19:00")
+		req.Recurrence.Days = []int{3}
+		_, err := testService.CreateBookingRequest(ctx, parent.ID.String(), "parent", req)
+		assert.ErrorIs(t, err, scheduling.ErrInvalidRecurrence)
+	})
+
+	t.Run("AcceptCreatesSeries", func(t *testing.T) {
+		created, err := testService.CreateBookingRequest(ctx, parent.ID.String(), "parent", weekly("18:00", "19:00"))
+		require.NoError(t, err)
+		require.NotNil(t, created.Recurrence)
+		require.Len(t, created.Occurrences, 4)
+		assert.Equal(t, firstTuesday.Format("2006-01-02"), created.Occurrences[0])
+		assert.Equal(t, firstTuesday.AddDate(0, 0, 21).Format("2006-01-02"), created.Occurrences[3])
+
+		_, err = testService.AcceptBookingRequest(ctx, created.ID, teacher.ID.String(), booking.AcceptBookingRequest{
+			Price:            1500,
+			ExistingSeriesID: uuid.NewString(),
+		})
+		assert.ErrorIs(t, err, scheduling.ErrInvalidRecurrence, "a weekly booking makes its own series")
+
+		accepted, err := testService.AcceptBookingRequest(ctx, created.ID, teacher.ID.String(), booking.AcceptBookingRequest{
+			Price:       1500,
+			Description: "Révisions du BEM",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "accepted", accepted.Status)
+		require.NotNil(t, accepted.SeriesID)
+
+		var sessions, participants, described, maxParticipants int
+		err = testDB.Pool.QueryRow(ctx,
+			`SELECT COUNT(*), (SELECT COUNT(*) FROM session_participants sp
+			                   JOIN sessions s ON s.id = sp.session_id
+			                   WHERE s.series_id = $1 AND sp.student_id = $2),
+			        COUNT(*) FILTER (WHERE description = 'Révisions du BEM'),
+			        MAX(max_participants)
+			 FROM sessions WHERE series_id = $1`,
+			*accepted.SeriesID, child.ID,
+		).Scan(&sessions, &participants, &described, &maxParticipants)
+		require.NoError(t, err)
+		assert.Equal(t, 4, sessions)
+		assert.Equal(t, 4, participants)
+		assert.Equal(t, 4, described, "sessions carry the booking's description")
+		assert.Equal(t, 1, maxParticipants, "sessions take the series' capacity")
+
+		t.Log("✓ Accepting created one series with a session per Tuesday")
+	})
+}
+
 // ═══════════════════════════════════════════════════════════════
 // Test: Multiple Parents, Multiple Children
 // ═══════════════════════════════════════════════════════════════
@@ -17,12 +17,25 @@ type BookingRequestResponse struct {
 	RequestedDate string  `json:"requested_date"` // YYYY-MM-DD
 	StartTime     string  `json:"start_time"`     // HH:MM
 	EndTime       string  `json:"end_time"`       // HH:MM
-	Message       string  `json:"message,omitempty"`
-	Purpose       string  `json:"purpose,omitempty"` // exam_prep, revision, homework, etc.
-	Status        string  `json:"status"`            // pending, accepted, declined, cancelled
-	DeclineReason string  `json:"decline_reason,omitempty"`
-	SessionID     *string `json:"session_id,omitempty"` // Set when accepted
-	SeriesID      *string `json:"series_id,omitempty"`  // Set when accepted — the series this booking feeds into
+	// Timezone is the IANA zone the date and times above are in (the
+	// teacher's); StartsAt and EndsAt are the same times with its offset.
+	Timezone      string     `json:"timezone"`
+	StartsAt      *time.Time `json:"starts_at,omitempty"`
+	EndsAt        *time.Time `json:"ends_at,omitempty"`
+	Message       string     `json:"message,omitempty"`
+	Purpose       string     `json:"purpose,omitempty"` // exam_prep, revision, homework, etc.
+	Status        string     `json:"status"`            // pending, accepted, declined, cancelled
+	DeclineReason string     `json:"decline_reason,omitempty"`
+	SessionID     *string    `json:"session_id,omitempty"` // Set when accepted
+	SeriesID      *string    `json:"series_id,omitempty"`  // Set when accepted — the series this booking feeds into
+	// PackagePurchaseID is set when a session package paid for the booking
+	PackagePurchaseID *string `json:"package_purchase_id,omitempty"`
+	// PromoCode is redeemed when the session is paid
+	PromoCode *string `json:"promo_code,omitempty"`
+	// Recurrence is set for a weekly booking; Occurrences lists its
+	// dates, RequestedDate being the first.
+	Recurrence  *RecurrenceInput `json:"recurrence,omitempty"`
+	Occurrences []string         `json:"occurrences,omitempty"` // YYYY-MM-DD
 	// Parent booking fields
 	BookedByParentID   *string   `json:"booked_by_parent_id,omitempty"`
 	BookedByParentName string    `json:"booked_by_parent_name,omitempty"`
@@ -30,6 +43,13 @@ type BookingRequestResponse struct {
 	UpdatedAt          time.Time `json:"updated_at"`
 }
 
+// OccurrenceConflict is why one date of a weekly booking cannot be
+// booked.
+type OccurrenceConflict struct {
+	Date   string `json:"date"` // YYYY-MM-DD
+	Reason string `json:"reason"`
+}
+
 // ─── Requests ───────────────────────────────────────────────────
 
 type CreateBookingRequest struct {
@@ -43,13 +63,25 @@ type CreateBookingRequest struct {
 	Purpose       string `json:"purpose,omitempty"` // exam_prep, revision, homework, regular, catch_up
 	// Parent booking: if set, parent is booking for this child
 	ForChildID string `json:"for_child_id,omitempty"`
+	// Promo code checked now and redeemed when the session is paid
+	PromoCode string `json:"promo_code,omitempty" binding:"omitempty,max=32"`
+	// Recurrence repeats the booking weekly from RequestedDate
+	Recurrence *RecurrenceInput `json:"recurrence,omitempty"`
+}
+
+// RecurrenceInput repeats a booking weekly on Days (0 = Sunday), until
+// Until or for Count occurrences.
+type RecurrenceInput struct {
+	Days  []int  `json:"days" binding:"required,min=1,max=7,dive,min=0,max=6"`
+	Until string `json:"until,omitempty"` // YYYY-MM-DD, included
+	Count int    `json:"count,omitempty" binding:"omitempty,min=1"`
 }
 
 type AcceptBookingRequest struct {
 	Title            string  `json:"title,omitempty"`
 	Description      string  `json:"description,omitempty"`
 	Price            float64 `json:"price" binding:"required,min=0"`
-	ExistingSeriesID string  `json:"existing_series_id,omitempty"` // If set, add student to this existing series instead of creating a new one
+	ExistingSeriesID string  `json:"existing_series_id,omitempty"` // If set, add student to this existing series instead of creating a new one (not for recurring bookings)
 }
 
 type DeclineBookingRequest struct {
@@ -7,6 +7,9 @@ import (
 	"strconv"
 	"strings"
 
+	"educonnect/internal/promotion"
+	"educonnect/internal/scheduling"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -59,6 +62,19 @@ func (h *Handler) CreateBookingRequest(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
 		case errors.Is(err, ErrAlreadyBooked):
 			c.JSON(http.StatusConflict, gin.H{"error": "Ce créneau est déjà réservé"})
+		case errors.Is(err, ErrRecurrenceConflict):
+			var rc *RecurrenceConflictError
+			errors.As(err, &rc)
+			c.JSON(http.StatusConflict, gin.H{
+				"error":     "Certaines dates de cette réservation ne sont pas disponibles",
+				"conflicts": rc.Conflicts,
+			})
+		case errors.Is(err, scheduling.ErrInvalidRecurrence):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Récurrence invalide: " + err.Error()})
+		case errors.Is(err, ErrSubscriptionExhausted):
+			c.JSON(http.StatusConflict, gin.H{"error": "Toutes les séances de votre abonnement ont été utilisées pour cette période"})
+		case promotion.IsRefusal(err):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Ce code promo n'est pas valable pour cette réservation"})
 		default:
 			slog.Error("create booking failed", "error", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur interne du serveur"})
@@ -142,6 +158,10 @@ func (h *Handler) AcceptBookingRequest(c *gin.Context) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "Non autorisé"})
 		case errors.Is(err, ErrInvalidStatus):
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Cette demande ne peut plus être acceptée"})
+		case errors.Is(err, ErrSubscriptionExhausted):
+			c.JSON(http.StatusConflict, gin.H{"error": "L'abonnement de cet élève n'a plus de séance disponible pour cette période"})
+		case errors.Is(err, scheduling.ErrInvalidRecurrence):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Une réservation récurrente crée sa propre série et ne peut pas rejoindre une série existante"})
 		case errors.Is(err, ErrTimeConflict), errors.Is(err, ErrSessionFull):
 			// Extract the friendly message after the sentinel prefix
 			msg := err.Error()
@@ -8,7 +8,11 @@ import (
 	"strings"
 	"time"
 
+	"educonnect/internal/events"
 	"educonnect/internal/notification"
+	"educonnect/internal/promotion"
+	"educonnect/internal/scheduling"
+	"educonnect/internal/sessionseries"
 	"educonnect/pkg/database"
 
 	"github.com/google/uuid"
@@ -16,22 +20,37 @@ import (
 )
 
 var (
-	ErrBookingNotFound  = errors.New("booking request not found")
-	ErrUnauthorized     = errors.New("unauthorized action")
-	ErrInvalidStatus    = errors.New("invalid booking status for this action")
-	ErrSlotNotAvailable = errors.New("teacher is not available at this time")
-	ErrAlreadyBooked    = errors.New("this time slot is already booked")
-	ErrTimeConflict     = errors.New("time slot conflict")
-	ErrSessionFull      = errors.New("session is full")
+	ErrBookingNotFound       = errors.New("booking request not found")
+	ErrUnauthorized          = errors.New("unauthorized action")
+	ErrInvalidStatus         = errors.New("invalid booking status for this action")
+	ErrSlotNotAvailable      = errors.New("teacher is not available at this time")
+	ErrAlreadyBooked         = errors.New("this time slot is already booked")
+	ErrTimeConflict          = errors.New("time slot conflict")
+	ErrSessionFull           = errors.New("session is full")
+	ErrSubscriptionExhausted = errors.New("subscription sessions exhausted")
+	ErrRecurrenceConflict    = errors.New("some dates of the recurring booking are not available")
 )
 
+// RecurrenceConflictError lists the dates of a weekly booking that
+// cannot be booked, each with the reason.
+type RecurrenceConflictError struct {
+	Conflicts []OccurrenceConflict
+}
+
+func (e *RecurrenceConflictError) Error() string {
+	return fmt.Sprintf("%v (%d)", ErrRecurrenceConflict, len(e.Conflicts))
+}
+
+func (e *RecurrenceConflictError) Unwrap() error { return ErrRecurrenceConflict }
+
 type Service struct {
 	db     *database.Postgres
 	notifs *notification.Service
+	events *events.Publisher
 }
 
-func NewService(db *database.Postgres, notifs *notification.Service) *Service {
-	return &Service{db: db, notifs: notifs}
+func NewService(db *database.Postgres, notifs *notification.Service, pub *events.Publisher) *Service {
+	return &Service{db: db, notifs: notifs, events: pub}
 }
 
 // CreateBookingRequest creates a new booking request from a student or parent.
@@ -97,77 +116,94 @@ func (s *Service) CreateBookingRequest(ctx context.Context, callerID string, cal
 		return nil, fmt.Errorf("invalid end_time format (use HH:MM): %w", err)
 	}
 
-	// Check teacher availability for this day/time
-	dayOfWeek := int(reqDate.Weekday())
-	var available bool
-	err = s.db.Pool.QueryRow(ctx,
-		`SELECT EXISTS(
-			SELECT 1 FROM availability_slots
-			WHERE teacher_id = $1
-			AND day_of_week = $2
-			AND start_time <= $3::time
-			AND end_time >= $4::time
-		)`,
-		tuid, dayOfWeek, req.StartTime, req.EndTime,
-	).Scan(&available)
-	if err != nil {
-		return nil, fmt.Errorf("check availability: %w", err)
-	}
-	if !available {
-		// Fetch the teacher's available slots for this day to include in the error
-		rows, qErr := s.db.Pool.Query(ctx,
-			`SELECT start_time::text, end_time::text
-			 FROM availability_slots
-			 WHERE teacher_id = $1 AND day_of_week = $2
-			 ORDER BY start_time`,
-			tuid, dayOfWeek,
-		)
-		var slots []string
-		if qErr == nil {
-			defer rows.Close()
-			for rows.Next() {
-				var st, et string
-				if rows.Scan(&st, &et) == nil {
-					slots = append(slots, st[:5]+"-"+et[:5])
-				}
-			}
+	// A weekly booking asks for every date of its rule at the same times
+	dates := []time.Time{reqDate}
+	var rule *scheduling.Recurrence
+	if req.Recurrence != nil {
+		rule, err = recurrenceRule(req.Recurrence)
+		if err != nil {
+			return nil, err
+		}
+		if dates, err = rule.Dates(reqDate); err != nil {
+			return nil, err
+		}
+	}
+	last := dates[len(dates)-1]
+
+	// A subscribed student books within the period's sessions
+	if err := checkSubscription(ctx, s.db.Pool, studentID, tuid, dates, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	// A single booking fails on its first problem; a weekly one is
+	// checked through and refused with every date in the way.
+	reasons := make(map[string]string)
+	refuse := func(date time.Time, reason string) {
+		key := date.Format("2006-01-02")
+		if _, ok := reasons[key]; !ok {
+			reasons[key] = reason // the first problem found is enough
+		}
+	}
+
+	// Check teacher availability for this day/time: weekly slots with
+	// the teacher's exceptions and observed holidays applied
+	days, err := scheduling.Availability(ctx, s.db.Pool, tuid, reqDate, last)
+	if err != nil {
+		return nil, err
+	}
+	byDate := make(map[string]scheduling.Day, len(days))
+	for _, day := range days {
+		byDate[day.Date] = day
+	}
+	for _, date := range dates {
+		reason := unavailableReason(byDate[date.Format("2006-01-02")], date, req.StartTime, req.EndTime)
+		if reason == "" {
+			continue
 		}
-		if len(slots) == 0 {
-			dayNames := []string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"}
-			return nil, fmt.Errorf("%w: L'enseignant n'a aucune disponibilité le %s.", ErrSlotNotAvailable, dayNames[dayOfWeek])
+		if rule == nil {
+			return nil, fmt.Errorf("%w: %s", ErrSlotNotAvailable, reason)
 		}
-		return nil, fmt.Errorf("%w: Ce créneau n'est pas dans les disponibilités de l'enseignant. Créneaux disponibles ce jour : %s",
-			ErrSlotNotAvailable, strings.Join(slots, ", "))
+		refuse(date, reason)
 	}
 
 	// Check no conflicting accepted booking exists
 	// For individual bookings: block if ANY accepted booking overlaps
 	// For group bookings: only block if an accepted INDIVIDUAL booking overlaps
 	//   (group bookings at the same time will be auto-merged when teacher accepts)
-	var conflict bool
 	conflictSessionTypeFilter := ""
 	if req.SessionType == "group" {
 		conflictSessionTypeFilter = "AND session_type = 'individual'"
 	}
-	err = s.db.Pool.QueryRow(ctx,
-		`SELECT EXISTS(
-			SELECT 1 FROM booking_requests
+	rows, err := s.db.Pool.Query(ctx,
+		`SELECT DISTINCT requested_date FROM booking_requests
 			WHERE teacher_id = $1
-			AND requested_date = $2
+			AND requested_date = ANY($2::date[])
 			AND status = 'accepted'
 			`+conflictSessionTypeFilter+`
 			AND (
 				(start_time <= $3::time AND end_time > $3::time) OR
 				(start_time < $4::time AND end_time >= $4::time) OR
 				(start_time >= $3::time AND end_time <= $4::time)
-			)
-		)`,
-		tuid, reqDate, req.StartTime, req.EndTime,
-	).Scan(&conflict)
+			)`,
+		tuid, dates, req.StartTime, req.EndTime,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("check conflicts: %w", err)
 	}
-	if conflict {
+	booked := make(map[string]bool)
+	for rows.Next() {
+		var date time.Time
+		if err := rows.Scan(&date); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan conflict: %w", err)
+		}
+		booked[date.Format("2006-01-02")] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("check conflicts: %w", err)
+	}
+	if rule == nil && len(booked) > 0 {
 		return nil, ErrAlreadyBooked
 	}
 
@@ -180,19 +216,114 @@ func (s *Service) CreateBookingRequest(ctx context.Context, callerID string, cal
 		}
 	}
 
+	// The price is settled at payment, so only the code's rules are
+	// checked here.
+	var promoCode *string
+	if req.PromoCode != "" {
+		_, err = promotion.Check(ctx, s.db.Pool, promotion.Redemption{
+			Code:       req.PromoCode,
+			StudentID:  studentID,
+			TeacherID:  tuid,
+			OfferingID: offeringID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		code := strings.ToUpper(req.PromoCode)
+		promoCode = &code
+	}
+
+	// The times are read in the teacher's zone, now and on acceptance
+	loc, err := scheduling.UserZone(ctx, s.db.Pool, tuid)
+	if err != nil {
+		return nil, err
+	}
+	for _, date := range dates {
+		startsAt, err := scheduling.At(date, req.StartTime, loc)
+		if err != nil {
+			return nil, err
+		}
+		endsAt, err := scheduling.At(date, req.EndTime, loc)
+		if err != nil {
+			return nil, err
+		}
+		if !endsAt.After(startsAt) {
+			return nil, fmt.Errorf("end_time must be after start_time")
+		}
+
+		if booked[date.Format("2006-01-02")] {
+			refuse(date, "Ce créneau est déjà réservé.")
+			continue
+		}
+
+		// The teacher's calendar must be free too. A group request may only
+		// meet the group session it would be merged into on acceptance.
+		busy, err := scheduling.Conflicts(ctx, s.db.Pool, tuid, startsAt, endsAt)
+		if err != nil {
+			return nil, err
+		}
+		for _, sess := range busy {
+			if rule != nil {
+				// Accepting a weekly booking creates its own series
+				refuse(date, "Ce créneau est déjà réservé.")
+				break
+			}
+			if req.SessionType != "group" || !joinable(sess, offeringID, startsAt, endsAt) {
+				return nil, ErrAlreadyBooked
+			}
+		}
+	}
+	if len(reasons) > 0 {
+		conflicts := make([]OccurrenceConflict, 0, len(reasons))
+		for _, date := range dates {
+			if reason, ok := reasons[date.Format("2006-01-02")]; ok {
+				conflicts = append(conflicts, OccurrenceConflict{Date: date.Format("2006-01-02"), Reason: reason})
+			}
+		}
+		return nil, &RecurrenceConflictError{Conflicts: conflicts}
+	}
+
+	var recurrenceDays []int16
+	var recurrenceUntil *time.Time
+	var recurrenceCount *int
+	if rule != nil {
+		for _, d := range rule.Days {
+			recurrenceDays = append(recurrenceDays, int16(d))
+		}
+		if !rule.Until.IsZero() {
+			recurrenceUntil = &rule.Until
+		}
+		if rule.Count > 0 {
+			recurrenceCount = &rule.Count
+		}
+	}
+
 	bookingID := uuid.New()
 	_, err = s.db.Pool.Exec(ctx,
 		`INSERT INTO booking_requests 
-			(id, student_id, teacher_id, offering_id, session_type, requested_date, start_time, end_time, message, purpose, status, booked_by_parent_id)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7::time, $8::time, $9, $10, 'pending', $11)`,
+			(id, student_id, teacher_id, offering_id, session_type, requested_date, start_time, end_time, message, purpose, status, booked_by_parent_id, promo_code, timezone,
+			 recurrence_days, recurrence_until, recurrence_count)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7::time, $8::time, $9, $10, 'pending', $11, $12, $13, $14, $15, $16)`,
 		bookingID, studentID, tuid, offeringID, req.SessionType,
 		reqDate, req.StartTime, req.EndTime,
-		req.Message, req.Purpose, bookedByParentID,
+		req.Message, req.Purpose, bookedByParentID, promoCode, loc.String(),
+		recurrenceDays, recurrenceUntil, recurrenceCount,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("insert booking: %w", err)
 	}
 
+	s.events.Emit(ctx, events.BookingRequested{
+		BookingID:   bookingID,
+		TeacherID:   tuid,
+		StudentID:   studentID,
+		SessionType: req.SessionType,
+		Date:        req.RequestedDate,
+		StartTime:   req.StartTime,
+		EndTime:     req.EndTime,
+		Occurrences: len(dates),
+	})
+
 	return s.GetBookingRequest(ctx, bookingID.String(), callerID)
 }
 
@@ -210,6 +341,9 @@ func (s *Service) GetBookingRequest(ctx context.Context, bookingID, callerID str
 	var subjectName, levelName, declineReason *string
 	var bookedByParentID *string
 	var bookedByParentName *string
+	var recurrenceDays []int16
+	var recurrenceUntil *time.Time
+	var recurrenceCount *int
 
 	err = s.db.Pool.QueryRow(ctx,
 		`SELECT br.id, br.student_id, us.first_name || ' ' || us.last_name,
@@ -220,7 +354,9 @@ func (s *Service) GetBookingRequest(ctx context.Context, bookingID, callerID str
 		        br.decline_reason, br.session_id::text, br.series_id::text,
 		        br.created_at, br.updated_at,
 		        br.booked_by_parent_id::text,
-		        (SELECT first_name || ' ' || last_name FROM users WHERE id = br.booked_by_parent_id)
+		        (SELECT first_name || ' ' || last_name FROM users WHERE id = br.booked_by_parent_id),
+		        br.package_purchase_id::text, br.promo_code, br.timezone,
+		        br.recurrence_days, br.recurrence_until, br.recurrence_count
 		 FROM booking_requests br
 		 JOIN users us ON us.id = br.student_id
 		 JOIN users ut ON ut.id = br.teacher_id
@@ -237,6 +373,8 @@ func (s *Service) GetBookingRequest(ctx context.Context, bookingID, callerID str
 		&declineReason, &sessionID, &seriesID,
 		&createdAt, &updatedAt,
 		&bookedByParentID, &bookedByParentName,
+		&br.PackagePurchaseID, &br.PromoCode, &br.Timezone,
+		&recurrenceDays, &recurrenceUntil, &recurrenceCount,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -264,6 +402,8 @@ func (s *Service) GetBookingRequest(ctx context.Context, bookingID, callerID str
 	br.RequestedDate = requestedDate.Format("2006-01-02")
 	br.StartTime = startTime.Format("15:04")
 	br.EndTime = endTime.Format("15:04")
+	br.setInstants(requestedDate)
+	br.setRecurrence(requestedDate, scheduling.StoredRecurrence(recurrenceDays, recurrenceUntil, recurrenceCount))
 	br.CreatedAt = createdAt
 	br.UpdatedAt = updatedAt
 
@@ -306,7 +446,9 @@ func (s *Service) ListBookingRequests(ctx context.Context, userID string, q List
 		       br.decline_reason, br.session_id::text, br.series_id::text,
 		       br.created_at, br.updated_at,
 		       br.booked_by_parent_id::text,
-		       (SELECT first_name || ' ' || last_name FROM users WHERE id = br.booked_by_parent_id)
+		       (SELECT first_name || ' ' || last_name FROM users WHERE id = br.booked_by_parent_id),
+		       br.package_purchase_id::text, br.promo_code, br.timezone,
+		       br.recurrence_days, br.recurrence_until, br.recurrence_count
 		FROM booking_requests br
 		JOIN users us ON us.id = br.student_id
 		JOIN users ut ON ut.id = br.teacher_id
@@ -340,6 +482,9 @@ func (s *Service) ListBookingRequests(ctx context.Context, userID string, q List
 		var offeringID, sessionID, seriesID, declineReason *string
 		var subjectName, levelName *string
 		var bookedByParentID, bookedByParentName *string
+		var recurrenceDays []int16
+		var recurrenceUntil *time.Time
+		var recurrenceCount *int
 
 		err := rows.Scan(
 			&br.ID, &br.StudentID, &br.StudentName,
@@ -350,6 +495,8 @@ func (s *Service) ListBookingRequests(ctx context.Context, userID string, q List
 			&declineReason, &sessionID, &seriesID,
 			&createdAt, &updatedAt,
 			&bookedByParentID, &bookedByParentName,
+			&br.PackagePurchaseID, &br.PromoCode, &br.Timezone,
+			&recurrenceDays, &recurrenceUntil, &recurrenceCount,
 		)
 		if err != nil {
 			continue
@@ -374,6 +521,8 @@ func (s *Service) ListBookingRequests(ctx context.Context, userID string, q List
 		br.RequestedDate = requestedDate.Format("2006-01-02")
 		br.StartTime = startTime.Format("15:04")
 		br.EndTime = endTime.Format("15:04")
+		br.setInstants(requestedDate)
+		br.setRecurrence(requestedDate, scheduling.StoredRecurrence(recurrenceDays, recurrenceUntil, recurrenceCount))
 		br.CreatedAt = createdAt
 		br.UpdatedAt = updatedAt
 
@@ -404,12 +553,18 @@ func (s *Service) AcceptBookingRequest(ctx context.Context, bookingID, teacherID
 	var startTime, endTime string
 	var sessionType string
 	var offeringID *uuid.UUID
+	var zone string
+	var recurrenceDays []int16
+	var recurrenceUntil *time.Time
+	var recurrenceCount *int
 
 	err = s.db.Pool.QueryRow(ctx,
 		`SELECT teacher_id, status, student_id, requested_date, 
-		        start_time::text, end_time::text, session_type, offering_id
+		        start_time::text, end_time::text, session_type, offering_id, timezone,
+		        recurrence_days, recurrence_until, recurrence_count
 		 FROM booking_requests WHERE id = $1`, bid,
-	).Scan(&ownerID, &status, &studentID, &requestedDate, &startTime, &endTime, &sessionType, &offeringID)
+	).Scan(&ownerID, &status, &studentID, &requestedDate, &startTime, &endTime, &sessionType, &offeringID, &zone,
+		&recurrenceDays, &recurrenceUntil, &recurrenceCount)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrBookingNotFound
@@ -430,11 +585,19 @@ func (s *Service) AcceptBookingRequest(ctx context.Context, bookingID, teacherID
 		sessionTypeForDB = "one_on_one"
 	}
 
-	// Parse times
-	startDT := requestedDate.Format("2006-01-02") + "T" + startTime[:5] + ":00Z"
-	endDT := requestedDate.Format("2006-01-02") + "T" + endTime[:5] + ":00Z"
-	startParsed, _ := time.Parse(time.RFC3339, startDT)
-	endParsed, _ := time.Parse(time.RFC3339, endDT)
+	// The requested times are the teacher's wall clock when booked
+	loc, err := scheduling.LoadZone(zone)
+	if err != nil {
+		return nil, err
+	}
+	startParsed, err := scheduling.At(requestedDate, startTime, loc)
+	if err != nil {
+		return nil, err
+	}
+	endParsed, err := scheduling.At(requestedDate, endTime, loc)
+	if err != nil {
+		return nil, err
+	}
 
 	durationHours := endParsed.Sub(startParsed).Hours()
 	if durationHours < 1.0 {
@@ -444,9 +607,36 @@ func (s *Service) AcceptBookingRequest(ctx context.Context, bookingID, teacherID
 		durationHours = 4.0
 	}
 
+	// A weekly booking becomes a series of its own, one session per date
+	rule := scheduling.StoredRecurrence(recurrenceDays, recurrenceUntil, recurrenceCount)
+	dates := []time.Time{requestedDate}
+	var occurrences []sessionseries.NewSession
+	if rule != nil {
+		if req.ExistingSeriesID != "" {
+			return nil, fmt.Errorf("%w: a recurring booking creates its own series", scheduling.ErrInvalidRecurrence)
+		}
+		if dates, err = rule.Dates(requestedDate); err != nil {
+			return nil, err
+		}
+		for _, date := range dates {
+			start, err := scheduling.At(date, startTime, loc)
+			if err != nil {
+				return nil, err
+			}
+			end, err := scheduling.At(date, endTime, loc)
+			if err != nil {
+				return nil, err
+			}
+			occurrences = append(occurrences, sessionseries.NewSession{Start: start, End: end})
+		}
+	}
+
 	title := req.Title
 	if title == "" {
 		title = "Séance du " + requestedDate.Format("02/01/2006")
+		if rule != nil {
+			title = "Séances à partir du " + requestedDate.Format("02/01/2006")
+		}
 	}
 
 	maxStudents := 1
@@ -460,10 +650,44 @@ func (s *Service) AcceptBookingRequest(ctx context.Context, bookingID, teacherID
 	}
 	defer tx.Rollback(ctx)
 
+	// Checked again under the subscription's lock: sessions booked since
+	// the request count against the period too.
+	if err := checkSubscription(ctx, tx, studentID, tid, dates, bid); err != nil {
+		return nil, err
+	}
+
 	var seriesID uuid.UUID
 	var sessionID uuid.UUID
+	var sessionIDs []uuid.UUID
+
+	if rule != nil {
+		// ── Weekly booking: new series, sessions added as AddSessions does ──
+		seriesID = uuid.New()
+		now := time.Now()
+
+		_, err = tx.Exec(ctx,
+			`INSERT INTO session_series (id, teacher_id, offering_id, title, description, session_type, duration_hours, min_students, max_students, price_per_hour, status, created_at, updated_at)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, 'active', $11, $11)`,
+			seriesID, tid, offeringID, title, req.Description, sessionTypeForDB,
+			durationHours, 1, maxStudents, req.Price, now,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("create series: %w", err)
+		}
 
-	if req.ExistingSeriesID != "" {
+		sessionIDs, err = sessionseries.InsertSessions(ctx, tx, seriesID, occurrences, req.Price)
+		var conflict *scheduling.ConflictError
+		switch {
+		case errors.As(err, &conflict):
+			return nil, overlapError(conflict.Session, loc)
+		case errors.Is(err, scheduling.ErrConflict):
+			return nil, fmt.Errorf("%w: Ce créneau chevauche une autre de vos séances.", ErrTimeConflict)
+		case err != nil:
+			return nil, fmt.Errorf("create sessions: %w", err)
+		}
+		sessionID = sessionIDs[0]
+
+	} else if req.ExistingSeriesID != "" {
 		// ── Add to existing series ────────────────────────────────
 		existingSID, err := uuid.Parse(req.ExistingSeriesID)
 		if err != nil {
@@ -500,6 +724,15 @@ func (s *Service) AcceptBookingRequest(ctx context.Context, bookingID, teacherID
 
 		seriesID = existingSID
 
+		err = scheduling.CheckFree(ctx, tx, tid, startParsed, endParsed)
+		var conflict *scheduling.ConflictError
+		if errors.As(err, &conflict) {
+			return nil, overlapError(conflict.Session, loc)
+		}
+		if err != nil {
+			return nil, err
+		}
+
 		// Create a new session within this existing series
 		sessionID = uuid.New()
 		var nextSessionNum int
@@ -510,11 +743,25 @@ func (s *Service) AcceptBookingRequest(ctx context.Context, bookingID, teacherID
 			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, 'scheduled')`,
 			sessionID, tid, offeringID, seriesID, nextSessionNum, title, req.Description, sessionTypeForDB, startParsed, endParsed, seriesMaxStudents, req.Price,
 		)
+		if scheduling.IsOverlapViolation(err) {
+			return nil, fmt.Errorf("%w: Ce créneau chevauche une autre de vos séances.", ErrTimeConflict)
+		}
 		if err != nil {
 			return nil, fmt.Errorf("create session in existing series: %w", err)
 		}
 
 	} else {
+		// ── Any overlap other than a session at these exact times is a conflict ──
+		busy, err := scheduling.Conflicts(ctx, tx, tid, startParsed, endParsed)
+		if err != nil {
+			return nil, err
+		}
+		for _, sess := range busy {
+			if !sess.StartTime.Equal(startParsed) || !sess.EndTime.Equal(endParsed) {
+				return nil, overlapError(sess, loc)
+			}
+		}
+
 		// ── Check if teacher already has a session at this exact time ──
 		var existingSessionID, existingSeriesID uuid.UUID
 		var existingSessionType string
@@ -584,6 +831,9 @@ func (s *Service) AcceptBookingRequest(ctx context.Context, bookingID, teacherID
 				 VALUES ($1, $2, $3, $4, 1, $5, $6, $7, $8, $9, $10, $11, 'scheduled')`,
 				sessionID, tid, offeringID, seriesID, title, req.Description, sessionTypeForDB, startParsed, endParsed, maxStudents, req.Price,
 			)
+			if scheduling.IsOverlapViolation(err) {
+				return nil, fmt.Errorf("%w: Ce créneau chevauche une autre de vos séances.", ErrTimeConflict)
+			}
 			if err != nil {
 				return nil, fmt.Errorf("create session: %w", err)
 			}
@@ -603,26 +853,71 @@ func (s *Service) AcceptBookingRequest(ctx context.Context, bookingID, teacherID
 		return nil, fmt.Errorf("auto-enroll student: %w", err)
 	}
 
-	// ── Also add as session participant for this specific session ──
-	_, err = tx.Exec(ctx,
-		`INSERT INTO session_participants (session_id, student_id)
-		 VALUES ($1, $2)
-		 ON CONFLICT DO NOTHING`,
-		sessionID, studentID,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("add participant: %w", err)
+	// ── Also add as session participant for the booked session(s) ──
+	if sessionIDs == nil {
+		sessionIDs = []uuid.UUID{sessionID}
+	}
+	for _, id := range sessionIDs {
+		_, err = tx.Exec(ctx,
+			`INSERT INTO session_participants (session_id, student_id)
+			 VALUES ($1, $2)
+			 ON CONFLICT DO NOTHING`,
+			id, studentID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("add participant: %w", err)
+		}
+	}
+
+	// ── Use a session package if the student holds one with this teacher ──
+	// The oldest active purchase goes first; a package for another offering
+	// does not cover this booking, nor one with fewer sessions left than
+	// the booking has.
+	var packagePurchaseID *uuid.UUID
+	err = tx.QueryRow(ctx,
+		`UPDATE package_purchases SET sessions_remaining = sessions_remaining - $4
+		 WHERE sessions_remaining >= $4 AND id = (
+			SELECT pp.id FROM package_purchases pp
+			JOIN packages p ON p.id = pp.package_id
+			WHERE pp.student_id = $1 AND p.teacher_id = $2
+			  AND pp.status = 'active' AND pp.sessions_remaining >= $4 AND pp.expires_at > NOW()
+			  AND ($3::uuid IS NULL OR p.offering_id IS NULL OR p.offering_id = $3)
+			ORDER BY pp.created_at
+			LIMIT 1
+			FOR UPDATE OF pp
+		 )
+		 RETURNING id`,
+		studentID, tid, offeringID, len(sessionIDs),
+	).Scan(&packagePurchaseID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("use package session: %w", err)
 	}
 
 	// ── Update booking status ──
 	_, err = tx.Exec(ctx,
-		`UPDATE booking_requests SET status = 'accepted', session_id = $1, series_id = $2, updated_at = NOW() WHERE id = $3`,
-		sessionID, seriesID, bid,
+		`UPDATE booking_requests SET status = 'accepted', session_id = $1, series_id = $2, package_purchase_id = $3, updated_at = NOW() WHERE id = $4`,
+		sessionID, seriesID, packagePurchaseID, bid,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("update booking: %w", err)
 	}
 
+	// ── Record the event in the outbox (published by the relay after commit) ──
+	err = events.Enqueue(ctx, tx, events.BookingAccepted{
+		BookingID:   bid,
+		TeacherID:   tid,
+		StudentID:   studentID,
+		SeriesID:    seriesID,
+		SessionID:   sessionID,
+		StartTime:   startParsed,
+		EndTime:     endParsed,
+		Price:       req.Price,
+		Occurrences: len(sessionIDs),
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return nil, fmt.Errorf("commit: %w", err)
 	}
@@ -639,11 +934,11 @@ func (s *Service) DeclineBookingRequest(ctx context.Context, bookingID, teacherI
 	tid, _ := uuid.Parse(teacherID)
 
 	// Verify ownership and status
-	var ownerID uuid.UUID
+	var ownerID, studentID uuid.UUID
 	var status string
 	err = s.db.Pool.QueryRow(ctx,
-		`SELECT teacher_id, status FROM booking_requests WHERE id = $1`, bid,
-	).Scan(&ownerID, &status)
+		`SELECT teacher_id, student_id, status FROM booking_requests WHERE id = $1`, bid,
+	).Scan(&ownerID, &studentID, &status)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrBookingNotFound
@@ -666,6 +961,13 @@ func (s *Service) DeclineBookingRequest(ctx context.Context, bookingID, teacherI
 		return nil, fmt.Errorf("decline booking: %w", err)
 	}
 
+	s.events.Emit(ctx, events.BookingDeclined{
+		BookingID: bid,
+		TeacherID: tid,
+		StudentID: studentID,
+		Reason:    req.Reason,
+	})
+
 	return s.GetBookingRequest(ctx, bookingID, teacherID)
 }
 
@@ -690,6 +992,8 @@ func (s *Service) CancelBookingRequest(ctx context.Context, bookingID, studentID
 		return ErrBookingNotFound
 	}
 
+	s.events.Emit(ctx, events.BookingCancelled{BookingID: bid, CancelledBy: sid})
+
 	return nil
 }
 
@@ -878,3 +1182,217 @@ func (s *Service) ListMessages(ctx context.Context, bookingID, callerID string,
 
 	return messages, nil
 }
+
+// setInstants fills StartsAt and EndsAt from the wall-clock fields and
+// Timezone. An unknown zone leaves them unset.
+func (br *BookingRequestResponse) setInstants(requestedDate time.Time) {
+	loc, err := scheduling.LoadZone(br.Timezone)
+	if err != nil {
+		return
+	}
+	if t, err := scheduling.At(requestedDate, br.StartTime, loc); err == nil {
+		br.StartsAt = &t
+	}
+	if t, err := scheduling.At(requestedDate, br.EndTime, loc); err == nil {
+		br.EndsAt = &t
+	}
+}
+
+// querier is satisfied by both the pool and a transaction.
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// checkSubscription fails with ErrSubscriptionExhausted when booking
+// dates would take a subscribed student past sessions_per_month in a
+// period. A period's sessions are those used so far, the scheduled and
+// live sessions the student is in (accepted bookings among them) and
+// the dates of pending bookings, leaving out the booking exclude. In a
+// transaction the subscriptions stay locked until it ends.
+func checkSubscription(ctx context.Context, q querier, studentID, teacherID uuid.UUID, dates []time.Time, exclude uuid.UUID) error {
+	first, last := dates[0], dates[len(dates)-1]
+
+	type period struct {
+		id               uuid.UUID
+		start, end       time.Time
+		committed, quota int
+	}
+	var periods []period
+	rows, err := q.Query(ctx,
+		`SELECT id, start_date, end_date, sessions_used, sessions_per_month FROM subscriptions
+		 WHERE student_id = $1 AND teacher_id = $2 AND status = 'active'
+		   AND start_date <= $4 AND end_date >= $3
+		 ORDER BY start_date
+		 FOR UPDATE`,
+		studentID, teacherID, first, last,
+	)
+	if err != nil {
+		return fmt.Errorf("check subscription: %w", err)
+	}
+	for rows.Next() {
+		var p period
+		if err := rows.Scan(&p.id, &p.start, &p.end, &p.committed, &p.quota); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan subscription: %w", err)
+		}
+		periods = append(periods, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("check subscription: %w", err)
+	}
+	if len(periods) == 0 {
+		return nil // not subscribed: paid per session
+	}
+
+	// Sessions still to come; those completed are in sessions_used
+	rows, err = q.Query(ctx,
+		`SELECT (s.start_time AT TIME ZONE 'Africa/Algiers')::date
+		 FROM sessions s
+		 JOIN session_participants sp ON sp.session_id = s.id
+		 WHERE sp.student_id = $1 AND s.teacher_id = $2 AND s.status IN ('scheduled', 'live')
+		   AND s.start_time >= $3::date - 1 AND s.start_time < $4::date + 2`,
+		studentID, teacherID, periods[0].start, periods[len(periods)-1].end,
+	)
+	if err != nil {
+		return fmt.Errorf("count scheduled sessions: %w", err)
+	}
+	var taken []time.Time
+	for rows.Next() {
+		var day time.Time
+		if err := rows.Scan(&day); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan session: %w", err)
+		}
+		taken = append(taken, day)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("count scheduled sessions: %w", err)
+	}
+
+	rows, err = q.Query(ctx,
+		`SELECT requested_date, recurrence_days, recurrence_until, recurrence_count
+		 FROM booking_requests
+		 WHERE student_id = $1 AND teacher_id = $2 AND status = 'pending' AND id <> $3
+		   AND requested_date <= $4`,
+		studentID, teacherID, exclude, periods[len(periods)-1].end,
+	)
+	if err != nil {
+		return fmt.Errorf("count pending bookings: %w", err)
+	}
+	for rows.Next() {
+		var date time.Time
+		var days []int16
+		var until *time.Time
+		var count *int
+		if err := rows.Scan(&date, &days, &until, &count); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan pending booking: %w", err)
+		}
+		bookingDates := []time.Time{date}
+		if rule := scheduling.StoredRecurrence(days, until, count); rule != nil {
+			if bookingDates, err = rule.Dates(date); err != nil {
+				continue
+			}
+		}
+		taken = append(taken, bookingDates...)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("count pending bookings: %w", err)
+	}
+
+	within := func(p period, d time.Time) bool { return !d.Before(p.start) && !d.After(p.end) }
+	for _, p := range periods {
+		wanted := 0
+		for _, d := range taken {
+			if within(p, d) {
+				p.committed++
+			}
+		}
+		for _, d := range dates {
+			if within(p, d) {
+				wanted++
+			}
+		}
+		if wanted > 0 && p.committed+wanted > p.quota {
+			return fmt.Errorf("%w: %d of %d sessions taken from %s to %s, %d more asked",
+				ErrSubscriptionExhausted, p.committed, p.quota,
+				p.start.Format("2006-01-02"), p.end.Format("2006-01-02"), wanted)
+		}
+	}
+	return nil
+}
+
+// recurrenceRule reads the weekly rule of a booking request.
+func recurrenceRule(in *RecurrenceInput) (*scheduling.Recurrence, error) {
+	rule := &scheduling.Recurrence{Count: in.Count}
+	for _, d := range in.Days {
+		rule.Days = append(rule.Days, time.Weekday(d))
+	}
+	if in.Until != "" {
+		until, err := time.Parse("2006-01-02", in.Until)
+		if err != nil {
+			return nil, fmt.Errorf("%w: until must be YYYY-MM-DD", scheduling.ErrInvalidRecurrence)
+		}
+		rule.Until = until
+	}
+	return rule, nil
+}
+
+// unavailableReason explains, in French, why [start, end) on date is
+// outside the teacher's availability, or returns "" when it is within.
+func unavailableReason(day scheduling.Day, date time.Time, start, end string) string {
+	if day.Covers(start, end) {
+		return ""
+	}
+	dayNames := []string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"}
+	dayName := dayNames[date.Weekday()] + " " + date.Format("02/01/2006")
+	var slots []string
+	for _, w := range day.Windows {
+		slots = append(slots, w.Start+"-"+w.End)
+	}
+	switch {
+	case len(slots) == 0 && day.Holiday != "":
+		return fmt.Sprintf("L'enseignant ne travaille pas le %s (%s).", dayName, day.Holiday)
+	case len(slots) == 0:
+		return fmt.Sprintf("L'enseignant n'a aucune disponibilité le %s.", dayName)
+	}
+	return "Ce créneau n'est pas dans les disponibilités de l'enseignant. Créneaux disponibles ce jour : " + strings.Join(slots, ", ")
+}
+
+// setRecurrence fills Recurrence and Occurrences for a weekly booking.
+func (br *BookingRequestResponse) setRecurrence(requestedDate time.Time, rule *scheduling.Recurrence) {
+	if rule == nil {
+		return
+	}
+	in := &RecurrenceInput{Count: rule.Count}
+	for _, d := range rule.Days {
+		in.Days = append(in.Days, int(d))
+	}
+	if !rule.Until.IsZero() {
+		in.Until = rule.Until.Format("2006-01-02")
+	}
+	br.Recurrence = in
+	dates, _ := rule.Dates(requestedDate)
+	for _, date := range dates {
+		br.Occurrences = append(br.Occurrences, date.Format("2006-01-02"))
+	}
+}
+
+// joinable reports whether a group request for offeringID at [start, end)
+// would be merged into sess on acceptance.
+func joinable(sess scheduling.Session, offeringID *uuid.UUID, start, end time.Time) bool {
+	sameOffering := (offeringID == nil && sess.OfferingID == nil) ||
+		(offeringID != nil && sess.OfferingID != nil && *offeringID == *sess.OfferingID)
+	return sess.SessionType == "group" && sameOffering &&
+		sess.StartTime.Equal(start) && sess.EndTime.Equal(end)
+}
+
+// overlapError tells the teacher which session is in the way, in their zone.
+func overlapError(sess scheduling.Session, loc *time.Location) error {
+	return fmt.Errorf("%w: Vous avez déjà « %s » de %s à %s le %s, qui chevauche ce créneau.", ErrTimeConflict,
+		sess.Title, sess.StartTime.In(loc).Format("15:04"), sess.EndTime.In(loc).Format("15:04"),
+		sess.StartTime.In(loc).Format("02/01/2006"))
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -18,6 +19,11 @@ type Config struct {
 	LiveKit     LiveKitConfig
 	JWT         JWTConfig
 	SMS         SMSConfig
+	SMTP        SMTPConfig
+	Payment     PaymentConfig
+	Wallet      WalletConfig
+	Invoice     InvoiceConfig
+	Worker      WorkerConfig
 	Platform    PlatformConfig
 }
 
@@ -80,6 +86,9 @@ type LiveKitConfig struct {
 	Host      string
 	APIKey    string
 	APISecret string
+	// EgressS3Endpoint is the MinIO URL as reached from the LiveKit Egress
+	// service, which usually runs in another container than the API.
+	EgressS3Endpoint string
 }
 
 type JWTConfig struct {
@@ -89,12 +98,67 @@ type JWTConfig struct {
 }
 
 type SMSConfig struct {
-	Provider      string
+	Provider      string // icosnet, twilio, file or console
+	ICOSNETURL    string
 	ICOSNETKey    string
 	ICOSNETSecret string
+	ICOSNETSender string
 	TwilioSID     string
 	TwilioToken   string
 	TwilioFrom    string
+	FilePath      string // development sink (provider "file")
+}
+
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// DefaultCallbackSecret is the development PAYMENT_CALLBACK_SECRET;
+// anyone can sign callbacks with it.
+const DefaultCallbackSecret = "payment_callback_secret_change_in_production"
+
+type PaymentConfig struct {
+	Gateway        string // live (SATIM + BaridiMob) or fake
+	CallbackSecret string // signs the callback URLs handed to SATIM and the fake gateway
+	ReturnURL      string // app page the payer lands on after checkout
+
+	SATIMURL        string
+	SATIMUsername   string
+	SATIMPassword   string
+	SATIMTerminalID string
+
+	BaridiMobURL           string
+	BaridiMobMerchantID    string
+	BaridiMobAPIKey        string
+	BaridiMobWebhookSecret string
+}
+
+type WalletConfig struct {
+	LowBalanceThresholds []float64 // DZD balances below which teachers are alerted
+
+	// Star refunds when a student leaves a series (see wallet.RefundPolicy)
+	RefundGraceWindow time.Duration // full refund within this long of acceptance
+	RefundProRated    bool          // otherwise refund the share of sessions not yet started
+	RefundMinimum     float64       // DZD; smaller refunds are not issued
+}
+
+// InvoiceConfig identifies the platform on the invoices it issues.
+type InvoiceConfig struct {
+	FontDir       string // directory holding DejaVuSans.ttf and DejaVuSans-Bold.ttf
+	IssuerName    string
+	IssuerAddress string
+	IssuerNIF     string // numéro d'identification fiscale
+	IssuerRC      string // registre du commerce
+}
+
+type WorkerConfig struct {
+	Timezone     string        // IANA zone cron schedules are evaluated in
+	LeaderTTL    time.Duration // leader lease duration in Redis
+	ReminderLead time.Duration // how long before start_time reminders go out
 }
 
 type PlatformConfig struct {
@@ -144,9 +208,10 @@ func Load() (*Config, error) {
 			MasterKey: getEnv("MEILI_MASTER_KEY", "educonnect_meili_key"),
 		},
 		LiveKit: LiveKitConfig{
-			Host:      getEnv("LIVEKIT_HOST", "http://localhost:7880"),
-			APIKey:    getEnv("LIVEKIT_API_KEY", "devkey"),
-			APISecret: getEnv("LIVEKIT_API_SECRET", "secret_that_is_at_least_32_characters_long"),
+			Host:             getEnv("LIVEKIT_HOST", "http://localhost:7880"),
+			APIKey:           getEnv("LIVEKIT_API_KEY", "devkey"),
+			APISecret:        getEnv("LIVEKIT_API_SECRET", "secret_that_is_at_least_32_characters_long"),
+			EgressS3Endpoint: getEnv("LIVEKIT_EGRESS_S3_ENDPOINT", "http://minio:9000"),
 		},
 		JWT: JWTConfig{
 			Secret:        getEnv("JWT_SECRET", "your_jwt_secret_key_change_in_production"),
@@ -155,11 +220,52 @@ func Load() (*Config, error) {
 		},
 		SMS: SMSConfig{
 			Provider:      getEnv("SMS_PROVIDER", "icosnet"),
+			ICOSNETURL:    getEnv("ICOSNET_API_URL", "https://api.icosnet.com/sms/v1/send"),
 			ICOSNETKey:    getEnv("ICOSNET_API_KEY", ""),
 			ICOSNETSecret: getEnv("ICOSNET_API_SECRET", ""),
+			ICOSNETSender: getEnv("ICOSNET_SENDER_ID", "EduConnect"),
 			TwilioSID:     getEnv("TWILIO_ACCOUNT_SID", ""),
 			TwilioToken:   getEnv("TWILIO_AUTH_TOKEN", ""),
 			TwilioFrom:    getEnv("TWILIO_FROM_NUMBER", ""),
+			FilePath:      getEnv("SMS_FILE_PATH", "./tmp/sms.log"),
+		},
+		SMTP: SMTPConfig{
+			Host:     getEnv("SMTP_HOST", ""),
+			Port:     getEnv("SMTP_PORT", "587"),
+			Username: getEnv("SMTP_USERNAME", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+			From:     getEnv("SMTP_FROM", "EduConnect <no-reply@educonnect.dz>"),
+		},
+		Payment: PaymentConfig{
+			Gateway:                getEnv("PAYMENT_GATEWAY", "live"),
+			CallbackSecret:         getEnv("PAYMENT_CALLBACK_SECRET", DefaultCallbackSecret),
+			ReturnURL:              getEnv("PAYMENT_RETURN_URL", "http://localhost:8080/payments/result"),
+			SATIMURL:               getEnv("SATIM_API_URL", "https://test.satim.dz/payment/rest"),
+			SATIMUsername:          getEnv("SATIM_USERNAME", ""),
+			SATIMPassword:          getEnv("SATIM_PASSWORD", ""),
+			SATIMTerminalID:        getEnv("SATIM_TERMINAL_ID", ""),
+			BaridiMobURL:           getEnv("BARIDIMOB_API_URL", "https://api.baridimob.poste.dz/merchant"),
+			BaridiMobMerchantID:    getEnv("BARIDIMOB_MERCHANT_ID", ""),
+			BaridiMobAPIKey:        getEnv("BARIDIMOB_API_KEY", ""),
+			BaridiMobWebhookSecret: getEnv("BARIDIMOB_WEBHOOK_SECRET", ""),
+		},
+		Wallet: WalletConfig{
+			LowBalanceThresholds: getEnvFloats("WALLET_LOW_BALANCE_THRESHOLDS", []float64{300, 100}),
+			RefundGraceWindow:    getEnvDuration("WALLET_REFUND_GRACE_WINDOW", 24*time.Hour),
+			RefundProRated:       getEnvBool("WALLET_REFUND_PRORATED", true),
+			RefundMinimum:        getEnvFloat("WALLET_REFUND_MINIMUM", 5),
+		},
+		Invoice: InvoiceConfig{
+			FontDir:       getEnv("INVOICE_FONT_DIR", "/usr/share/fonts/dejavu"),
+			IssuerName:    getEnv("INVOICE_ISSUER_NAME", "EduConnect"),
+			IssuerAddress: getEnv("INVOICE_ISSUER_ADDRESS", "Alger, Algérie"),
+			IssuerNIF:     getEnv("INVOICE_ISSUER_NIF", ""),
+			IssuerRC:      getEnv("INVOICE_ISSUER_RC", ""),
+		},
+		Worker: WorkerConfig{
+			Timezone:     getEnv("WORKER_TIMEZONE", "Africa/Algiers"),
+			LeaderTTL:    getEnvDuration("WORKER_LEADER_TTL", 30*time.Second),
+			ReminderLead: getEnvDuration("WORKER_REMINDER_LEAD", time.Hour),
 		},
 		Platform: PlatformConfig{
 			CommissionRate:  getEnvFloat("PLATFORM_COMMISSION_RATE", 0.20),
@@ -167,9 +273,30 @@ func Load() (*Config, error) {
 		},
 	}
 
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
 	return cfg, nil
 }
 
+// Validate refuses, in production, the settings that are only safe in
+// development.
+func (c *Config) Validate() error {
+	if c.App.Env != "production" {
+		return nil
+	}
+	if c.SMS.Provider == "console" || c.SMS.Provider == "file" {
+		return fmt.Errorf("config: SMS_PROVIDER=%s writes login codes and reset links in plain text and is refused in production", c.SMS.Provider)
+	}
+	if c.Payment.Gateway == "fake" {
+		return fmt.Errorf("config: PAYMENT_GATEWAY=fake lets payers complete their own payments and is refused in production")
+	}
+	if c.Payment.CallbackSecret == DefaultCallbackSecret {
+		return fmt.Errorf("config: PAYMENT_CALLBACK_SECRET must be changed from its default in production")
+	}
+	return nil
+}
+
 // ─── Helpers ────────────────────────────────────────────────────
 
 func getEnv(key, fallback string) string {
@@ -206,6 +333,24 @@ func getEnvFloat(key string, fallback float64) float64 {
 	return fallback
 }
 
+// getEnvFloats parses a comma-separated list; an invalid entry falls
+// back to the default for the whole list.
+func getEnvFloats(key string, fallback []float64) []float64 {
+	val := os.Getenv(key)
+	if val == "" {
+		return fallback
+	}
+	var out []float64
+	for _, part := range strings.Split(val, ",") {
+		f, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return fallback
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
 func getEnvDuration(key string, fallback time.Duration) time.Duration {
 	if val := os.Getenv(key); val != "" {
 		if d, err := time.ParseDuration(val); err == nil {
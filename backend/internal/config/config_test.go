@@ -0,0 +1,62 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Defaults(t *testing.T) {
+	t.Setenv("APP_ENV", "development")
+	t.Setenv("PAYMENT_GATEWAY", "")
+	t.Setenv("SMS_PROVIDER", "")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "live", cfg.Payment.Gateway, "an unset gateway must not be the fake one")
+	assert.Equal(t, "icosnet", cfg.SMS.Provider, "an unset provider must not print codes")
+}
+
+func TestValidate_Production(t *testing.T) {
+	production := func() *Config {
+		return &Config{
+			App:     AppConfig{Env: "production"},
+			SMS:     SMSConfig{Provider: "icosnet"},
+			Payment: PaymentConfig{Gateway: "live", CallbackSecret: "a-real-secret"},
+		}
+	}
+
+	assert.NoError(t, production().Validate())
+
+	cfg := production()
+	cfg.Payment.Gateway = "fake"
+	assert.Error(t, cfg.Validate(), "fake gateway")
+
+	cfg = production()
+	cfg.Payment.CallbackSecret = DefaultCallbackSecret
+	assert.Error(t, cfg.Validate(), "default callback secret")
+
+	for _, provider := range []string{"console", "file"} {
+		cfg = production()
+		cfg.SMS.Provider = provider
+		assert.Error(t, cfg.Validate(), "SMS provider %s", provider)
+	}
+
+	cfg = production()
+	cfg.App.Env = "development"
+	cfg.Payment.Gateway = "fake"
+	cfg.Payment.CallbackSecret = DefaultCallbackSecret
+	cfg.SMS.Provider = "console"
+	assert.NoError(t, cfg.Validate(), "development keeps the fake gateway and the console")
+}
+
+func TestLoad_RefusesFakeGatewayInProduction(t *testing.T) {
+	t.Setenv("APP_ENV", "production")
+	t.Setenv("PAYMENT_GATEWAY", "fake")
+	t.Setenv("PAYMENT_CALLBACK_SECRET", "a-real-secret")
+	t.Setenv("SMS_PROVIDER", "icosnet")
+
+	_, err := Load()
+	assert.Error(t, err)
+}
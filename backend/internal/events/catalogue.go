@@ -0,0 +1,372 @@
+package events
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ─── Event Types ────────────────────────────────────────────────
+
+const (
+	TypeBookingRequested = "booking.requested"
+	TypeBookingAccepted  = "booking.accepted"
+	TypeBookingDeclined  = "booking.declined"
+	TypeBookingCancelled = "booking.cancelled"
+
+	TypeEnrollmentRequested      = "enrollment.requested"
+	TypeEnrollmentAccepted       = "enrollment.accepted"
+	TypeEnrollmentAwaitingCredit = "enrollment.awaiting_credit"
+	TypeEnrollmentRemoved        = "enrollment.removed"
+
+	TypeSessionStarted     = "session.started"
+	TypeSessionEnded       = "session.ended"
+	TypeSessionCancelled   = "session.cancelled"
+	TypeSessionRescheduled = "session.rescheduled"
+	TypeSessionNoShow      = "session.no_show"
+
+	TypeWalletPurchaseApproved = "wallet.purchase.approved"
+	TypeWalletPurchaseRejected = "wallet.purchase.rejected"
+	TypeWalletPurchaseExpired  = "wallet.purchase.expired"
+	TypeWalletLowBalance       = "wallet.low_balance"
+
+	TypePaymentCompleted = "payment.completed"
+	TypePaymentRefunded  = "payment.refunded"
+
+	TypeSubscriptionRenewed = "subscription.renewed"
+	TypeSubscriptionExpired = "subscription.expired"
+
+	TypePackageExpired = "package.expired"
+
+	TypePayoutRequested = "payout.requested"
+	TypePayoutUpdated   = "payout.updated"
+
+	TypeHomeworkSubmitted = "homework.submitted"
+	TypeHomeworkGraded    = "homework.graded"
+)
+
+// ─── Booking ────────────────────────────────────────────────────
+
+type BookingRequested struct {
+	BookingID   uuid.UUID `json:"booking_id"`
+	TeacherID   uuid.UUID `json:"teacher_id"`
+	StudentID   uuid.UUID `json:"student_id"`
+	SessionType string    `json:"session_type"`
+	Date        string    `json:"date"`       // YYYY-MM-DD
+	StartTime   string    `json:"start_time"` // HH:MM
+	EndTime     string    `json:"end_time"`   // HH:MM
+	// Occurrences counts the dates of a weekly booking, Date the first
+	Occurrences int `json:"occurrences,omitempty"`
+}
+
+func (BookingRequested) EventType() string { return TypeBookingRequested }
+func (BookingRequested) EventVersion() int { return 1 }
+
+type BookingAccepted struct {
+	BookingID uuid.UUID `json:"booking_id"`
+	TeacherID uuid.UUID `json:"teacher_id"`
+	StudentID uuid.UUID `json:"student_id"`
+	SeriesID  uuid.UUID `json:"series_id"`
+	SessionID uuid.UUID `json:"session_id"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Price     float64   `json:"price"`
+	// Occurrences counts the sessions of a weekly booking; SessionID,
+	// StartTime and EndTime are the first one's.
+	Occurrences int `json:"occurrences,omitempty"`
+}
+
+func (BookingAccepted) EventType() string { return TypeBookingAccepted }
+func (BookingAccepted) EventVersion() int { return 1 }
+
+type BookingDeclined struct {
+	BookingID uuid.UUID `json:"booking_id"`
+	TeacherID uuid.UUID `json:"teacher_id"`
+	StudentID uuid.UUID `json:"student_id"`
+	Reason    string    `json:"reason"`
+}
+
+func (BookingDeclined) EventType() string { return TypeBookingDeclined }
+func (BookingDeclined) EventVersion() int { return 1 }
+
+type BookingCancelled struct {
+	BookingID   uuid.UUID `json:"booking_id"`
+	CancelledBy uuid.UUID `json:"cancelled_by"` // student or booking parent
+}
+
+func (BookingCancelled) EventType() string { return TypeBookingCancelled }
+func (BookingCancelled) EventVersion() int { return 1 }
+
+// ─── Enrollment ─────────────────────────────────────────────────
+
+type EnrollmentRequested struct {
+	EnrollmentID uuid.UUID `json:"enrollment_id"`
+	SeriesID     uuid.UUID `json:"series_id"`
+	TeacherID    uuid.UUID `json:"teacher_id"`
+	StudentID    uuid.UUID `json:"student_id"`
+}
+
+func (EnrollmentRequested) EventType() string { return TypeEnrollmentRequested }
+func (EnrollmentRequested) EventVersion() int { return 1 }
+
+type EnrollmentAccepted struct {
+	EnrollmentID uuid.UUID `json:"enrollment_id"`
+	SeriesID     uuid.UUID `json:"series_id"`
+	TeacherID    uuid.UUID `json:"teacher_id"`
+	StudentID    uuid.UUID `json:"student_id"`
+	InitiatedBy  string    `json:"initiated_by"` // "teacher" (invitation) or "student" (request)
+	SeriesTitle  string    `json:"series_title"`
+}
+
+func (EnrollmentAccepted) EventType() string { return TypeEnrollmentAccepted }
+func (EnrollmentAccepted) EventVersion() int { return 1 }
+
+// EnrollmentAwaitingCredit is emitted when an acceptance is parked because
+// the teacher's wallet cannot cover the star.
+type EnrollmentAwaitingCredit struct {
+	EnrollmentID uuid.UUID `json:"enrollment_id"`
+	SeriesID     uuid.UUID `json:"series_id"`
+	TeacherID    uuid.UUID `json:"teacher_id"`
+	StudentID    uuid.UUID `json:"student_id"`
+	InitiatedBy  string    `json:"initiated_by"`
+	SeriesTitle  string    `json:"series_title"`
+}
+
+func (EnrollmentAwaitingCredit) EventType() string { return TypeEnrollmentAwaitingCredit }
+func (EnrollmentAwaitingCredit) EventVersion() int { return 1 }
+
+type EnrollmentRemoved struct {
+	EnrollmentID uuid.UUID `json:"enrollment_id"`
+	SeriesID     uuid.UUID `json:"series_id"`
+	TeacherID    uuid.UUID `json:"teacher_id"`
+	StudentID    uuid.UUID `json:"student_id"`
+}
+
+func (EnrollmentRemoved) EventType() string { return TypeEnrollmentRemoved }
+func (EnrollmentRemoved) EventVersion() int { return 1 }
+
+// ─── Session ────────────────────────────────────────────────────
+
+type SessionStarted struct {
+	SessionID uuid.UUID  `json:"session_id"`
+	SeriesID  *uuid.UUID `json:"series_id,omitempty"`
+	TeacherID uuid.UUID  `json:"teacher_id"`
+	RoomID    string     `json:"room_id"`
+	StartedAt time.Time  `json:"started_at"`
+}
+
+func (SessionStarted) EventType() string { return TypeSessionStarted }
+func (SessionStarted) EventVersion() int { return 1 }
+
+type SessionEnded struct {
+	SessionID uuid.UUID  `json:"session_id"`
+	SeriesID  *uuid.UUID `json:"series_id,omitempty"`
+	TeacherID uuid.UUID  `json:"teacher_id"`
+	EndedAt   time.Time  `json:"ended_at"`
+}
+
+func (SessionEnded) EventType() string { return TypeSessionEnded }
+func (SessionEnded) EventVersion() int { return 1 }
+
+type SessionCancelled struct {
+	SessionID   uuid.UUID `json:"session_id"`
+	TeacherID   uuid.UUID `json:"teacher_id"`
+	CancelledBy uuid.UUID `json:"cancelled_by"`
+	Reason      string    `json:"reason"`
+}
+
+func (SessionCancelled) EventType() string { return TypeSessionCancelled }
+func (SessionCancelled) EventVersion() int { return 1 }
+
+type SessionRescheduled struct {
+	SessionID uuid.UUID `json:"session_id"`
+	TeacherID uuid.UUID `json:"teacher_id"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+func (SessionRescheduled) EventType() string { return TypeSessionRescheduled }
+func (SessionRescheduled) EventVersion() int { return 1 }
+
+// SessionNoShow is emitted when a scheduled session's window passes with
+// nobody having joined.
+type SessionNoShow struct {
+	SessionID uuid.UUID  `json:"session_id"`
+	SeriesID  *uuid.UUID `json:"series_id,omitempty"`
+	TeacherID uuid.UUID  `json:"teacher_id"`
+	StartTime time.Time  `json:"start_time"`
+	EndTime   time.Time  `json:"end_time"`
+}
+
+func (SessionNoShow) EventType() string { return TypeSessionNoShow }
+func (SessionNoShow) EventVersion() int { return 1 }
+
+// ─── Wallet ─────────────────────────────────────────────────────
+
+type WalletPurchaseApproved struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+	WalletID      uuid.UUID `json:"wallet_id"`
+	TeacherID     uuid.UUID `json:"teacher_id"`
+	AdminID       uuid.UUID `json:"admin_id"`
+	Amount        float64   `json:"amount"`
+	BalanceAfter  float64   `json:"balance_after"`
+}
+
+func (WalletPurchaseApproved) EventType() string { return TypeWalletPurchaseApproved }
+func (WalletPurchaseApproved) EventVersion() int { return 1 }
+
+type WalletPurchaseRejected struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+	WalletID      uuid.UUID `json:"wallet_id"`
+	TeacherID     uuid.UUID `json:"teacher_id"`
+	AdminID       uuid.UUID `json:"admin_id"`
+	Amount        float64   `json:"amount"`
+	Notes         string    `json:"notes"`
+}
+
+func (WalletPurchaseRejected) EventType() string { return TypeWalletPurchaseRejected }
+func (WalletPurchaseRejected) EventVersion() int { return 1 }
+
+type WalletPurchaseExpired struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+	WalletID      uuid.UUID `json:"wallet_id"`
+	TeacherID     uuid.UUID `json:"teacher_id"`
+	Amount        float64   `json:"amount"`
+}
+
+func (WalletPurchaseExpired) EventType() string { return TypeWalletPurchaseExpired }
+func (WalletPurchaseExpired) EventVersion() int { return 1 }
+
+// WalletLowBalance is emitted when a deduction takes the balance below one
+// of the configured alert thresholds.
+type WalletLowBalance struct {
+	WalletID  uuid.UUID `json:"wallet_id"`
+	TeacherID uuid.UUID `json:"teacher_id"`
+	Balance   float64   `json:"balance"`
+	Threshold float64   `json:"threshold"`
+}
+
+func (WalletLowBalance) EventType() string { return TypeWalletLowBalance }
+func (WalletLowBalance) EventVersion() int { return 1 }
+
+// ─── Payments ───────────────────────────────────────────────────
+
+// PaymentCompleted is emitted when a gateway callback settles a student
+// payment.
+type PaymentCompleted struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+	PayerID       uuid.UUID `json:"payer_id"`
+	PayeeID       uuid.UUID `json:"payee_id"`
+	Amount        float64   `json:"amount"`
+	Gateway       string    `json:"gateway"`
+}
+
+func (PaymentCompleted) EventType() string { return TypePaymentCompleted }
+func (PaymentCompleted) EventVersion() int { return 1 }
+
+type PaymentRefunded struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+	PayerID       uuid.UUID `json:"payer_id"`
+	PayeeID       uuid.UUID `json:"payee_id"`
+	Amount        float64   `json:"amount"` // refunded
+	Reason        string    `json:"reason"`
+}
+
+func (PaymentRefunded) EventType() string { return TypePaymentRefunded }
+func (PaymentRefunded) EventVersion() int { return 1 }
+
+// ─── Subscriptions ──────────────────────────────────────────────
+
+// SubscriptionRenewed is emitted when a new period opens; the student
+// pays it through TransactionID's checkout.
+type SubscriptionRenewed struct {
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	StudentID      uuid.UUID `json:"student_id"`
+	TeacherID      uuid.UUID `json:"teacher_id"`
+	TransactionID  uuid.UUID `json:"transaction_id"`
+	Period         int       `json:"period"`
+	Amount         float64   `json:"amount"`
+	StartDate      string    `json:"start_date"` // YYYY-MM-DD
+	EndDate        string    `json:"end_date"`   // YYYY-MM-DD
+}
+
+func (SubscriptionRenewed) EventType() string { return TypeSubscriptionRenewed }
+func (SubscriptionRenewed) EventVersion() int { return 1 }
+
+// SubscriptionExpired is emitted when a subscription ends: Reason is
+// "ended" (no auto-renewal) or "unpaid" (renewal not paid in time).
+type SubscriptionExpired struct {
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	StudentID      uuid.UUID `json:"student_id"`
+	TeacherID      uuid.UUID `json:"teacher_id"`
+	Reason         string    `json:"reason"`
+}
+
+func (SubscriptionExpired) EventType() string { return TypeSubscriptionExpired }
+func (SubscriptionExpired) EventVersion() int { return 1 }
+
+// ─── Session Packages ───────────────────────────────────────────
+
+// PackageExpired is emitted when an active package purchase passes its
+// expiry date; SessionsRemaining were lost.
+type PackageExpired struct {
+	PurchaseID        uuid.UUID `json:"purchase_id"`
+	PackageID         uuid.UUID `json:"package_id"`
+	StudentID         uuid.UUID `json:"student_id"`
+	TeacherID         uuid.UUID `json:"teacher_id"`
+	SessionsRemaining int       `json:"sessions_remaining"`
+}
+
+func (PackageExpired) EventType() string { return TypePackageExpired }
+func (PackageExpired) EventVersion() int { return 1 }
+
+// ─── Payouts ────────────────────────────────────────────────────
+
+type PayoutRequested struct {
+	PayoutID  uuid.UUID `json:"payout_id"`
+	TeacherID uuid.UUID `json:"teacher_id"`
+	Amount    float64   `json:"amount"`
+}
+
+func (PayoutRequested) EventType() string { return TypePayoutRequested }
+func (PayoutRequested) EventVersion() int { return 1 }
+
+// PayoutUpdated is emitted on every admin transition: Status is the new
+// payment_status (processing, completed or failed).
+type PayoutUpdated struct {
+	PayoutID  uuid.UUID `json:"payout_id"`
+	TeacherID uuid.UUID `json:"teacher_id"`
+	AdminID   uuid.UUID `json:"admin_id"`
+	Amount    float64   `json:"amount"`
+	Status    string    `json:"status"`
+	Reference string    `json:"reference,omitempty"`
+	Notes     string    `json:"notes,omitempty"`
+}
+
+func (PayoutUpdated) EventType() string { return TypePayoutUpdated }
+func (PayoutUpdated) EventVersion() int { return 1 }
+
+// ─── Homework ───────────────────────────────────────────────────
+
+type HomeworkSubmitted struct {
+	SubmissionID uuid.UUID `json:"submission_id"`
+	HomeworkID   uuid.UUID `json:"homework_id"`
+	TeacherID    uuid.UUID `json:"teacher_id"`
+	StudentID    uuid.UUID `json:"student_id"`
+	IsLate       bool      `json:"is_late"`
+}
+
+func (HomeworkSubmitted) EventType() string { return TypeHomeworkSubmitted }
+func (HomeworkSubmitted) EventVersion() int { return 1 }
+
+type HomeworkGraded struct {
+	SubmissionID uuid.UUID `json:"submission_id"`
+	HomeworkID   uuid.UUID `json:"homework_id"`
+	TeacherID    uuid.UUID `json:"teacher_id"`
+	StudentID    uuid.UUID `json:"student_id"`
+	Grade        float64   `json:"grade"`
+	MaxGrade     float64   `json:"max_grade"`
+}
+
+func (HomeworkGraded) EventType() string { return TypeHomeworkGraded }
+func (HomeworkGraded) EventVersion() int { return 1 }
@@ -0,0 +1,58 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"educonnect/pkg/messaging"
+
+	"github.com/google/uuid"
+)
+
+// Event is implemented by every payload in the catalogue.
+// The type and version together select the NATS subject, so a breaking
+// payload change must bump the version rather than mutate the struct.
+type Event interface {
+	EventType() string
+	EventVersion() int
+}
+
+// Envelope wraps an event on the wire.
+type Envelope struct {
+	ID         uuid.UUID       `json:"id"`
+	Type       string          `json:"type"`
+	Version    int             `json:"version"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// NewEnvelope serialises an event into a fresh envelope.
+func NewEnvelope(e Event) (*Envelope, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("marshal %s: %w", e.EventType(), err)
+	}
+	return &Envelope{
+		ID:         uuid.New(),
+		Type:       e.EventType(),
+		Version:    e.EventVersion(),
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}, nil
+}
+
+// Decode unmarshals the envelope payload into target.
+func (env *Envelope) Decode(target Event) error {
+	if env.Type != target.EventType() || env.Version != target.EventVersion() {
+		return fmt.Errorf("event %s.v%d cannot decode into %s.v%d",
+			env.Type, env.Version, target.EventType(), target.EventVersion())
+	}
+	return json.Unmarshal(env.Data, target)
+}
+
+// Subject returns the NATS subject for an event type and version,
+// e.g. "educonnect.events.booking.accepted.v1".
+func Subject(eventType string, version int) string {
+	return fmt.Sprintf("%s.%s.v%d", messaging.SubjectDomainEvent, eventType, version)
+}
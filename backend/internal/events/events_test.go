@@ -0,0 +1,53 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubject(t *testing.T) {
+	assert.Equal(t, "educonnect.events.booking.accepted.v1", Subject(TypeBookingAccepted, 1))
+	assert.Equal(t, "educonnect.events.wallet.purchase.approved.v2", Subject(TypeWalletPurchaseApproved, 2))
+}
+
+func TestEnvelope_RoundTrip(t *testing.T) {
+	in := HomeworkGraded{
+		SubmissionID: uuid.New(),
+		HomeworkID:   uuid.New(),
+		TeacherID:    uuid.New(),
+		StudentID:    uuid.New(),
+		Grade:        15,
+		MaxGrade:     20,
+	}
+
+	env, err := NewEnvelope(in)
+	require.NoError(t, err)
+	assert.Equal(t, TypeHomeworkGraded, env.Type)
+	assert.Equal(t, 1, env.Version)
+	assert.NotEqual(t, uuid.Nil, env.ID)
+
+	var out HomeworkGraded
+	require.NoError(t, env.Decode(&out))
+	assert.Equal(t, in, out)
+
+	var wrong SessionEnded
+	assert.Error(t, env.Decode(&wrong), "decoding into another event type must fail")
+}
+
+func TestPublisher_NilIsNoop(t *testing.T) {
+	var p *Publisher
+	assert.NoError(t, p.Publish(context.Background(), SessionEnded{SessionID: uuid.New()}))
+	assert.NoError(t, NewPublisher(nil).Publish(context.Background(), SessionEnded{SessionID: uuid.New()}))
+}
+
+func TestOutboxBackoff(t *testing.T) {
+	assert.Equal(t, 5*time.Second, backoff(1))
+	assert.Equal(t, 10*time.Second, backoff(2))
+	assert.Equal(t, 40*time.Second, backoff(4))
+	assert.Equal(t, relayMaxBackoff, backoff(20))
+}
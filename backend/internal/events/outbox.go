@@ -0,0 +1,188 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"educonnect/pkg/database"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ─── Outbox Writer ──────────────────────────────────────────────
+
+// Enqueue records an event in the outbox inside the caller's transaction.
+// The event is only published if the transaction commits.
+func Enqueue(ctx context.Context, tx pgx.Tx, e Event) error {
+	env, err := NewEnvelope(e)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(env)
+	if err != nil {
+		return fmt.Errorf("marshal envelope: %w", err)
+	}
+	_, err = tx.Exec(ctx,
+		`INSERT INTO event_outbox (id, event_type, event_version, envelope)
+		 VALUES ($1, $2, $3, $4)`,
+		env.ID, env.Type, env.Version, data,
+	)
+	if err != nil {
+		return fmt.Errorf("enqueue %s: %w", env.Type, err)
+	}
+	return nil
+}
+
+// ─── Outbox Relay ───────────────────────────────────────────────
+
+const (
+	relayBatchSize    = 100
+	relayMaxBackoff   = 10 * time.Minute
+	relayRetention    = 7 * 24 * time.Hour
+	relayPruneEvery   = time.Hour
+	relayPollInterval = 2 * time.Second
+	relayLease        = 5 * time.Minute // longer than a batch takes to publish
+)
+
+// Relay delivers pending outbox rows to NATS. Several relays may run
+// concurrently: each claims its rows with FOR UPDATE SKIP LOCKED and a
+// lease.
+type Relay struct {
+	db  *database.Postgres
+	pub *Publisher
+}
+
+// NewRelay creates an outbox relay.
+func NewRelay(db *database.Postgres, pub *Publisher) *Relay {
+	return &Relay{db: db, pub: pub}
+}
+
+// Run polls the outbox until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(relayPollInterval)
+	defer ticker.Stop()
+	lastPrune := time.Time{}
+
+	for {
+		for {
+			n, err := r.RelayOnce(ctx)
+			if err != nil {
+				slog.Error("outbox relay failed", "error", err)
+				break
+			}
+			if n < relayBatchSize {
+				break
+			}
+		}
+
+		if time.Since(lastPrune) > relayPruneEvery {
+			if err := r.Prune(ctx); err != nil {
+				slog.Warn("outbox prune failed", "error", err)
+			}
+			lastPrune = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayOnce publishes one batch of due rows and returns how many were claimed.
+// The batch is claimed by a statement of its own that leases the rows for
+// relayLease, so no transaction or row lock is held while NATS answers. A
+// row whose lease runs out before it is marked, because the relay died or
+// the batch outlasted it, is claimed again; JetStream drops the duplicate
+// by its message ID.
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`UPDATE event_outbox SET next_attempt_at = NOW() + make_interval(secs => $2)
+		 WHERE id IN (
+		     SELECT id FROM event_outbox
+		     WHERE published_at IS NULL AND next_attempt_at <= NOW()
+		     ORDER BY created_at
+		     LIMIT $1
+		     FOR UPDATE SKIP LOCKED)
+		 RETURNING id, envelope, attempts, created_at`, relayBatchSize, relayLease.Seconds(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("claim outbox rows: %w", err)
+	}
+
+	type pending struct {
+		id        uuid.UUID
+		envelope  []byte
+		attempts  int
+		createdAt time.Time
+	}
+	var batch []pending
+	for rows.Next() {
+		var p pending
+		if err := rows.Scan(&p.id, &p.envelope, &p.attempts, &p.createdAt); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan outbox row: %w", err)
+		}
+		batch = append(batch, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("iterate outbox rows: %w", err)
+	}
+	// RETURNING keeps no order
+	sort.Slice(batch, func(i, j int) bool { return batch[i].createdAt.Before(batch[j].createdAt) })
+
+	for _, p := range batch {
+		var env Envelope
+		pubErr := json.Unmarshal(p.envelope, &env)
+		if pubErr == nil {
+			pubErr = r.pub.PublishEnvelope(ctx, &env)
+		}
+
+		if pubErr == nil {
+			_, err = r.db.Pool.Exec(ctx,
+				`UPDATE event_outbox SET published_at = NOW(), attempts = attempts + 1, last_error = NULL WHERE id = $1`, p.id)
+		} else {
+			_, err = r.db.Pool.Exec(ctx,
+				`UPDATE event_outbox
+				 SET attempts = attempts + 1, last_error = $1, next_attempt_at = $2
+				 WHERE id = $3`,
+				pubErr.Error(), time.Now().Add(backoff(p.attempts+1)), p.id)
+			slog.Warn("outbox publish failed", "id", p.id, "attempt", p.attempts+1, "error", pubErr)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("update outbox row: %w", err)
+		}
+	}
+
+	return len(batch), nil
+}
+
+// Prune deletes outbox rows published, and dedup records written, more
+// than a week ago — past the stream's MaxAge nothing can be redelivered.
+func (r *Relay) Prune(ctx context.Context) error {
+	cutoff := time.Now().Add(-relayRetention)
+	if _, err := r.db.Pool.Exec(ctx, `DELETE FROM event_outbox WHERE published_at < $1`, cutoff); err != nil {
+		return err
+	}
+	_, err := r.db.Pool.Exec(ctx, `DELETE FROM processed_events WHERE processed_at < $1`, cutoff)
+	return err
+}
+
+// backoff returns the delay before the given attempt: 5s, 10s, 20s … capped.
+func backoff(attempt int) time.Duration {
+	d := 5 * time.Second
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if d >= relayMaxBackoff {
+			return relayMaxBackoff
+		}
+	}
+	return d
+}
@@ -0,0 +1,58 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"educonnect/pkg/messaging"
+)
+
+// Publisher publishes catalogue events to the EVENTS stream.
+// A nil Publisher (or one without a NATS connection) is a no-op, so
+// services and tests can run without a message bus.
+type Publisher struct {
+	mq *messaging.NATS
+}
+
+// NewPublisher creates a publisher on top of the shared NATS connection.
+func NewPublisher(mq *messaging.NATS) *Publisher {
+	return &Publisher{mq: mq}
+}
+
+// Publish wraps the event in an envelope and publishes it.
+func (p *Publisher) Publish(ctx context.Context, e Event) error {
+	if p == nil || p.mq == nil {
+		return nil
+	}
+	env, err := NewEnvelope(e)
+	if err != nil {
+		return err
+	}
+	return p.PublishEnvelope(ctx, env)
+}
+
+// PublishEnvelope publishes a pre-built envelope. The envelope ID is used as
+// the JetStream message ID so re-publishing the same envelope is idempotent.
+func (p *Publisher) PublishEnvelope(ctx context.Context, env *Envelope) error {
+	if p == nil || p.mq == nil {
+		return nil
+	}
+	data, err := json.Marshal(env)
+	if err != nil {
+		return fmt.Errorf("marshal envelope: %w", err)
+	}
+	if err := p.mq.PublishMsg(Subject(env.Type, env.Version), data, env.ID.String()); err != nil {
+		return fmt.Errorf("publish %s: %w", env.Type, err)
+	}
+	return nil
+}
+
+// Emit publishes after a commit on a best-effort basis: the state change has
+// already happened, so a bus failure is logged rather than returned.
+func (p *Publisher) Emit(ctx context.Context, e Event) {
+	if err := p.Publish(ctx, e); err != nil {
+		slog.Warn("event publish failed", "type", e.EventType(), "error", err)
+	}
+}
@@ -0,0 +1,127 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"educonnect/pkg/database"
+	"educonnect/pkg/messaging"
+
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+)
+
+// HandlerFunc reacts to a single event. Returning an error redelivers the
+// message after a back-off; returning nil acknowledges it.
+type HandlerFunc func(ctx context.Context, env *Envelope) error
+
+type route struct {
+	durable   string
+	eventType string
+	version   int
+	handler   HandlerFunc
+}
+
+// Runner binds handlers to durable JetStream consumers on the EVENTS stream.
+// Each durable name receives its own copy of every matching event; processes
+// sharing a durable name split its messages between them. When a
+// database is supplied, handled event IDs are recorded per durable so a
+// redelivered event is acknowledged without running the handler again.
+type Runner struct {
+	mq     *messaging.NATS
+	db     *database.Postgres
+	routes []route
+	subs   []*nats.Subscription
+}
+
+// NewRunner creates a consumer runner. db may be nil to disable deduplication.
+func NewRunner(mq *messaging.NATS, db *database.Postgres) *Runner {
+	return &Runner{mq: mq, db: db}
+}
+
+// Handle registers a handler for an event type/version under a durable
+// consumer name. Durable names must be unique per (consumer, event type).
+func (r *Runner) Handle(durable, eventType string, version int, h HandlerFunc) {
+	r.routes = append(r.routes, route{durable: durable, eventType: eventType, version: version, handler: h})
+}
+
+// Start subscribes every registered handler. Handlers receive ctx; they stop
+// receiving messages once Stop is called.
+func (r *Runner) Start(ctx context.Context) error {
+	if r.mq == nil {
+		return nil
+	}
+	for _, rt := range r.routes {
+		rt := rt
+		sub, err := r.mq.QueueSubscribe(Subject(rt.eventType, rt.version), rt.durable, func(msg *nats.Msg) {
+			r.dispatch(ctx, rt, msg)
+		})
+		if err != nil {
+			r.Stop()
+			return fmt.Errorf("subscribe %s (%s): %w", rt.eventType, rt.durable, err)
+		}
+		r.subs = append(r.subs, sub)
+		slog.Info("event consumer started", "type", rt.eventType, "durable", rt.durable)
+	}
+	return nil
+}
+
+// Stop unsubscribes all consumers. Durable state is kept on the server.
+func (r *Runner) Stop() {
+	for _, sub := range r.subs {
+		_ = sub.Drain()
+	}
+	r.subs = nil
+}
+
+func (r *Runner) dispatch(ctx context.Context, rt route, msg *nats.Msg) {
+	var env Envelope
+	if err := json.Unmarshal(msg.Data, &env); err != nil {
+		slog.Error("malformed event dropped", "subject", msg.Subject, "error", err)
+		_ = msg.Term()
+		return
+	}
+
+	if r.alreadyProcessed(ctx, rt.durable, env.ID) {
+		_ = msg.Ack()
+		return
+	}
+
+	if err := rt.handler(ctx, &env); err != nil {
+		slog.Warn("event handler failed, will retry",
+			"type", env.Type, "id", env.ID, "durable", rt.durable, "error", err)
+		_ = msg.NakWithDelay(10 * time.Second)
+		return
+	}
+
+	r.markProcessed(ctx, rt.durable, env.ID)
+	_ = msg.Ack()
+}
+
+func (r *Runner) alreadyProcessed(ctx context.Context, durable string, eventID uuid.UUID) bool {
+	if r.db == nil {
+		return false
+	}
+	var exists bool
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT EXISTS(SELECT 1 FROM processed_events WHERE consumer = $1 AND event_id = $2)`,
+		durable, eventID,
+	).Scan(&exists)
+	return err == nil && exists
+}
+
+func (r *Runner) markProcessed(ctx context.Context, durable string, eventID uuid.UUID) {
+	if r.db == nil {
+		return
+	}
+	_, err := r.db.Pool.Exec(ctx,
+		`INSERT INTO processed_events (consumer, event_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+		durable, eventID,
+	)
+	if err != nil {
+		slog.Warn("record processed event failed", "id", eventID, "durable", durable, "error", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"educonnect/internal/events"
 	"educonnect/pkg/database"
 
 	"github.com/google/uuid"
@@ -20,11 +21,12 @@ var (
 )
 
 type Service struct {
-	db *database.Postgres
+	db     *database.Postgres
+	events *events.Publisher
 }
 
-func NewService(db *database.Postgres) *Service {
-	return &Service{db: db}
+func NewService(db *database.Postgres, pub *events.Publisher) *Service {
+	return &Service{db: db, events: pub}
 }
 
 // ─── Homework CRUD ──────────────────────────────────────────────
@@ -194,7 +196,8 @@ func (s *Service) SubmitHomework(ctx context.Context, homeworkID, studentID stri
 
 	// Check homework exists
 	var deadline *time.Time
-	err := s.db.Pool.QueryRow(ctx, `SELECT deadline FROM homework WHERE id = $1`, hid).Scan(&deadline)
+	var hwTeacherID uuid.UUID
+	err := s.db.Pool.QueryRow(ctx, `SELECT deadline, teacher_id FROM homework WHERE id = $1`, hid).Scan(&deadline, &hwTeacherID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrHomeworkNotFound
@@ -222,6 +225,14 @@ func (s *Service) SubmitHomework(ctx context.Context, homeworkID, studentID stri
 	_, _ = s.db.Pool.Exec(ctx,
 		`UPDATE homework_assignments SET status = 'submitted' WHERE homework_id = $1 AND student_id = $2`, hid, sid)
 
+	s.events.Emit(ctx, events.HomeworkSubmitted{
+		SubmissionID: id,
+		HomeworkID:   hid,
+		TeacherID:    hwTeacherID,
+		StudentID:    sid,
+		IsLate:       isLate,
+	})
+
 	return s.getSubmission(ctx, id)
 }
 
@@ -232,12 +243,12 @@ func (s *Service) GradeHomework(ctx context.Context, submissionID, teacherID str
 	tid, _ := uuid.Parse(teacherID)
 
 	// Verify teacher owns the homework
-	var hwTeacherID uuid.UUID
+	var hwTeacherID, homeworkID, studentID uuid.UUID
 	err := s.db.Pool.QueryRow(ctx,
-		`SELECT hw.teacher_id FROM homework_submissions hs
+		`SELECT hw.teacher_id, hs.homework_id, hs.student_id FROM homework_submissions hs
 		 JOIN homework hw ON hw.id = hs.homework_id
 		 WHERE hs.id = $1`, subID,
-	).Scan(&hwTeacherID)
+	).Scan(&hwTeacherID, &homeworkID, &studentID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrSubmissionNotFound
@@ -264,6 +275,15 @@ func (s *Service) GradeHomework(ctx context.Context, submissionID, teacherID str
 		 FROM homework_submissions hs
 		 WHERE hs.id = $1 AND ha.homework_id = hs.homework_id AND ha.student_id = hs.student_id`, subID)
 
+	s.events.Emit(ctx, events.HomeworkGraded{
+		SubmissionID: subID,
+		HomeworkID:   homeworkID,
+		TeacherID:    tid,
+		StudentID:    studentID,
+		Grade:        req.Grade,
+		MaxGrade:     req.MaxGrade,
+	})
+
 	return s.getSubmission(ctx, subID)
 }
 
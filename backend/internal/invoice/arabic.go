@@ -0,0 +1,168 @@
+package invoice
+
+import "unicode"
+
+// ─── Arabic Text ────────────────────────────────────────────────
+// The PDF writer places glyphs one code point at a time, left to
+// right. Arabic therefore has to be shaped (each letter replaced by
+// its isolated, final, initial or medial presentation form) and laid
+// out in visual order before it is drawn.
+
+// arabicForms maps a letter to its first presentation form in the
+// Arabic Presentation Forms-B block and how many forms follow it, in
+// the order isolated, final, initial, medial. Letters with two forms
+// join only to the preceding letter.
+var arabicForms = map[rune]struct {
+	first rune
+	count int
+}{
+	'ء': {0xFE80, 1}, 'آ': {0xFE81, 2}, 'أ': {0xFE83, 2}, 'ؤ': {0xFE85, 2},
+	'إ': {0xFE87, 2}, 'ئ': {0xFE89, 4}, 'ا': {0xFE8D, 2}, 'ب': {0xFE8F, 4},
+	'ة': {0xFE93, 2}, 'ت': {0xFE95, 4}, 'ث': {0xFE99, 4}, 'ج': {0xFE9D, 4},
+	'ح': {0xFEA1, 4}, 'خ': {0xFEA5, 4}, 'د': {0xFEA9, 2}, 'ذ': {0xFEAB, 2},
+	'ر': {0xFEAD, 2}, 'ز': {0xFEAF, 2}, 'س': {0xFEB1, 4}, 'ش': {0xFEB5, 4},
+	'ص': {0xFEB9, 4}, 'ض': {0xFEBD, 4}, 'ط': {0xFEC1, 4}, 'ظ': {0xFEC5, 4},
+	'ع': {0xFEC9, 4}, 'غ': {0xFECD, 4}, 'ف': {0xFED1, 4}, 'ق': {0xFED5, 4},
+	'ك': {0xFED9, 4}, 'ل': {0xFEDD, 4}, 'م': {0xFEE1, 4}, 'ن': {0xFEE5, 4},
+	'ه': {0xFEE9, 4}, 'و': {0xFEED, 2}, 'ى': {0xFEEF, 2}, 'ي': {0xFEF1, 4},
+}
+
+// lamAlef maps the alef following a lam to the isolated form of their
+// ligature; the final form is the next code point.
+var lamAlef = map[rune]rune{'آ': 0xFEF5, 'أ': 0xFEF7, 'إ': 0xFEF9, 'ا': 0xFEFB}
+
+const tatweel = 'ـ'
+
+func isArabic(r rune) bool {
+	return (r >= 0x0600 && r <= 0x06FF) || (r >= 0xFB50 && r <= 0xFDFF) || (r >= 0xFE70 && r <= 0xFEFF)
+}
+
+// isTransparent reports harakat, which sit on a letter without
+// breaking the joining between its neighbours.
+func isTransparent(r rune) bool { return (r >= 0x064B && r <= 0x065F) || r == 0x0670 }
+
+// joinsNext reports whether r connects to the letter after it.
+func joinsNext(r rune) bool {
+	if r == tatweel {
+		return true
+	}
+	f, ok := arabicForms[r]
+	return ok && f.count == 4
+}
+
+// joinsPrev reports whether r connects to the letter before it.
+func joinsPrev(r rune) bool {
+	if r == tatweel {
+		return true
+	}
+	f, ok := arabicForms[r]
+	return ok && f.count > 1
+}
+
+// shape replaces Arabic letters with their contextual forms, keeping
+// logical order.
+func shape(in []rune) []rune {
+	// neighbour returns the closest non-transparent rune from i in step
+	// direction, or 0.
+	neighbour := func(i, step int) rune {
+		for j := i + step; j >= 0 && j < len(in); j += step {
+			if !isTransparent(in[j]) {
+				return in[j]
+			}
+		}
+		return 0
+	}
+
+	out := make([]rune, 0, len(in))
+	for i := 0; i < len(in); i++ {
+		r := in[i]
+		f, ok := arabicForms[r]
+		if !ok {
+			out = append(out, r)
+			continue
+		}
+		prev := joinsNext(neighbour(i, -1))
+
+		if r == 'ل' && i+1 < len(in) {
+			if lig, ok := lamAlef[in[i+1]]; ok {
+				if prev {
+					lig++
+				}
+				out = append(out, lig)
+				i++
+				continue
+			}
+		}
+
+		next := f.count == 4 && joinsPrev(neighbour(i, 1))
+		form := 0 // isolated
+		switch {
+		case prev && next:
+			form = 3
+		case prev && f.count > 1:
+			form = 1
+		case next:
+			form = 2
+		}
+		out = append(out, f.first+rune(form))
+	}
+	return out
+}
+
+// visual lays out a left-to-right line containing Arabic: each run of
+// Arabic words is shaped and reversed in place, digits inside it keep
+// their order.
+func visual(s string) string {
+	in := []rune(s)
+	hasArabic := false
+	for _, r := range in {
+		if isArabic(r) {
+			hasArabic = true
+			break
+		}
+	}
+	if !hasArabic {
+		return s
+	}
+
+	in = shape(in)
+	for i := 0; i < len(in); {
+		if !isArabic(in[i]) {
+			i++
+			continue
+		}
+		// A run spans Arabic letters and the spaces, digits and
+		// punctuation between them, and ends on an Arabic letter or on
+		// a number following one (numbers take the Arabic direction).
+		end := i
+		for j := i; j < len(in); j++ {
+			if isArabic(in[j]) || unicode.IsDigit(in[j]) {
+				end = j
+			} else if unicode.IsLetter(in[j]) {
+				break
+			}
+		}
+		reverse(in[i : end+1])
+		// Restore number order inside the run.
+		for j := i; j <= end; {
+			if !unicode.IsDigit(in[j]) {
+				j++
+				continue
+			}
+			k := j
+			for k <= end && (unicode.IsDigit(in[k]) || in[k] == '.' || in[k] == ',') {
+				k++
+			}
+			reverse(in[j:k])
+			j = k
+		}
+		i = end + 1
+	}
+	return string(in)
+}
+
+func reverse(r []rune) {
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+}
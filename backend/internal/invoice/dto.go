@@ -0,0 +1,66 @@
+package invoice
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invoice kinds.
+const (
+	KindInvoice    = "invoice"
+	KindCreditNote = "credit_note"
+)
+
+// What an invoice documents.
+const (
+	SourceWalletPurchase = "wallet_purchase" // teacher buying wallet credit
+	SourcePayment        = "payment"         // student paying a teacher
+)
+
+// ═══════════════════════════════════════════════════════════════
+// Responses
+// ═══════════════════════════════════════════════════════════════
+
+type InvoiceResponse struct {
+	ID                 uuid.UUID  `json:"id"`
+	Kind               string     `json:"kind"`
+	Number             string     `json:"number"`
+	SourceType         string     `json:"source_type"`
+	SourceID           uuid.UUID  `json:"source_id"`
+	CreditNoteOf       *uuid.UUID `json:"credit_note_of,omitempty"`
+	CreditNoteOfNumber string     `json:"credit_note_of_number,omitempty"`
+	CustomerID         uuid.UUID  `json:"customer_id"`
+	CustomerName       string     `json:"customer_name"`
+	CustomerWilaya     string     `json:"customer_wilaya,omitempty"`
+	TeacherID          uuid.UUID  `json:"teacher_id"`
+	TeacherName        string     `json:"teacher_name"`
+	Description        string     `json:"description"`
+	Amount             float64    `json:"amount"`
+	CreditedAmount     float64    `json:"credited_amount"` // invoices: total of their credit notes
+	PaymentMethod      string     `json:"payment_method,omitempty"`
+	Reference          string     `json:"reference,omitempty"`
+	Reason             string     `json:"reason,omitempty"`
+	HasPDF             bool       `json:"has_pdf"`
+	GeneratedAt        *time.Time `json:"generated_at,omitempty"`
+	IssuedBy           *uuid.UUID `json:"issued_by,omitempty"`
+	IssuedAt           time.Time  `json:"issued_at"`
+}
+
+// ═══════════════════════════════════════════════════════════════
+// Admin Requests
+// ═══════════════════════════════════════════════════════════════
+
+// IssueInvoiceRequest issues the invoice of a completed purchase or
+// payment by hand, e.g. for one settled before invoicing existed.
+type IssueInvoiceRequest struct {
+	SourceType string    `json:"source_type" validate:"required,oneof=wallet_purchase payment"`
+	SourceID   uuid.UUID `json:"source_id" validate:"required"`
+}
+
+// CreditNoteRequest credits part or all of an invoice. A zero amount
+// credits whatever is left.
+type CreditNoteRequest struct {
+	Amount float64 `json:"amount" validate:"gte=0"`
+	Reason string  `json:"reason" validate:"required,max=500"`
+}
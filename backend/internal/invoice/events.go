@@ -0,0 +1,44 @@
+package invoice
+
+import (
+	"context"
+
+	"educonnect/internal/events"
+)
+
+// ─── Event Consumers ────────────────────────────────────────────
+
+// RegisterEventHandlers issues invoices as purchases and payments
+// complete, and credit notes as payments are refunded.
+func (s *Service) RegisterEventHandlers(r *events.Runner) {
+	r.Handle("invoice-wallet-purchase-approved", events.TypeWalletPurchaseApproved, 1, s.onWalletPurchaseApproved)
+	r.Handle("invoice-payment-completed", events.TypePaymentCompleted, 1, s.onPaymentCompleted)
+	r.Handle("invoice-payment-refunded", events.TypePaymentRefunded, 1, s.onPaymentRefunded)
+}
+
+func (s *Service) onWalletPurchaseApproved(ctx context.Context, env *events.Envelope) error {
+	var e events.WalletPurchaseApproved
+	if err := env.Decode(&e); err != nil {
+		return err
+	}
+	_, err := s.Issue(ctx, SourceWalletPurchase, e.TransactionID, nil)
+	return err
+}
+
+func (s *Service) onPaymentCompleted(ctx context.Context, env *events.Envelope) error {
+	var e events.PaymentCompleted
+	if err := env.Decode(&e); err != nil {
+		return err
+	}
+	_, err := s.Issue(ctx, SourcePayment, e.TransactionID, nil)
+	return err
+}
+
+func (s *Service) onPaymentRefunded(ctx context.Context, env *events.Envelope) error {
+	var e events.PaymentRefunded
+	if err := env.Decode(&e); err != nil {
+		return err
+	}
+	_, err := s.CreditPaymentRefund(ctx, e.TransactionID)
+	return err
+}
@@ -0,0 +1,176 @@
+package invoice
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"educonnect/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	service  *Service
+	validate *validator.Validate
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service, validate: validator.New()}
+}
+
+// ═══════════════════════════════════════════════════════════════
+// User Endpoints
+// ═══════════════════════════════════════════════════════════════
+
+// ListInvoices GET /invoices
+func (h *Handler) ListInvoices(c *gin.Context) {
+	page, limit := pagination(c)
+	invoices, total, err := h.service.ListInvoices(c.Request.Context(), middleware.GetUserID(c), page, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    invoices,
+		"meta":    gin.H{"page": page, "limit": limit, "total": total, "has_more": int64(page*limit) < total},
+	})
+}
+
+// GetInvoice GET /invoices/:id
+func (h *Handler) GetInvoice(c *gin.Context) {
+	inv, err := h.service.GetInvoice(c.Request.Context(), middleware.GetUserID(c), middleware.GetUserRole(c), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": inv})
+}
+
+// DownloadInvoice GET /invoices/:id/pdf
+func (h *Handler) DownloadInvoice(c *gin.Context) {
+	inv, data, err := h.service.Download(c.Request.Context(), middleware.GetUserID(c), middleware.GetUserRole(c), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.pdf"`, inv.Number))
+	c.Data(http.StatusOK, "application/pdf", data)
+}
+
+// ═══════════════════════════════════════════════════════════════
+// Admin Endpoints
+// ═══════════════════════════════════════════════════════════════
+
+// AdminListInvoices GET /admin/invoices?kind=&source_type=
+func (h *Handler) AdminListInvoices(c *gin.Context) {
+	page, limit := pagination(c)
+	invoices, total, err := h.service.AdminListInvoices(c.Request.Context(), c.Query("kind"), c.Query("source_type"), page, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    invoices,
+		"meta":    gin.H{"page": page, "limit": limit, "total": total, "has_more": int64(page*limit) < total},
+	})
+}
+
+// AdminIssueInvoice POST /admin/invoices
+func (h *Handler) AdminIssueInvoice(c *gin.Context) {
+	var req IssueInvoiceRequest
+	if !h.bind(c, &req) {
+		return
+	}
+	adminID, _ := uuid.Parse(middleware.GetUserID(c))
+	inv, err := h.service.Issue(c.Request.Context(), req.SourceType, req.SourceID, &adminID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": inv})
+}
+
+// AdminCreditNote POST /admin/invoices/:id/credit-notes
+func (h *Handler) AdminCreditNote(c *gin.Context) {
+	var req CreditNoteRequest
+	if !h.bind(c, &req) {
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, ErrInvoiceNotFound)
+		return
+	}
+	adminID, _ := uuid.Parse(middleware.GetUserID(c))
+	cn, err := h.service.IssueCreditNote(c.Request.Context(), id, req.Amount, req.Reason, &adminID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": cn})
+}
+
+// AdminRegenerate POST /admin/invoices/:id/regenerate
+func (h *Handler) AdminRegenerate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, ErrInvoiceNotFound)
+		return
+	}
+	inv, err := h.service.Regenerate(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": inv})
+}
+
+// ═══════════════════════════════════════════════════════════════
+// Helpers
+// ═══════════════════════════════════════════════════════════════
+
+// bind decodes and validates a JSON body, writing the error response
+// itself when it fails.
+func (h *Handler) bind(c *gin.Context, req any) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+		return false
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"success": false, "error": gin.H{"message": "validation failed", "details": err.Error()}})
+		return false
+	}
+	return true
+}
+
+func pagination(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+	return page, limit
+}
+
+func respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrInvoiceNotFound), errors.Is(err, ErrSourceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+	case errors.Is(err, ErrNotAuthorized):
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+	case errors.Is(err, ErrNotAnInvoice), errors.Is(err, ErrCreditExceedsInvoice):
+		c.JSON(http.StatusConflict, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+	default:
+		fmt.Printf("[ERROR] invoice: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"message": "internal server error"}})
+	}
+}
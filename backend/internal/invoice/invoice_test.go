@@ -0,0 +1,92 @@
+package invoice
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"educonnect/internal/config"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatNumber(t *testing.T) {
+	assert.Equal(t, "FA-2026-000042", FormatNumber(KindInvoice, 2026, 42))
+	assert.Equal(t, "AV-2026-000007", FormatNumber(KindCreditNote, 2026, 7))
+}
+
+func TestFormatAmount(t *testing.T) {
+	assert.Equal(t, "0,50 DA", formatAmount(0.5))
+	assert.Equal(t, "950,00 DA", formatAmount(950))
+	assert.Equal(t, "1\u00a0500,00 DA", formatAmount(1500))
+	assert.Equal(t, "1\u00a0234\u00a0567,89 DA", formatAmount(1234567.891))
+}
+
+func TestVisual(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"latin untouched", "Facture N° 12", "Facture N° 12"},
+		// ف ا ت و ر ة: alef, waw and reh do not join forward.
+		{"joining forms", "فاتورة", string([]rune{0xFE93, 0xFEAD, 0xFEEE, 0xFE97, 0xFE8E, 0xFED3})},
+		{"lam-alef ligature", "لا", string([]rune{0xFEFB})},
+		{"final lam-alef", "سلا", string([]rune{0xFEFC, 0xFEB3})},
+		{"mixed line", "Total / مع", "Total / " + string([]rune{0xFECA, 0xFEE3})},
+		{"digits keep order", "رقم 12", "12 " + string([]rune{0xFEE2, 0xFED7, 0xFEAD})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, visual(tt.in))
+		})
+	}
+}
+
+// fontDir finds DejaVu on the machine running the tests.
+func fontDir(t *testing.T) string {
+	for _, dir := range []string{"/usr/share/fonts/dejavu", "/usr/share/fonts/truetype/dejavu"} {
+		if _, err := os.Stat(filepath.Join(dir, fontRegular)); err == nil {
+			return dir
+		}
+	}
+	t.Skip("DejaVu fonts not installed")
+	return ""
+}
+
+func TestRender(t *testing.T) {
+	cfg := config.InvoiceConfig{FontDir: fontDir(t), IssuerName: "EduConnect", IssuerAddress: "Alger, Algérie", IssuerNIF: "000016000000000"}
+	origin := uuid.New()
+	inv := &InvoiceResponse{
+		ID:                 uuid.New(),
+		Kind:               KindCreditNote,
+		Number:             "AV-2026-000001",
+		SourceType:         SourcePayment,
+		CreditNoteOf:       &origin,
+		CreditNoteOfNumber: "FA-2026-000001",
+		CustomerName:       "Amina Benali",
+		CustomerWilaya:     "Oran",
+		TeacherName:        "يوسف حداد",
+		Description:        "Avoir sur facture FA-2026-000001 — Cours de mathématiques",
+		Amount:             1500,
+		PaymentMethod:      "edahabia",
+		Reason:             "Séance annulée",
+		IssuedAt:           time.Date(2026, 3, 1, 23, 30, 0, 0, time.UTC),
+	}
+
+	data, err := render(inv, cfg)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(data, []byte("%PDF-")))
+
+	// Same row, same document.
+	again, err := render(inv, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, data, again)
+}
+
+func TestRender_MissingFonts(t *testing.T) {
+	_, err := render(&InvoiceResponse{Number: "FA-2026-000001"}, config.InvoiceConfig{FontDir: t.TempDir()})
+	assert.Error(t, err)
+}
@@ -0,0 +1,186 @@
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"educonnect/internal/config"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// ─── PDF Rendering ──────────────────────────────────────────────
+// One A4 page, labels in French and Arabic. Everything printed comes
+// from the invoice row, so regenerating a PDF reproduces the original.
+
+const (
+	fontFamily   = "dejavu"
+	fontRegular  = "DejaVuSans.ttf"
+	fontBold     = "DejaVuSans-Bold.ttf"
+	pageWidth    = 210.0
+	margin       = 15.0
+	contentWidth = pageWidth - 2*margin
+)
+
+// algiers is where invoices are dated. Algeria has no DST, so the fixed
+// offset is exact when the zone database is missing.
+var algiers = func() *time.Location {
+	if loc, err := time.LoadLocation("Africa/Algiers"); err == nil {
+		return loc
+	}
+	return time.FixedZone("CET", 3600)
+}()
+
+// label is a bilingual caption.
+func label(fr, ar string) string { return visual(fr + " / " + ar) }
+
+var paymentMethodNames = map[string]string{
+	"ccp_baridimob": "CCP / BaridiMob",
+	"edahabia":      "Edahabia",
+	"cib":           "CIB",
+}
+
+// render draws inv as a PDF.
+func render(inv *InvoiceResponse, issuer config.InvoiceConfig) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", issuer.FontDir)
+	pdf.SetCatalogSort(true) // fonts are kept in a map; sorting keeps the bytes stable
+	pdf.AddUTF8Font(fontFamily, "", fontRegular)
+	pdf.AddUTF8Font(fontFamily, "B", fontBold)
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(true, margin)
+	pdf.SetTitle(inv.Number, true)
+	pdf.SetAuthor(issuer.IssuerName, true)
+	pdf.SetCreationDate(inv.IssuedAt)
+	pdf.SetModificationDate(inv.IssuedAt)
+	pdf.AddPage()
+	if err := pdf.Error(); err != nil {
+		return nil, fmt.Errorf("load fonts from %s: %w", issuer.FontDir, err)
+	}
+
+	// Issuer (left) and document title (right)
+	top := pdf.GetY()
+	pdf.SetFont(fontFamily, "B", 14)
+	pdf.CellFormat(contentWidth/2, 7, issuer.IssuerName, "", 2, "L", false, 0, "")
+	pdf.SetFont(fontFamily, "", 9)
+	for _, line := range []string{
+		issuer.IssuerAddress,
+		prefixed("NIF : ", issuer.IssuerNIF),
+		prefixed("RC : ", issuer.IssuerRC),
+	} {
+		if line != "" {
+			pdf.CellFormat(contentWidth/2, 5, line, "", 2, "L", false, 0, "")
+		}
+	}
+	bottom := pdf.GetY()
+
+	title := label("FACTURE", "فاتورة")
+	if inv.Kind == KindCreditNote {
+		title = label("AVOIR", "إشعار دائن")
+	}
+	pdf.SetXY(margin+contentWidth/2, top)
+	pdf.SetFont(fontFamily, "B", 16)
+	pdf.CellFormat(contentWidth/2, 8, title, "", 2, "R", false, 0, "")
+	pdf.SetFont(fontFamily, "", 10)
+	pdf.CellFormat(contentWidth/2, 6, label("N°", "رقم")+" : "+inv.Number, "", 2, "R", false, 0, "")
+	pdf.CellFormat(contentWidth/2, 6, label("Date", "التاريخ")+" : "+inv.IssuedAt.In(algiers).Format("02/01/2006"), "", 2, "R", false, 0, "")
+	if inv.CreditNoteOfNumber != "" {
+		pdf.CellFormat(contentWidth/2, 6, label("Facture d'origine", "الفاتورة الأصلية")+" : "+inv.CreditNoteOfNumber, "", 2, "R", false, 0, "")
+	}
+	pdf.SetY(math.Max(bottom, pdf.GetY()) + 8)
+
+	// Parties
+	party := func(caption, name, detail string) {
+		pdf.SetFont(fontFamily, "B", 10)
+		pdf.CellFormat(contentWidth, 6, caption, "", 1, "L", false, 0, "")
+		pdf.SetFont(fontFamily, "", 10)
+		pdf.CellFormat(contentWidth, 6, visual(name), "", 1, "L", false, 0, "")
+		if detail != "" {
+			pdf.CellFormat(contentWidth, 6, detail, "", 1, "L", false, 0, "")
+		}
+		pdf.Ln(2)
+	}
+	wilaya := ""
+	if inv.CustomerWilaya != "" {
+		wilaya = label("Wilaya", "الولاية") + " : " + visual(inv.CustomerWilaya)
+	}
+	party(label("Client", "الزبون"), inv.CustomerName, wilaya)
+	if inv.SourceType == SourcePayment {
+		party(label("Enseignant", "الأستاذ"), inv.TeacherName, "")
+	}
+	pdf.Ln(4)
+
+	// Line and total
+	amountWidth := 45.0
+	pdf.SetFillColor(235, 235, 235)
+	pdf.SetFont(fontFamily, "B", 10)
+	pdf.CellFormat(contentWidth-amountWidth, 8, label("Désignation", "البيان"), "1", 0, "L", true, 0, "")
+	pdf.CellFormat(amountWidth, 8, label("Montant", "المبلغ"), "1", 1, "R", true, 0, "")
+
+	pdf.SetFont(fontFamily, "", 10)
+	x, y := pdf.GetXY()
+	pdf.MultiCell(contentWidth-amountWidth, 7, visual(inv.Description), "1", "L", false)
+	h := pdf.GetY() - y
+	pdf.SetXY(x+contentWidth-amountWidth, y)
+	pdf.CellFormat(amountWidth, h, formatAmount(inv.Amount), "1", 1, "R", false, 0, "")
+
+	pdf.SetFont(fontFamily, "B", 11)
+	pdf.CellFormat(contentWidth-amountWidth, 9, label("Total", "المجموع"), "1", 0, "R", false, 0, "")
+	pdf.CellFormat(amountWidth, 9, formatAmount(inv.Amount), "1", 1, "R", false, 0, "")
+	pdf.Ln(6)
+
+	// Settlement
+	pdf.SetFont(fontFamily, "", 10)
+	if name := paymentMethodName(inv.PaymentMethod); name != "" {
+		pdf.CellFormat(contentWidth, 6, label("Mode de paiement", "طريقة الدفع")+" : "+name, "", 1, "L", false, 0, "")
+	}
+	if inv.Reference != "" {
+		pdf.CellFormat(contentWidth, 6, label("Référence", "المرجع")+" : "+inv.Reference, "", 1, "L", false, 0, "")
+	}
+	if inv.Reason != "" {
+		pdf.MultiCell(contentWidth, 6, label("Motif", "السبب")+" : "+visual(inv.Reason), "", "L", false)
+	}
+
+	pdf.SetY(-margin - 8)
+	pdf.SetFont(fontFamily, "", 8)
+	pdf.CellFormat(contentWidth, 5, label("Document généré électroniquement", "وثيقة صادرة إلكترونيا"), "", 0, "C", false, 0, "")
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("render pdf: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// formatAmount writes a DZD amount the French way: 1 500,00 DA.
+func formatAmount(v float64) string {
+	cents := int64(math.Round(math.Abs(v) * 100))
+	whole := fmt.Sprintf("%d", cents/100)
+	var b strings.Builder
+	if v < 0 {
+		b.WriteByte('-')
+	}
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteRune('\u00a0') // non-breaking space
+		}
+		b.WriteRune(r)
+	}
+	return fmt.Sprintf("%s,%02d DA", b.String(), cents%100)
+}
+
+func paymentMethodName(method string) string {
+	if name, ok := paymentMethodNames[method]; ok {
+		return name
+	}
+	return method
+}
+
+func prefixed(prefix, value string) string {
+	if value == "" {
+		return ""
+	}
+	return prefix + value
+}
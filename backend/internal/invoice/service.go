@@ -0,0 +1,477 @@
+package invoice
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"educonnect/internal/config"
+	"educonnect/pkg/database"
+	"educonnect/pkg/storage"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ═══════════════════════════════════════════════════════════════
+// Errors
+// ═══════════════════════════════════════════════════════════════
+
+var (
+	ErrInvoiceNotFound      = errors.New("invoice not found")
+	ErrSourceNotFound       = errors.New("no completed purchase or payment with that ID")
+	ErrNotAuthorized        = errors.New("not authorized")
+	ErrNotAnInvoice         = errors.New("credit notes can only be issued against an invoice")
+	ErrCreditExceedsInvoice = errors.New("credit exceeds what is left on the invoice")
+)
+
+// ═══════════════════════════════════════════════════════════════
+// Service
+// ═══════════════════════════════════════════════════════════════
+
+type Service struct {
+	db      *database.Postgres
+	storage *storage.MinIO
+	cfg     config.InvoiceConfig
+}
+
+func NewService(db *database.Postgres, store *storage.MinIO, cfg config.InvoiceConfig) *Service {
+	return &Service{db: db, storage: store, cfg: cfg}
+}
+
+// ═══════════════════════════════════════════════════════════════
+// Issuing
+// ═══════════════════════════════════════════════════════════════
+// An invoice is numbered and committed first, then rendered and
+// stored. If rendering fails the row stays without a PDF; issuing
+// again (event redelivery) or downloading it renders it then.
+
+// draft is an invoice or credit note about to be numbered.
+type draft struct {
+	kind, sourceType          string
+	sourceID                  uuid.UUID
+	creditNoteOf              *uuid.UUID
+	customerID, teacherID     uuid.UUID
+	customerName, teacherName string
+	customerWilaya            string
+	description               string
+	amount                    float64
+	paymentMethod, reference  string
+	reason                    string
+	issuedBy                  *uuid.UUID
+}
+
+// Issue returns the invoice of a completed wallet purchase or payment,
+// issuing it if needed. It is idempotent.
+func (s *Service) Issue(ctx context.Context, sourceType string, sourceID uuid.UUID, issuedBy *uuid.UUID) (*InvoiceResponse, error) {
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var d *draft
+	switch sourceType {
+	case SourceWalletPurchase:
+		d, err = walletPurchaseDraft(ctx, tx, sourceID)
+	case SourcePayment:
+		d, err = paymentDraft(ctx, tx, sourceID)
+	default:
+		err = ErrSourceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The source row is locked, so this check cannot race another issue.
+	var id uuid.UUID
+	err = tx.QueryRow(ctx,
+		`SELECT id FROM invoices WHERE source_type = $1 AND source_id = $2 AND kind = 'invoice'`,
+		sourceType, sourceID,
+	).Scan(&id)
+	switch {
+	case err == nil:
+		tx.Rollback(ctx)
+		return s.ensurePDF(ctx, id)
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, fmt.Errorf("find invoice: %w", err)
+	}
+
+	d.issuedBy = issuedBy
+	id, err = insertInvoice(ctx, tx, d)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return s.generate(ctx, id)
+}
+
+// IssueCreditNote credits amount of an invoice, or all that is left of
+// it when amount is zero.
+func (s *Service) IssueCreditNote(ctx context.Context, invoiceID uuid.UUID, amount float64, reason string, issuedBy *uuid.UUID) (*InvoiceResponse, error) {
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	inv, err := scanInvoice(tx.QueryRow(ctx,
+		`SELECT `+invoiceColumns+invoiceFrom+` WHERE i.id = $1 FOR UPDATE OF i`, invoiceID,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInvoiceNotFound
+		}
+		return nil, fmt.Errorf("lock invoice: %w", err)
+	}
+	if inv.Kind != KindInvoice {
+		return nil, ErrNotAnInvoice
+	}
+
+	left := math.Round((inv.Amount-inv.CreditedAmount)*100) / 100
+	if amount == 0 {
+		amount = left
+	}
+	if left <= 0 || amount > left {
+		return nil, ErrCreditExceedsInvoice
+	}
+
+	id, err := insertInvoice(ctx, tx, &draft{
+		kind:           KindCreditNote,
+		sourceType:     inv.SourceType,
+		sourceID:       inv.SourceID,
+		creditNoteOf:   &inv.ID,
+		customerID:     inv.CustomerID,
+		customerName:   inv.CustomerName,
+		customerWilaya: inv.CustomerWilaya,
+		teacherID:      inv.TeacherID,
+		teacherName:    inv.TeacherName,
+		description:    "Avoir sur facture " + inv.Number + " — " + inv.Description,
+		amount:         amount,
+		paymentMethod:  inv.PaymentMethod,
+		reference:      inv.Reference,
+		reason:         strings.TrimSpace(reason),
+		issuedBy:       issuedBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return s.generate(ctx, id)
+}
+
+// CreditPaymentRefund issues the credit note for a refunded payment,
+// covering whatever of the refund is not credited yet. It returns nil
+// when the refund is already fully credited.
+func (s *Service) CreditPaymentRefund(ctx context.Context, transactionID uuid.UUID) (*InvoiceResponse, error) {
+	inv, err := s.Issue(ctx, SourcePayment, transactionID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var refunded float64
+	var reason string
+	err = s.db.Pool.QueryRow(ctx,
+		`SELECT refund_amount, COALESCE(refund_reason, '') FROM transactions WHERE id = $1`, transactionID,
+	).Scan(&refunded, &reason)
+	if err != nil {
+		return nil, fmt.Errorf("query refund: %w", err)
+	}
+
+	due := math.Round((math.Min(refunded, inv.Amount)-inv.CreditedAmount)*100) / 100
+	if due <= 0 {
+		return nil, nil
+	}
+	cn, err := s.IssueCreditNote(ctx, inv.ID, due, reason, nil)
+	if errors.Is(err, ErrCreditExceedsInvoice) {
+		return nil, nil // credited concurrently
+	}
+	return cn, err
+}
+
+// Regenerate renders an invoice's PDF again from its row and replaces
+// the stored copy.
+func (s *Service) Regenerate(ctx context.Context, invoiceID uuid.UUID) (*InvoiceResponse, error) {
+	return s.generate(ctx, invoiceID)
+}
+
+// walletPurchaseDraft reads a completed credit purchase under lock. The
+// invoiced amount is what the teacher paid, without the package bonus.
+func walletPurchaseDraft(ctx context.Context, tx pgx.Tx, id uuid.UUID) (*draft, error) {
+	d := draft{kind: KindInvoice, sourceType: SourceWalletPurchase, sourceID: id}
+	var credited, listPrice float64
+	var price *float64
+	err := tx.QueryRow(ctx,
+		`SELECT tw.teacher_id, u.first_name || ' ' || u.last_name, COALESCE(u.wilaya, ''),
+		        wt.description, wt.amount, COALESCE(cp.amount, wt.amount), wt.price,
+		        COALESCE(wt.payment_method, ''), COALESCE(wt.provider_ref, '')
+		 FROM wallet_transactions wt
+		 JOIN teacher_wallets tw ON tw.id = wt.wallet_id
+		 JOIN users u ON u.id = tw.teacher_id
+		 LEFT JOIN credit_packages cp ON cp.id = wt.package_id
+		 WHERE wt.id = $1 AND wt.type = 'purchase' AND wt.status = 'completed'
+		 FOR UPDATE OF wt`, id,
+	).Scan(&d.customerID, &d.customerName, &d.customerWilaya,
+		&d.description, &credited, &listPrice, &price, &d.paymentMethod, &d.reference)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSourceNotFound
+		}
+		return nil, fmt.Errorf("lock purchase: %w", err)
+	}
+	d.teacherID, d.teacherName = d.customerID, d.customerName
+
+	// The purchase records the price paid at the time; the package
+	// itself may have been repriced since.
+	d.amount = math.Min(listPrice, credited)
+	if price != nil {
+		d.amount = *price
+	}
+	return &d, nil
+}
+
+// paymentDraft reads a settled student payment under lock. Refunded
+// payments were completed first and keep their invoice.
+func paymentDraft(ctx context.Context, tx pgx.Tx, id uuid.UUID) (*draft, error) {
+	d := draft{kind: KindInvoice, sourceType: SourcePayment, sourceID: id}
+	err := tx.QueryRow(ctx,
+		`SELECT t.payer_id, p.first_name || ' ' || p.last_name, COALESCE(p.wilaya, ''),
+		        t.payee_id, e.first_name || ' ' || e.last_name,
+		        COALESCE(NULLIF(t.description, ''), 'Cours particuliers — ' || e.first_name || ' ' || e.last_name),
+		        t.amount, t.payment_method::text, COALESCE(t.provider_reference, '')
+		 FROM transactions t
+		 JOIN users p ON p.id = t.payer_id
+		 JOIN users e ON e.id = t.payee_id
+		 WHERE t.id = $1 AND t.status IN ('completed', 'refunded')
+		 FOR UPDATE OF t`, id,
+	).Scan(&d.customerID, &d.customerName, &d.customerWilaya,
+		&d.teacherID, &d.teacherName, &d.description,
+		&d.amount, &d.paymentMethod, &d.reference)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSourceNotFound
+		}
+		return nil, fmt.Errorf("lock payment: %w", err)
+	}
+	return &d, nil
+}
+
+// insertInvoice numbers and records d. The sequence row stays locked
+// until tx ends, so numbers are gapless and in issue order.
+func insertInvoice(ctx context.Context, tx pgx.Tx, d *draft) (uuid.UUID, error) {
+	var year, seq int
+	err := tx.QueryRow(ctx,
+		`INSERT INTO invoice_sequences (kind, year, last_number)
+		 VALUES ($1, EXTRACT(YEAR FROM NOW() AT TIME ZONE 'Africa/Algiers')::int, 1)
+		 ON CONFLICT (kind, year) DO UPDATE SET last_number = invoice_sequences.last_number + 1
+		 RETURNING year, last_number`, d.kind,
+	).Scan(&year, &seq)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("next invoice number: %w", err)
+	}
+
+	id := uuid.New()
+	_, err = tx.Exec(ctx,
+		`INSERT INTO invoices
+		    (id, kind, number, source_type, source_id, credit_note_of,
+		     customer_id, customer_name, customer_wilaya, teacher_id, teacher_name,
+		     description, amount, payment_method, reference, reason, issued_by)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), $10, $11, $12, $13,
+		         NULLIF($14, ''), NULLIF($15, ''), NULLIF($16, ''), $17)`,
+		id, d.kind, FormatNumber(d.kind, year, seq), d.sourceType, d.sourceID, d.creditNoteOf,
+		d.customerID, d.customerName, d.customerWilaya, d.teacherID, d.teacherName,
+		d.description, d.amount, d.paymentMethod, d.reference, d.reason, d.issuedBy,
+	)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("insert invoice: %w", err)
+	}
+	return id, nil
+}
+
+// FormatNumber builds an invoice number: FA-2026-000042 for invoices,
+// AV-2026-000007 for credit notes (avoirs).
+func FormatNumber(kind string, year, seq int) string {
+	prefix := "FA"
+	if kind == KindCreditNote {
+		prefix = "AV"
+	}
+	return fmt.Sprintf("%s-%d-%06d", prefix, year, seq)
+}
+
+// ═══════════════════════════════════════════════════════════════
+// PDF Storage
+// ═══════════════════════════════════════════════════════════════
+
+func objectKey(inv *InvoiceResponse) string {
+	return fmt.Sprintf("invoices/%d/%s.pdf", inv.IssuedAt.In(algiers).Year(), inv.Number)
+}
+
+// generate renders and stores an invoice's PDF, replacing any previous copy.
+func (s *Service) generate(ctx context.Context, id uuid.UUID) (*InvoiceResponse, error) {
+	inv, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := render(inv, s.cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	key := objectKey(inv)
+	if err := s.storage.Upload(ctx, s.storage.BucketDocuments(), key, bytes.NewReader(data), int64(len(data)), "application/pdf"); err != nil {
+		return nil, fmt.Errorf("upload invoice: %w", err)
+	}
+	_, err = s.db.Pool.Exec(ctx,
+		`UPDATE invoices SET object_key = $1, generated_at = NOW() WHERE id = $2`, key, id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("record invoice pdf: %w", err)
+	}
+	return s.get(ctx, id)
+}
+
+// ensurePDF returns an invoice, generating its PDF if it has none yet.
+func (s *Service) ensurePDF(ctx context.Context, id uuid.UUID) (*InvoiceResponse, error) {
+	inv, err := s.get(ctx, id)
+	if err != nil || inv.HasPDF {
+		return inv, err
+	}
+	return s.generate(ctx, id)
+}
+
+// ═══════════════════════════════════════════════════════════════
+// Reading
+// ═══════════════════════════════════════════════════════════════
+
+// ListInvoices returns the invoices and credit notes a user is party to:
+// billed to them, or for their lessons as a teacher.
+func (s *Service) ListInvoices(ctx context.Context, userID string, page, limit int) ([]InvoiceResponse, int64, error) {
+	uid, _ := uuid.Parse(userID)
+	return s.list(ctx, `WHERE i.customer_id = $1 OR i.teacher_id = $1`, []any{uid}, page, limit)
+}
+
+// GetInvoice returns one invoice to a party to it, or to an admin.
+func (s *Service) GetInvoice(ctx context.Context, userID, role, invoiceID string) (*InvoiceResponse, error) {
+	id, err := uuid.Parse(invoiceID)
+	if err != nil {
+		return nil, ErrInvoiceNotFound
+	}
+	inv, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if role != "admin" && inv.CustomerID.String() != userID && inv.TeacherID.String() != userID {
+		return nil, ErrNotAuthorized
+	}
+	return inv, nil
+}
+
+// Download returns an invoice's PDF, rendering it first if needed.
+func (s *Service) Download(ctx context.Context, userID, role, invoiceID string) (*InvoiceResponse, []byte, error) {
+	inv, err := s.GetInvoice(ctx, userID, role, invoiceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !inv.HasPDF {
+		if inv, err = s.generate(ctx, inv.ID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var key string
+	if err := s.db.Pool.QueryRow(ctx, `SELECT object_key FROM invoices WHERE id = $1`, inv.ID).Scan(&key); err != nil {
+		return nil, nil, fmt.Errorf("query object key: %w", err)
+	}
+	obj, err := s.storage.Download(ctx, s.storage.BucketDocuments(), key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("download invoice: %w", err)
+	}
+	defer obj.Close()
+	data, err := io.ReadAll(obj)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read invoice: %w", err)
+	}
+	return inv, data, nil
+}
+
+// AdminListInvoices lists every invoice, optionally of one kind or source.
+func (s *Service) AdminListInvoices(ctx context.Context, kind, sourceType string, page, limit int) ([]InvoiceResponse, int64, error) {
+	return s.list(ctx,
+		`WHERE ($1 = '' OR i.kind::text = $1) AND ($2 = '' OR i.source_type = $2)`,
+		[]any{kind, sourceType}, page, limit)
+}
+
+const invoiceColumns = `i.id, i.kind::text, i.number, i.source_type, i.source_id,
+    i.credit_note_of, COALESCE(o.number, ''),
+    i.customer_id, i.customer_name, COALESCE(i.customer_wilaya, ''), i.teacher_id, i.teacher_name,
+    i.description, i.amount,
+    COALESCE((SELECT SUM(c.amount) FROM invoices c WHERE c.credit_note_of = i.id), 0),
+    COALESCE(i.payment_method, ''), COALESCE(i.reference, ''), COALESCE(i.reason, ''),
+    i.object_key IS NOT NULL, i.generated_at, i.issued_by, i.issued_at`
+
+const invoiceFrom = ` FROM invoices i LEFT JOIN invoices o ON o.id = i.credit_note_of `
+
+func scanInvoice(row pgx.Row) (*InvoiceResponse, error) {
+	var inv InvoiceResponse
+	err := row.Scan(
+		&inv.ID, &inv.Kind, &inv.Number, &inv.SourceType, &inv.SourceID,
+		&inv.CreditNoteOf, &inv.CreditNoteOfNumber,
+		&inv.CustomerID, &inv.CustomerName, &inv.CustomerWilaya, &inv.TeacherID, &inv.TeacherName,
+		&inv.Description, &inv.Amount, &inv.CreditedAmount,
+		&inv.PaymentMethod, &inv.Reference, &inv.Reason,
+		&inv.HasPDF, &inv.GeneratedAt, &inv.IssuedBy, &inv.IssuedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+func (s *Service) get(ctx context.Context, id uuid.UUID) (*InvoiceResponse, error) {
+	inv, err := scanInvoice(s.db.Pool.QueryRow(ctx,
+		`SELECT `+invoiceColumns+invoiceFrom+` WHERE i.id = $1`, id,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInvoiceNotFound
+		}
+		return nil, fmt.Errorf("get invoice: %w", err)
+	}
+	return inv, nil
+}
+
+func (s *Service) list(ctx context.Context, where string, args []any, page, limit int) ([]InvoiceResponse, int64, error) {
+	var total int64
+	if err := s.db.Pool.QueryRow(ctx, `SELECT COUNT(*)`+invoiceFrom+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count invoices: %w", err)
+	}
+
+	n := len(args)
+	rows, err := s.db.Pool.Query(ctx,
+		`SELECT `+invoiceColumns+invoiceFrom+where+
+			fmt.Sprintf(` ORDER BY i.issued_at DESC, i.number DESC LIMIT $%d OFFSET $%d`, n+1, n+2),
+		append(args, limit, (page-1)*limit)...,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query invoices: %w", err)
+	}
+	defer rows.Close()
+
+	invoices := []InvoiceResponse{}
+	for rows.Next() {
+		inv, err := scanInvoice(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan invoice: %w", err)
+		}
+		invoices = append(invoices, *inv)
+	}
+	return invoices, total, rows.Err()
+}
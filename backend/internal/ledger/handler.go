@@ -0,0 +1,40 @@
+package ledger
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ─── Admin Endpoints ────────────────────────────────────────────
+
+// Balances GET /admin/ledger/balances
+func (h *Handler) Balances(c *gin.Context) {
+	b, err := h.service.Balances(c.Request.Context())
+	if err != nil {
+		fmt.Printf("[ERROR] ledger: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"message": "internal server error"}})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": b})
+}
+
+// Reconcile GET /admin/ledger/reconciliation
+func (h *Handler) Reconcile(c *gin.Context) {
+	r, err := h.service.Reconcile(c.Request.Context())
+	if err != nil {
+		fmt.Printf("[ERROR] ledger: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"message": "internal server error"}})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"ok": r.OK(), "report": r}})
+}
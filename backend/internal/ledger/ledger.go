@@ -0,0 +1,182 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ─── Accounts ───────────────────────────────────────────────────
+
+// Kind is the type of a ledger account (see migration 000015).
+type Kind string
+
+const (
+	Cash             Kind = "cash"
+	PendingPurchases Kind = "pending_purchases"
+	TeacherWallet    Kind = "teacher_wallet"
+	TeacherEarnings  Kind = "teacher_earnings"
+	PayoutsPending   Kind = "payouts_pending"
+	PlatformRevenue  Kind = "platform_revenue"
+	Refunds          Kind = "refunds"
+	BonusExpense     Kind = "bonus_expense"
+	OpeningBalance   Kind = "opening_balance"
+)
+
+// Account identifies a ledger account: a system account (no owner) or a
+// per-teacher account.
+type Account struct {
+	Kind  Kind
+	Owner uuid.UUID
+}
+
+// System returns the platform-wide account of the given kind.
+func System(k Kind) Account { return Account{Kind: k} }
+
+// WalletOf is the teacher's prepaid star credit.
+func WalletOf(teacherID uuid.UUID) Account { return Account{Kind: TeacherWallet, Owner: teacherID} }
+
+// EarningsOf is what the platform owes the teacher from student payments.
+func EarningsOf(teacherID uuid.UUID) Account { return Account{Kind: TeacherEarnings, Owner: teacherID} }
+
+// Code is the account's primary key in ledger_accounts.
+func (a Account) Code() string {
+	if a.Owner == uuid.Nil {
+		return string(a.Kind)
+	}
+	return string(a.Kind) + ":" + a.Owner.String()
+}
+
+// ─── Journals ───────────────────────────────────────────────────
+
+var ErrUnbalanced = errors.New("ledger entry does not balance")
+
+// Journal kinds, one per business event that moves money.
+const (
+	JournalPurchaseSubmitted = "purchase_submitted" // cash in, awaiting approval (before migration 000029)
+	JournalPurchaseApproved  = "purchase_approved"  // cash in, credited to the wallet
+	JournalPurchaseRejected  = "purchase_rejected"  // submission returned / never received
+	JournalPurchaseExpired   = "purchase_expired"   // submission never reviewed in time
+	JournalStarDeduction     = "star_deduction"
+	JournalStarRefund        = "star_refund"
+	JournalPaymentCompleted  = "payment_completed"
+	JournalPaymentRefunded   = "payment_refunded"
+	JournalPayoutRequested   = "payout_requested"
+	JournalPayoutRejected    = "payout_rejected"
+	JournalPayoutPaid        = "payout_paid"
+)
+
+// Reference types: the table a journal's reference_id points into.
+const (
+	RefWalletTransaction = "wallet_transaction"
+	RefPayment           = "transaction"
+	RefPayout            = "payout"
+)
+
+// Line is one posting. Amount is a debit when positive and a credit when
+// negative; Debit and Credit build lines without sign mistakes.
+type Line struct {
+	Account Account
+	Amount  float64
+}
+
+func Debit(a Account, amount float64) Line  { return Line{Account: a, Amount: amount} }
+func Credit(a Account, amount float64) Line { return Line{Account: a, Amount: -amount} }
+
+// Entry is a journal: one business event and its balanced postings.
+// (Kind, RefType, RefID) is unique, so an event cannot be posted twice.
+type Entry struct {
+	Kind        string
+	RefType     string
+	RefID       uuid.UUID
+	Description string
+	Lines       []Line
+}
+
+// cents converts an amount to integer cents so balance checks are exact.
+func cents(v float64) int64 { return int64(math.Round(v * 100)) }
+
+// validate drops zero lines and checks that what remains balances.
+func (e Entry) validate() ([]Line, error) {
+	var lines []Line
+	var sum int64
+	for _, l := range e.Lines {
+		c := cents(l.Amount)
+		if c == 0 {
+			continue
+		}
+		sum += c
+		lines = append(lines, Line{Account: l.Account, Amount: float64(c) / 100})
+	}
+	if len(lines) < 2 || sum != 0 {
+		return nil, fmt.Errorf("%w: %s %s/%s", ErrUnbalanced, e.Kind, e.RefType, e.RefID)
+	}
+	return lines, nil
+}
+
+// Post writes a journal inside the caller's transaction, so the ledger
+// commits or rolls back together with the state change it records.
+func Post(ctx context.Context, tx pgx.Tx, e Entry) error {
+	lines, err := e.validate()
+	if err != nil {
+		return err
+	}
+
+	var journalID uuid.UUID
+	err = tx.QueryRow(ctx,
+		`INSERT INTO ledger_journals (kind, reference_type, reference_id, description)
+		 VALUES ($1, $2, $3, $4) RETURNING id`,
+		e.Kind, e.RefType, e.RefID, e.Description,
+	).Scan(&journalID)
+	if err != nil {
+		return fmt.Errorf("post %s journal: %w", e.Kind, err)
+	}
+
+	for _, l := range lines {
+		var owner *uuid.UUID
+		if l.Account.Owner != uuid.Nil {
+			owner = &l.Account.Owner
+		}
+		_, err = tx.Exec(ctx,
+			`INSERT INTO ledger_accounts (code, kind, owner_id) VALUES ($1, $2, $3)
+			 ON CONFLICT (code) DO NOTHING`,
+			l.Account.Code(), string(l.Account.Kind), owner,
+		)
+		if err != nil {
+			return fmt.Errorf("ensure account %s: %w", l.Account.Code(), err)
+		}
+		_, err = tx.Exec(ctx,
+			`INSERT INTO ledger_postings (journal_id, account_code, amount) VALUES ($1, $2, $3)`,
+			journalID, l.Account.Code(), l.Amount,
+		)
+		if err != nil {
+			return fmt.Errorf("post %s line: %w", e.Kind, err)
+		}
+	}
+	return nil
+}
+
+// PostedAmount returns the net amount a journal posted to an account, and
+// false if no such journal exists. Later journals use it to reverse or
+// settle exactly what an earlier one recorded.
+func PostedAmount(ctx context.Context, tx pgx.Tx, kind, refType string, refID uuid.UUID, a Account) (float64, bool, error) {
+	var amount *float64
+	err := tx.QueryRow(ctx,
+		`SELECT SUM(p.amount)
+		 FROM ledger_journals j
+		 JOIN ledger_postings p ON p.journal_id = j.id AND p.account_code = $4
+		 WHERE j.kind = $1 AND j.reference_type = $2 AND j.reference_id = $3`,
+		kind, refType, refID, a.Code(),
+	).Scan(&amount)
+	if err != nil {
+		return 0, false, fmt.Errorf("posted amount: %w", err)
+	}
+	if amount == nil {
+		return 0, false, nil
+	}
+	return *amount, true, nil
+}
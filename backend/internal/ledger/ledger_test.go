@@ -0,0 +1,86 @@
+package ledger
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountCode(t *testing.T) {
+	id := uuid.MustParse("6f1c1f2e-8a1b-4c2d-9e3f-0a1b2c3d4e5f")
+
+	assert.Equal(t, "cash", System(Cash).Code())
+	assert.Equal(t, "teacher_wallet:"+id.String(), WalletOf(id).Code())
+	assert.Equal(t, "teacher_earnings:"+id.String(), EarningsOf(id).Code())
+}
+
+func TestEntryValidate(t *testing.T) {
+	teacher := uuid.New()
+
+	tests := []struct {
+		name    string
+		lines   []Line
+		want    int
+		wantErr bool
+	}{
+		{
+			name:  "balanced",
+			lines: []Line{Debit(WalletOf(teacher), 100), Credit(System(PlatformRevenue), 100)},
+			want:  2,
+		},
+		{
+			name: "balanced three ways",
+			lines: []Line{
+				Debit(System(Cash), 1000),
+				Credit(EarningsOf(teacher), 850),
+				Credit(System(PlatformRevenue), 150),
+			},
+			want: 3,
+		},
+		{
+			name: "zero lines dropped",
+			lines: []Line{
+				Debit(System(PendingPurchases), 500),
+				Debit(System(BonusExpense), 0),
+				Credit(WalletOf(teacher), 500),
+			},
+			want: 2,
+		},
+		{
+			name:  "float noise below a cent",
+			lines: []Line{Debit(System(Cash), 0.1+0.2), Credit(System(PendingPurchases), 0.3)},
+			want:  2,
+		},
+		{
+			name:    "unbalanced",
+			lines:   []Line{Debit(System(Cash), 100), Credit(System(PendingPurchases), 99.99)},
+			wantErr: true,
+		},
+		{
+			name:    "single line",
+			lines:   []Line{Debit(System(Cash), 100)},
+			wantErr: true,
+		},
+		{
+			name:    "only zero lines",
+			lines:   []Line{Debit(System(Cash), 0), Credit(System(Refunds), 0)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := Entry{Kind: JournalStarDeduction, RefType: RefWalletTransaction, RefID: uuid.New(), Lines: tt.lines}.validate()
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, ErrUnbalanced))
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, lines, tt.want)
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package ledger
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"educonnect/pkg/database"
+
+	"github.com/google/uuid"
+)
+
+// ─── Service ────────────────────────────────────────────────────
+
+type Service struct {
+	db *database.Postgres
+}
+
+func NewService(db *database.Postgres) *Service {
+	return &Service{db: db}
+}
+
+// ─── Reconciliation ─────────────────────────────────────────────
+
+// WalletMismatch is a teacher whose wallet row disagrees with the ledger.
+type WalletMismatch struct {
+	TeacherID     uuid.UUID `json:"teacher_id"`
+	WalletBalance float64   `json:"wallet_balance"`
+	LedgerBalance float64   `json:"ledger_balance"`
+	Difference    float64   `json:"difference"`
+}
+
+type ReconciliationReport struct {
+	CheckedAt          time.Time        `json:"checked_at"`
+	WalletsChecked     int              `json:"wallets_checked"`
+	WalletMismatches   []WalletMismatch `json:"wallet_mismatches"`
+	UnbalancedJournals []uuid.UUID      `json:"unbalanced_journals"`
+	TrialBalance       float64          `json:"trial_balance"` // sum of all postings; must be 0
+}
+
+// OK reports whether the books agree everywhere.
+func (r *ReconciliationReport) OK() bool {
+	return len(r.WalletMismatches) == 0 && len(r.UnbalancedJournals) == 0 && cents(r.TrialBalance) == 0
+}
+
+// Reconcile proves teacher_wallets.balance equals the credit balance of each
+// teacher's wallet account, and that every journal balances. Wallets with no
+// ledger account and ledger accounts with no wallet are both compared
+// against zero.
+func (s *Service) Reconcile(ctx context.Context) (*ReconciliationReport, error) {
+	r := &ReconciliationReport{CheckedAt: time.Now(), WalletMismatches: []WalletMismatch{}, UnbalancedJournals: []uuid.UUID{}}
+
+	rows, err := s.db.Pool.Query(ctx,
+		`WITH ledger AS (
+		     SELECT a.owner_id AS teacher_id, -COALESCE(SUM(p.amount), 0) AS balance
+		     FROM ledger_accounts a
+		     LEFT JOIN ledger_postings p ON p.account_code = a.code
+		     WHERE a.kind = 'teacher_wallet'
+		     GROUP BY a.owner_id
+		 )
+		 SELECT COALESCE(w.teacher_id, l.teacher_id), COALESCE(w.balance, 0), COALESCE(l.balance, 0)
+		 FROM teacher_wallets w
+		 FULL JOIN ledger l ON l.teacher_id = w.teacher_id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("compare wallets: %w", err)
+	}
+	for rows.Next() {
+		var m WalletMismatch
+		if err := rows.Scan(&m.TeacherID, &m.WalletBalance, &m.LedgerBalance); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan wallet: %w", err)
+		}
+		r.WalletsChecked++
+		if cents(m.WalletBalance) != cents(m.LedgerBalance) {
+			m.Difference = float64(cents(m.WalletBalance)-cents(m.LedgerBalance)) / 100
+			r.WalletMismatches = append(r.WalletMismatches, m)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate wallets: %w", err)
+	}
+
+	// The commit-time trigger already rejects unbalanced journals; this
+	// catches anything written around it (manual fixes, restores).
+	rows, err = s.db.Pool.Query(ctx,
+		`SELECT journal_id FROM ledger_postings GROUP BY journal_id HAVING SUM(amount) <> 0 LIMIT 100`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("check journals: %w", err)
+	}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan journal: %w", err)
+		}
+		r.UnbalancedJournals = append(r.UnbalancedJournals, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate journals: %w", err)
+	}
+
+	err = s.db.Pool.QueryRow(ctx, `SELECT COALESCE(SUM(amount), 0) FROM ledger_postings`).Scan(&r.TrialBalance)
+	if err != nil {
+		return nil, fmt.Errorf("trial balance: %w", err)
+	}
+	return r, nil
+}
+
+// ─── Account Balances ───────────────────────────────────────────
+
+type KindBalance struct {
+	Kind     string  `json:"kind"`
+	Accounts int     `json:"accounts"`
+	Debit    float64 `json:"debit"`
+	Credit   float64 `json:"credit"`
+	Balance  float64 `json:"balance"` // debit − credit
+}
+
+// Balances totals postings per account kind, a trial balance for finance.
+func (s *Service) Balances(ctx context.Context) ([]KindBalance, error) {
+	rows, err := s.db.Pool.Query(ctx,
+		`SELECT a.kind, COUNT(DISTINCT a.code),
+		        COALESCE(SUM(p.amount) FILTER (WHERE p.amount > 0), 0),
+		        COALESCE(-SUM(p.amount) FILTER (WHERE p.amount < 0), 0),
+		        COALESCE(SUM(p.amount), 0)
+		 FROM ledger_accounts a
+		 LEFT JOIN ledger_postings p ON p.account_code = a.code
+		 GROUP BY a.kind
+		 ORDER BY a.kind`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ledger balances: %w", err)
+	}
+	defer rows.Close()
+
+	results := []KindBalance{}
+	for rows.Next() {
+		var b KindBalance
+		if err := rows.Scan(&b.Kind, &b.Accounts, &b.Debit, &b.Credit, &b.Balance); err != nil {
+			return nil, fmt.Errorf("scan balance: %w", err)
+		}
+		results = append(results, b)
+	}
+	return results, rows.Err()
+}
@@ -0,0 +1,211 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+
+	"educonnect/internal/events"
+	"educonnect/internal/scheduling"
+)
+
+// ─── Event Consumers ────────────────────────────────────────────
+
+// durablePrefix namespaces this package's JetStream consumers.
+const durablePrefix = "notification-"
+
+// RegisterEventHandlers subscribes the in-app notification feed to domain events.
+func (s *Service) RegisterEventHandlers(r *events.Runner) {
+	r.Handle(durablePrefix+"booking-accepted", events.TypeBookingAccepted, 1, s.onBookingAccepted)
+	r.Handle(durablePrefix+"enrollment-accepted", events.TypeEnrollmentAccepted, 1, s.onEnrollmentAccepted)
+	r.Handle(durablePrefix+"enrollment-awaiting-credit", events.TypeEnrollmentAwaitingCredit, 1, s.onEnrollmentAwaitingCredit)
+	r.Handle(durablePrefix+"wallet-purchase-approved", events.TypeWalletPurchaseApproved, 1, s.onWalletPurchaseApproved)
+	r.Handle(durablePrefix+"wallet-purchase-expired", events.TypeWalletPurchaseExpired, 1, s.onWalletPurchaseExpired)
+	r.Handle(durablePrefix+"wallet-low-balance", events.TypeWalletLowBalance, 1, s.onWalletLowBalance)
+	r.Handle(durablePrefix+"payout-updated", events.TypePayoutUpdated, 1, s.onPayoutUpdated)
+	r.Handle(durablePrefix+"homework-graded", events.TypeHomeworkGraded, 1, s.onHomeworkGraded)
+	r.Handle(durablePrefix+"subscription-renewed", events.TypeSubscriptionRenewed, 1, s.onSubscriptionRenewed)
+	r.Handle(durablePrefix+"subscription-expired", events.TypeSubscriptionExpired, 1, s.onSubscriptionExpired)
+	r.Handle(durablePrefix+"package-expired", events.TypePackageExpired, 1, s.onPackageExpired)
+}
+
+func (s *Service) onBookingAccepted(ctx context.Context, env *events.Envelope) error {
+	var e events.BookingAccepted
+	if err := env.Decode(&e); err != nil {
+		return err
+	}
+	// Dated on the student's clock
+	loc, err := scheduling.UserZone(ctx, s.db.Pool, e.StudentID)
+	if err != nil {
+		return err
+	}
+	body := "Votre séance du " + e.StartTime.In(loc).Format("02/01/2006 à 15:04") + " est confirmée."
+	if e.Occurrences > 1 {
+		body = fmt.Sprintf("Vos %d séances à partir du %s sont confirmées.", e.Occurrences, e.StartTime.In(loc).Format("02/01/2006 à 15:04"))
+	}
+	return s.CreateNotification(ctx, e.StudentID,
+		"booking_accepted",
+		"Réservation acceptée",
+		body,
+		map[string]interface{}{"booking_id": e.BookingID, "session_id": e.SessionID, "event_id": env.ID},
+	)
+}
+
+func (s *Service) onEnrollmentAccepted(ctx context.Context, env *events.Envelope) error {
+	var e events.EnrollmentAccepted
+	if err := env.Decode(&e); err != nil {
+		return err
+	}
+	// Notify whoever did not perform the acceptance.
+	recipient, body := e.StudentID, "Votre demande d'inscription à « "+e.SeriesTitle+" » a été acceptée."
+	if e.InitiatedBy == "teacher" {
+		recipient, body = e.TeacherID, "Un élève a accepté votre invitation à « "+e.SeriesTitle+" »."
+	}
+	return s.CreateNotification(ctx, recipient,
+		"enrollment_accepted",
+		"Inscription confirmée",
+		body,
+		map[string]interface{}{"series_id": e.SeriesID, "enrollment_id": e.EnrollmentID, "event_id": env.ID},
+	)
+}
+
+func (s *Service) onEnrollmentAwaitingCredit(ctx context.Context, env *events.Envelope) error {
+	var e events.EnrollmentAwaitingCredit
+	if err := env.Decode(&e); err != nil {
+		return err
+	}
+	data := map[string]interface{}{"series_id": e.SeriesID, "enrollment_id": e.EnrollmentID, "event_id": env.ID}
+	err := s.CreateNotification(ctx, e.TeacherID,
+		"enrollment_awaiting_credit",
+		"Solde insuffisant",
+		"Votre solde ne couvre pas l'étoile d'une nouvelle inscription à « "+e.SeriesTitle+" ». Rechargez votre portefeuille : elle sera confirmée automatiquement.",
+		data,
+	)
+	if err != nil {
+		return err
+	}
+	return s.CreateNotification(ctx, e.StudentID,
+		"enrollment_awaiting_credit",
+		"Inscription en attente",
+		"Votre inscription à « "+e.SeriesTitle+" » est en attente de confirmation par l'enseignant. Vous serez notifié dès qu'elle sera confirmée.",
+		data,
+	)
+}
+
+func (s *Service) onWalletPurchaseApproved(ctx context.Context, env *events.Envelope) error {
+	var e events.WalletPurchaseApproved
+	if err := env.Decode(&e); err != nil {
+		return err
+	}
+	return s.CreateNotification(ctx, e.TeacherID,
+		"wallet_purchase_approved",
+		"Recharge validée",
+		fmt.Sprintf("%.0f DZD ont été ajoutés à votre portefeuille. Nouveau solde : %.0f DZD.", e.Amount, e.BalanceAfter),
+		map[string]interface{}{"transaction_id": e.TransactionID, "event_id": env.ID},
+	)
+}
+
+func (s *Service) onWalletLowBalance(ctx context.Context, env *events.Envelope) error {
+	var e events.WalletLowBalance
+	if err := env.Decode(&e); err != nil {
+		return err
+	}
+	return s.CreateNotification(ctx, e.TeacherID,
+		"wallet_low_balance",
+		"Solde faible",
+		fmt.Sprintf("Votre solde est passé sous %.0f DZD (solde actuel : %.0f DZD). Rechargez votre portefeuille pour continuer à accepter des élèves.", e.Threshold, e.Balance),
+		map[string]interface{}{"wallet_id": e.WalletID, "event_id": env.ID},
+	)
+}
+
+func (s *Service) onWalletPurchaseExpired(ctx context.Context, env *events.Envelope) error {
+	var e events.WalletPurchaseExpired
+	if err := env.Decode(&e); err != nil {
+		return err
+	}
+	return s.CreateNotification(ctx, e.TeacherID,
+		"wallet_purchase_expired",
+		"Recharge expirée",
+		fmt.Sprintf("Votre demande de recharge de %.0f DZD n'a pas pu être validée à temps. Si vous avez effectué le virement, soumettez une nouvelle demande avec votre reçu.", e.Amount),
+		map[string]interface{}{"transaction_id": e.TransactionID, "event_id": env.ID},
+	)
+}
+
+func (s *Service) onPayoutUpdated(ctx context.Context, env *events.Envelope) error {
+	var e events.PayoutUpdated
+	if err := env.Decode(&e); err != nil {
+		return err
+	}
+	var title, body string
+	switch e.Status {
+	case "processing":
+		title, body = "Retrait en cours", fmt.Sprintf("Votre demande de retrait de %.0f DZD a été validée et le virement est en cours.", e.Amount)
+	case "completed":
+		title, body = "Retrait effectué", fmt.Sprintf("Le virement de %.0f DZD a été effectué (référence : %s).", e.Amount, e.Reference)
+	case "failed":
+		title, body = "Retrait refusé", fmt.Sprintf("Votre demande de retrait de %.0f DZD a été refusée : %s", e.Amount, e.Notes)
+	default:
+		return nil
+	}
+	return s.CreateNotification(ctx, e.TeacherID,
+		"payout_"+e.Status,
+		title,
+		body,
+		map[string]interface{}{"payout_id": e.PayoutID, "event_id": env.ID},
+	)
+}
+
+func (s *Service) onHomeworkGraded(ctx context.Context, env *events.Envelope) error {
+	var e events.HomeworkGraded
+	if err := env.Decode(&e); err != nil {
+		return err
+	}
+	return s.CreateNotification(ctx, e.StudentID,
+		"homework_graded",
+		"Devoir corrigé",
+		fmt.Sprintf("Votre devoir a été noté : %g/%g.", e.Grade, e.MaxGrade),
+		map[string]interface{}{"homework_id": e.HomeworkID, "submission_id": e.SubmissionID, "event_id": env.ID},
+	)
+}
+
+func (s *Service) onSubscriptionRenewed(ctx context.Context, env *events.Envelope) error {
+	var e events.SubscriptionRenewed
+	if err := env.Decode(&e); err != nil {
+		return err
+	}
+	return s.CreateNotification(ctx, e.StudentID,
+		"subscription_renewed",
+		"Abonnement renouvelé",
+		fmt.Sprintf("Votre abonnement a été renouvelé du %s au %s. Réglez %.0f DZD sous 3 jours pour le conserver.", e.StartDate, e.EndDate, e.Amount),
+		map[string]interface{}{"subscription_id": e.SubscriptionID, "transaction_id": e.TransactionID, "event_id": env.ID},
+	)
+}
+
+func (s *Service) onSubscriptionExpired(ctx context.Context, env *events.Envelope) error {
+	var e events.SubscriptionExpired
+	if err := env.Decode(&e); err != nil {
+		return err
+	}
+	body := "Votre abonnement est arrivé à échéance. Vous pouvez en souscrire un nouveau à tout moment."
+	if e.Reason == "unpaid" {
+		body = "Votre abonnement a pris fin : le paiement du renouvellement n'a pas été reçu à temps."
+	}
+	return s.CreateNotification(ctx, e.StudentID,
+		"subscription_expired",
+		"Abonnement expiré",
+		body,
+		map[string]interface{}{"subscription_id": e.SubscriptionID, "event_id": env.ID},
+	)
+}
+
+func (s *Service) onPackageExpired(ctx context.Context, env *events.Envelope) error {
+	var e events.PackageExpired
+	if err := env.Decode(&e); err != nil {
+		return err
+	}
+	return s.CreateNotification(ctx, e.StudentID,
+		"package_expired",
+		"Pack de séances expiré",
+		fmt.Sprintf("Votre pack de séances a expiré avec %d séance(s) non utilisée(s).", e.SessionsRemaining),
+		map[string]interface{}{"purchase_id": e.PurchaseID, "package_id": e.PackageID, "event_id": env.ID},
+	)
+}
@@ -0,0 +1,88 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ─── Session Reminders ──────────────────────────────────────────
+
+// SendSessionReminders notifies the teacher, participants and accepted
+// series enrollees of every scheduled session starting within lead, once per
+// session. Users who turned off session reminders are skipped. Returns the
+// number of sessions reminded.
+func (s *Service) SendSessionReminders(ctx context.Context, lead time.Duration) (int, error) {
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx,
+		`UPDATE sessions SET reminder_sent_at = NOW()
+		 WHERE id IN (
+		     SELECT id FROM sessions
+		     WHERE status = 'scheduled' AND reminder_sent_at IS NULL
+		       AND start_time > NOW() AND start_time <= NOW() + make_interval(secs => $1)
+		     FOR UPDATE SKIP LOCKED
+		 )
+		 RETURNING id, title, start_time`,
+		lead.Seconds(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("claim due sessions: %w", err)
+	}
+
+	type due struct {
+		id        uuid.UUID
+		title     string
+		startTime time.Time
+	}
+	var sessions []due
+	for rows.Next() {
+		var d due
+		if err := rows.Scan(&d.id, &d.title, &d.startTime); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan due session: %w", err)
+		}
+		sessions = append(sessions, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("iterate due sessions: %w", err)
+	}
+
+	for _, d := range sessions {
+		minutes := int(time.Until(d.startTime).Round(time.Minute).Minutes())
+		_, err := tx.Exec(ctx,
+			`INSERT INTO notifications (user_id, type, title, body, data, channel)
+			 SELECT r.user_id, 'session_reminder', $2, $3, $4, 'in_app'
+			 FROM (
+			     SELECT teacher_id AS user_id FROM sessions WHERE id = $1
+			     UNION
+			     SELECT student_id FROM session_participants WHERE session_id = $1
+			     UNION
+			     SELECT e.student_id FROM session_enrollments e
+			     JOIN sessions ss ON ss.series_id = e.series_id
+			     WHERE ss.id = $1 AND e.status = 'accepted'
+			 ) r
+			 LEFT JOIN notification_preferences np ON np.user_id = r.user_id
+			 WHERE COALESCE(np.session_reminders, true)`,
+			d.id,
+			"Rappel de séance",
+			fmt.Sprintf("Votre séance « %s » commence dans %d minutes.", d.title, minutes),
+			map[string]interface{}{"session_id": d.id, "start_time": d.startTime},
+		)
+		if err != nil {
+			return 0, fmt.Errorf("insert reminders for session %s: %w", d.id, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit: %w", err)
+	}
+	return len(sessions), nil
+}
@@ -26,6 +26,12 @@ type TransactionResponse struct {
 	Description       *string    `json:"description,omitempty"`
 	RefundAmount      float64    `json:"refund_amount"`
 	RefundReason      *string    `json:"refund_reason,omitempty"`
+	Gateway           *string    `json:"gateway,omitempty"`
+	CheckoutURL       *string    `json:"checkout_url,omitempty"` // where to send the payer while processing
+	FailureReason     *string    `json:"failure_reason,omitempty"`
+	PromotionID       *uuid.UUID `json:"promotion_id,omitempty"`
+	DiscountAmount    float64    `json:"discount_amount"` // already taken off amount
+	PaidAt            *time.Time `json:"paid_at,omitempty"`
 	CreatedAt         time.Time  `json:"created_at"`
 	UpdatedAt         time.Time  `json:"updated_at"`
 }
@@ -35,13 +41,9 @@ type InitiatePaymentRequest struct {
 	SessionID     *uuid.UUID `json:"session_id,omitempty"`
 	CourseID      *uuid.UUID `json:"course_id,omitempty"`
 	Amount        float64    `json:"amount" validate:"required,gt=0"`
-	PaymentMethod string     `json:"payment_method" validate:"required,oneof=ccp_baridimob edahabia"`
+	PaymentMethod string     `json:"payment_method" validate:"required,oneof=ccp_baridimob edahabia cib"`
 	Description   *string    `json:"description,omitempty"`
-}
-
-type ConfirmPaymentRequest struct {
-	TransactionID     uuid.UUID `json:"transaction_id" validate:"required"`
-	ProviderReference string    `json:"provider_reference" validate:"required"`
+	PromoCode     string     `json:"promo_code,omitempty" validate:"omitempty,max=32"`
 }
 
 type RefundPaymentRequest struct {
@@ -53,20 +55,25 @@ type RefundPaymentRequest struct {
 // ─── Subscription ───────────────────────────────────────────────
 
 type SubscriptionResponse struct {
-	ID               uuid.UUID `json:"id"`
-	StudentID        uuid.UUID `json:"student_id"`
-	TeacherID        uuid.UUID `json:"teacher_id"`
-	TeacherName      string    `json:"teacher_name,omitempty"`
-	PlanType         string    `json:"plan_type"`
-	SessionsPerMonth int       `json:"sessions_per_month"`
-	SessionsUsed     int       `json:"sessions_used"`
-	Price            float64   `json:"price"`
-	Status           string    `json:"status"`
-	StartDate        string    `json:"start_date"`
-	EndDate          string    `json:"end_date"`
-	AutoRenew        bool      `json:"auto_renew"`
-	CreatedAt        time.Time `json:"created_at"`
-	UpdatedAt        time.Time `json:"updated_at"`
+	ID                   uuid.UUID  `json:"id"`
+	StudentID            uuid.UUID  `json:"student_id"`
+	TeacherID            uuid.UUID  `json:"teacher_id"`
+	TeacherName          string     `json:"teacher_name,omitempty"`
+	PlanType             string     `json:"plan_type"`
+	SessionsPerMonth     int        `json:"sessions_per_month"`
+	SessionsUsed         int        `json:"sessions_used"`
+	Price                float64    `json:"price"`
+	Status               string     `json:"status"`
+	StartDate            string     `json:"start_date"` // current period
+	EndDate              string     `json:"end_date"`
+	Period               int        `json:"period"` // 1 for the first month, +1 per renewal
+	AutoRenew            bool       `json:"auto_renew"`
+	RenewalMethod        string     `json:"renewal_method"`
+	RenewalTransactionID *uuid.UUID `json:"renewal_transaction_id,omitempty"` // payment due for the current period
+	RenewedAt            *time.Time `json:"renewed_at,omitempty"`
+	PausedAt             *time.Time `json:"paused_at,omitempty"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
 }
 
 type CreateSubscriptionRequest struct {
@@ -77,4 +84,65 @@ type CreateSubscriptionRequest struct {
 	StartDate        string    `json:"start_date" validate:"required"`
 	EndDate          string    `json:"end_date" validate:"required"`
 	AutoRenew        *bool     `json:"auto_renew,omitempty"`
+	RenewalMethod    string    `json:"renewal_method,omitempty" validate:"omitempty,oneof=ccp_baridimob edahabia cib"`
+}
+
+// ─── Session Package ────────────────────────────────────────────
+
+type PackageResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	TeacherID     uuid.UUID  `json:"teacher_id"`
+	OfferingID    *uuid.UUID `json:"offering_id,omitempty"`
+	SubjectName   string     `json:"subject_name,omitempty"`
+	LevelName     string     `json:"level_name,omitempty"`
+	Name          string     `json:"name"`
+	TotalSessions int        `json:"total_sessions"`
+	Price         float64    `json:"price"`
+	ValidDays     int        `json:"valid_days"` // from payment to expiry
+	IsActive      bool       `json:"is_active"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+type CreatePackageRequest struct {
+	OfferingID    uuid.UUID `json:"offering_id" validate:"required"`
+	Name          string    `json:"name" validate:"required,max=255"`
+	TotalSessions int       `json:"total_sessions" validate:"required,min=1"`
+	Price         float64   `json:"price" validate:"required,gt=0"`
+	ValidDays     int       `json:"valid_days,omitempty" validate:"omitempty,min=1"`
+}
+
+// UpdatePackageRequest changes the package for future purchases only.
+type UpdatePackageRequest struct {
+	Name          *string  `json:"name,omitempty" validate:"omitempty,max=255"`
+	TotalSessions *int     `json:"total_sessions,omitempty" validate:"omitempty,min=1"`
+	Price         *float64 `json:"price,omitempty" validate:"omitempty,gt=0"`
+	ValidDays     *int     `json:"valid_days,omitempty" validate:"omitempty,min=1"`
+	IsActive      *bool    `json:"is_active,omitempty"`
+}
+
+type PurchasePackageRequest struct {
+	PaymentMethod string     `json:"payment_method" validate:"required,oneof=ccp_baridimob edahabia cib"`
+	ForChildID    *uuid.UUID `json:"for_child_id,omitempty"` // required when a parent buys
+	PromoCode     string     `json:"promo_code,omitempty" validate:"omitempty,max=32"`
+}
+
+type PackagePurchaseResponse struct {
+	ID                uuid.UUID  `json:"id"`
+	PackageID         uuid.UUID  `json:"package_id"`
+	PackageName       string     `json:"package_name"`
+	TeacherID         uuid.UUID  `json:"teacher_id"`
+	TeacherName       string     `json:"teacher_name"`
+	StudentID         uuid.UUID  `json:"student_id"`
+	StudentName       string     `json:"student_name"`
+	PurchasedBy       *uuid.UUID `json:"purchased_by,omitempty"`
+	TotalSessions     int        `json:"total_sessions"`
+	SessionsRemaining int        `json:"sessions_remaining"`
+	SessionsUsed      int        `json:"sessions_used"`
+	Status            string     `json:"status"` // pending, active, expired, cancelled
+	ExpiresAt         time.Time  `json:"expires_at"`
+	ActivatedAt       *time.Time `json:"activated_at,omitempty"`
+	TransactionID     *uuid.UUID `json:"transaction_id,omitempty"`
+	CheckoutURL       *string    `json:"checkout_url,omitempty"` // set while the purchase awaits payment
+	CreatedAt         time.Time  `json:"created_at"`
 }
@@ -0,0 +1,23 @@
+package payment
+
+import (
+	"context"
+
+	"educonnect/internal/events"
+)
+
+// ─── Event Consumers ────────────────────────────────────────────
+
+// RegisterEventHandlers counts completed sessions against subscriptions.
+func (s *Service) RegisterEventHandlers(r *events.Runner) {
+	r.Handle("payment-subscription-usage", events.TypeSessionEnded, 1, s.onSessionEnded)
+}
+
+func (s *Service) onSessionEnded(ctx context.Context, env *events.Envelope) error {
+	var e events.SessionEnded
+	if err := env.Decode(&e); err != nil {
+		return err
+	}
+	_, err := s.RecordSessionUsage(ctx, e.SessionID)
+	return err
+}
@@ -2,10 +2,12 @@ package payment
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
 	"educonnect/internal/middleware"
+	"educonnect/internal/promotion"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -44,20 +46,10 @@ func (h *Handler) InitiatePayment(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{"success": true, "data": txn})
 }
 
-// ConfirmPayment POST /payments/confirm
-func (h *Handler) ConfirmPayment(c *gin.Context) {
-	var req ConfirmPaymentRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
-		return
-	}
-	if err := h.validate.Struct(req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
-		return
-	}
-
+// GetPayment GET /payments/:id
+func (h *Handler) GetPayment(c *gin.Context) {
 	userID := middleware.GetUserID(c)
-	txn, err := h.service.ConfirmPayment(c.Request.Context(), userID, req)
+	txn, err := h.service.GetPayment(c.Request.Context(), userID, c.Param("id"))
 	if err != nil {
 		handleError(c, err)
 		return
@@ -66,6 +58,34 @@ func (h *Handler) ConfirmPayment(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"success": true, "data": txn})
 }
 
+// GatewayCallback GET|POST /webhooks/payments/:gateway
+//
+// Gateways that redirect the payer's browser (SATIM, the fake gateway) hit
+// this with GET and are sent on to the app's result page; server-to-server
+// callbacks (BaridiMob) POST and get JSON.
+func (h *Handler) GatewayCallback(c *gin.Context) {
+	txn, err := h.service.HandleCallback(c.Request.Context(), c.Param("gateway"), c.Request)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidCallback):
+			c.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": gin.H{"message": "invalid payment callback"}})
+		case errors.Is(err, ErrUnknownGateway), errors.Is(err, ErrTransactionNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+		default:
+			// Non-2xx makes the gateway retry the delivery.
+			fmt.Printf("[ERROR] payment callback: %v\n", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"message": "internal server error"}})
+		}
+		return
+	}
+
+	if c.Request.Method == http.MethodGet {
+		c.Redirect(http.StatusFound, h.service.ResultURL(txn))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"transaction_id": txn.ID, "status": txn.Status}})
+}
+
 // PaymentHistory GET /payments/history
 func (h *Handler) PaymentHistory(c *gin.Context) {
 	userID := middleware.GetUserID(c)
@@ -158,18 +178,164 @@ func (h *Handler) CancelSubscription(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"message": "subscription cancelled"}})
 }
 
+// PauseSubscription PUT /subscriptions/:id/pause
+func (h *Handler) PauseSubscription(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+
+	sub, err := h.service.PauseSubscription(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": sub})
+}
+
+// ResumeSubscription PUT /subscriptions/:id/resume
+func (h *Handler) ResumeSubscription(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+
+	sub, err := h.service.ResumeSubscription(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": sub})
+}
+
+// ─── Session Package Handlers ───────────────────────────────────
+
+// CreatePackage POST /teachers/packages
+func (h *Handler) CreatePackage(c *gin.Context) {
+	var req CreatePackageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+		return
+	}
+
+	pkg, err := h.service.CreatePackage(c.Request.Context(), middleware.GetUserID(c), req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": pkg})
+}
+
+// ListMyPackages GET /teachers/packages
+func (h *Handler) ListMyPackages(c *gin.Context) {
+	pkgs, err := h.service.ListPackages(c.Request.Context(), middleware.GetUserID(c), false)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": pkgs})
+}
+
+// ListTeacherPackages GET /teachers/:id/packages
+func (h *Handler) ListTeacherPackages(c *gin.Context) {
+	pkgs, err := h.service.ListPackages(c.Request.Context(), c.Param("id"), true)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": pkgs})
+}
+
+// UpdatePackage PUT /teachers/packages/:id
+func (h *Handler) UpdatePackage(c *gin.Context) {
+	var req UpdatePackageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+		return
+	}
+
+	pkg, err := h.service.UpdatePackage(c.Request.Context(), middleware.GetUserID(c), c.Param("id"), req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": pkg})
+}
+
+// DeactivatePackage DELETE /teachers/packages/:id
+func (h *Handler) DeactivatePackage(c *gin.Context) {
+	if err := h.service.DeactivatePackage(c.Request.Context(), middleware.GetUserID(c), c.Param("id")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"message": "package withdrawn from sale"}})
+}
+
+// PurchasePackage POST /packages/:id/purchase
+func (h *Handler) PurchasePackage(c *gin.Context) {
+	var req PurchasePackageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+		return
+	}
+
+	purchase, err := h.service.PurchasePackage(c.Request.Context(),
+		middleware.GetUserID(c), middleware.GetUserRole(c), c.Param("id"), req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": purchase})
+}
+
+// ListPackagePurchases GET /packages/purchases
+func (h *Handler) ListPackagePurchases(c *gin.Context) {
+	purchases, err := h.service.ListPackagePurchases(c.Request.Context(),
+		middleware.GetUserID(c), middleware.GetUserRole(c))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": purchases})
+}
+
 func handleError(c *gin.Context, err error) {
 	switch {
-	case errors.Is(err, ErrTransactionNotFound), errors.Is(err, ErrSubscriptionNotFound):
+	case errors.Is(err, ErrTransactionNotFound), errors.Is(err, ErrSubscriptionNotFound),
+		errors.Is(err, ErrPackageNotFound), errors.Is(err, ErrOfferingNotFound):
 		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
 	case errors.Is(err, ErrNotAuthorized):
 		c.JSON(http.StatusForbidden, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
-	case errors.Is(err, ErrAlreadyCancelled), errors.Is(err, ErrAlreadyRefunded):
+	case errors.Is(err, ErrAlreadyCancelled), errors.Is(err, ErrAlreadyRefunded),
+		errors.Is(err, ErrNotActive), errors.Is(err, ErrNotPaused), errors.Is(err, ErrPackageInactive):
 		c.JSON(http.StatusConflict, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
 	case errors.Is(err, ErrNotPending), errors.Is(err, ErrNotCompleted):
 		c.JSON(http.StatusConflict, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
-	case errors.Is(err, ErrInvalidRefund):
+	case errors.Is(err, ErrInvalidRefund), errors.Is(err, ErrChildRequired):
 		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+	case promotion.IsRefusal(err):
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{
+			"code":    "PROMO_NOT_APPLICABLE",
+			"message": err.Error(),
+		}})
+	case errors.Is(err, ErrGatewayUnavailable):
+		fmt.Printf("[ERROR] payment: %v\n", err)
+		c.JSON(http.StatusBadGateway, gin.H{"success": false, "error": gin.H{"message": "payment gateway unavailable, please try again"}})
 	default:
 		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"message": "internal server error"}})
 	}
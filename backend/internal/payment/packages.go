@@ -0,0 +1,456 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"educonnect/internal/events"
+	"educonnect/internal/promotion"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ─── Session Packages ───────────────────────────────────────────
+//
+// A package is a bundle of sessions a teacher sells for one offering.
+// Buying one opens a payment like InitiatePayment; the purchase
+// becomes active when the gateway confirms it (HandleCallback) and
+// lasts valid_days from then. Accepted bookings use its sessions —
+// see booking.AcceptBookingRequest.
+
+const defaultPackageValidDays = 90
+
+var (
+	ErrPackageNotFound  = errors.New("package not found")
+	ErrPackageInactive  = errors.New("package is no longer sold")
+	ErrOfferingNotFound = errors.New("offering not found")
+	ErrChildRequired    = errors.New("for_child_id is required when a parent buys a package")
+)
+
+// CreatePackage adds a package to one of the teacher's offerings.
+func (s *Service) CreatePackage(ctx context.Context, teacherID string, req CreatePackageRequest) (*PackageResponse, error) {
+	uid, _ := uuid.Parse(teacherID)
+
+	var ownerID uuid.UUID
+	err := s.db.Pool.QueryRow(ctx,
+		`SELECT teacher_id FROM offerings WHERE id = $1`, req.OfferingID,
+	).Scan(&ownerID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrOfferingNotFound
+		}
+		return nil, fmt.Errorf("query offering: %w", err)
+	}
+	if ownerID != uid {
+		return nil, ErrNotAuthorized
+	}
+
+	validDays := req.ValidDays
+	if validDays == 0 {
+		validDays = defaultPackageValidDays
+	}
+
+	var id uuid.UUID
+	err = s.db.Pool.QueryRow(ctx,
+		`INSERT INTO packages (teacher_id, offering_id, name, total_sessions, price, valid_days)
+		 VALUES ($1,$2,$3,$4,$5,$6)
+		 RETURNING id`,
+		uid, req.OfferingID, req.Name, req.TotalSessions, req.Price, validDays,
+	).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("insert package: %w", err)
+	}
+
+	return s.getPackage(ctx, id)
+}
+
+// UpdatePackage edits one of the teacher's packages. Purchases already
+// made keep the sessions and validity they were sold with.
+func (s *Service) UpdatePackage(ctx context.Context, teacherID, packageID string, req UpdatePackageRequest) (*PackageResponse, error) {
+	id, err := s.ownPackage(ctx, teacherID, packageID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.db.Pool.Exec(ctx,
+		`UPDATE packages SET
+		    name = COALESCE($2, name),
+		    total_sessions = COALESCE($3, total_sessions),
+		    price = COALESCE($4, price),
+		    valid_days = COALESCE($5, valid_days),
+		    is_active = COALESCE($6, is_active)
+		 WHERE id = $1`,
+		id, req.Name, req.TotalSessions, req.Price, req.ValidDays, req.IsActive,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("update package: %w", err)
+	}
+
+	return s.getPackage(ctx, id)
+}
+
+// DeactivatePackage withdraws a package from sale. Purchases reference
+// it, so it is never deleted.
+func (s *Service) DeactivatePackage(ctx context.Context, teacherID, packageID string) error {
+	id, err := s.ownPackage(ctx, teacherID, packageID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Pool.Exec(ctx, `UPDATE packages SET is_active = false WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("deactivate package: %w", err)
+	}
+	return nil
+}
+
+// ListPackages returns a teacher's packages; students only see the ones
+// on sale.
+func (s *Service) ListPackages(ctx context.Context, teacherID string, activeOnly bool) ([]PackageResponse, error) {
+	uid, err := uuid.Parse(teacherID)
+	if err != nil {
+		return []PackageResponse{}, nil
+	}
+
+	rows, err := s.db.Pool.Query(ctx,
+		`SELECT `+packageColumns+` FROM `+packageFrom+`
+		 WHERE p.teacher_id = $1 AND (NOT $2 OR COALESCE(p.is_active, false))
+		 ORDER BY p.price`, uid, activeOnly,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query packages: %w", err)
+	}
+	defer rows.Close()
+
+	packages := []PackageResponse{}
+	for rows.Next() {
+		var p PackageResponse
+		if err := scanPackage(rows, &p); err != nil {
+			return nil, fmt.Errorf("scan package: %w", err)
+		}
+		packages = append(packages, p)
+	}
+	return packages, rows.Err()
+}
+
+// PurchasePackage orders a package for the buyer — or, for a parent, for
+// one of their children — and opens the checkout that pays for it.
+func (s *Service) PurchasePackage(ctx context.Context, buyerID, role, packageID string, req PurchasePackageRequest) (*PackagePurchaseResponse, error) {
+	uid, _ := uuid.Parse(buyerID)
+	pid, err := uuid.Parse(packageID)
+	if err != nil {
+		return nil, ErrPackageNotFound
+	}
+
+	studentID := uid
+	var purchasedBy *uuid.UUID
+	switch role {
+	case "student":
+	case "parent":
+		if req.ForChildID == nil {
+			return nil, ErrChildRequired
+		}
+		var isParent bool
+		err = s.db.Pool.QueryRow(ctx,
+			`SELECT EXISTS(SELECT 1 FROM student_profiles WHERE user_id = $1 AND parent_id = $2)`,
+			*req.ForChildID, uid,
+		).Scan(&isParent)
+		if err != nil {
+			return nil, fmt.Errorf("verify parent relationship: %w", err)
+		}
+		if !isParent {
+			return nil, ErrNotAuthorized
+		}
+		studentID = *req.ForChildID
+		purchasedBy = &uid
+	default:
+		return nil, ErrNotAuthorized
+	}
+
+	gw, err := s.gateways.ForMethod(req.PaymentMethod)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrGatewayUnavailable, err)
+	}
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var teacherID uuid.UUID
+	var offeringID *uuid.UUID
+	var name string
+	var totalSessions, validDays int
+	var price float64
+	var active bool
+	err = tx.QueryRow(ctx,
+		`SELECT teacher_id, offering_id, name, total_sessions, price,
+		    COALESCE(valid_days, $2), COALESCE(is_active, false)
+		 FROM packages WHERE id = $1`, pid, defaultPackageValidDays,
+	).Scan(&teacherID, &offeringID, &name, &totalSessions, &price, &validDays, &active)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPackageNotFound
+		}
+		return nil, fmt.Errorf("query package: %w", err)
+	}
+	if !active {
+		return nil, ErrPackageInactive
+	}
+
+	// A buy_x_get_y promotion adds sessions; the others lower the price.
+	txnID := uuid.New()
+	discount, err := promotion.Redeem(ctx, tx, promotion.Redemption{
+		Code:          req.PromoCode,
+		TransactionID: txnID,
+		StudentID:     studentID,
+		TeacherID:     teacherID,
+		OfferingID:    offeringID,
+		Amount:        price,
+		Sessions:      totalSessions,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var promotionID *uuid.UUID
+	var discountAmount float64
+	if discount != nil {
+		promotionID = &discount.PromotionID
+		discountAmount = discount.Amount
+		price -= discount.Amount
+		totalSessions += discount.FreeSessions
+	}
+
+	var t TransactionResponse
+	commission := price * commissionRate
+	err = scanTransaction(tx.QueryRow(ctx,
+		`INSERT INTO transactions (id, payer_id, payee_id,
+		    amount, commission, net_amount, payment_method, description, status, gateway,
+		    promotion_id, discount_amount)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7::payment_method,$8,'pending',$9,$10,$11)
+		 RETURNING `+transactionColumns,
+		txnID, uid, teacherID, price, commission, price-commission, req.PaymentMethod,
+		"Pack de séances : "+name, gw.Name(), promotionID, discountAmount,
+	), &t)
+	if err != nil {
+		return nil, fmt.Errorf("insert transaction: %w", err)
+	}
+
+	// expires_at is provisional: validity restarts when the payment lands.
+	var purchaseID uuid.UUID
+	err = tx.QueryRow(ctx,
+		`INSERT INTO package_purchases (package_id, student_id, purchased_by,
+		    total_sessions, sessions_remaining, expires_at, transaction_id)
+		 VALUES ($1,$2,$3,$4,$4,NOW() + make_interval(days => $5),$6)
+		 RETURNING id`,
+		pid, studentID, purchasedBy, totalSessions, validDays, t.ID,
+	).Scan(&purchaseID)
+	if err != nil {
+		return nil, fmt.Errorf("insert package purchase: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+
+	if err := s.openCheckout(ctx, gw, &t); err != nil {
+		if !errors.Is(err, ErrGatewayUnavailable) {
+			return nil, err
+		}
+		s.failCheckout(ctx, t.ID)
+		return nil, err
+	}
+
+	return s.getPackagePurchase(ctx, purchaseID)
+}
+
+// ListPackagePurchases is the remaining-balance view: a student's
+// purchases, those a parent made or their children hold, or for a
+// teacher the purchases of their packages.
+func (s *Service) ListPackagePurchases(ctx context.Context, userID, role string) ([]PackagePurchaseResponse, error) {
+	uid, _ := uuid.Parse(userID)
+
+	var whereClause string
+	switch role {
+	case "teacher":
+		whereClause = "p.teacher_id = $1"
+	case "parent":
+		whereClause = "(pp.purchased_by = $1 OR pp.student_id IN (SELECT user_id FROM student_profiles WHERE parent_id = $1))"
+	default:
+		whereClause = "pp.student_id = $1"
+	}
+
+	rows, err := s.db.Pool.Query(ctx,
+		fmt.Sprintf(`SELECT `+purchaseColumns+` FROM `+purchaseFrom+`
+		 WHERE %s
+		 ORDER BY pp.created_at DESC`, whereClause), uid,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query package purchases: %w", err)
+	}
+	defer rows.Close()
+
+	purchases := []PackagePurchaseResponse{}
+	for rows.Next() {
+		var pp PackagePurchaseResponse
+		if err := scanPackagePurchase(rows, &pp); err != nil {
+			return nil, fmt.Errorf("scan package purchase: %w", err)
+		}
+		purchases = append(purchases, pp)
+	}
+	return purchases, rows.Err()
+}
+
+// ExpirePackagePurchases closes active purchases past their expiry date
+// and returns how many it expired.
+func (s *Service) ExpirePackagePurchases(ctx context.Context) (int, error) {
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx,
+		`UPDATE package_purchases pp SET status = 'expired'
+		 FROM packages p
+		 WHERE p.id = pp.package_id AND pp.status = 'active' AND pp.expires_at <= NOW()
+		 RETURNING pp.id, pp.package_id, pp.student_id, p.teacher_id, pp.sessions_remaining`,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("expire package purchases: %w", err)
+	}
+	var expired []events.PackageExpired
+	for rows.Next() {
+		var e events.PackageExpired
+		if err := rows.Scan(&e.PurchaseID, &e.PackageID, &e.StudentID, &e.TeacherID, &e.SessionsRemaining); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan expired purchase: %w", err)
+		}
+		expired = append(expired, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("expire package purchases: %w", err)
+	}
+
+	for _, e := range expired {
+		if e.SessionsRemaining == 0 {
+			continue // fully used: nothing was lost
+		}
+		if err := events.Enqueue(ctx, tx, e); err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit: %w", err)
+	}
+	return len(expired), nil
+}
+
+// settlePackagePurchase activates the package purchase a transaction paid
+// for, or cancels it when the payment failed. Other transactions match
+// no purchase and are left alone.
+func settlePackagePurchase(ctx context.Context, tx pgx.Tx, transactionID uuid.UUID, paid bool) error {
+	query := `UPDATE package_purchases SET status = 'cancelled'
+		 WHERE transaction_id = $1 AND status = 'pending'`
+	if paid {
+		// Validity runs from the payment, not from the order.
+		query = `UPDATE package_purchases
+		 SET status = 'active', activated_at = NOW(), expires_at = NOW() + (expires_at - created_at)
+		 WHERE transaction_id = $1 AND status = 'pending'`
+	}
+	if _, err := tx.Exec(ctx, query, transactionID); err != nil {
+		return fmt.Errorf("settle package purchase: %w", err)
+	}
+	return nil
+}
+
+// ─── Package Helpers ────────────────────────────────────────────
+
+const packageColumns = `p.id, p.teacher_id, p.offering_id, COALESCE(sub.name_fr, ''), COALESCE(lvl.name, ''),
+    p.name, p.total_sessions, p.price, COALESCE(p.valid_days, 0), COALESCE(p.is_active, false),
+    p.created_at, p.updated_at`
+
+const packageFrom = `packages p
+    LEFT JOIN offerings o ON o.id = p.offering_id
+    LEFT JOIN subjects sub ON sub.id = o.subject_id
+    LEFT JOIN levels lvl ON lvl.id = o.level_id`
+
+func scanPackage(row pgx.Row, p *PackageResponse) error {
+	return row.Scan(
+		&p.ID, &p.TeacherID, &p.OfferingID, &p.SubjectName, &p.LevelName,
+		&p.Name, &p.TotalSessions, &p.Price, &p.ValidDays, &p.IsActive,
+		&p.CreatedAt, &p.UpdatedAt,
+	)
+}
+
+const purchaseColumns = `pp.id, pp.package_id, p.name, p.teacher_id, ut.first_name || ' ' || ut.last_name,
+    pp.student_id, us.first_name || ' ' || us.last_name, pp.purchased_by,
+    pp.total_sessions, pp.sessions_remaining, pp.status::text, pp.expires_at, pp.activated_at,
+    pp.transaction_id, CASE WHEN pp.status = 'pending' THEN t.checkout_url END, pp.created_at`
+
+const purchaseFrom = `package_purchases pp
+    JOIN packages p ON p.id = pp.package_id
+    JOIN users ut ON ut.id = p.teacher_id
+    JOIN users us ON us.id = pp.student_id
+    LEFT JOIN transactions t ON t.id = pp.transaction_id`
+
+func scanPackagePurchase(row pgx.Row, pp *PackagePurchaseResponse) error {
+	err := row.Scan(
+		&pp.ID, &pp.PackageID, &pp.PackageName, &pp.TeacherID, &pp.TeacherName,
+		&pp.StudentID, &pp.StudentName, &pp.PurchasedBy,
+		&pp.TotalSessions, &pp.SessionsRemaining, &pp.Status, &pp.ExpiresAt, &pp.ActivatedAt,
+		&pp.TransactionID, &pp.CheckoutURL, &pp.CreatedAt,
+	)
+	pp.SessionsUsed = pp.TotalSessions - pp.SessionsRemaining
+	return err
+}
+
+func (s *Service) getPackage(ctx context.Context, id uuid.UUID) (*PackageResponse, error) {
+	var p PackageResponse
+	err := scanPackage(s.db.Pool.QueryRow(ctx,
+		`SELECT `+packageColumns+` FROM `+packageFrom+` WHERE p.id = $1`, id,
+	), &p)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPackageNotFound
+		}
+		return nil, fmt.Errorf("query package: %w", err)
+	}
+	return &p, nil
+}
+
+func (s *Service) getPackagePurchase(ctx context.Context, id uuid.UUID) (*PackagePurchaseResponse, error) {
+	var pp PackagePurchaseResponse
+	err := scanPackagePurchase(s.db.Pool.QueryRow(ctx,
+		`SELECT `+purchaseColumns+` FROM `+purchaseFrom+` WHERE pp.id = $1`, id,
+	), &pp)
+	if err != nil {
+		return nil, fmt.Errorf("query package purchase: %w", err)
+	}
+	return &pp, nil
+}
+
+// ownPackage checks the package belongs to the teacher.
+func (s *Service) ownPackage(ctx context.Context, teacherID, packageID string) (uuid.UUID, error) {
+	uid, _ := uuid.Parse(teacherID)
+	id, err := uuid.Parse(packageID)
+	if err != nil {
+		return uuid.Nil, ErrPackageNotFound
+	}
+
+	var ownerID uuid.UUID
+	err = s.db.Pool.QueryRow(ctx, `SELECT teacher_id FROM packages WHERE id = $1`, id).Scan(&ownerID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, ErrPackageNotFound
+		}
+		return uuid.Nil, fmt.Errorf("query package: %w", err)
+	}
+	if ownerID != uid {
+		return uuid.Nil, ErrNotAuthorized
+	}
+	return id, nil
+}
@@ -4,8 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
-
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"educonnect/internal/events"
+	"educonnect/internal/ledger"
+	"educonnect/internal/promotion"
 	"educonnect/pkg/database"
+	"educonnect/pkg/paygate"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -18,92 +26,334 @@ var (
 	ErrSubscriptionNotFound = errors.New("subscription not found")
 	ErrNotAuthorized        = errors.New("not authorized")
 	ErrAlreadyCancelled     = errors.New("subscription already cancelled")
+	ErrNotActive            = errors.New("subscription is not active")
+	ErrNotPaused            = errors.New("subscription is not paused")
 	ErrInvalidRefund        = errors.New("refund amount exceeds transaction amount")
 	ErrAlreadyRefunded      = errors.New("transaction already refunded")
 	ErrNotPending           = errors.New("transaction is not in pending status")
 	ErrNotCompleted         = errors.New("only completed transactions can be refunded")
+	ErrGatewayUnavailable   = errors.New("payment gateway unavailable")
+	ErrUnknownGateway       = errors.New("unknown payment gateway")
+	ErrInvalidCallback      = errors.New("invalid payment callback")
 )
 
 type Service struct {
-	db *database.Postgres
+	db        *database.Postgres
+	gateways  *paygate.Gateways
+	apiURL    string // public base URL gateways call back to
+	returnURL string // app page shown once the payment is settled
 }
 
-func NewService(db *database.Postgres) *Service {
-	return &Service{db: db}
+func NewService(db *database.Postgres, gateways *paygate.Gateways, apiURL, returnURL string) *Service {
+	return &Service{
+		db:        db,
+		gateways:  gateways,
+		apiURL:    strings.TrimSuffix(apiURL, "/"),
+		returnURL: returnURL,
+	}
 }
 
 // ─── InitiatePayment ────────────────────────────────────────────
 
+// InitiatePayment records a pending transaction and opens a checkout with
+// the gateway for its payment method. The payer follows checkout_url;
+// only the gateway's callback can complete the transaction.
+//
+// A promo code — given here or, for a session, when it was booked — or
+// else the payee's best automatic promotion is redeemed against the
+// transaction and taken off its amount.
 func (s *Service) InitiatePayment(ctx context.Context, payerID string, req InitiatePaymentRequest) (*TransactionResponse, error) {
 	uid, _ := uuid.Parse(payerID)
 
-	commission := req.Amount * commissionRate
-	netAmount := req.Amount - commission
+	gw, err := s.gateways.ForMethod(req.PaymentMethod)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrGatewayUnavailable, err)
+	}
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	code := req.PromoCode
+	var offeringID *uuid.UUID
+	if req.SessionID != nil {
+		var bookedCode *string
+		err = tx.QueryRow(ctx,
+			`SELECT s.offering_id,
+			        (SELECT br.promo_code FROM booking_requests br
+			         WHERE br.session_id = s.id AND $2 IN (br.student_id, br.booked_by_parent_id)
+			           AND br.promo_code IS NOT NULL
+			         LIMIT 1)
+			 FROM sessions s WHERE s.id = $1`,
+			*req.SessionID, uid,
+		).Scan(&offeringID, &bookedCode)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("query session: %w", err)
+		}
+		if code == "" && bookedCode != nil {
+			code = *bookedCode
+		}
+	}
+
+	txnID := uuid.New()
+	discount, err := promotion.Redeem(ctx, tx, promotion.Redemption{
+		Code:          code,
+		TransactionID: txnID,
+		StudentID:     uid,
+		TeacherID:     req.PayeeID,
+		OfferingID:    offeringID,
+		Amount:        req.Amount,
+	})
+	if err != nil {
+		return nil, err
+	}
+	amount := req.Amount
+	var promotionID *uuid.UUID
+	var discountAmount float64
+	if discount != nil {
+		promotionID = &discount.PromotionID
+		discountAmount = discount.Amount
+		amount -= discount.Amount
+	}
+
+	commission := amount * commissionRate
+	netAmount := amount - commission
 
 	var t TransactionResponse
-	err := s.db.Pool.QueryRow(ctx,
-		`INSERT INTO transactions (payer_id, payee_id, session_id, course_id,
-		    amount, commission, net_amount, payment_method, description, status)
-		 VALUES ($1,$2,$3,$4,$5,$6,$7,$8::payment_method,$9,'pending')
-		 RETURNING id, payer_id, payee_id, session_id, course_id, subscription_id,
-		    amount, commission, net_amount, payment_method::text, status::text,
-		    provider_reference, description, refund_amount, refund_reason,
-		    created_at, updated_at`,
-		uid, req.PayeeID, req.SessionID, req.CourseID,
-		req.Amount, commission, netAmount, req.PaymentMethod, req.Description,
-	).Scan(
-		&t.ID, &t.PayerID, &t.PayeeID, &t.SessionID, &t.CourseID, &t.SubscriptionID,
-		&t.Amount, &t.Commission, &t.NetAmount, &t.PaymentMethod, &t.Status,
-		&t.ProviderReference, &t.Description, &t.RefundAmount, &t.RefundReason,
-		&t.CreatedAt, &t.UpdatedAt,
-	)
+	err = scanTransaction(tx.QueryRow(ctx,
+		`INSERT INTO transactions (id, payer_id, payee_id, session_id, course_id,
+		    amount, commission, net_amount, payment_method, description, status, gateway,
+		    promotion_id, discount_amount)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9::payment_method,$10,'pending',$11,$12,$13)
+		 RETURNING `+transactionColumns,
+		txnID, uid, req.PayeeID, req.SessionID, req.CourseID,
+		amount, commission, netAmount, req.PaymentMethod, req.Description, gw.Name(),
+		promotionID, discountAmount,
+	), &t)
 	if err != nil {
 		return nil, fmt.Errorf("insert transaction: %w", err)
 	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+
+	if err := s.openCheckout(ctx, gw, &t); err != nil {
+		if !errors.Is(err, ErrGatewayUnavailable) {
+			return nil, err
+		}
+		s.failCheckout(ctx, t.ID)
+		return nil, err
+	}
 
 	s.populateNames(ctx, &t)
 	return &t, nil
 }
 
-// ─── ConfirmPayment ─────────────────────────────────────────────
+// openCheckout opens the gateway checkout for a pending transaction and
+// moves it to processing. On error the transaction is left pending.
+func (s *Service) openCheckout(ctx context.Context, gw paygate.PaymentGateway, t *TransactionResponse) error {
+	description := "Paiement EduConnect"
+	if t.Description != nil && *t.Description != "" {
+		description = *t.Description
+	}
+	checkout, err := gw.Checkout(ctx, paygate.CheckoutRequest{
+		OrderID:     t.ID.String(),
+		Amount:      t.Amount,
+		Description: description,
+		CallbackURL: s.apiURL + "/api/v1/webhooks/payments/" + gw.Name(),
+		ReturnURL:   s.ResultURL(t),
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrGatewayUnavailable, err)
+	}
 
-func (s *Service) ConfirmPayment(ctx context.Context, userID string, req ConfirmPaymentRequest) (*TransactionResponse, error) {
-	// Verify payer owns this transaction and status is pending
-	var dbPayerID uuid.UUID
-	var currentStatus string
-	err := s.db.Pool.QueryRow(ctx,
-		`SELECT payer_id, status::text FROM transactions WHERE id = $1`, req.TransactionID,
-	).Scan(&dbPayerID, &currentStatus)
+	err = scanTransaction(s.db.Pool.QueryRow(ctx,
+		`UPDATE transactions SET status = 'processing', provider_reference = $1, checkout_url = $2
+		 WHERE id = $3 AND status = 'pending'
+		 RETURNING `+transactionColumns,
+		checkout.Reference, checkout.RedirectURL, t.ID,
+	), t)
+	if err != nil {
+		return fmt.Errorf("record checkout: %w", err)
+	}
+	return nil
+}
+
+// failCheckout gives up on a transaction whose checkout could not be
+// opened, releasing what it held. Best effort: a transaction left
+// pending is harmless.
+func (s *Service) failCheckout(ctx context.Context, transactionID uuid.UUID) {
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx,
+		`UPDATE transactions SET status = 'failed', failure_reason = $1 WHERE id = $2`,
+		"checkout failed", transactionID,
+	)
+	if err != nil {
+		return
+	}
+	if err := promotion.Release(ctx, tx, transactionID); err != nil {
+		return
+	}
+	if err := settlePackagePurchase(ctx, tx, transactionID, false); err != nil {
+		return
+	}
+	_ = tx.Commit(ctx)
+}
+
+// ─── Gateway Callback ───────────────────────────────────────────
+
+// HandleCallback settles a transaction from a gateway callback. It is the
+// only path to 'completed'. Callbacks are idempotent: gateways retry, and
+// a transaction that is already settled is returned unchanged.
+func (s *Service) HandleCallback(ctx context.Context, gatewayName string, r *http.Request) (*TransactionResponse, error) {
+	gw, ok := s.gateways.ByName(gatewayName)
+	if !ok {
+		return nil, ErrUnknownGateway
+	}
+	cb, err := gw.VerifyCallback(ctx, r)
+	if err != nil {
+		if errors.Is(err, paygate.ErrInvalidCallback) {
+			return nil, ErrInvalidCallback
+		}
+		return nil, fmt.Errorf("verify %s callback: %w", gatewayName, err)
+	}
+	id, err := uuid.Parse(cb.OrderID)
+	if err != nil {
+		return nil, ErrTransactionNotFound
+	}
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var t TransactionResponse
+	err = scanTransaction(tx.QueryRow(ctx,
+		`SELECT `+transactionColumns+` FROM transactions WHERE id = $1 AND gateway = $2 FOR UPDATE`,
+		id, gw.Name(),
+	), &t)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrTransactionNotFound
 		}
-		return nil, fmt.Errorf("query transaction: %w", err)
+		return nil, fmt.Errorf("lock transaction: %w", err)
 	}
-	if dbPayerID.String() != userID {
-		return nil, ErrNotAuthorized
+	if t.ProviderReference == nil || *t.ProviderReference != cb.Reference {
+		return nil, ErrInvalidCallback
+	}
+	if t.Status != "processing" || cb.Status == paygate.StatusPending {
+		s.populateNames(ctx, &t)
+		return &t, nil
+	}
+
+	switch {
+	case cb.Status == paygate.StatusPaid && cents(cb.Amount) == cents(t.Amount):
+		err = scanTransaction(tx.QueryRow(ctx,
+			`UPDATE transactions SET status = 'completed', paid_at = NOW()
+			 WHERE id = $1 RETURNING `+transactionColumns, t.ID,
+		), &t)
+		if err != nil {
+			return nil, fmt.Errorf("complete transaction: %w", err)
+		}
+
+		// Cash in; the teacher's share is owed to them, the rest is ours
+		// (taken as amount − net so column rounding cannot unbalance it).
+		err = ledger.Post(ctx, tx, ledger.Entry{
+			Kind:        ledger.JournalPaymentCompleted,
+			RefType:     ledger.RefPayment,
+			RefID:       t.ID,
+			Description: "Paiement confirmé par " + gw.Name(),
+			Lines: []ledger.Line{
+				ledger.Debit(ledger.System(ledger.Cash), t.Amount),
+				ledger.Credit(ledger.EarningsOf(t.PayeeID), t.NetAmount),
+				ledger.Credit(ledger.System(ledger.PlatformRevenue), t.Amount-t.NetAmount),
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		err = events.Enqueue(ctx, tx, events.PaymentCompleted{
+			TransactionID: t.ID,
+			PayerID:       t.PayerID,
+			PayeeID:       t.PayeeID,
+			Amount:        t.Amount,
+			Gateway:       gw.Name(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := settlePackagePurchase(ctx, tx, t.ID, true); err != nil {
+			return nil, err
+		}
+	default:
+		reason := "declined by gateway"
+		if cb.Status == paygate.StatusPaid {
+			// Money moved but not the amount we asked for: keep it out of
+			// the books and leave it to an admin.
+			reason = fmt.Sprintf("amount mismatch: gateway reported %.2f", cb.Amount)
+		}
+		err = scanTransaction(tx.QueryRow(ctx,
+			`UPDATE transactions SET status = 'failed', failure_reason = $1
+			 WHERE id = $2 RETURNING `+transactionColumns, reason, t.ID,
+		), &t)
+		if err != nil {
+			return nil, fmt.Errorf("fail transaction: %w", err)
+		}
+		if err := settlePackagePurchase(ctx, tx, t.ID, false); err != nil {
+			return nil, err
+		}
+		if err := promotion.Release(ctx, tx, t.ID); err != nil {
+			return nil, err
+		}
 	}
-	if currentStatus != "pending" {
-		return nil, ErrNotPending
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+
+	s.populateNames(ctx, &t)
+	return &t, nil
+}
+
+// ResultURL is the app page the payer is sent to for a transaction.
+func (s *Service) ResultURL(t *TransactionResponse) string {
+	sep := "?"
+	if strings.Contains(s.returnURL, "?") {
+		sep = "&"
+	}
+	return s.returnURL + sep + url.Values{"transaction_id": {t.ID.String()}, "status": {t.Status}}.Encode()
+}
+
+// ─── GetPayment ─────────────────────────────────────────────────
+
+// GetPayment returns one transaction to its payer or payee, e.g. to poll
+// for the outcome after checkout.
+func (s *Service) GetPayment(ctx context.Context, userID, transactionID string) (*TransactionResponse, error) {
+	uid, _ := uuid.Parse(userID)
+	id, err := uuid.Parse(transactionID)
+	if err != nil {
+		return nil, ErrTransactionNotFound
 	}
 
 	var t TransactionResponse
-	err = s.db.Pool.QueryRow(ctx,
-		`UPDATE transactions SET status = 'completed', provider_reference = $1
-		 WHERE id = $2
-		 RETURNING id, payer_id, payee_id, session_id, course_id, subscription_id,
-		    amount, commission, net_amount, payment_method::text, status::text,
-		    provider_reference, description, refund_amount, refund_reason,
-		    created_at, updated_at`,
-		req.ProviderReference, req.TransactionID,
-	).Scan(
-		&t.ID, &t.PayerID, &t.PayeeID, &t.SessionID, &t.CourseID, &t.SubscriptionID,
-		&t.Amount, &t.Commission, &t.NetAmount, &t.PaymentMethod, &t.Status,
-		&t.ProviderReference, &t.Description, &t.RefundAmount, &t.RefundReason,
-		&t.CreatedAt, &t.UpdatedAt,
-	)
+	err = scanTransaction(s.db.Pool.QueryRow(ctx,
+		`SELECT `+transactionColumns+` FROM transactions WHERE id = $1`, id,
+	), &t)
 	if err != nil {
-		return nil, fmt.Errorf("update transaction: %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTransactionNotFound
+		}
+		return nil, fmt.Errorf("query transaction: %w", err)
+	}
+	if t.PayerID != uid && t.PayeeID != uid {
+		return nil, ErrNotAuthorized
 	}
 
 	s.populateNames(ctx, &t)
@@ -124,17 +374,12 @@ func (s *Service) PaymentHistory(ctx context.Context, userID string, limit, offs
 	}
 
 	rows, err := s.db.Pool.Query(ctx,
-		`SELECT t.id, t.payer_id, t.payee_id, t.session_id, t.course_id, t.subscription_id,
-		    t.amount, t.commission, t.net_amount, t.payment_method::text, t.status::text,
-		    t.provider_reference, t.description, t.refund_amount, t.refund_reason,
-		    t.created_at, t.updated_at,
-		    payer.first_name || ' ' || payer.last_name,
-		    payee.first_name || ' ' || payee.last_name
-		 FROM transactions t
-		 JOIN users payer ON payer.id = t.payer_id
-		 JOIN users payee ON payee.id = t.payee_id
-		 WHERE t.payer_id = $1 OR t.payee_id = $1
-		 ORDER BY t.created_at DESC
+		`SELECT `+transactionColumns+`,
+		    (SELECT first_name || ' ' || last_name FROM users WHERE users.id = payer_id),
+		    (SELECT first_name || ' ' || last_name FROM users WHERE users.id = payee_id)
+		 FROM transactions
+		 WHERE payer_id = $1 OR payee_id = $1
+		 ORDER BY created_at DESC
 		 LIMIT $2 OFFSET $3`, uid, limit, offset,
 	)
 	if err != nil {
@@ -145,13 +390,7 @@ func (s *Service) PaymentHistory(ctx context.Context, userID string, limit, offs
 	var transactions []TransactionResponse
 	for rows.Next() {
 		var t TransactionResponse
-		if err := rows.Scan(
-			&t.ID, &t.PayerID, &t.PayeeID, &t.SessionID, &t.CourseID, &t.SubscriptionID,
-			&t.Amount, &t.Commission, &t.NetAmount, &t.PaymentMethod, &t.Status,
-			&t.ProviderReference, &t.Description, &t.RefundAmount, &t.RefundReason,
-			&t.CreatedAt, &t.UpdatedAt,
-			&t.PayerName, &t.PayeeName,
-		); err != nil {
+		if err := scanTransaction(rows, &t, &t.PayerName, &t.PayeeName); err != nil {
 			return nil, 0, fmt.Errorf("scan transaction: %w", err)
 		}
 		transactions = append(transactions, t)
@@ -193,25 +432,64 @@ func (s *Service) RefundPayment(ctx context.Context, userID string, req RefundPa
 		return nil, ErrInvalidRefund
 	}
 
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
 	var t TransactionResponse
-	err = s.db.Pool.QueryRow(ctx,
+	err = scanTransaction(tx.QueryRow(ctx,
 		`UPDATE transactions SET status = 'refunded', refund_amount = $1, refund_reason = $2
-		 WHERE id = $3
-		 RETURNING id, payer_id, payee_id, session_id, course_id, subscription_id,
-		    amount, commission, net_amount, payment_method::text, status::text,
-		    provider_reference, description, refund_amount, refund_reason,
-		    created_at, updated_at`,
+		 WHERE id = $3 AND status = 'completed'
+		 RETURNING `+transactionColumns,
 		req.Amount, req.Reason, req.TransactionID,
-	).Scan(
-		&t.ID, &t.PayerID, &t.PayeeID, &t.SessionID, &t.CourseID, &t.SubscriptionID,
-		&t.Amount, &t.Commission, &t.NetAmount, &t.PaymentMethod, &t.Status,
-		&t.ProviderReference, &t.Description, &t.RefundAmount, &t.RefundReason,
-		&t.CreatedAt, &t.UpdatedAt,
-	)
+	), &t)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAlreadyRefunded
+		}
 		return nil, fmt.Errorf("update transaction: %w", err)
 	}
 
+	// Refunds come out of the teacher's earnings, matching the payout
+	// balance (net_amount − refund_amount).
+	err = ledger.Post(ctx, tx, ledger.Entry{
+		Kind:        ledger.JournalPaymentRefunded,
+		RefType:     ledger.RefPayment,
+		RefID:       t.ID,
+		Description: "Remboursement du paiement",
+		Lines: []ledger.Line{
+			ledger.Debit(ledger.EarningsOf(t.PayeeID), req.Amount),
+			ledger.Credit(ledger.System(ledger.Cash), req.Amount),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = events.Enqueue(ctx, tx, events.PaymentRefunded{
+		TransactionID: t.ID,
+		PayerID:       t.PayerID,
+		PayeeID:       t.PayeeID,
+		Amount:        req.Amount,
+		Reason:        req.Reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// A refunded package cannot be used any more.
+	_, err = tx.Exec(ctx,
+		`UPDATE package_purchases SET status = 'cancelled'
+		 WHERE transaction_id = $1 AND status IN ('pending', 'active')`, t.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cancel package purchase: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+
 	s.populateNames(ctx, &t)
 	return &t, nil
 }
@@ -226,19 +504,20 @@ func (s *Service) CreateSubscription(ctx context.Context, studentID string, req
 		autoRenew = *req.AutoRenew
 	}
 
+	renewalMethod := req.RenewalMethod
+	if renewalMethod == "" {
+		renewalMethod = defaultRenewalMethod
+	}
+
 	var sub SubscriptionResponse
-	err := s.db.Pool.QueryRow(ctx,
-		`INSERT INTO subscriptions (student_id, teacher_id, plan_type, sessions_per_month, price, start_date, end_date, auto_renew)
-		 VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
-		 RETURNING id, student_id, teacher_id, plan_type, sessions_per_month, sessions_used,
-		    price, status::text, start_date::text, end_date::text, auto_renew, created_at, updated_at`,
+	err := scanSubscription(s.db.Pool.QueryRow(ctx,
+		`INSERT INTO subscriptions AS s (student_id, teacher_id, plan_type, sessions_per_month,
+		    price, start_date, end_date, auto_renew, renewal_method)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9::payment_method)
+		 RETURNING `+subscriptionColumns,
 		uid, req.TeacherID, req.PlanType, req.SessionsPerMonth, req.Price,
-		req.StartDate, req.EndDate, autoRenew,
-	).Scan(
-		&sub.ID, &sub.StudentID, &sub.TeacherID, &sub.PlanType, &sub.SessionsPerMonth,
-		&sub.SessionsUsed, &sub.Price, &sub.Status, &sub.StartDate, &sub.EndDate,
-		&sub.AutoRenew, &sub.CreatedAt, &sub.UpdatedAt,
-	)
+		req.StartDate, req.EndDate, autoRenew, renewalMethod,
+	), &sub)
 	if err != nil {
 		return nil, fmt.Errorf("insert subscription: %w", err)
 	}
@@ -260,9 +539,7 @@ func (s *Service) ListSubscriptions(ctx context.Context, userID string, role str
 	}
 
 	rows, err := s.db.Pool.Query(ctx,
-		fmt.Sprintf(`SELECT s.id, s.student_id, s.teacher_id, s.plan_type, s.sessions_per_month,
-		    s.sessions_used, s.price, s.status::text, s.start_date::text, s.end_date::text,
-		    s.auto_renew, s.created_at, s.updated_at,
+		fmt.Sprintf(`SELECT `+subscriptionColumns+`,
 		    u.first_name || ' ' || u.last_name AS teacher_name
 		 FROM subscriptions s
 		 JOIN users u ON u.id = s.teacher_id
@@ -277,11 +554,7 @@ func (s *Service) ListSubscriptions(ctx context.Context, userID string, role str
 	var subs []SubscriptionResponse
 	for rows.Next() {
 		var sub SubscriptionResponse
-		if err := rows.Scan(
-			&sub.ID, &sub.StudentID, &sub.TeacherID, &sub.PlanType, &sub.SessionsPerMonth,
-			&sub.SessionsUsed, &sub.Price, &sub.Status, &sub.StartDate, &sub.EndDate,
-			&sub.AutoRenew, &sub.CreatedAt, &sub.UpdatedAt, &sub.TeacherName,
-		); err != nil {
+		if err := scanSubscription(rows, &sub, &sub.TeacherName); err != nil {
 			return nil, fmt.Errorf("scan subscription: %w", err)
 		}
 		subs = append(subs, sub)
@@ -330,6 +603,44 @@ func (s *Service) CancelSubscription(ctx context.Context, userID string, subID s
 
 // ─── Helpers ────────────────────────────────────────────────────
 
+const transactionColumns = `id, payer_id, payee_id, session_id, course_id, subscription_id,
+    amount, commission, net_amount, payment_method::text, status::text,
+    provider_reference, description, refund_amount, refund_reason,
+    gateway, checkout_url, failure_reason, promotion_id, discount_amount,
+    paid_at, created_at, updated_at`
+
+// scanTransaction scans transactionColumns, followed by any extra columns.
+func scanTransaction(row pgx.Row, t *TransactionResponse, extra ...any) error {
+	dest := []any{
+		&t.ID, &t.PayerID, &t.PayeeID, &t.SessionID, &t.CourseID, &t.SubscriptionID,
+		&t.Amount, &t.Commission, &t.NetAmount, &t.PaymentMethod, &t.Status,
+		&t.ProviderReference, &t.Description, &t.RefundAmount, &t.RefundReason,
+		&t.Gateway, &t.CheckoutURL, &t.FailureReason, &t.PromotionID, &t.DiscountAmount,
+		&t.PaidAt, &t.CreatedAt, &t.UpdatedAt,
+	}
+	return row.Scan(append(dest, extra...)...)
+}
+
+// subscriptionColumns reads a subscription aliased as s.
+const subscriptionColumns = `s.id, s.student_id, s.teacher_id, s.plan_type, s.sessions_per_month,
+    s.sessions_used, s.price, s.status::text, s.start_date::text, s.end_date::text,
+    s.period, s.auto_renew, s.renewal_method::text, s.renewal_transaction_id,
+    s.renewed_at, s.paused_at, s.created_at, s.updated_at`
+
+// scanSubscription scans subscriptionColumns, followed by any extra columns.
+func scanSubscription(row pgx.Row, sub *SubscriptionResponse, extra ...any) error {
+	dest := []any{
+		&sub.ID, &sub.StudentID, &sub.TeacherID, &sub.PlanType, &sub.SessionsPerMonth,
+		&sub.SessionsUsed, &sub.Price, &sub.Status, &sub.StartDate, &sub.EndDate,
+		&sub.Period, &sub.AutoRenew, &sub.RenewalMethod, &sub.RenewalTransactionID,
+		&sub.RenewedAt, &sub.PausedAt, &sub.CreatedAt, &sub.UpdatedAt,
+	}
+	return row.Scan(append(dest, extra...)...)
+}
+
+// cents compares amounts exactly.
+func cents(v float64) int64 { return int64(math.Round(v * 100)) }
+
 func (s *Service) populateNames(ctx context.Context, t *TransactionResponse) {
 	_ = s.db.Pool.QueryRow(ctx,
 		`SELECT first_name || ' ' || last_name FROM users WHERE id = $1`, t.PayerID,
@@ -0,0 +1,457 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"educonnect/internal/events"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ─── Subscription Lifecycle ─────────────────────────────────────
+//
+// A subscription runs in monthly periods:
+//
+//	active  → active   period over, auto_renew: next period opens with a
+//	                   renewal transaction the student pays by checkout
+//	active  → expired  period over without auto_renew, its renewal not
+//	                   paid within renewalGrace or by the period's end,
+//	                   or the next period already over as well
+//	active ⇄ paused    by the student; resuming pushes end_date back by
+//	                   the days spent paused
+//
+// Every completed session the student attended with the teacher uses
+// one of the period's sessions, until sessions_per_month is reached.
+
+const (
+	renewalGrace           = 72 * time.Hour
+	subscriptionBatchSize  = 200
+	defaultRenewalMethod   = "ccp_baridimob"
+	subscriptionExpiredEnd = "ended"
+	subscriptionExpiredDue = "unpaid"
+)
+
+// SubscriptionLifecycleResult counts what one sweep did.
+type SubscriptionLifecycleResult struct {
+	Renewed   int
+	Expired   int
+	Checkouts int // renewal checkouts opened
+}
+
+// RunSubscriptionLifecycle expires unpaid renewals, closes periods that
+// have ended and opens checkouts for renewals still waiting for one.
+func (s *Service) RunSubscriptionLifecycle(ctx context.Context) (*SubscriptionLifecycleResult, error) {
+	var res SubscriptionLifecycleResult
+
+	n, err := s.expireUnpaidRenewals(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res.Expired += n
+
+	rows, err := s.db.Pool.Query(ctx,
+		`SELECT id FROM subscriptions
+		 WHERE status = 'active' AND end_date < (NOW() AT TIME ZONE 'Africa/Algiers')::date
+		 ORDER BY end_date
+		 LIMIT $1`, subscriptionBatchSize,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list ended subscriptions: %w", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, fmt.Errorf("scan ended subscriptions: %w", err)
+	}
+	for _, id := range ids {
+		renewed, err := s.closePeriod(ctx, id)
+		if err != nil {
+			slog.Warn("subscription lifecycle: close period", "subscription_id", id, "error", err)
+			continue
+		}
+		if renewed {
+			res.Renewed++
+		} else {
+			res.Expired++
+		}
+	}
+
+	res.Checkouts, err = s.openRenewalCheckouts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// expireUnpaidRenewals ends subscriptions whose renewal was not paid
+// within renewalGrace of the period opening.
+func (s *Service) expireUnpaidRenewals(ctx context.Context) (int, error) {
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx,
+		`UPDATE subscriptions s SET status = 'expired'
+		 FROM transactions t
+		 WHERE t.id = s.renewal_transaction_id
+		   AND s.status = 'active' AND t.status IN ('pending', 'processing', 'failed')
+		   AND s.renewed_at <= NOW() - make_interval(secs => $1)
+		 RETURNING s.id, s.student_id, s.teacher_id`, renewalGrace.Seconds(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("expire unpaid renewals: %w", err)
+	}
+	var expired []events.SubscriptionExpired
+	for rows.Next() {
+		e := events.SubscriptionExpired{Reason: subscriptionExpiredDue}
+		if err := rows.Scan(&e.SubscriptionID, &e.StudentID, &e.TeacherID); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan expired subscription: %w", err)
+		}
+		expired = append(expired, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("expire unpaid renewals: %w", err)
+	}
+
+	for _, e := range expired {
+		if err := events.Enqueue(ctx, tx, e); err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit: %w", err)
+	}
+	return len(expired), nil
+}
+
+// closePeriod ends a subscription's elapsed period: it renews it when
+// auto_renew is set and expires it otherwise. A period whose renewal is
+// still unpaid is not renewed again, nor one the sweep reaches after the
+// next period is over: renewing it would only open a period already
+// gone. It reports whether it renewed.
+func (s *Service) closePeriod(ctx context.Context, id uuid.UUID) (bool, error) {
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var sub SubscriptionResponse
+	err = scanSubscription(tx.QueryRow(ctx,
+		`SELECT `+subscriptionColumns+` FROM subscriptions s
+		 WHERE s.id = $1 AND s.status = 'active'
+		   AND s.end_date < (NOW() AT TIME ZONE 'Africa/Algiers')::date
+		 FOR UPDATE`, id,
+	), &sub)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil // closed concurrently
+		}
+		return false, fmt.Errorf("lock subscription: %w", err)
+	}
+
+	var lapsed, unpaid bool
+	err = tx.QueryRow(ctx,
+		`SELECT (s.end_date + INTERVAL '1 month')::date < (NOW() AT TIME ZONE 'Africa/Algiers')::date,
+		        COALESCE(t.status IN ('pending', 'processing', 'failed'), false)
+		 FROM subscriptions s
+		 LEFT JOIN transactions t ON t.id = s.renewal_transaction_id
+		 WHERE s.id = $1`, id,
+	).Scan(&lapsed, &unpaid)
+	if err != nil {
+		return false, fmt.Errorf("check renewal: %w", err)
+	}
+
+	if !sub.AutoRenew || lapsed || unpaid {
+		reason := subscriptionExpiredEnd
+		if unpaid {
+			reason = subscriptionExpiredDue
+		}
+		if _, err := tx.Exec(ctx, `UPDATE subscriptions SET status = 'expired' WHERE id = $1`, id); err != nil {
+			return false, fmt.Errorf("expire subscription: %w", err)
+		}
+		if err := events.Enqueue(ctx, tx, events.SubscriptionExpired{
+			SubscriptionID: sub.ID,
+			StudentID:      sub.StudentID,
+			TeacherID:      sub.TeacherID,
+			Reason:         reason,
+		}); err != nil {
+			return false, err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return false, fmt.Errorf("commit: %w", err)
+		}
+		return false, nil
+	}
+
+	gw, err := s.gateways.ForMethod(sub.RenewalMethod)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrGatewayUnavailable, err)
+	}
+
+	var txnID uuid.UUID
+	commission := sub.Price * commissionRate
+	description := fmt.Sprintf("Renouvellement d'abonnement — période %d", sub.Period+1)
+	err = tx.QueryRow(ctx,
+		`INSERT INTO transactions (payer_id, payee_id, subscription_id,
+		    amount, commission, net_amount, payment_method, description, status, gateway)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7::payment_method,$8,'pending',$9)
+		 RETURNING id`,
+		sub.StudentID, sub.TeacherID, sub.ID,
+		sub.Price, commission, sub.Price-commission, sub.RenewalMethod, description, gw.Name(),
+	).Scan(&txnID)
+	if err != nil {
+		return false, fmt.Errorf("insert renewal transaction: %w", err)
+	}
+
+	// Periods run from the day after the last one ended, for a month.
+	err = scanSubscription(tx.QueryRow(ctx,
+		`UPDATE subscriptions s
+		 SET period = period + 1,
+		     start_date = end_date + 1,
+		     end_date = (end_date + 1 + INTERVAL '1 month' - INTERVAL '1 day')::date,
+		     sessions_used = 0,
+		     renewal_transaction_id = $2,
+		     renewed_at = NOW()
+		 WHERE id = $1
+		 RETURNING `+subscriptionColumns, id, txnID,
+	), &sub)
+	if err != nil {
+		return false, fmt.Errorf("renew subscription: %w", err)
+	}
+
+	if err := events.Enqueue(ctx, tx, events.SubscriptionRenewed{
+		SubscriptionID: sub.ID,
+		StudentID:      sub.StudentID,
+		TeacherID:      sub.TeacherID,
+		TransactionID:  txnID,
+		Period:         sub.Period,
+		Amount:         sub.Price,
+		StartDate:      sub.StartDate,
+		EndDate:        sub.EndDate,
+	}); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit: %w", err)
+	}
+	return true, nil
+}
+
+// openRenewalCheckouts opens the gateway checkout of renewal transactions
+// still pending, so a gateway outage only delays them to the next sweep.
+func (s *Service) openRenewalCheckouts(ctx context.Context) (int, error) {
+	rows, err := s.db.Pool.Query(ctx,
+		`SELECT `+transactionColumns+` FROM transactions
+		 WHERE status = 'pending' AND gateway IS NOT NULL
+		   AND id IN (SELECT renewal_transaction_id FROM subscriptions WHERE status = 'active')
+		 LIMIT $1`, subscriptionBatchSize,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("list pending renewals: %w", err)
+	}
+	var pending []TransactionResponse
+	for rows.Next() {
+		var t TransactionResponse
+		if err := scanTransaction(rows, &t); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan renewal: %w", err)
+		}
+		pending = append(pending, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("list pending renewals: %w", err)
+	}
+
+	opened := 0
+	for i := range pending {
+		t := &pending[i]
+		gw, ok := s.gateways.ByName(*t.Gateway)
+		if !ok {
+			slog.Warn("subscription lifecycle: unknown gateway", "transaction_id", t.ID, "gateway", *t.Gateway)
+			continue
+		}
+		if err := s.openCheckout(ctx, gw, t); err != nil {
+			slog.Warn("subscription lifecycle: open renewal checkout", "transaction_id", t.ID, "error", err)
+			continue
+		}
+		opened++
+	}
+	return opened, nil
+}
+
+// ─── Session Usage ──────────────────────────────────────────────
+
+// RecordSessionUsage counts a completed session against the subscription
+// of each student who attended it (present or late), if the session falls
+// within their current period with the teacher. It is idempotent and
+// returns how many usages it recorded.
+func (s *Service) RecordSessionUsage(ctx context.Context, sessionID uuid.UUID) (int, error) {
+	rows, err := s.db.Pool.Query(ctx,
+		`SELECT sp.student_id, s.teacher_id, (s.start_time AT TIME ZONE 'Africa/Algiers')::date
+		 FROM session_participants sp
+		 JOIN sessions s ON s.id = sp.session_id
+		 WHERE sp.session_id = $1 AND s.status = 'completed'
+		   AND sp.attendance IN ('present', 'late')`, sessionID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("list attendees: %w", err)
+	}
+	type attendee struct {
+		studentID, teacherID uuid.UUID
+		day                  time.Time
+	}
+	var attendees []attendee
+	for rows.Next() {
+		var a attendee
+		if err := rows.Scan(&a.studentID, &a.teacherID, &a.day); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan attendee: %w", err)
+		}
+		attendees = append(attendees, a)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("list attendees: %w", err)
+	}
+
+	recorded := 0
+	for _, a := range attendees {
+		ok, err := s.useSession(ctx, sessionID, a.studentID, a.teacherID, a.day)
+		if err != nil {
+			return recorded, err
+		}
+		if ok {
+			recorded++
+		}
+	}
+	return recorded, nil
+}
+
+// useSession records one student's attendance against their subscription.
+func (s *Service) useSession(ctx context.Context, sessionID, studentID, teacherID uuid.UUID, day time.Time) (bool, error) {
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var subID uuid.UUID
+	var period, used, quota int
+	err = tx.QueryRow(ctx,
+		`SELECT id, period, sessions_used, sessions_per_month FROM subscriptions
+		 WHERE student_id = $1 AND teacher_id = $2 AND status = 'active'
+		   AND $3::date BETWEEN start_date AND end_date
+		 ORDER BY start_date DESC
+		 LIMIT 1
+		 FOR UPDATE`, studentID, teacherID, day,
+	).Scan(&subID, &period, &used, &quota)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil // not subscribed: paid per session
+		}
+		return false, fmt.Errorf("lock subscription: %w", err)
+	}
+	if used >= quota {
+		slog.Info("session beyond subscription quota", "subscription_id", subID, "session_id", sessionID)
+		return false, nil
+	}
+
+	tag, err := tx.Exec(ctx,
+		`INSERT INTO subscription_usages (subscription_id, period, session_id, student_id)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (session_id, student_id) DO NOTHING`,
+		subID, period, sessionID, studentID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("record usage: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil // already counted
+	}
+	if _, err := tx.Exec(ctx,
+		`UPDATE subscriptions SET sessions_used = sessions_used + 1 WHERE id = $1`, subID,
+	); err != nil {
+		return false, fmt.Errorf("count usage: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit: %w", err)
+	}
+	return true, nil
+}
+
+// ─── Pause / Resume ─────────────────────────────────────────────
+
+// PauseSubscription freezes an active subscription: it neither counts
+// sessions nor ends while paused.
+func (s *Service) PauseSubscription(ctx context.Context, userID, subID string) (*SubscriptionResponse, error) {
+	return s.transitionSubscription(ctx, userID, subID, "active", ErrNotActive,
+		`UPDATE subscriptions s SET status = 'paused', paused_at = NOW()
+		 WHERE s.id = $1 RETURNING `+subscriptionColumns)
+}
+
+// ResumeSubscription reactivates a paused subscription, moving its end
+// date back by the days it spent paused.
+func (s *Service) ResumeSubscription(ctx context.Context, userID, subID string) (*SubscriptionResponse, error) {
+	return s.transitionSubscription(ctx, userID, subID, "paused", ErrNotPaused,
+		`UPDATE subscriptions s
+		 SET status = 'active',
+		     end_date = end_date + GREATEST(0, (NOW() AT TIME ZONE 'Africa/Algiers')::date
+		                                      - (paused_at AT TIME ZONE 'Africa/Algiers')::date),
+		     paused_at = NULL
+		 WHERE s.id = $1 RETURNING `+subscriptionColumns)
+}
+
+// transitionSubscription applies update to one of the student's
+// subscriptions if it is in status from.
+func (s *Service) transitionSubscription(ctx context.Context, userID, subID, from string, wrongStatus error, update string) (*SubscriptionResponse, error) {
+	uid, _ := uuid.Parse(userID)
+	sid, err := uuid.Parse(subID)
+	if err != nil {
+		return nil, ErrSubscriptionNotFound
+	}
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var studentID uuid.UUID
+	var status string
+	err = tx.QueryRow(ctx,
+		`SELECT student_id, status::text FROM subscriptions WHERE id = $1 FOR UPDATE`, sid,
+	).Scan(&studentID, &status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("lock subscription: %w", err)
+	}
+	if studentID != uid {
+		return nil, ErrNotAuthorized
+	}
+	if status != from {
+		return nil, wrongStatus
+	}
+
+	var sub SubscriptionResponse
+	if err := scanSubscription(tx.QueryRow(ctx, update, sid), &sub); err != nil {
+		return nil, fmt.Errorf("update subscription: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+
+	s.populateSubNames(ctx, &sub)
+	return &sub, nil
+}
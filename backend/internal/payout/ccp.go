@@ -0,0 +1,77 @@
+package payout
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ═══════════════════════════════════════════════════════════════
+// CCP Account Validation
+// ═══════════════════════════════════════════════════════════════
+// Algérie Poste accounts are written either as a CCP number (up to 10
+// digits) followed by its 2-digit clé, e.g. "1234567 68", or as the 20-digit
+// RIP used by BaridiMob transfers: "00799999" + 10-digit account + 2-digit
+// RIP key. Both carry a checksum, so typos are caught before money moves.
+
+const ripPrefix = "00799999"
+
+var ErrInvalidCCP = errors.New("invalid CCP account number")
+
+// NormalizeCCP validates a CCP number with its clé, or a RIP, and returns the
+// canonical 20-digit RIP.
+func NormalizeCCP(input string) (string, error) {
+	var digits strings.Builder
+	for _, r := range input {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == ' ' || r == '/' || r == '-' || r == '.':
+		default:
+			return "", ErrInvalidCCP
+		}
+	}
+	d := digits.String()
+
+	if len(d) == 20 {
+		if !strings.HasPrefix(d, ripPrefix) || ripKey(d[8:18]) != d[18:] {
+			return "", ErrInvalidCCP
+		}
+		return d, nil
+	}
+
+	// CCP: account (1–10 digits) + clé (2 digits)
+	if len(d) < 3 || len(d) > 12 {
+		return "", ErrInvalidCCP
+	}
+	account, key := d[:len(d)-2], d[len(d)-2:]
+	if ccpKey(account) != key {
+		return "", ErrInvalidCCP
+	}
+	account = fmt.Sprintf("%010s", strings.TrimLeft(account, "0"))
+	if strings.Trim(account, "0") == "" {
+		return "", ErrInvalidCCP
+	}
+	return ripPrefix + account + ripKey(account), nil
+}
+
+// ccpKey computes the CCP clé: digits weighted 4, 5, 6 … from the right,
+// summed, modulo 100.
+func ccpKey(account string) string {
+	sum := 0
+	for i := 0; i < len(account); i++ {
+		d := int(account[len(account)-1-i] - '0')
+		sum += d * (i + 4)
+	}
+	return fmt.Sprintf("%02d", sum%100)
+}
+
+// ripKey computes the RIP key for a 10-digit account:
+// 97 - ((prefix + account) × 100 mod 97).
+func ripKey(account10 string) string {
+	rem := 0
+	for _, c := range ripPrefix + account10 + "00" {
+		rem = (rem*10 + int(c-'0')) % 97
+	}
+	return fmt.Sprintf("%02d", 97-rem)
+}
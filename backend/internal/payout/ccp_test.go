@@ -0,0 +1,43 @@
+package payout
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeCCP(t *testing.T) {
+	const rip = "00799999000123456787"
+
+	cases := []struct {
+		name  string
+		input string
+		want  string
+		err   bool
+	}{
+		{"ccp with space", "1234567 68", rip, false},
+		{"ccp with slash", "1234567/68", rip, false},
+		{"ccp with leading zeros", "0001234567-68", rip, false},
+		{"ccp run together", "123456768", rip, false},
+		{"rip", rip, rip, false},
+		{"rip with spaces", "007 99999 0001234567 87", rip, false},
+		{"wrong clé", "1234567 69", "", true},
+		{"wrong rip key", "00799999000123456788", "", true},
+		{"rip from another bank", "00199999000123456787", "", true},
+		{"letters", "1234567 AB", "", true},
+		{"too short", "12", "", true},
+		{"all zeros", "0000000000 00", "", true},
+		{"empty", "", "", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := NormalizeCCP(tc.input)
+			if tc.err {
+				assert.ErrorIs(t, err, ErrInvalidCCP)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package payout
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ═══════════════════════════════════════════════════════════════
+// Payout Limits
+// ═══════════════════════════════════════════════════════════════
+
+// MinPayoutAmount is the smallest payout a teacher may request (DZD);
+// smaller transfers cost more to process than they are worth.
+const MinPayoutAmount = 1000.0
+
+// ═══════════════════════════════════════════════════════════════
+// Responses
+// ═══════════════════════════════════════════════════════════════
+
+type PayoutResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	TeacherID     uuid.UUID  `json:"teacher_id"`
+	TeacherName   string     `json:"teacher_name,omitempty"`
+	Amount        float64    `json:"amount"`
+	PaymentMethod string     `json:"payment_method"`
+	AccountNumber string     `json:"account_number"` // normalized 20-digit RIP
+	Status        string     `json:"status"`         // pending, processing, completed, failed
+	Reference     string     `json:"reference,omitempty"`
+	AdminNotes    string     `json:"admin_notes,omitempty"`
+	ProcessedAt   *time.Time `json:"processed_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+type BalanceResponse struct {
+	TotalEarnings    float64 `json:"total_earnings"`    // net of commission and refunds
+	TotalPaidOut     float64 `json:"total_paid_out"`    // completed payouts
+	PendingPayouts   float64 `json:"pending_payouts"`   // pending + processing
+	AvailableBalance float64 `json:"available_balance"` // what can still be requested
+	MinPayoutAmount  float64 `json:"min_payout_amount"`
+}
+
+// ═══════════════════════════════════════════════════════════════
+// Requests
+// ═══════════════════════════════════════════════════════════════
+
+type RequestPayoutRequest struct {
+	Amount        float64 `json:"amount" validate:"required,gt=0"`
+	PaymentMethod string  `json:"payment_method" validate:"omitempty,oneof=ccp_baridimob edahabia"`
+	AccountNumber string  `json:"account_number" validate:"required,max=50"` // CCP + clé, or RIP
+}
+
+type AdminPayoutNotesRequest struct {
+	Notes string `json:"notes"`
+}
+
+type AdminRejectPayoutRequest struct {
+	Notes string `json:"notes" validate:"required"`
+}
+
+type AdminMarkPaidRequest struct {
+	Reference string `json:"reference" validate:"required,max=255"`
+	Notes     string `json:"notes"`
+}
@@ -0,0 +1,191 @@
+package payout
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"educonnect/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type Handler struct {
+	service  *Service
+	validate *validator.Validate
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service, validate: validator.New()}
+}
+
+// ═══════════════════════════════════════════════════════════════
+// Teacher Endpoints
+// ═══════════════════════════════════════════════════════════════
+
+// RequestPayout POST /teachers/payouts
+func (h *Handler) RequestPayout(c *gin.Context) {
+	var req RequestPayoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"success": false, "error": gin.H{"message": "validation failed", "details": err.Error()}})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	p, err := h.service.RequestPayout(c.Request.Context(), userID, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": p})
+}
+
+// ListPayouts GET /teachers/payouts
+func (h *Handler) ListPayouts(c *gin.Context) {
+	page, limit := pagination(c)
+	userID := middleware.GetUserID(c)
+
+	payouts, total, err := h.service.ListPayouts(c.Request.Context(), userID, c.Query("status"), page, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    payouts,
+		"meta":    gin.H{"page": page, "limit": limit, "total": total, "has_more": int64(page*limit) < total},
+	})
+}
+
+// GetBalance GET /teachers/payouts/balance
+func (h *Handler) GetBalance(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	b, err := h.service.GetBalance(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": b})
+}
+
+// ═══════════════════════════════════════════════════════════════
+// Admin Endpoints
+// ═══════════════════════════════════════════════════════════════
+
+// AdminListPayouts GET /admin/payouts
+func (h *Handler) AdminListPayouts(c *gin.Context) {
+	page, limit := pagination(c)
+
+	payouts, total, err := h.service.AdminListPayouts(c.Request.Context(), c.Query("status"), page, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    payouts,
+		"meta":    gin.H{"page": page, "limit": limit, "total": total, "has_more": int64(page*limit) < total},
+	})
+}
+
+// AdminApprovePayout PUT /admin/payouts/:id/approve
+func (h *Handler) AdminApprovePayout(c *gin.Context) {
+	var req AdminPayoutNotesRequest
+	// The body is optional here.
+	_ = c.ShouldBindJSON(&req)
+
+	p, err := h.service.AdminApprovePayout(c.Request.Context(), c.Param("id"), middleware.GetUserID(c), req.Notes)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": p})
+}
+
+// AdminRejectPayout PUT /admin/payouts/:id/reject
+func (h *Handler) AdminRejectPayout(c *gin.Context) {
+	var req AdminRejectPayoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"success": false, "error": gin.H{"message": "validation failed", "details": err.Error()}})
+		return
+	}
+
+	p, err := h.service.AdminRejectPayout(c.Request.Context(), c.Param("id"), middleware.GetUserID(c), req.Notes)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": p})
+}
+
+// AdminMarkPaid PUT /admin/payouts/:id/paid
+func (h *Handler) AdminMarkPaid(c *gin.Context) {
+	var req AdminMarkPaidRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"success": false, "error": gin.H{"message": "validation failed", "details": err.Error()}})
+		return
+	}
+
+	p, err := h.service.AdminMarkPaid(c.Request.Context(), c.Param("id"), middleware.GetUserID(c), req.Reference, req.Notes)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": p})
+}
+
+// ═══════════════════════════════════════════════════════════════
+// Helpers
+// ═══════════════════════════════════════════════════════════════
+
+func pagination(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+	return page, limit
+}
+
+func respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrPayoutNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+	case errors.Is(err, ErrNotTeacher):
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+	case errors.Is(err, ErrInvalidCCP):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"success": false, "error": gin.H{
+			"code":    "INVALID_CCP",
+			"message": err.Error(),
+		}})
+	case errors.Is(err, ErrBelowMinimum):
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+	case errors.Is(err, ErrInsufficientEarnings):
+		c.JSON(http.StatusConflict, gin.H{"success": false, "error": gin.H{
+			"code":    "INSUFFICIENT_EARNINGS",
+			"message": err.Error(),
+		}})
+	case errors.Is(err, ErrInvalidTransition):
+		c.JSON(http.StatusConflict, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+	default:
+		fmt.Printf("[ERROR] payout: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"message": "internal server error"}})
+	}
+}
@@ -0,0 +1,377 @@
+package payout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"educonnect/internal/events"
+	"educonnect/internal/ledger"
+	"educonnect/pkg/database"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ═══════════════════════════════════════════════════════════════
+// Errors
+// ═══════════════════════════════════════════════════════════════
+
+var (
+	ErrNotTeacher           = errors.New("only teachers can request payouts")
+	ErrPayoutNotFound       = errors.New("payout not found")
+	ErrBelowMinimum         = fmt.Errorf("payout amount must be at least %.0f DZD", MinPayoutAmount)
+	ErrInsufficientEarnings = errors.New("amount exceeds available earnings")
+	ErrInvalidTransition    = errors.New("payout cannot move to that status")
+)
+
+// ═══════════════════════════════════════════════════════════════
+// Service
+// ═══════════════════════════════════════════════════════════════
+
+type Service struct {
+	db     *database.Postgres
+	events *events.Publisher
+}
+
+func NewService(db *database.Postgres, pub *events.Publisher) *Service {
+	return &Service{db: db, events: pub}
+}
+
+// querier is satisfied by both the pool and a transaction, so the balance
+// can be read either standalone or under the teacher lock.
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// ═══════════════════════════════════════════════════════════════
+// Balance
+// ═══════════════════════════════════════════════════════════════
+// Earnings are completed transactions paid to the teacher, net of
+// commission and of any refunded amount. Pending and processing
+// payouts are reserved against them just like completed ones, so a
+// teacher cannot request the same money twice while an admin is
+// still handling the first request. Failed payouts release it.
+
+func (s *Service) GetBalance(ctx context.Context, teacherID string) (*BalanceResponse, error) {
+	tid, err := uuid.Parse(teacherID)
+	if err != nil {
+		return nil, ErrNotTeacher
+	}
+	return balance(ctx, s.db.Pool, tid)
+}
+
+func balance(ctx context.Context, q querier, teacherID uuid.UUID) (*BalanceResponse, error) {
+	var b BalanceResponse
+	err := q.QueryRow(ctx,
+		`SELECT
+		    (SELECT COALESCE(SUM(net_amount - COALESCE(refund_amount, 0)), 0)
+		     FROM transactions WHERE payee_id = $1 AND status IN ('completed', 'refunded')),
+		    (SELECT COALESCE(SUM(amount) FILTER (WHERE status = 'completed'), 0)
+		     FROM payouts WHERE teacher_id = $1),
+		    (SELECT COALESCE(SUM(amount) FILTER (WHERE status IN ('pending', 'processing')), 0)
+		     FROM payouts WHERE teacher_id = $1)`, teacherID,
+	).Scan(&b.TotalEarnings, &b.TotalPaidOut, &b.PendingPayouts)
+	if err != nil {
+		return nil, fmt.Errorf("payout balance: %w", err)
+	}
+	b.AvailableBalance = math.Max(0, round2(b.TotalEarnings-b.TotalPaidOut-b.PendingPayouts))
+	b.MinPayoutAmount = MinPayoutAmount
+	return &b, nil
+}
+
+// ═══════════════════════════════════════════════════════════════
+// Request Payout (teacher)
+// ═══════════════════════════════════════════════════════════════
+
+func (s *Service) RequestPayout(ctx context.Context, teacherID string, req RequestPayoutRequest) (*PayoutResponse, error) {
+	tid, err := uuid.Parse(teacherID)
+	if err != nil {
+		return nil, ErrNotTeacher
+	}
+	amount := round2(req.Amount)
+	if amount < MinPayoutAmount {
+		return nil, ErrBelowMinimum
+	}
+	account, err := NormalizeCCP(req.AccountNumber)
+	if err != nil {
+		return nil, err
+	}
+	method := req.PaymentMethod
+	if method == "" {
+		method = "ccp_baridimob"
+	}
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Teachers have no balance row, so the profile row serializes
+	// concurrent requests from the same teacher.
+	var locked uuid.UUID
+	err = tx.QueryRow(ctx,
+		`SELECT user_id FROM teacher_profiles WHERE user_id = $1 FOR UPDATE`, tid,
+	).Scan(&locked)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotTeacher
+		}
+		return nil, fmt.Errorf("lock teacher: %w", err)
+	}
+
+	bal, err := balance(ctx, tx, tid)
+	if err != nil {
+		return nil, err
+	}
+	if amount > bal.AvailableBalance {
+		return nil, ErrInsufficientEarnings
+	}
+
+	payoutID := uuid.New()
+	_, err = tx.Exec(ctx,
+		`INSERT INTO payouts (id, teacher_id, amount, payment_method, account_number, status)
+		 VALUES ($1, $2, $3, $4, $5, 'pending')`,
+		payoutID, tid, amount, method, account,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("insert payout: %w", err)
+	}
+
+	err = ledger.Post(ctx, tx, ledger.Entry{
+		Kind:        ledger.JournalPayoutRequested,
+		RefType:     ledger.RefPayout,
+		RefID:       payoutID,
+		Description: "Demande de retrait",
+		Lines: []ledger.Line{
+			ledger.Debit(ledger.EarningsOf(tid), amount),
+			ledger.Credit(ledger.System(ledger.PayoutsPending), amount),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := events.Enqueue(ctx, tx, events.PayoutRequested{
+		PayoutID:  payoutID,
+		TeacherID: tid,
+		Amount:    amount,
+	}); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+
+	return s.getPayout(ctx, payoutID)
+}
+
+// ═══════════════════════════════════════════════════════════════
+// Admin Approve / Reject / Mark Paid
+// ═══════════════════════════════════════════════════════════════
+//
+//	pending    → processing  (approve: transfer is being made)
+//	processing → completed   (mark paid: requires the transfer reference)
+//	pending    → failed      (reject)
+//	processing → failed      (reject: the transfer bounced)
+
+func (s *Service) AdminApprovePayout(ctx context.Context, payoutID, adminID, notes string) (*PayoutResponse, error) {
+	return s.transition(ctx, payoutID, adminID, []string{"pending"}, "processing", "", notes)
+}
+
+func (s *Service) AdminRejectPayout(ctx context.Context, payoutID, adminID, notes string) (*PayoutResponse, error) {
+	return s.transition(ctx, payoutID, adminID, []string{"pending", "processing"}, "failed", "", notes)
+}
+
+func (s *Service) AdminMarkPaid(ctx context.Context, payoutID, adminID, reference, notes string) (*PayoutResponse, error) {
+	return s.transition(ctx, payoutID, adminID, []string{"processing"}, "completed", reference, notes)
+}
+
+func (s *Service) transition(ctx context.Context, payoutID, adminID string, from []string, to, reference, notes string) (*PayoutResponse, error) {
+	pid, err := uuid.Parse(payoutID)
+	if err != nil {
+		return nil, ErrPayoutNotFound
+	}
+	aid, _ := uuid.Parse(adminID)
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the payout row
+	var teacherID uuid.UUID
+	var status string
+	var amount float64
+	err = tx.QueryRow(ctx,
+		`SELECT teacher_id, status::text, amount FROM payouts WHERE id = $1 FOR UPDATE`, pid,
+	).Scan(&teacherID, &status, &amount)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPayoutNotFound
+		}
+		return nil, fmt.Errorf("lock payout: %w", err)
+	}
+	allowed := false
+	for _, f := range from {
+		if status == f {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, ErrInvalidTransition
+	}
+
+	_, err = tx.Exec(ctx,
+		`UPDATE payouts
+		 SET status       = $2,
+		     admin_id     = $3,
+		     admin_notes  = COALESCE(NULLIF($4, ''), admin_notes),
+		     reference    = COALESCE(NULLIF($5, ''), reference),
+		     processed_at = CASE WHEN $2 IN ('completed', 'failed') THEN NOW() ELSE processed_at END,
+		     updated_at   = NOW()
+		 WHERE id = $1`,
+		pid, to, aid, notes, reference,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("update payout: %w", err)
+	}
+
+	// Approval moves no money; paying or rejecting settles payouts_pending.
+	switch to {
+	case "completed":
+		err = ledger.Post(ctx, tx, ledger.Entry{
+			Kind:        ledger.JournalPayoutPaid,
+			RefType:     ledger.RefPayout,
+			RefID:       pid,
+			Description: "Retrait effectué — " + reference,
+			Lines: []ledger.Line{
+				ledger.Debit(ledger.System(ledger.PayoutsPending), amount),
+				ledger.Credit(ledger.System(ledger.Cash), amount),
+			},
+		})
+	case "failed":
+		err = ledger.Post(ctx, tx, ledger.Entry{
+			Kind:        ledger.JournalPayoutRejected,
+			RefType:     ledger.RefPayout,
+			RefID:       pid,
+			Description: "Retrait refusé",
+			Lines: []ledger.Line{
+				ledger.Debit(ledger.System(ledger.PayoutsPending), amount),
+				ledger.Credit(ledger.EarningsOf(teacherID), amount),
+			},
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := events.Enqueue(ctx, tx, events.PayoutUpdated{
+		PayoutID:  pid,
+		TeacherID: teacherID,
+		AdminID:   aid,
+		Amount:    amount,
+		Status:    to,
+		Reference: reference,
+		Notes:     notes,
+	}); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+
+	return s.getPayout(ctx, pid)
+}
+
+// ═══════════════════════════════════════════════════════════════
+// Listings
+// ═══════════════════════════════════════════════════════════════
+
+// ListPayouts returns the teacher's payout history, newest first.
+func (s *Service) ListPayouts(ctx context.Context, teacherID, status string, page, limit int) ([]PayoutResponse, int64, error) {
+	tid, _ := uuid.Parse(teacherID)
+	return s.list(ctx, &tid, status, page, limit)
+}
+
+// AdminListPayouts returns payouts across teachers, optionally by status.
+func (s *Service) AdminListPayouts(ctx context.Context, status string, page, limit int) ([]PayoutResponse, int64, error) {
+	return s.list(ctx, nil, status, page, limit)
+}
+
+func (s *Service) list(ctx context.Context, teacherID *uuid.UUID, status string, page, limit int) ([]PayoutResponse, int64, error) {
+	offset := (page - 1) * limit
+
+	where := `WHERE ($1::uuid IS NULL OR p.teacher_id = $1) AND ($2 = '' OR p.status::text = $2)`
+
+	var total int64
+	err := s.db.Pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM payouts p `+where, teacherID, status,
+	).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count payouts: %w", err)
+	}
+
+	rows, err := s.db.Pool.Query(ctx,
+		payoutSelect+` `+where+` ORDER BY p.created_at DESC LIMIT $3 OFFSET $4`,
+		teacherID, status, limit, offset,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list payouts: %w", err)
+	}
+	defer rows.Close()
+
+	var results []PayoutResponse
+	for rows.Next() {
+		p, err := scanPayout(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan payout: %w", err)
+		}
+		results = append(results, *p)
+	}
+	return results, total, rows.Err()
+}
+
+// ═══════════════════════════════════════════════════════════════
+// Helpers
+// ═══════════════════════════════════════════════════════════════
+
+const payoutSelect = `SELECT p.id, p.teacher_id, u.first_name || ' ' || u.last_name, p.amount,
+	        p.payment_method::text, COALESCE(p.account_number,''), p.status::text,
+	        COALESCE(p.reference,''), COALESCE(p.admin_notes,''), p.processed_at,
+	        p.created_at, p.updated_at
+	 FROM payouts p
+	 JOIN users u ON u.id = p.teacher_id`
+
+func scanPayout(row pgx.Row) (*PayoutResponse, error) {
+	var p PayoutResponse
+	err := row.Scan(
+		&p.ID, &p.TeacherID, &p.TeacherName, &p.Amount,
+		&p.PaymentMethod, &p.AccountNumber, &p.Status,
+		&p.Reference, &p.AdminNotes, &p.ProcessedAt,
+		&p.CreatedAt, &p.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (s *Service) getPayout(ctx context.Context, id uuid.UUID) (*PayoutResponse, error) {
+	p, err := scanPayout(s.db.Pool.QueryRow(ctx, payoutSelect+` WHERE p.id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPayoutNotFound
+		}
+		return nil, fmt.Errorf("get payout: %w", err)
+	}
+	return p, nil
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
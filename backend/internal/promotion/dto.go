@@ -0,0 +1,78 @@
+package promotion
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Promotion types
+const (
+	TypePercentage = "percentage"  // value % off
+	TypeFixed      = "fixed"       // value DZD off
+	TypeBuyXGetY   = "buy_x_get_y" // free_quantity extra sessions per buy_quantity bought (packages)
+)
+
+type PromotionResponse struct {
+	ID              uuid.UUID  `json:"id"`
+	TeacherID       uuid.UUID  `json:"teacher_id"`
+	Name            string     `json:"name"`
+	Code            *string    `json:"code,omitempty"` // nil: applied automatically
+	Type            string     `json:"type"`
+	Value           float64    `json:"value"`
+	BuyQuantity     *int       `json:"buy_quantity,omitempty"`
+	FreeQuantity    *int       `json:"free_quantity,omitempty"`
+	OfferingID      *uuid.UUID `json:"offering_id,omitempty"` // nil: any offering
+	StartDate       time.Time  `json:"start_date"`
+	EndDate         time.Time  `json:"end_date"`
+	IsActive        bool       `json:"is_active"`
+	UsageCount      int        `json:"usage_count"`
+	MaxUsage        *int       `json:"max_usage,omitempty"`
+	PerStudentLimit *int       `json:"per_student_limit,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+type CreatePromotionRequest struct {
+	Name            string     `json:"name" validate:"required,max=255"`
+	Code            string     `json:"code,omitempty" validate:"omitempty,alphanum,min=4,max=32"`
+	Type            string     `json:"type" validate:"required,oneof=percentage fixed buy_x_get_y"`
+	Value           float64    `json:"value" validate:"gte=0"`
+	BuyQuantity     *int       `json:"buy_quantity,omitempty" validate:"omitempty,min=1"`
+	FreeQuantity    *int       `json:"free_quantity,omitempty" validate:"omitempty,min=1"`
+	OfferingID      *uuid.UUID `json:"offering_id,omitempty"`
+	StartDate       time.Time  `json:"start_date" validate:"required"`
+	EndDate         time.Time  `json:"end_date" validate:"required"`
+	MaxUsage        *int       `json:"max_usage,omitempty" validate:"omitempty,min=1"`
+	PerStudentLimit *int       `json:"per_student_limit,omitempty" validate:"omitempty,min=1"`
+}
+
+// UpdatePromotionRequest changes a promotion's name, window, caps or
+// status; its type and value are fixed once created.
+type UpdatePromotionRequest struct {
+	Name            *string    `json:"name,omitempty" validate:"omitempty,max=255"`
+	StartDate       *time.Time `json:"start_date,omitempty"`
+	EndDate         *time.Time `json:"end_date,omitempty"`
+	IsActive        *bool      `json:"is_active,omitempty"`
+	MaxUsage        *int       `json:"max_usage,omitempty" validate:"omitempty,min=1"`
+	PerStudentLimit *int       `json:"per_student_limit,omitempty" validate:"omitempty,min=1"`
+}
+
+// QuoteRequest previews what a code would take off a price. Amount may
+// be left out when the price is not known yet (at booking).
+type QuoteRequest struct {
+	Code       string     `json:"code" validate:"required"`
+	TeacherID  uuid.UUID  `json:"teacher_id" validate:"required"`
+	OfferingID *uuid.UUID `json:"offering_id,omitempty"`
+	Amount     float64    `json:"amount,omitempty" validate:"gte=0"`
+	Sessions   int        `json:"sessions,omitempty" validate:"gte=0"` // sessions in the package bought
+}
+
+type QuoteResponse struct {
+	PromotionID    uuid.UUID `json:"promotion_id"`
+	Name           string    `json:"name"`
+	Type           string    `json:"type"`
+	DiscountAmount float64   `json:"discount_amount"`
+	FreeSessions   int       `json:"free_sessions"`
+	FinalAmount    float64   `json:"final_amount"`
+}
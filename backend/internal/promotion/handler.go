@@ -0,0 +1,134 @@
+package promotion
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"educonnect/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type Handler struct {
+	service  *Service
+	validate *validator.Validate
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service, validate: validator.New()}
+}
+
+// ═══════════════════════════════════════════════════════════════
+// Teacher Endpoints
+// ═══════════════════════════════════════════════════════════════
+
+// CreatePromotion POST /teachers/promotions
+func (h *Handler) CreatePromotion(c *gin.Context) {
+	var req CreatePromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"success": false, "error": gin.H{"message": "validation failed", "details": err.Error()}})
+		return
+	}
+
+	p, err := h.service.CreatePromotion(c.Request.Context(), middleware.GetUserID(c), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": p})
+}
+
+// ListPromotions GET /teachers/promotions
+func (h *Handler) ListPromotions(c *gin.Context) {
+	promotions, err := h.service.ListPromotions(c.Request.Context(), middleware.GetUserID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": promotions})
+}
+
+// UpdatePromotion PUT /teachers/promotions/:id
+func (h *Handler) UpdatePromotion(c *gin.Context) {
+	var req UpdatePromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"success": false, "error": gin.H{"message": "validation failed", "details": err.Error()}})
+		return
+	}
+
+	p, err := h.service.UpdatePromotion(c.Request.Context(), middleware.GetUserID(c), c.Param("id"), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": p})
+}
+
+// DeactivatePromotion DELETE /teachers/promotions/:id
+func (h *Handler) DeactivatePromotion(c *gin.Context) {
+	if err := h.service.DeactivatePromotion(c.Request.Context(), middleware.GetUserID(c), c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"message": "promotion deactivated"}})
+}
+
+// ═══════════════════════════════════════════════════════════════
+// Student Endpoints
+// ═══════════════════════════════════════════════════════════════
+
+// Quote POST /promotions/quote
+func (h *Handler) Quote(c *gin.Context) {
+	var req QuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"success": false, "error": gin.H{"message": "validation failed", "details": err.Error()}})
+		return
+	}
+
+	q, err := h.service.Quote(c.Request.Context(), middleware.GetUserID(c), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": q})
+}
+
+// ─── Helpers ────────────────────────────────────────────────────
+
+func respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrPromotionNotFound), errors.Is(err, ErrOfferingNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+	case errors.Is(err, ErrNotAuthorized):
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+	case errors.Is(err, ErrCodeTaken):
+		c.JSON(http.StatusConflict, gin.H{"success": false, "error": gin.H{
+			"code":    "PROMO_CODE_TAKEN",
+			"message": err.Error(),
+		}})
+	case errors.Is(err, ErrInvalidPromotion):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+	case IsRefusal(err):
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{
+			"code":    "PROMO_NOT_APPLICABLE",
+			"message": err.Error(),
+		}})
+	default:
+		fmt.Printf("[ERROR] promotion: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"message": "internal server error"}})
+	}
+}
@@ -0,0 +1,192 @@
+package promotion
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(n int) *int { return &n }
+
+func TestApply(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	teacher := uuid.New()
+	offering := uuid.New()
+	other := uuid.New()
+
+	promo := func(typ string, value float64) *PromotionResponse {
+		return &PromotionResponse{
+			ID:        uuid.New(),
+			TeacherID: teacher,
+			Type:      typ,
+			Value:     value,
+			StartDate: now.AddDate(0, 0, -1),
+			EndDate:   now.AddDate(0, 0, 1),
+			IsActive:  true,
+		}
+	}
+
+	tests := []struct {
+		name         string
+		promo        func() *PromotionResponse
+		redemption   Redemption
+		uses         int
+		wantErr      error
+		wantAmount   float64
+		wantSessions int
+	}{
+		{
+			name:       "percentage",
+			promo:      func() *PromotionResponse { return promo(TypePercentage, 15) },
+			redemption: Redemption{TeacherID: teacher, Amount: 2500},
+			wantAmount: 375,
+		},
+		{
+			name:       "percentage rounds to the centime",
+			promo:      func() *PromotionResponse { return promo(TypePercentage, 33) },
+			redemption: Redemption{TeacherID: teacher, Amount: 1000.55},
+			wantAmount: 330.18,
+		},
+		{
+			name:       "fixed",
+			promo:      func() *PromotionResponse { return promo(TypeFixed, 500) },
+			redemption: Redemption{TeacherID: teacher, Amount: 2500},
+			wantAmount: 500,
+		},
+		{
+			name:       "fixed equal to the price",
+			promo:      func() *PromotionResponse { return promo(TypeFixed, 2500) },
+			redemption: Redemption{TeacherID: teacher, Amount: 2500},
+			wantErr:    ErrNotApplicable,
+		},
+		{
+			name:       "fixed with the price unknown",
+			promo:      func() *PromotionResponse { return promo(TypeFixed, 500) },
+			redemption: Redemption{TeacherID: teacher},
+		},
+		{
+			name: "buy 4 get 1 on 10 sessions",
+			promo: func() *PromotionResponse {
+				p := promo(TypeBuyXGetY, 0)
+				p.BuyQuantity, p.FreeQuantity = intPtr(4), intPtr(1)
+				return p
+			},
+			redemption:   Redemption{TeacherID: teacher, Amount: 20000, Sessions: 10},
+			wantSessions: 2,
+		},
+		{
+			name: "buy 4 get 1 on a single payment",
+			promo: func() *PromotionResponse {
+				p := promo(TypeBuyXGetY, 0)
+				p.BuyQuantity, p.FreeQuantity = intPtr(4), intPtr(1)
+				return p
+			},
+			redemption: Redemption{TeacherID: teacher, Amount: 2000},
+			wantErr:    ErrNotApplicable,
+		},
+		{
+			name: "inactive",
+			promo: func() *PromotionResponse {
+				p := promo(TypeFixed, 500)
+				p.IsActive = false
+				return p
+			},
+			redemption: Redemption{TeacherID: teacher, Amount: 2500},
+			wantErr:    ErrPromotionNotFound,
+		},
+		{
+			name:       "another teacher",
+			promo:      func() *PromotionResponse { return promo(TypeFixed, 500) },
+			redemption: Redemption{TeacherID: uuid.New(), Amount: 2500},
+			wantErr:    ErrNotApplicable,
+		},
+		{
+			name: "not started",
+			promo: func() *PromotionResponse {
+				p := promo(TypeFixed, 500)
+				p.StartDate = now.Add(time.Hour)
+				return p
+			},
+			redemption: Redemption{TeacherID: teacher, Amount: 2500},
+			wantErr:    ErrNotStarted,
+		},
+		{
+			name: "ended",
+			promo: func() *PromotionResponse {
+				p := promo(TypeFixed, 500)
+				p.EndDate = now
+				return p
+			},
+			redemption: Redemption{TeacherID: teacher, Amount: 2500},
+			wantErr:    ErrExpired,
+		},
+		{
+			name: "usage cap reached",
+			promo: func() *PromotionResponse {
+				p := promo(TypeFixed, 500)
+				p.MaxUsage, p.UsageCount = intPtr(3), 3
+				return p
+			},
+			redemption: Redemption{TeacherID: teacher, Amount: 2500},
+			wantErr:    ErrUsageExhausted,
+		},
+		{
+			name: "per-student limit reached",
+			promo: func() *PromotionResponse {
+				p := promo(TypeFixed, 500)
+				p.PerStudentLimit = intPtr(1)
+				return p
+			},
+			redemption: Redemption{TeacherID: teacher, Amount: 2500},
+			uses:       1,
+			wantErr:    ErrStudentLimit,
+		},
+		{
+			name: "scoped to the offering",
+			promo: func() *PromotionResponse {
+				p := promo(TypeFixed, 500)
+				p.OfferingID = &offering
+				return p
+			},
+			redemption: Redemption{TeacherID: teacher, OfferingID: &offering, Amount: 2500},
+			wantAmount: 500,
+		},
+		{
+			name: "scoped to another offering",
+			promo: func() *PromotionResponse {
+				p := promo(TypeFixed, 500)
+				p.OfferingID = &offering
+				return p
+			},
+			redemption: Redemption{TeacherID: teacher, OfferingID: &other, Amount: 2500},
+			wantErr:    ErrNotApplicable,
+		},
+		{
+			name: "scoped with no offering known",
+			promo: func() *PromotionResponse {
+				p := promo(TypeFixed, 500)
+				p.OfferingID = &offering
+				return p
+			},
+			redemption: Redemption{TeacherID: teacher, Amount: 2500},
+			wantErr:    ErrNotApplicable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := apply(tt.promo(), tt.redemption, tt.uses, now)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.True(t, IsRefusal(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, tt.wantAmount, d.Amount, 0.001)
+			assert.Equal(t, tt.wantSessions, d.FreeSessions)
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package promotion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ─── Redemption ─────────────────────────────────────────────────
+// Payments redeem promotions inside their own database transaction,
+// the way they post to the ledger, so a discount is recorded exactly
+// when the discounted transaction is.
+
+// querier is satisfied by both the pool and a transaction.
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// Redemption describes what a promotion is used for.
+type Redemption struct {
+	Code          string     // empty: the best automatic promotion, if any
+	TransactionID uuid.UUID  // the payment discounted (Redeem only)
+	StudentID     uuid.UUID  // who the purchase is for
+	TeacherID     uuid.UUID  // who is paid
+	OfferingID    *uuid.UUID // what is paid for, when known
+	Amount        float64    // price before discount; 0 when not known yet
+	Sessions      int        // sessions in the package bought; 0 for a single payment
+}
+
+// Discount is what a promotion takes off a purchase.
+type Discount struct {
+	PromotionID  uuid.UUID
+	Name         string
+	Type         string
+	Code         string
+	Amount       float64 // DZD off the price
+	FreeSessions int     // sessions added to a package
+}
+
+// Check validates a redemption without using the promotion, for quotes
+// and for codes entered before the price is settled.
+func Check(ctx context.Context, q querier, r Redemption) (*Discount, error) {
+	p, err := byCode(ctx, q, r.Code, false)
+	if err != nil {
+		return nil, err
+	}
+	uses, err := studentUses(ctx, q, p.ID, r.StudentID)
+	if err != nil {
+		return nil, err
+	}
+	return apply(p, r, uses, time.Now())
+}
+
+// Redeem uses the promotion r.Code — or, without a code, the teacher's
+// automatic promotion worth the most — for r.TransactionID and returns
+// the discount to apply. It returns nil, nil when no code is given and
+// no automatic promotion applies.
+//
+// The transaction must be inserted in tx with r.TransactionID before it
+// commits.
+func Redeem(ctx context.Context, tx pgx.Tx, r Redemption) (*Discount, error) {
+	now := time.Now()
+
+	var d *Discount
+	if r.Code != "" {
+		p, err := byCode(ctx, tx, r.Code, true)
+		if err != nil {
+			return nil, err
+		}
+		uses, err := studentUses(ctx, tx, p.ID, r.StudentID)
+		if err != nil {
+			return nil, err
+		}
+		if d, err = apply(p, r, uses, now); err != nil {
+			return nil, err
+		}
+	} else {
+		candidates, err := automatic(ctx, tx, r.TeacherID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range candidates {
+			uses, err := studentUses(ctx, tx, candidates[i].ID, r.StudentID)
+			if err != nil {
+				return nil, err
+			}
+			c, err := apply(&candidates[i], r, uses, now)
+			if err != nil {
+				continue
+			}
+			if d == nil || c.Amount > d.Amount || (c.Amount == d.Amount && c.FreeSessions > d.FreeSessions) {
+				d = c
+			}
+		}
+		if d == nil {
+			return nil, nil
+		}
+	}
+
+	_, err := tx.Exec(ctx,
+		`INSERT INTO promotion_redemptions (promotion_id, student_id, transaction_id, discount_amount, free_sessions)
+		 VALUES ($1, $2, $3, $4, $5)`,
+		d.PromotionID, r.StudentID, r.TransactionID, d.Amount, d.FreeSessions,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("record redemption: %w", err)
+	}
+	_, err = tx.Exec(ctx,
+		`UPDATE promotions SET usage_count = COALESCE(usage_count, 0) + 1 WHERE id = $1`, d.PromotionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("count redemption: %w", err)
+	}
+	return d, nil
+}
+
+// Release gives back the use of a promotion whose payment failed. It is
+// a no-op for transactions that redeemed none.
+func Release(ctx context.Context, tx pgx.Tx, transactionID uuid.UUID) error {
+	var promotionID uuid.UUID
+	err := tx.QueryRow(ctx,
+		`UPDATE promotion_redemptions SET released_at = NOW()
+		 WHERE transaction_id = $1 AND released_at IS NULL
+		 RETURNING promotion_id`, transactionID,
+	).Scan(&promotionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("release redemption: %w", err)
+	}
+	_, err = tx.Exec(ctx,
+		`UPDATE promotions SET usage_count = GREATEST(COALESCE(usage_count, 0) - 1, 0) WHERE id = $1`, promotionID,
+	)
+	if err != nil {
+		return fmt.Errorf("uncount redemption: %w", err)
+	}
+	return nil
+}
+
+// apply checks p's rules against a redemption and works out the
+// discount. uses is how many times the student already used p.
+func apply(p *PromotionResponse, r Redemption, uses int, now time.Time) (*Discount, error) {
+	switch {
+	case !p.IsActive:
+		return nil, ErrPromotionNotFound
+	case p.TeacherID != r.TeacherID:
+		return nil, ErrNotApplicable
+	case now.Before(p.StartDate):
+		return nil, ErrNotStarted
+	case !now.Before(p.EndDate):
+		return nil, ErrExpired
+	case p.MaxUsage != nil && p.UsageCount >= *p.MaxUsage:
+		return nil, ErrUsageExhausted
+	case p.PerStudentLimit != nil && uses >= *p.PerStudentLimit:
+		return nil, ErrStudentLimit
+	case p.OfferingID != nil && (r.OfferingID == nil || *r.OfferingID != *p.OfferingID):
+		return nil, ErrNotApplicable
+	}
+
+	d := &Discount{PromotionID: p.ID, Name: p.Name, Type: p.Type}
+	if p.Code != nil {
+		d.Code = *p.Code
+	}
+	switch p.Type {
+	case TypePercentage:
+		d.Amount = math.Round(r.Amount*p.Value) / 100
+	case TypeFixed:
+		d.Amount = math.Min(p.Value, r.Amount)
+	case TypeBuyXGetY:
+		if p.BuyQuantity == nil || p.FreeQuantity == nil || r.Sessions < *p.BuyQuantity {
+			return nil, ErrNotApplicable
+		}
+		d.FreeSessions = r.Sessions / *p.BuyQuantity * *p.FreeQuantity
+	default:
+		return nil, ErrNotApplicable
+	}
+	// Gateways cannot take a payment of nothing.
+	if r.Amount > 0 && d.Amount >= r.Amount {
+		return nil, ErrNotApplicable
+	}
+	return d, nil
+}
+
+func byCode(ctx context.Context, q querier, code string, lock bool) (*PromotionResponse, error) {
+	query := `SELECT ` + promotionColumns + ` FROM promotions WHERE UPPER(code) = UPPER($1)`
+	if lock {
+		query += ` FOR UPDATE`
+	}
+	var p PromotionResponse
+	if err := scanPromotion(q.QueryRow(ctx, query, code), &p); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPromotionNotFound
+		}
+		return nil, fmt.Errorf("query promotion: %w", err)
+	}
+	return &p, nil
+}
+
+// automatic locks the teacher's code-less promotions in force.
+func automatic(ctx context.Context, tx pgx.Tx, teacherID uuid.UUID) ([]PromotionResponse, error) {
+	rows, err := tx.Query(ctx,
+		`SELECT `+promotionColumns+` FROM promotions
+		 WHERE teacher_id = $1 AND code IS NULL AND is_active
+		   AND start_date <= NOW() AND end_date > NOW()
+		 ORDER BY created_at
+		 FOR UPDATE`, teacherID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query automatic promotions: %w", err)
+	}
+	defer rows.Close()
+
+	var promotions []PromotionResponse
+	for rows.Next() {
+		var p PromotionResponse
+		if err := scanPromotion(rows, &p); err != nil {
+			return nil, fmt.Errorf("scan promotion: %w", err)
+		}
+		promotions = append(promotions, p)
+	}
+	return promotions, rows.Err()
+}
+
+func studentUses(ctx context.Context, q querier, promotionID, studentID uuid.UUID) (int, error) {
+	var n int
+	err := q.QueryRow(ctx,
+		`SELECT COUNT(*) FROM promotion_redemptions
+		 WHERE promotion_id = $1 AND student_id = $2 AND released_at IS NULL`,
+		promotionID, studentID,
+	).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("count redemptions: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,263 @@
+package promotion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"educonnect/pkg/database"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ═══════════════════════════════════════════════════════════════
+// Errors
+// ═══════════════════════════════════════════════════════════════
+
+var (
+	ErrPromotionNotFound = errors.New("promotion not found")
+	ErrNotAuthorized     = errors.New("not authorized")
+	ErrCodeTaken         = errors.New("promotion code already in use")
+	ErrInvalidPromotion  = errors.New("invalid promotion")
+	ErrOfferingNotFound  = errors.New("offering not found")
+
+	// Refusals: the promotion exists but cannot be used here.
+	ErrNotStarted     = errors.New("promotion has not started yet")
+	ErrExpired        = errors.New("promotion has ended")
+	ErrUsageExhausted = errors.New("promotion has been fully used")
+	ErrStudentLimit   = errors.New("promotion already used the maximum number of times")
+	ErrNotApplicable  = errors.New("promotion does not apply to this purchase")
+)
+
+// IsRefusal reports whether err means a code cannot be used, as opposed
+// to a failure to check it.
+func IsRefusal(err error) bool {
+	for _, refusal := range []error{
+		ErrPromotionNotFound, ErrNotStarted, ErrExpired,
+		ErrUsageExhausted, ErrStudentLimit, ErrNotApplicable,
+	} {
+		if errors.Is(err, refusal) {
+			return true
+		}
+	}
+	return false
+}
+
+// ═══════════════════════════════════════════════════════════════
+// Service
+// ═══════════════════════════════════════════════════════════════
+
+type Service struct {
+	db *database.Postgres
+}
+
+func NewService(db *database.Postgres) *Service {
+	return &Service{db: db}
+}
+
+// CreatePromotion adds a promotion for the teacher. Codes are stored in
+// upper case and unique across teachers.
+func (s *Service) CreatePromotion(ctx context.Context, teacherID string, req CreatePromotionRequest) (*PromotionResponse, error) {
+	uid, _ := uuid.Parse(teacherID)
+
+	if err := validateCreate(req); err != nil {
+		return nil, err
+	}
+	if req.OfferingID != nil {
+		var ownerID uuid.UUID
+		err := s.db.Pool.QueryRow(ctx, `SELECT teacher_id FROM offerings WHERE id = $1`, *req.OfferingID).Scan(&ownerID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrOfferingNotFound
+			}
+			return nil, fmt.Errorf("query offering: %w", err)
+		}
+		if ownerID != uid {
+			return nil, ErrNotAuthorized
+		}
+	}
+
+	var code *string
+	if req.Code != "" {
+		upper := strings.ToUpper(req.Code)
+		code = &upper
+
+		var taken bool
+		err := s.db.Pool.QueryRow(ctx,
+			`SELECT EXISTS(SELECT 1 FROM promotions WHERE UPPER(code) = $1)`, upper,
+		).Scan(&taken)
+		if err != nil {
+			return nil, fmt.Errorf("check code: %w", err)
+		}
+		if taken {
+			return nil, ErrCodeTaken
+		}
+	}
+
+	var p PromotionResponse
+	err := scanPromotion(s.db.Pool.QueryRow(ctx,
+		`INSERT INTO promotions (teacher_id, name, code, type, value, buy_quantity, free_quantity,
+		    offering_id, start_date, end_date, max_usage, per_student_limit)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)
+		 RETURNING `+promotionColumns,
+		uid, req.Name, code, req.Type, req.Value, req.BuyQuantity, req.FreeQuantity,
+		req.OfferingID, req.StartDate, req.EndDate, req.MaxUsage, req.PerStudentLimit,
+	), &p)
+	if err != nil {
+		return nil, fmt.Errorf("insert promotion: %w", err)
+	}
+	return &p, nil
+}
+
+// validateCreate checks what the struct tags cannot: the fields each
+// type needs and the date window.
+func validateCreate(req CreatePromotionRequest) error {
+	switch req.Type {
+	case TypePercentage:
+		if req.Value <= 0 || req.Value >= 100 {
+			return fmt.Errorf("%w: a percentage must be between 0 and 100", ErrInvalidPromotion)
+		}
+	case TypeFixed:
+		if req.Value <= 0 {
+			return fmt.Errorf("%w: a fixed discount must be positive", ErrInvalidPromotion)
+		}
+	case TypeBuyXGetY:
+		if req.BuyQuantity == nil || req.FreeQuantity == nil {
+			return fmt.Errorf("%w: buy_quantity and free_quantity are required", ErrInvalidPromotion)
+		}
+	}
+	if !req.EndDate.After(req.StartDate) {
+		return fmt.Errorf("%w: end_date must be after start_date", ErrInvalidPromotion)
+	}
+	return nil
+}
+
+// ListPromotions returns the teacher's promotions, newest first.
+func (s *Service) ListPromotions(ctx context.Context, teacherID string) ([]PromotionResponse, error) {
+	uid, _ := uuid.Parse(teacherID)
+
+	rows, err := s.db.Pool.Query(ctx,
+		`SELECT `+promotionColumns+` FROM promotions WHERE teacher_id = $1 ORDER BY created_at DESC`, uid,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query promotions: %w", err)
+	}
+	defer rows.Close()
+
+	promotions := []PromotionResponse{}
+	for rows.Next() {
+		var p PromotionResponse
+		if err := scanPromotion(rows, &p); err != nil {
+			return nil, fmt.Errorf("scan promotion: %w", err)
+		}
+		promotions = append(promotions, p)
+	}
+	return promotions, rows.Err()
+}
+
+// UpdatePromotion edits one of the teacher's promotions.
+func (s *Service) UpdatePromotion(ctx context.Context, teacherID, promotionID string, req UpdatePromotionRequest) (*PromotionResponse, error) {
+	id, err := s.ownPromotion(ctx, teacherID, promotionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var p PromotionResponse
+	err = scanPromotion(s.db.Pool.QueryRow(ctx,
+		`UPDATE promotions SET
+		    name = COALESCE($2, name),
+		    start_date = COALESCE($3, start_date),
+		    end_date = COALESCE($4, end_date),
+		    is_active = COALESCE($5, is_active),
+		    max_usage = COALESCE($6, max_usage),
+		    per_student_limit = COALESCE($7, per_student_limit)
+		 WHERE id = $1
+		 RETURNING `+promotionColumns,
+		id, req.Name, req.StartDate, req.EndDate, req.IsActive, req.MaxUsage, req.PerStudentLimit,
+	), &p)
+	if err != nil {
+		if strings.Contains(err.Error(), "chk_promotions_window") {
+			return nil, fmt.Errorf("%w: end_date must be after start_date", ErrInvalidPromotion)
+		}
+		return nil, fmt.Errorf("update promotion: %w", err)
+	}
+	return &p, nil
+}
+
+// DeactivatePromotion stops a promotion. Redemptions reference it, so
+// it is kept.
+func (s *Service) DeactivatePromotion(ctx context.Context, teacherID, promotionID string) error {
+	id, err := s.ownPromotion(ctx, teacherID, promotionID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Pool.Exec(ctx, `UPDATE promotions SET is_active = false WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("deactivate promotion: %w", err)
+	}
+	return nil
+}
+
+// Quote previews a code for the student without using it.
+func (s *Service) Quote(ctx context.Context, studentID string, req QuoteRequest) (*QuoteResponse, error) {
+	uid, _ := uuid.Parse(studentID)
+
+	d, err := Check(ctx, s.db.Pool, Redemption{
+		Code:       req.Code,
+		StudentID:  uid,
+		TeacherID:  req.TeacherID,
+		OfferingID: req.OfferingID,
+		Amount:     req.Amount,
+		Sessions:   req.Sessions,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &QuoteResponse{
+		PromotionID:    d.PromotionID,
+		Name:           d.Name,
+		Type:           d.Type,
+		DiscountAmount: d.Amount,
+		FreeSessions:   d.FreeSessions,
+		FinalAmount:    req.Amount - d.Amount,
+	}, nil
+}
+
+// ownPromotion checks the promotion belongs to the teacher.
+func (s *Service) ownPromotion(ctx context.Context, teacherID, promotionID string) (uuid.UUID, error) {
+	uid, _ := uuid.Parse(teacherID)
+	id, err := uuid.Parse(promotionID)
+	if err != nil {
+		return uuid.Nil, ErrPromotionNotFound
+	}
+
+	var ownerID uuid.UUID
+	err = s.db.Pool.QueryRow(ctx, `SELECT teacher_id FROM promotions WHERE id = $1`, id).Scan(&ownerID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, ErrPromotionNotFound
+		}
+		return uuid.Nil, fmt.Errorf("query promotion: %w", err)
+	}
+	if ownerID != uid {
+		return uuid.Nil, ErrNotAuthorized
+	}
+	return id, nil
+}
+
+// ─── Helpers ────────────────────────────────────────────────────
+
+const promotionColumns = `id, teacher_id, name, code, type, value, buy_quantity, free_quantity,
+    offering_id, start_date, end_date, COALESCE(is_active, false), COALESCE(usage_count, 0),
+    max_usage, per_student_limit, created_at, updated_at`
+
+func scanPromotion(row pgx.Row, p *PromotionResponse) error {
+	return row.Scan(
+		&p.ID, &p.TeacherID, &p.Name, &p.Code, &p.Type, &p.Value, &p.BuyQuantity, &p.FreeQuantity,
+		&p.OfferingID, &p.StartDate, &p.EndDate, &p.IsActive, &p.UsageCount,
+		&p.MaxUsage, &p.PerStudentLimit, &p.CreatedAt, &p.UpdatedAt,
+	)
+}
@@ -0,0 +1,276 @@
+package scheduling
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ─── Availability ───────────────────────────────────────────────
+// A teacher's availability on a date is their weekly slots for that
+// weekday, less any day off (an observed holiday, or an exception with
+// is_available = false), plus the extra slots they open that day. An
+// exception without times takes the whole day off; with times, only
+// those hours.
+
+// Window is a stretch of a day on the teacher's wall clock.
+type Window struct {
+	Start string `json:"start_time"` // "15:04"
+	End   string `json:"end_time"`
+}
+
+// Day is a teacher's availability on one date.
+type Day struct {
+	Date    string   `json:"date"` // YYYY-MM-DD
+	Windows []Window `json:"windows"`
+	Holiday string   `json:"holiday,omitempty"` // observed holiday that closes the day
+}
+
+// Covers reports whether [start, end) falls within one of the day's
+// windows.
+func (d Day) Covers(start, end string) bool {
+	s, err := clockMinutes(start)
+	if err != nil {
+		return false
+	}
+	e, err := clockMinutes(end)
+	if err != nil || e <= s {
+		return false
+	}
+	for _, w := range d.Windows {
+		ws, _ := clockMinutes(w.Start)
+		we, _ := clockMinutes(w.End)
+		if ws <= s && e <= we {
+			return true
+		}
+	}
+	return false
+}
+
+// span is a stretch of a day in minutes since midnight, [start, end).
+type span struct{ start, end int }
+
+// exception is an availability_exceptions row; a nil span is the
+// whole day.
+type exception struct {
+	available bool
+	hours     *span
+}
+
+// Availability resolves the teacher's availability for each date from
+// from to to, both included.
+func Availability(ctx context.Context, q rowsQuerier, teacherID uuid.UUID, from, to time.Time) ([]Day, error) {
+	weekly := make(map[int][]span)
+	rows, err := q.Query(ctx,
+		`SELECT day_of_week, start_time::text, end_time::text
+		 FROM availability_slots
+		 WHERE teacher_id = $1 AND is_active = true`, teacherID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query availability: %w", err)
+	}
+	for rows.Next() {
+		var dow int
+		var st, et string
+		if err := rows.Scan(&dow, &st, &et); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan availability: %w", err)
+		}
+		if sp, ok := parseSpan(st, et); ok {
+			weekly[dow] = append(weekly[dow], sp)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	exceptions := make(map[string][]exception)
+	rows, err = q.Query(ctx,
+		`SELECT exception_date, is_available, start_time::text, end_time::text
+		 FROM availability_exceptions
+		 WHERE teacher_id = $1 AND exception_date BETWEEN $2 AND $3`,
+		teacherID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query exceptions: %w", err)
+	}
+	for rows.Next() {
+		var date time.Time
+		var ex exception
+		var st, et *string
+		if err := rows.Scan(&date, &ex.available, &st, &et); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan exception: %w", err)
+		}
+		if st != nil && et != nil {
+			if sp, ok := parseSpan(*st, *et); ok {
+				ex.hours = &sp
+			}
+		}
+		if ex.available && ex.hours == nil {
+			continue
+		}
+		key := date.Format("2006-01-02")
+		exceptions[key] = append(exceptions[key], ex)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	type holiday struct {
+		start, end time.Time
+		name       string
+	}
+	var holidays []holiday
+	rows, err = q.Query(ctx,
+		`SELECT h.start_date, h.end_date, h.name_fr
+		 FROM holidays h
+		 JOIN teacher_profiles tp ON tp.user_id = $1 AND h.kind = ANY(tp.observed_holidays)
+		 WHERE h.start_date <= $3 AND h.end_date >= $2
+		 ORDER BY h.start_date`,
+		teacherID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query holidays: %w", err)
+	}
+	for rows.Next() {
+		var h holiday
+		if err := rows.Scan(&h.start, &h.end, &h.name); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan holiday: %w", err)
+		}
+		holidays = append(holidays, h)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var days []Day
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		day := Day{Date: date.Format("2006-01-02")}
+		for _, h := range holidays {
+			if !date.Before(h.start) && !date.After(h.end) {
+				day.Holiday = h.name
+				break
+			}
+		}
+		day.Windows = windows(resolve(weekly[int(date.Weekday())], day.Holiday != "", exceptions[day.Date]))
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// resolve applies a date's holiday and exceptions to its weekly slots.
+// Days off are taken first, so an extra slot is open even on a day off.
+func resolve(weekly []span, holiday bool, exceptions []exception) []span {
+	var open []span
+	if !holiday {
+		open = append(open, weekly...)
+	}
+	for _, ex := range exceptions {
+		if ex.available {
+			continue
+		}
+		if ex.hours == nil {
+			open = nil
+			continue
+		}
+		open = subtract(open, *ex.hours)
+	}
+	for _, ex := range exceptions {
+		if ex.available {
+			open = append(open, *ex.hours)
+		}
+	}
+	return merge(open)
+}
+
+// subtract removes cut from each span.
+func subtract(spans []span, cut span) []span {
+	var out []span
+	for _, s := range spans {
+		if cut.end <= s.start || cut.start >= s.end {
+			out = append(out, s)
+			continue
+		}
+		if s.start < cut.start {
+			out = append(out, span{s.start, cut.start})
+		}
+		if cut.end < s.end {
+			out = append(out, span{cut.end, s.end})
+		}
+	}
+	return out
+}
+
+// merge sorts spans and joins those that overlap or touch.
+func merge(spans []span) []span {
+	if len(spans) == 0 {
+		return nil
+	}
+	sorted := append([]span(nil), spans...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].start < sorted[j].start })
+
+	out := []span{sorted[0]}
+	for _, s := range sorted[1:] {
+		last := &out[len(out)-1]
+		if s.start <= last.end {
+			if s.end > last.end {
+				last.end = s.end
+			}
+			continue
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+func windows(spans []span) []Window {
+	out := make([]Window, 0, len(spans))
+	for _, s := range spans {
+		out = append(out, Window{Start: clock(s.start), End: clock(s.end)})
+	}
+	return out
+}
+
+func parseSpan(start, end string) (span, bool) {
+	s, err := clockMinutes(start)
+	if err != nil {
+		return span{}, false
+	}
+	e, err := clockMinutes(end)
+	if err != nil || e <= s {
+		return span{}, false
+	}
+	return span{s, e}, true
+}
+
+// clockMinutes reads "15:04" or PostgreSQL's "15:04:05" as minutes since
+// midnight. "24:00" is the end of the day.
+func clockMinutes(c string) (int, error) {
+	parts := strings.Split(c, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid time of day %q", c)
+	}
+	h, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q", c)
+	}
+	m, err := strconv.Atoi(parts[1])
+	if err != nil || h < 0 || h > 24 || m < 0 || m > 59 || (h == 24 && m > 0) {
+		return 0, fmt.Errorf("invalid time of day %q", c)
+	}
+	return h*60 + m, nil
+}
+
+func clock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
@@ -0,0 +1,59 @@
+package scheduling
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolve(t *testing.T) {
+	h := func(start, end string) *span {
+		sp, ok := parseSpan(start, end)
+		if !ok {
+			t.Fatalf("bad span %s-%s", start, end)
+		}
+		return &sp
+	}
+	weekly := []span{*h("09:00", "12:00"), *h("14:00", "18:00")}
+
+	tests := []struct {
+		name       string
+		holiday    bool
+		exceptions []exception
+		want       []Window
+	}{
+		{"weekly slots", false, nil,
+			[]Window{{"09:00", "12:00"}, {"14:00", "18:00"}}},
+		{"day off", false, []exception{{available: false}},
+			[]Window{}},
+		{"hours off", false, []exception{{available: false, hours: h("10:00", "11:00")}},
+			[]Window{{"09:00", "10:00"}, {"11:00", "12:00"}, {"14:00", "18:00"}}},
+		{"extra slot joins a weekly one", false, []exception{{available: true, hours: h("12:00", "13:00")}},
+			[]Window{{"09:00", "13:00"}, {"14:00", "18:00"}}},
+		{"holiday", true, nil,
+			[]Window{}},
+		{"extra slot on a holiday", true, []exception{{available: true, hours: h("10:00", "11:00")}},
+			[]Window{{"10:00", "11:00"}}},
+		{"extra slot on a day off", false, []exception{
+			{available: true, hours: h("20:00", "24:00")},
+			{available: false},
+		}, []Window{{"20:00", "24:00"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, windows(resolve(weekly, tt.holiday, tt.exceptions)))
+		})
+	}
+}
+
+func TestDayCovers(t *testing.T) {
+	day := Day{Windows: []Window{{"09:00", "12:00"}, {"14:00", "24:00"}}}
+
+	assert.True(t, day.Covers("09:00", "12:00"))
+	assert.True(t, day.Covers("10:00:00", "11:00:00"))
+	assert.True(t, day.Covers("23:00", "24:00"))
+	assert.False(t, day.Covers("11:00", "13:00"), "across two windows")
+	assert.False(t, day.Covers("13:00", "14:00"))
+	assert.False(t, day.Covers("11:00", "10:00"))
+	assert.False(t, day.Covers("9h", "10:00"))
+}
@@ -0,0 +1,109 @@
+package scheduling
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ─── Calendar Conflicts ─────────────────────────────────────────
+// A teacher cannot be in two sessions at once: scheduled and live
+// sessions may not overlap, whatever creates or moves them. Conflicts
+// is the check callers run to explain a refusal; the sessions_no_overlap
+// exclusion constraint enforces it against races.
+
+// ErrConflict is returned when a time overlaps another session of the
+// teacher's.
+var ErrConflict = errors.New("time slot conflict")
+
+// overlapConstraint is the exclusion constraint on sessions.
+const overlapConstraint = "sessions_no_overlap"
+
+// Session is a session occupying a teacher's calendar.
+type Session struct {
+	ID          uuid.UUID
+	Title       string
+	SessionType string // one_on_one or group
+	OfferingID  *uuid.UUID
+	StartTime   time.Time
+	EndTime     time.Time
+}
+
+// ConflictError names the session a time overlaps.
+type ConflictError struct {
+	Session Session
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%v with %q (%s – %s)", ErrConflict, e.Session.Title,
+		e.Session.StartTime.Format(time.RFC3339), e.Session.EndTime.Format(time.RFC3339))
+}
+
+func (e *ConflictError) Unwrap() error { return ErrConflict }
+
+// rowsQuerier is satisfied by both the pool and a transaction.
+type rowsQuerier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// Conflicts returns the teacher's scheduled or live sessions overlapping
+// [start, end), earliest first, leaving out the sessions in exclude.
+// Sessions that merely touch (one ends as the other starts) do not
+// overlap.
+func Conflicts(ctx context.Context, q rowsQuerier, teacherID uuid.UUID, start, end time.Time, exclude ...uuid.UUID) ([]Session, error) {
+	if exclude == nil {
+		exclude = []uuid.UUID{}
+	}
+	rows, err := q.Query(ctx,
+		`SELECT id, title, session_type::text, offering_id, start_time, end_time
+		 FROM sessions
+		 WHERE teacher_id = $1
+		   AND status IN ('scheduled', 'live')
+		   AND tstzrange(start_time, end_time) && tstzrange($2, $3)
+		   AND id <> ALL($4)
+		 ORDER BY start_time`,
+		teacherID, start, end, exclude,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query conflicts: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []Session
+	for rows.Next() {
+		var s Session
+		if err := rows.Scan(&s.ID, &s.Title, &s.SessionType, &s.OfferingID, &s.StartTime, &s.EndTime); err != nil {
+			return nil, fmt.Errorf("scan conflict: %w", err)
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, rows.Err()
+}
+
+// CheckFree returns a *ConflictError for the first session overlapping
+// [start, end), or nil when the teacher is free.
+func CheckFree(ctx context.Context, q rowsQuerier, teacherID uuid.UUID, start, end time.Time, exclude ...uuid.UUID) error {
+	if !end.After(start) {
+		return fmt.Errorf("end %s is not after start %s", end.Format(time.RFC3339), start.Format(time.RFC3339))
+	}
+	sessions, err := Conflicts(ctx, q, teacherID, start, end, exclude...)
+	if err != nil {
+		return err
+	}
+	if len(sessions) > 0 {
+		return &ConflictError{Session: sessions[0]}
+	}
+	return nil
+}
+
+// IsOverlapViolation reports whether err is the database refusing an
+// overlapping session, for writes that raced past CheckFree.
+func IsOverlapViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23P01" && pgErr.ConstraintName == overlapConstraint
+}
@@ -0,0 +1,38 @@
+package scheduling
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConflictError(t *testing.T) {
+	err := fmt.Errorf("session 2: %w", &ConflictError{Session: Session{Title: "Maths"}})
+
+	assert.ErrorIs(t, err, ErrConflict)
+	var conflict *ConflictError
+	assert.True(t, errors.As(err, &conflict))
+	assert.Equal(t, "Maths", conflict.Session.Title)
+}
+
+func TestIsOverlapViolation(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"overlap", &pgconn.PgError{Code: "23P01", ConstraintName: "sessions_no_overlap"}, true},
+		{"wrapped", fmt.Errorf("insert: %w", &pgconn.PgError{Code: "23P01", ConstraintName: "sessions_no_overlap"}), true},
+		{"other exclusion", &pgconn.PgError{Code: "23P01", ConstraintName: "other"}, false},
+		{"unique violation", &pgconn.PgError{Code: "23505", ConstraintName: "sessions_no_overlap"}, false},
+		{"nil", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsOverlapViolation(tt.err))
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package scheduling
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ─── Free Slots ─────────────────────────────────────────────────
+// The start times a student can book: the teacher's availability,
+// cut into sessions of the asked duration on a fixed grid, less what
+// overlaps a scheduled or live session or a booking awaiting the
+// teacher's answer, and less what has already started.
+
+// Slot is a bookable time on the teacher's wall clock.
+type Slot struct {
+	StartTime string    `json:"start_time"` // "15:04", as CreateBookingRequest takes it
+	EndTime   string    `json:"end_time"`
+	StartsAt  time.Time `json:"starts_at"`
+}
+
+// FreeDay is one date's bookable slots.
+type FreeDay struct {
+	Date    string `json:"date"` // YYYY-MM-DD
+	Holiday string `json:"holiday,omitempty"`
+	Slots   []Slot `json:"slots"`
+}
+
+type interval struct{ start, end time.Time }
+
+// FreeSlots lists, for each date from from to to, the slots of length
+// duration that start on multiples of step past midnight. Dates are
+// read in loc, the teacher's zone.
+func FreeSlots(ctx context.Context, q rowsQuerier, teacherID uuid.UUID, loc *time.Location, from, to time.Time, duration, step time.Duration, now time.Time) ([]FreeDay, error) {
+	days, err := Availability(ctx, q, teacherID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	busy, err := busyIntervals(ctx, q, teacherID, loc, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	free := make([]FreeDay, 0, len(days))
+	for _, day := range days {
+		fd := FreeDay{Date: day.Date, Holiday: day.Holiday, Slots: []Slot{}}
+		date, _ := time.Parse("2006-01-02", day.Date)
+
+		var open []span
+		for _, w := range day.Windows {
+			if sp, ok := parseSpan(w.Start, w.End); ok {
+				open = append(open, sp)
+			}
+		}
+		for _, sp := range fit(open, int(duration/time.Minute), int(step/time.Minute)) {
+			if sp.end >= 24*60 {
+				break // a booking ends by 23:59
+			}
+			start, _ := At(date, clock(sp.start), loc)
+			end := start.Add(duration)
+			if start.Before(now) || overlapsAny(busy, start, end) {
+				continue
+			}
+			fd.Slots = append(fd.Slots, Slot{StartTime: clock(sp.start), EndTime: clock(sp.end), StartsAt: start})
+		}
+		free = append(free, fd)
+	}
+	return free, nil
+}
+
+// busyIntervals returns the teacher's scheduled and live sessions and
+// pending bookings around from to to.
+func busyIntervals(ctx context.Context, q rowsQuerier, teacherID uuid.UUID, loc *time.Location, from, to time.Time) ([]interval, error) {
+	rangeStart, _ := At(from, "00:00", loc)
+	rangeEnd, _ := At(to.AddDate(0, 0, 1), "00:00", loc)
+
+	var busy []interval
+	rows, err := q.Query(ctx,
+		`SELECT start_time, end_time FROM sessions
+		 WHERE teacher_id = $1 AND status IN ('scheduled', 'live')
+		   AND start_time < $3 AND end_time > $2`,
+		teacherID, rangeStart, rangeEnd,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query sessions: %w", err)
+	}
+	for rows.Next() {
+		var iv interval
+		if err := rows.Scan(&iv.start, &iv.end); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan session: %w", err)
+		}
+		busy = append(busy, iv)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// A booking keeps the zone it was made in, so the dates around the
+	// range are read too. A weekly booking is busy on each of its dates.
+	rows, err = q.Query(ctx,
+		`SELECT requested_date, start_time::text, end_time::text, timezone,
+		        recurrence_days, recurrence_until, recurrence_count
+		 FROM booking_requests
+		 WHERE teacher_id = $1 AND status = 'pending'
+		   AND requested_date <= $3::date + 1
+		   AND (requested_date >= $2::date - 1 OR recurrence_days IS NOT NULL)`,
+		teacherID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query pending bookings: %w", err)
+	}
+	for rows.Next() {
+		var date time.Time
+		var st, et, zone string
+		var days []int16
+		var until *time.Time
+		var count *int
+		if err := rows.Scan(&date, &st, &et, &zone, &days, &until, &count); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan pending booking: %w", err)
+		}
+		bookingLoc, err := LoadZone(zone)
+		if err != nil {
+			continue
+		}
+		dates := []time.Time{date}
+		if rule := StoredRecurrence(days, until, count); rule != nil {
+			if dates, err = rule.Dates(date); err != nil {
+				continue
+			}
+		}
+		for _, d := range dates {
+			start, err1 := At(d, st, bookingLoc)
+			end, err2 := At(d, et, bookingLoc)
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			busy = append(busy, interval{start, end})
+		}
+	}
+	rows.Close()
+	return busy, rows.Err()
+}
+
+// fit cuts spans into slots of duration minutes starting on multiples
+// of step minutes past midnight.
+func fit(spans []span, duration, step int) []span {
+	if duration <= 0 || step <= 0 {
+		return nil
+	}
+	var out []span
+	for _, sp := range spans {
+		first := (sp.start + step - 1) / step * step
+		for s := first; s+duration <= sp.end; s += step {
+			out = append(out, span{s, s + duration})
+		}
+	}
+	return out
+}
+
+// overlapsAny reports whether [start, end) overlaps one of busy.
+func overlapsAny(busy []interval, start, end time.Time) bool {
+	for _, b := range busy {
+		if b.start.Before(end) && start.Before(b.end) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package scheduling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		name           string
+		spans          []span
+		duration, step int
+		want           []span
+	}{
+		{"hour on the half hour", []span{{540, 720}}, 60, 30,
+			[]span{{540, 600}, {570, 630}, {600, 660}, {630, 690}, {660, 720}}},
+		{"starts on the grid", []span{{545, 660}}, 30, 30,
+			[]span{{570, 600}, {600, 630}, {630, 660}}},
+		{"window too short", []span{{540, 570}}, 60, 15, nil},
+		{"several windows", []span{{540, 600}, {840, 900}}, 60, 60,
+			[]span{{540, 600}, {840, 900}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, fit(tt.spans, tt.duration, tt.step))
+		})
+	}
+}
+
+func TestOverlapsAny(t *testing.T) {
+	at := func(h int) time.Time { return time.Date(2026, 11, 2, h, 0, 0, 0, time.UTC) }
+	busy := []interval{{at(10), at(11)}}
+
+	assert.True(t, overlapsAny(busy, at(9), at(11)))
+	assert.True(t, overlapsAny(busy, at(10), at(11)))
+	assert.False(t, overlapsAny(busy, at(9), at(10)), "ends as the session starts")
+	assert.False(t, overlapsAny(busy, at(11), at(12)), "starts as the session ends")
+}
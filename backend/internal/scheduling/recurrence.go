@@ -0,0 +1,87 @@
+package scheduling
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ─── Recurrence ─────────────────────────────────────────────────
+// A weekly rule, as in an RRULE with FREQ=WEEKLY: the weekdays an
+// appointment repeats on, until a date or for a number of
+// occurrences. The rule yields dates; the times are the teacher's
+// wall clock on each, so a series keeps its hour across DST changes.
+
+// MaxOccurrences caps the dates a rule may yield, a school year of
+// weekly sessions.
+const MaxOccurrences = 52
+
+// ErrInvalidRecurrence is returned for a rule that yields no dates or
+// too many.
+var ErrInvalidRecurrence = errors.New("invalid recurrence")
+
+// Recurrence repeats weekly on Days, until Until (included) or for
+// Count occurrences; exactly one of the two is set.
+type Recurrence struct {
+	Days  []time.Weekday
+	Until time.Time
+	Count int
+}
+
+// Dates lists the dates the rule yields from first, which must fall on
+// one of Days and is the first of them.
+func (r Recurrence) Dates(first time.Time) ([]time.Time, error) {
+	if len(r.Days) == 0 {
+		return nil, fmt.Errorf("%w: no weekday given", ErrInvalidRecurrence)
+	}
+	on := make(map[time.Weekday]bool, len(r.Days))
+	for _, d := range r.Days {
+		if d < time.Sunday || d > time.Saturday {
+			return nil, fmt.Errorf("%w: weekday %d out of range", ErrInvalidRecurrence, d)
+		}
+		on[d] = true
+	}
+	switch {
+	case r.Until.IsZero() == (r.Count == 0):
+		return nil, fmt.Errorf("%w: give either an end date or a count", ErrInvalidRecurrence)
+	case r.Count < 0 || r.Count > MaxOccurrences:
+		return nil, fmt.Errorf("%w: count must be between 1 and %d", ErrInvalidRecurrence, MaxOccurrences)
+	case !r.Until.IsZero() && r.Until.Before(first):
+		return nil, fmt.Errorf("%w: end date before the first date", ErrInvalidRecurrence)
+	case !on[first.Weekday()]:
+		return nil, fmt.Errorf("%w: the first date is not on one of the weekdays", ErrInvalidRecurrence)
+	}
+
+	var dates []time.Time
+	for d := first; ; d = d.AddDate(0, 0, 1) {
+		if r.Count > 0 && len(dates) == r.Count || !r.Until.IsZero() && d.After(r.Until) {
+			return dates, nil
+		}
+		if !on[d.Weekday()] {
+			continue
+		}
+		if len(dates) == MaxOccurrences {
+			return nil, fmt.Errorf("%w: more than %d occurrences", ErrInvalidRecurrence, MaxOccurrences)
+		}
+		dates = append(dates, d)
+	}
+}
+
+// StoredRecurrence reads a rule from booking_requests' recurrence
+// columns, or returns nil for a booking that does not repeat.
+func StoredRecurrence(days []int16, until *time.Time, count *int) *Recurrence {
+	if len(days) == 0 {
+		return nil
+	}
+	r := &Recurrence{}
+	for _, d := range days {
+		r.Days = append(r.Days, time.Weekday(d))
+	}
+	if until != nil {
+		r.Until = *until
+	}
+	if count != nil {
+		r.Count = *count
+	}
+	return r
+}
@@ -0,0 +1,67 @@
+package scheduling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecurrenceDates(t *testing.T) {
+	day := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		require.NoError(t, err)
+		return d
+	}
+	days := func(ss ...string) []time.Time {
+		var out []time.Time
+		for _, s := range ss {
+			out = append(out, day(s))
+		}
+		return out
+	}
+	tuesday := day("2026-09-01")
+
+	tests := []struct {
+		name string
+		rule Recurrence
+		want []time.Time
+	}{
+		{"count", Recurrence{Days: []time.Weekday{time.Tuesday}, Count: 3},
+			days("2026-09-01", "2026-09-08", "2026-09-15")},
+		{"until, included", Recurrence{Days: []time.Weekday{time.Tuesday}, Until: day("2026-09-15")},
+			days("2026-09-01", "2026-09-08", "2026-09-15")},
+		{"two days a week", Recurrence{Days: []time.Weekday{time.Thursday, time.Tuesday}, Count: 4},
+			days("2026-09-01", "2026-09-03", "2026-09-08", "2026-09-10")},
+		{"single date", Recurrence{Days: []time.Weekday{time.Tuesday}, Until: tuesday},
+			days("2026-09-01")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.rule.Dates(tuesday)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	invalid := []struct {
+		name string
+		rule Recurrence
+	}{
+		{"no days", Recurrence{Count: 3}},
+		{"bad weekday", Recurrence{Days: []time.Weekday{7}, Count: 3}},
+		{"neither end", Recurrence{Days: []time.Weekday{time.Tuesday}}},
+		{"both ends", Recurrence{Days: []time.Weekday{time.Tuesday}, Count: 3, Until: day("2026-12-01")}},
+		{"count too high", Recurrence{Days: []time.Weekday{time.Tuesday}, Count: MaxOccurrences + 1}},
+		{"until before first", Recurrence{Days: []time.Weekday{time.Tuesday}, Until: day("2026-08-25")}},
+		{"first not on a day", Recurrence{Days: []time.Weekday{time.Wednesday}, Count: 3}},
+		{"until too far", Recurrence{Days: []time.Weekday{time.Tuesday, time.Thursday}, Until: day("2027-06-30")}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.rule.Dates(tuesday)
+			assert.ErrorIs(t, err, ErrInvalidRecurrence)
+		})
+	}
+}
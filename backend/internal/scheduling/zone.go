@@ -0,0 +1,73 @@
+// Package scheduling holds the time model bookings, availability and
+// sessions share.
+//
+// Instants (sessions, timestamps) are stored as TIMESTAMPTZ. Wall-clock
+// values — availability_slots' TIME columns and a booking's
+// requested_date/start_time/end_time — have no zone of their own: they
+// are read in the teacher's zone (users.timezone), which a booking
+// snapshots when it is made. At is the one place the two meet.
+package scheduling
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	_ "time/tzdata" // zones must resolve in minimal containers too
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// DefaultZone is the zone of users who have not chosen one.
+const DefaultZone = "Africa/Algiers"
+
+var ErrUnknownZone = errors.New("unknown time zone")
+
+// querier is satisfied by both the pool and a transaction.
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// LoadZone resolves an IANA zone name; "" is DefaultZone.
+func LoadZone(name string) (*time.Location, error) {
+	if name == "" {
+		name = DefaultZone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownZone, name)
+	}
+	return loc, nil
+}
+
+// UserZone returns the zone a user schedules in.
+func UserZone(ctx context.Context, q querier, userID uuid.UUID) (*time.Location, error) {
+	var name string
+	err := q.QueryRow(ctx, `SELECT timezone FROM users WHERE id = $1`, userID).Scan(&name)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return LoadZone(DefaultZone)
+		}
+		return nil, fmt.Errorf("query user timezone: %w", err)
+	}
+	return LoadZone(name)
+}
+
+// At is the instant a wall clock in loc reads clock ("15:04" or
+// "15:04:05", as Postgres prints a TIME) on date. Only date's calendar
+// day is used. A time skipped by a DST change is moved forward.
+func At(date time.Time, clock string, loc *time.Location) (time.Time, error) {
+	var t time.Time
+	var err error
+	if len(clock) > len("15:04") {
+		t, err = time.Parse("15:04:05", clock)
+	} else {
+		t, err = time.Parse("15:04", clock)
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time of day %q: %w", clock, err)
+	}
+	y, m, d := date.Date()
+	return time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), 0, loc), nil
+}
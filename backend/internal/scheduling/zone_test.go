@@ -0,0 +1,57 @@
+package scheduling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAt(t *testing.T) {
+	algiers, err := LoadZone("")
+	require.NoError(t, err)
+	paris, err := LoadZone("Europe/Paris")
+	require.NoError(t, err)
+
+	date := time.Date(2026, 3, 29, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		clock string
+		loc   *time.Location
+		want  string
+	}{
+		{"algiers is utc+1", "16:00", algiers, "2026-03-29T15:00:00Z"},
+		{"postgres time text", "16:00:00", algiers, "2026-03-29T15:00:00Z"},
+		{"paris before the change", "01:30", paris, "2026-03-29T00:30:00Z"},
+		{"paris after the change", "16:00", paris, "2026-03-29T14:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := At(date, tt.clock, tt.loc)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.UTC().Format(time.RFC3339))
+		})
+	}
+
+	// Only the calendar day of date counts, whatever its zone.
+	late := time.Date(2026, 3, 29, 23, 30, 0, 0, time.FixedZone("", -5*3600))
+	got, err := At(late, "09:00", algiers)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-03-29T09:00:00+01:00", got.Format(time.RFC3339))
+
+	_, err = At(date, "9h", algiers)
+	assert.Error(t, err)
+}
+
+func TestLoadZone(t *testing.T) {
+	loc, err := LoadZone("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultZone, loc.String())
+
+	_, err = LoadZone("Mars/Olympus")
+	assert.ErrorIs(t, err, ErrUnknownZone)
+	_, err = LoadZone("Local")
+	assert.ErrorIs(t, err, ErrUnknownZone)
+}
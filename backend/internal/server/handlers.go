@@ -93,8 +93,8 @@ func (s *Server) handleLogin() gin.HandlerFunc           { return s.authHandler.
 func (s *Server) handlePhoneLogin() gin.HandlerFunc      { return s.authHandler.PhoneLogin }
 func (s *Server) handleVerifyOTP() gin.HandlerFunc       { return s.authHandler.VerifyOTP }
 func (s *Server) handleRefreshToken() gin.HandlerFunc    { return s.authHandler.RefreshToken }
-func (s *Server) handleForgotPassword() gin.HandlerFunc  { return notImplemented() }
-func (s *Server) handleResetPassword() gin.HandlerFunc   { return notImplemented() }
+func (s *Server) handleForgotPassword() gin.HandlerFunc  { return s.authHandler.ForgotPassword }
+func (s *Server) handleResetPassword() gin.HandlerFunc   { return s.authHandler.ResetPassword }
 
 // ─── User ────────────────────────────────────────────────────
 func (s *Server) handleGetProfile() gin.HandlerFunc        { return s.userHandler.GetProfile }
@@ -117,8 +117,17 @@ func (s *Server) handleUpdateOffering() gin.HandlerFunc       { return s.teacher
 func (s *Server) handleDeleteOffering() gin.HandlerFunc       { return s.teacherHandler.DeleteOffering }
 func (s *Server) handleSetAvailability() gin.HandlerFunc      { return s.teacherHandler.SetAvailability }
 func (s *Server) handleGetAvailability() gin.HandlerFunc      { return s.teacherHandler.GetAvailability }
+func (s *Server) handleGetSchedule() gin.HandlerFunc          { return s.teacherHandler.GetSchedule }
+func (s *Server) handleGetFreeSlots() gin.HandlerFunc         { return s.teacherHandler.FreeSlots }
+func (s *Server) handleListExceptions() gin.HandlerFunc       { return s.teacherHandler.ListExceptions }
+func (s *Server) handleCreateException() gin.HandlerFunc      { return s.teacherHandler.CreateException }
+func (s *Server) handleUpdateException() gin.HandlerFunc      { return s.teacherHandler.UpdateException }
+func (s *Server) handleDeleteException() gin.HandlerFunc      { return s.teacherHandler.DeleteException }
+func (s *Server) handleListHolidays() gin.HandlerFunc         { return s.teacherHandler.ListHolidays }
 func (s *Server) handleGetEarnings() gin.HandlerFunc          { return s.teacherHandler.GetEarnings }
-func (s *Server) handleRequestPayout() gin.HandlerFunc        { return notImplemented() }
+func (s *Server) handleRequestPayout() gin.HandlerFunc        { return s.payoutHandler.RequestPayout }
+func (s *Server) handleListPayouts() gin.HandlerFunc          { return s.payoutHandler.ListPayouts }
+func (s *Server) handlePayoutBalance() gin.HandlerFunc        { return s.payoutHandler.GetBalance }
 
 // ─── Student ─────────────────────────────────────────────────
 func (s *Server) handleStudentDashboard() gin.HandlerFunc   { return s.studentHandler.Dashboard }
@@ -141,7 +150,9 @@ func (s *Server) handleJoinSession() gin.HandlerFunc       { return s.sessionHan
 func (s *Server) handleCancelSession() gin.HandlerFunc     { return s.sessionHandler.CancelSession }
 func (s *Server) handleRescheduleSession() gin.HandlerFunc { return s.sessionHandler.RescheduleSession }
 func (s *Server) handleEndSession() gin.HandlerFunc        { return s.sessionHandler.EndSession }
-func (s *Server) handleGetRecording() gin.HandlerFunc      { return notImplemented() }
+func (s *Server) handleGetRecording() gin.HandlerFunc      { return s.sessionHandler.GetRecording }
+func (s *Server) handleLiveKitWebhook() gin.HandlerFunc    { return s.sessionHandler.LiveKitWebhook }
+func (s *Server) handlePaymentCallback() gin.HandlerFunc   { return s.paymentHandler.GatewayCallback }
 
 // ─── Course ──────────────────────────────────────────────────
 func (s *Server) handleCreateCourse() gin.HandlerFunc  { return s.courseHandler.CreateCourse }
@@ -170,7 +181,7 @@ func (s *Server) handleQuizResults() gin.HandlerFunc { return s.quizHandler.Quiz
 
 // ─── Payment ─────────────────────────────────────────────────
 func (s *Server) handleInitiatePayment() gin.HandlerFunc { return s.paymentHandler.InitiatePayment }
-func (s *Server) handleConfirmPayment() gin.HandlerFunc  { return s.paymentHandler.ConfirmPayment }
+func (s *Server) handleGetPayment() gin.HandlerFunc      { return s.paymentHandler.GetPayment }
 func (s *Server) handlePaymentHistory() gin.HandlerFunc  { return s.paymentHandler.PaymentHistory }
 func (s *Server) handleRefundPayment() gin.HandlerFunc   { return s.paymentHandler.RefundPayment }
 
@@ -182,6 +193,34 @@ func (s *Server) handleListSubscriptions() gin.HandlerFunc { return s.paymentHan
 func (s *Server) handleCancelSubscription() gin.HandlerFunc {
 	return s.paymentHandler.CancelSubscription
 }
+func (s *Server) handlePauseSubscription() gin.HandlerFunc {
+	return s.paymentHandler.PauseSubscription
+}
+func (s *Server) handleResumeSubscription() gin.HandlerFunc {
+	return s.paymentHandler.ResumeSubscription
+}
+
+// ─── Session Packages ────────────────────────────────────────
+func (s *Server) handleCreatePackage() gin.HandlerFunc   { return s.paymentHandler.CreatePackage }
+func (s *Server) handleListMyPackages() gin.HandlerFunc  { return s.paymentHandler.ListMyPackages }
+func (s *Server) handleUpdatePackage() gin.HandlerFunc   { return s.paymentHandler.UpdatePackage }
+func (s *Server) handleDeletePackage() gin.HandlerFunc   { return s.paymentHandler.DeactivatePackage }
+func (s *Server) handlePurchasePackage() gin.HandlerFunc { return s.paymentHandler.PurchasePackage }
+func (s *Server) handleGetTeacherPackages() gin.HandlerFunc {
+	return s.paymentHandler.ListTeacherPackages
+}
+func (s *Server) handleListPackagePurchases() gin.HandlerFunc {
+	return s.paymentHandler.ListPackagePurchases
+}
+
+// ─── Promotions ──────────────────────────────────────────────
+func (s *Server) handleCreatePromotion() gin.HandlerFunc { return s.promotionHandler.CreatePromotion }
+func (s *Server) handleListPromotions() gin.HandlerFunc  { return s.promotionHandler.ListPromotions }
+func (s *Server) handleUpdatePromotion() gin.HandlerFunc { return s.promotionHandler.UpdatePromotion }
+func (s *Server) handleDeletePromotion() gin.HandlerFunc {
+	return s.promotionHandler.DeactivatePromotion
+}
+func (s *Server) handleQuotePromotion() gin.HandlerFunc { return s.promotionHandler.Quote }
 
 // ─── Review ──────────────────────────────────────────────────
 func (s *Server) handleCreateReview() gin.HandlerFunc      { return s.reviewHandler.CreateReview }
@@ -225,3 +264,5 @@ func (s *Server) handleAdminAnalyticsRevenue() gin.HandlerFunc {
 }
 func (s *Server) handleAdminUpdateSubjects() gin.HandlerFunc { return s.adminHandler.UpdateSubjects }
 func (s *Server) handleAdminUpdateLevels() gin.HandlerFunc   { return s.adminHandler.UpdateLevels }
+func (s *Server) handleAdminUpdateHolidays() gin.HandlerFunc { return s.adminHandler.UpdateHolidays }
+func (s *Server) handleAdminDeleteHoliday() gin.HandlerFunc  { return s.adminHandler.DeleteHoliday }
@@ -47,6 +47,14 @@ func (s *Server) setupRoutes() {
 	v1.GET("/levels", s.handleGetLevels())
 	v1.GET("/subjects", s.handleGetSubjects())
 
+	// ── Webhooks (signature-verified, no JWT) ───────────────────
+	webhooks := v1.Group("/webhooks")
+	{
+		webhooks.POST("/livekit", s.handleLiveKitWebhook())
+		webhooks.GET("/payments/:gateway", s.handlePaymentCallback())
+		webhooks.POST("/payments/:gateway", s.handlePaymentCallback())
+	}
+
 	// ── Protected routes ────────────────────────────────────────
 	protected := v1.Group("")
 	protected.Use(middleware.Auth(s.deps.Config.JWT.Secret))
@@ -67,6 +75,7 @@ func (s *Server) setupRoutes() {
 		teachers.GET("", s.handleListTeachers())                      // search & list
 		teachers.GET("/:id", s.handleGetTeacher())                    // public profile
 		teachers.GET("/:id/offerings", s.handleGetTeacherOfferings()) // public offerings
+		teachers.GET("/:id/packages", s.handleGetTeacherPackages())   // packages on sale
 		teachers.PUT("/profile", s.handleUpdateTeacherProfile())
 		teachers.GET("/dashboard", s.handleTeacherDashboard())
 
@@ -76,13 +85,34 @@ func (s *Server) setupRoutes() {
 		teachers.PUT("/offerings/:id", s.handleUpdateOffering())
 		teachers.DELETE("/offerings/:id", s.handleDeleteOffering())
 
+		// Session packages
+		teachers.POST("/packages", s.handleCreatePackage())
+		teachers.GET("/packages", s.handleListMyPackages())
+		teachers.PUT("/packages/:id", s.handleUpdatePackage())
+		teachers.DELETE("/packages/:id", s.handleDeletePackage())
+
+		// Promotions and coupon codes
+		teachers.POST("/promotions", s.handleCreatePromotion())
+		teachers.GET("/promotions", s.handleListPromotions())
+		teachers.PUT("/promotions/:id", s.handleUpdatePromotion())
+		teachers.DELETE("/promotions/:id", s.handleDeletePromotion())
+
 		// Availability
 		teachers.PUT("/availability", s.handleSetAvailability())
 		teachers.GET("/:id/availability", s.handleGetAvailability())
+		teachers.GET("/:id/availability/days", s.handleGetSchedule()) // resolved per date
+		teachers.GET("/:id/free-slots", s.handleGetFreeSlots())       // bookable start times
+		teachers.GET("/availability/exceptions", s.handleListExceptions())
+		teachers.POST("/availability/exceptions", s.handleCreateException())
+		teachers.PUT("/availability/exceptions/:id", s.handleUpdateException())
+		teachers.DELETE("/availability/exceptions/:id", s.handleDeleteException())
+		teachers.GET("/holidays", s.handleListHolidays()) // national calendar teachers can observe
 
 		// Earnings
 		teachers.GET("/earnings", s.handleGetEarnings())
 		teachers.POST("/payouts", s.handleRequestPayout())
+		teachers.GET("/payouts", s.handleListPayouts())
+		teachers.GET("/payouts/balance", s.handlePayoutBalance())
 	}
 
 	// ── Student routes ──────────────────────────────────────────
@@ -133,8 +163,9 @@ func (s *Server) setupRoutes() {
 			series.PUT("/:id/requests/:enrollmentId/decline", s.seriesHandler.DeclineRequest)
 			series.DELETE("/:id/students/:studentId", s.seriesHandler.RemoveStudent)
 
-			// Student requests to join
+			// Student requests to join, or leaves
 			series.POST("/:id/request", s.seriesHandler.RequestToJoin)
+			series.POST("/:id/withdraw", s.seriesHandler.Withdraw)
 		}
 	}
 
@@ -146,20 +177,14 @@ func (s *Server) setupRoutes() {
 		invitations.POST("/:id/decline", s.seriesHandler.DeclineInvitation)
 	}
 
-	// ── Platform Fees (legacy — kept for backward compat) ──────
-	fees := protected.Group("/fees")
-	{
-		fees.GET("/pending", s.seriesHandler.ListPendingFees)
-		fees.POST("/:id/confirm", s.seriesHandler.ConfirmPayment)
-	}
-
 	// ── Wallet routes (teacher credit system) ───────────────────
 	walletRoutes := protected.Group("/wallet")
 	{
-		walletRoutes.GET("", s.walletHandler.GetWallet)                     // GET /wallet
-		walletRoutes.POST("/buy", s.walletHandler.BuyCredits)               // POST /wallet/buy
-		walletRoutes.GET("/transactions", s.walletHandler.ListTransactions) // GET /wallet/transactions
-		walletRoutes.GET("/packages", s.walletHandler.ListPackages)         // GET /wallet/packages
+		walletRoutes.GET("", s.walletHandler.GetWallet)                            // GET /wallet
+		walletRoutes.POST("/buy", s.walletHandler.BuyCredits)                      // POST /wallet/buy
+		walletRoutes.POST("/purchases/:id/receipt", s.walletHandler.UploadReceipt) // POST /wallet/purchases/:id/receipt
+		walletRoutes.GET("/transactions", s.walletHandler.ListTransactions)        // GET /wallet/transactions
+		walletRoutes.GET("/packages", s.walletHandler.ListPackages)                // GET /wallet/packages
 	}
 
 	// ── Booking routes (Student/Parent books sessions) ──────────
@@ -217,17 +242,40 @@ func (s *Server) setupRoutes() {
 	payments := protected.Group("/payments")
 	{
 		payments.POST("/initiate", s.handleInitiatePayment())
-		payments.POST("/confirm", s.handleConfirmPayment())
 		payments.GET("/history", s.handlePaymentHistory())
+		payments.GET("/:id", s.handleGetPayment())
 		payments.POST("/refund", s.handleRefundPayment())
 	}
 
+	// ── Invoice routes (purchases and payments) ─────────────────
+	invoices := protected.Group("/invoices")
+	{
+		invoices.GET("", s.invoiceHandler.ListInvoices)
+		invoices.GET("/:id", s.invoiceHandler.GetInvoice)
+		invoices.GET("/:id/pdf", s.invoiceHandler.DownloadInvoice)
+	}
+
 	// ── Subscription routes ─────────────────────────────────────
 	subscriptions := protected.Group("/subscriptions")
 	{
 		subscriptions.POST("", s.handleCreateSubscription())
 		subscriptions.GET("", s.handleListSubscriptions())
 		subscriptions.DELETE("/:id", s.handleCancelSubscription())
+		subscriptions.PUT("/:id/pause", s.handlePauseSubscription())
+		subscriptions.PUT("/:id/resume", s.handleResumeSubscription())
+	}
+
+	// ── Session package routes (students & parents buy) ────────
+	packages := protected.Group("/packages")
+	{
+		packages.POST("/:id/purchase", s.handlePurchasePackage())
+		packages.GET("/purchases", s.handleListPackagePurchases())
+	}
+
+	// ── Promotion routes (students & parents check a code) ─────
+	promotions := protected.Group("/promotions")
+	{
+		promotions.POST("/quote", s.handleQuotePromotion())
 	}
 
 	// ── Review routes ───────────────────────────────────────────
@@ -274,12 +322,37 @@ func (s *Server) setupRoutes() {
 
 		admin.PUT("/config/subjects", s.handleAdminUpdateSubjects())
 		admin.PUT("/config/levels", s.handleAdminUpdateLevels())
-
-		// Platform fees verification (legacy)
-		admin.PUT("/fees/:id/verify", s.seriesHandler.AdminVerifyPayment)
+		admin.PUT("/config/holidays", s.handleAdminUpdateHolidays())
+		admin.DELETE("/config/holidays/:id", s.handleAdminDeleteHoliday())
 
 		// Wallet purchase verification
 		admin.GET("/wallet/purchases", s.walletHandler.AdminListPendingPurchases)
 		admin.PUT("/wallet/purchases/:id/verify", s.walletHandler.AdminApprovePurchase)
+		admin.POST("/wallet/refunds", s.walletHandler.AdminRefundStar)
+
+		// Wallet pricing: credit packages and versioned star prices
+		admin.GET("/wallet/packages", s.walletHandler.AdminListPackages)
+		admin.POST("/wallet/packages", s.walletHandler.AdminCreatePackage)
+		admin.PUT("/wallet/packages/:id", s.walletHandler.AdminUpdatePackage)
+		admin.DELETE("/wallet/packages/:id", s.walletHandler.AdminDeletePackage)
+		admin.GET("/wallet/star-prices", s.walletHandler.AdminListStarPrices)
+		admin.POST("/wallet/star-prices", s.walletHandler.AdminCreateStarPrice)
+		admin.PUT("/wallet/star-prices/:id/end", s.walletHandler.AdminEndStarPrice)
+
+		// Teacher payouts
+		admin.GET("/payouts", s.payoutHandler.AdminListPayouts)
+		admin.PUT("/payouts/:id/approve", s.payoutHandler.AdminApprovePayout)
+		admin.PUT("/payouts/:id/reject", s.payoutHandler.AdminRejectPayout)
+		admin.PUT("/payouts/:id/paid", s.payoutHandler.AdminMarkPaid)
+
+		// Ledger (finance)
+		admin.GET("/ledger/balances", s.ledgerHandler.Balances)
+		admin.GET("/ledger/reconciliation", s.ledgerHandler.Reconcile)
+
+		// Invoices & credit notes
+		admin.GET("/invoices", s.invoiceHandler.AdminListInvoices)
+		admin.POST("/invoices", s.invoiceHandler.AdminIssueInvoice)
+		admin.POST("/invoices/:id/credit-notes", s.invoiceHandler.AdminCreditNote)
+		admin.POST("/invoices/:id/regenerate", s.invoiceHandler.AdminRegenerate)
 	}
 }
@@ -6,15 +6,21 @@ import (
 	"log/slog"
 	"net/http"
 
+	"educonnect/internal/access"
 	"educonnect/internal/admin"
 	"educonnect/internal/auth"
 	"educonnect/internal/booking"
 	"educonnect/internal/config"
 	"educonnect/internal/course"
+	"educonnect/internal/events"
 	"educonnect/internal/homework"
+	"educonnect/internal/invoice"
+	"educonnect/internal/ledger"
 	"educonnect/internal/notification"
 	"educonnect/internal/parent"
 	"educonnect/internal/payment"
+	"educonnect/internal/payout"
+	"educonnect/internal/promotion"
 	"educonnect/internal/quiz"
 	"educonnect/internal/review"
 	searchmod "educonnect/internal/search"
@@ -27,8 +33,11 @@ import (
 	"educonnect/pkg/cache"
 	"educonnect/pkg/database"
 	"educonnect/pkg/livekit"
+	"educonnect/pkg/mailer"
 	"educonnect/pkg/messaging"
+	"educonnect/pkg/paygate"
 	"educonnect/pkg/search"
+	"educonnect/pkg/sms"
 	"educonnect/pkg/storage"
 
 	"github.com/gin-gonic/gin"
@@ -44,6 +53,9 @@ type Dependencies struct {
 	Storage *storage.MinIO
 	Search  *search.Meilisearch
 	LiveKit *livekit.Client
+	Mailer  *mailer.Mailer
+	SMS     sms.SMSSender
+	Payment *paygate.Gateways
 }
 
 // Server wraps the HTTP server and dependencies.
@@ -68,6 +80,10 @@ type Server struct {
 	seriesHandler       *sessionseries.Handler
 	bookingHandler      *booking.Handler
 	walletHandler       *wallet.Handler
+	payoutHandler       *payout.Handler
+	ledgerHandler       *ledger.Handler
+	invoiceHandler      *invoice.Handler
+	promotionHandler    *promotion.Handler
 }
 
 // New creates a new Server instance and sets up routes.
@@ -78,8 +94,11 @@ func New(deps *Dependencies) *Server {
 
 	router := gin.New()
 
+	// Domain events are published here and consumed by cmd/worker
+	publisher := events.NewPublisher(deps.MQ)
+
 	// Initialize services and handlers
-	authService := auth.NewService(deps.DB, deps.Cache, deps.Config, deps.Search)
+	authService := auth.NewService(deps.DB, deps.Cache, deps.Config, deps.Search, deps.Mailer, deps.SMS)
 	authHandler := auth.NewHandler(authService)
 
 	userService := user.NewService(deps.DB, deps.Cache, deps.Storage)
@@ -94,7 +113,10 @@ func New(deps *Dependencies) *Server {
 	parentService := parent.NewService(deps.DB)
 	parentHandler := parent.NewHandler(parentService)
 
-	sessionService := session.NewService(deps.DB, deps.LiveKit)
+	// Both join paths (single sessions and series) share one access policy.
+	accessPolicy := access.NewPolicy(deps.DB)
+
+	sessionService := session.NewService(deps.DB, deps.LiveKit, deps.Storage, accessPolicy, publisher)
 	sessionHandler := session.NewHandler(sessionService)
 
 	searchService := searchmod.NewService(deps.Search)
@@ -103,7 +125,7 @@ func New(deps *Dependencies) *Server {
 	courseService := course.NewService(deps.DB, deps.Storage)
 	courseHandler := course.NewHandler(courseService)
 
-	homeworkService := homework.NewService(deps.DB)
+	homeworkService := homework.NewService(deps.DB, publisher)
 	homeworkHandler := homework.NewHandler(homeworkService)
 
 	quizService := quiz.NewService(deps.DB)
@@ -115,19 +137,31 @@ func New(deps *Dependencies) *Server {
 	notificationService := notification.NewService(deps.DB)
 	notificationHandler := notification.NewHandler(notificationService)
 
-	paymentService := payment.NewService(deps.DB)
+	paymentService := payment.NewService(deps.DB, deps.Payment, deps.Config.App.URL, deps.Config.Payment.ReturnURL)
 	paymentHandler := payment.NewHandler(paymentService)
 
+	promotionService := promotion.NewService(deps.DB)
+	promotionHandler := promotion.NewHandler(promotionService)
+
 	adminService := admin.NewService(deps.DB)
 	adminHandler := admin.NewHandler(adminService)
 
-	walletService := wallet.NewService(deps.DB)
+	walletService := wallet.NewService(deps.DB, deps.Storage, publisher, deps.Config.Wallet)
 	walletHandler := wallet.NewHandler(walletService)
 
-	seriesService := sessionseries.NewService(deps.DB, deps.LiveKit, walletService)
+	payoutService := payout.NewService(deps.DB, publisher)
+	payoutHandler := payout.NewHandler(payoutService)
+
+	ledgerService := ledger.NewService(deps.DB)
+	ledgerHandler := ledger.NewHandler(ledgerService)
+
+	invoiceService := invoice.NewService(deps.DB, deps.Storage, deps.Config.Invoice)
+	invoiceHandler := invoice.NewHandler(invoiceService)
+
+	seriesService := sessionseries.NewService(deps.DB, deps.LiveKit, walletService, accessPolicy, publisher)
 	seriesHandler := sessionseries.NewHandler(seriesService)
 
-	bookingService := booking.NewService(deps.DB, notificationService)
+	bookingService := booking.NewService(deps.DB, notificationService, publisher)
 	bookingHandler := booking.NewHandler(bookingService)
 
 	s := &Server{
@@ -150,6 +184,10 @@ func New(deps *Dependencies) *Server {
 		seriesHandler:       seriesHandler,
 		bookingHandler:      bookingHandler,
 		walletHandler:       walletHandler,
+		payoutHandler:       payoutHandler,
+		ledgerHandler:       ledgerHandler,
+		invoiceHandler:      invoiceHandler,
+		promotionHandler:    promotionHandler,
 		httpServer: &http.Server{
 			Addr:    fmt.Sprintf(":%s", deps.Config.App.Port),
 			Handler: router,
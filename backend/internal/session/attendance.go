@@ -0,0 +1,169 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	lkproto "github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/webhook"
+)
+
+var ErrInvalidWebhook = errors.New("invalid webhook signature")
+
+// lateAfter is how long after start_time a first join still counts as present.
+const lateAfter = 10 * time.Minute
+
+// attendanceFor grades a student's first join against the session start.
+func attendanceFor(start, firstJoin time.Time) string {
+	if firstJoin.After(start.Add(lateAfter)) {
+		return "late"
+	}
+	return "present"
+}
+
+// ─── LiveKit Webhook ────────────────────────────────────────────
+
+// HandleWebhook verifies a LiveKit webhook request and applies it:
+// participant_joined / participant_left record real join and leave times,
+// room_finished completes the session and egress_ended settles its
+// recording. Events for rooms that do not belong to a session are ignored.
+// LiveKit retries on failure, so every update is idempotent.
+func (s *Service) HandleWebhook(ctx context.Context, r *http.Request) error {
+	if s.livekit == nil {
+		return ErrInvalidWebhook
+	}
+	evt, err := s.livekit.ReceiveWebhook(r)
+	if err != nil {
+		slog.Warn("rejected LiveKit webhook", "error", err)
+		return ErrInvalidWebhook
+	}
+	if evt.Event == webhook.EventEgressEnded && evt.EgressInfo != nil {
+		return s.finishRecording(ctx, evt.EgressInfo)
+	}
+	if evt.Room == nil {
+		return nil
+	}
+
+	var (
+		sid, teacherID uuid.UUID
+		seriesID       *uuid.UUID
+		start          time.Time
+	)
+	err = s.db.Pool.QueryRow(ctx,
+		`SELECT id, teacher_id, series_id, start_time FROM sessions WHERE livekit_room_id = $1`, evt.Room.Name,
+	).Scan(&sid, &teacherID, &seriesID, &start)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("find session for room: %w", err)
+	}
+
+	switch evt.Event {
+	case webhook.EventParticipantJoined:
+		uid, ok := studentIdentity(evt.Participant, teacherID)
+		if !ok {
+			return nil
+		}
+		joinedAt := eventTime(evt)
+		if evt.Participant.JoinedAt > 0 {
+			joinedAt = time.Unix(evt.Participant.JoinedAt, 0)
+		}
+		return s.recordJoin(ctx, sid, uid, joinedAt, attendanceFor(start, joinedAt))
+
+	case webhook.EventParticipantLeft:
+		uid, ok := studentIdentity(evt.Participant, teacherID)
+		if !ok {
+			return nil
+		}
+		_, err := s.db.Pool.Exec(ctx,
+			`UPDATE session_participants SET left_at = $3 WHERE session_id = $1 AND student_id = $2`,
+			sid, uid, eventTime(evt),
+		)
+		if err != nil {
+			return fmt.Errorf("record leave: %w", err)
+		}
+		return nil
+
+	case webhook.EventRoomFinished:
+		// The room is already closed, so there is nothing to delete.
+		_, err := s.markCompleted(ctx, sid, seriesID, teacherID, "")
+		return err
+	}
+	return nil
+}
+
+// recordJoin keeps the first join time across reconnects and only grades
+// attendance on that first join; an 'excused' mark set by the teacher wins.
+func (s *Service) recordJoin(ctx context.Context, sid, studentID uuid.UUID, joinedAt time.Time, attendance string) error {
+	_, err := s.db.Pool.Exec(ctx,
+		`INSERT INTO session_participants (session_id, student_id, joined_at, attendance)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (session_id, student_id) DO UPDATE SET
+		     left_at    = NULL,
+		     attendance = CASE
+		         WHEN session_participants.attendance = 'excused' THEN session_participants.attendance
+		         WHEN session_participants.joined_at IS NOT NULL THEN session_participants.attendance
+		         ELSE EXCLUDED.attendance
+		     END,
+		     joined_at  = COALESCE(session_participants.joined_at, EXCLUDED.joined_at)`,
+		sid, studentID, joinedAt, attendance,
+	)
+	if err != nil {
+		return fmt.Errorf("record join: %w", err)
+	}
+	return nil
+}
+
+// finalizeAttendance runs when a session completes: accepted series enrollees
+// who never joined get an 'absent' row, and anyone still connected is
+// stamped as having left now.
+func finalizeAttendance(ctx context.Context, tx pgx.Tx, sid uuid.UUID) error {
+	_, err := tx.Exec(ctx,
+		`INSERT INTO session_participants (session_id, student_id, attendance)
+		 SELECT s.id, e.student_id, 'absent'
+		 FROM sessions s
+		 JOIN session_enrollments e ON e.series_id = s.series_id AND e.status = 'accepted'
+		 WHERE s.id = $1
+		 ON CONFLICT (session_id, student_id) DO NOTHING`, sid,
+	)
+	if err != nil {
+		return fmt.Errorf("insert absentees: %w", err)
+	}
+	_, err = tx.Exec(ctx,
+		`UPDATE session_participants
+		 SET left_at    = CASE WHEN joined_at IS NOT NULL THEN COALESCE(left_at, NOW()) END,
+		     attendance = CASE WHEN joined_at IS NULL AND attendance <> 'excused' THEN 'absent' ELSE attendance END
+		 WHERE session_id = $1`, sid,
+	)
+	if err != nil {
+		return fmt.Errorf("close attendance: %w", err)
+	}
+	return nil
+}
+
+// studentIdentity maps a LiveKit participant to a student user ID. Tokens
+// are issued with the user ID as identity; the teacher is not tracked.
+func studentIdentity(p *lkproto.ParticipantInfo, teacherID uuid.UUID) (uuid.UUID, bool) {
+	if p == nil {
+		return uuid.Nil, false
+	}
+	uid, err := uuid.Parse(p.Identity)
+	if err != nil || uid == teacherID {
+		return uuid.Nil, false
+	}
+	return uid, true
+}
+
+func eventTime(evt *lkproto.WebhookEvent) time.Time {
+	if evt.CreatedAt > 0 {
+		return time.Unix(evt.CreatedAt, 0)
+	}
+	return time.Now()
+}
@@ -17,6 +17,9 @@ type SessionResponse struct {
 	RoomID       string             `json:"room_id,omitempty"`
 	RecordingURL string             `json:"recording_url,omitempty"`
 	Participants []ParticipantBrief `json:"participants,omitempty"`
+
+	RecordingEnabled bool   `json:"recording_enabled"`
+	RecordingStatus  string `json:"recording_status,omitempty"` // recording, ready, failed
 }
 
 type ParticipantBrief struct {
@@ -32,6 +35,12 @@ type JoinSessionResponse struct {
 	IsTeacher bool   `json:"is_teacher"`
 }
 
+type RecordingResponse struct {
+	SessionID string `json:"session_id"`
+	URL       string `json:"url"`
+	ExpiresAt string `json:"expires_at"`
+}
+
 // ─── Requests ───────────────────────────────────────────────────
 
 type CreateSessionRequest struct {
@@ -43,6 +52,8 @@ type CreateSessionRequest struct {
 	EndTime     string  `json:"end_time" binding:"required"`
 	MaxStudents int     `json:"max_students" binding:"required,min=1,max=50"`
 	Price       float64 `json:"price" binding:"required"`
+
+	RecordingEnabled bool `json:"recording_enabled"`
 }
 
 type RescheduleSessionRequest struct {
@@ -5,7 +5,9 @@ import (
 	"net/http"
 	"strconv"
 
+	"educonnect/internal/access"
 	"educonnect/internal/middleware"
+	"educonnect/internal/scheduling"
 
 	"github.com/gin-gonic/gin"
 )
@@ -152,13 +154,54 @@ func (h *Handler) EndSession(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"message": "session ended"}})
 }
 
+// GetRecording GET /sessions/:id/recording
+func (h *Handler) GetRecording(c *gin.Context) {
+	sessionID := c.Param("id")
+	userID := middleware.GetUserID(c)
+
+	rec, err := h.service.GetRecording(c.Request.Context(), sessionID, userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": rec})
+}
+
+// LiveKitWebhook POST /webhooks/livekit
+// Authenticated by the LiveKit request signature rather than a user token.
+func (h *Handler) LiveKitWebhook(c *gin.Context) {
+	err := h.service.HandleWebhook(c.Request.Context(), c.Request)
+	if err != nil {
+		if errors.Is(err, ErrInvalidWebhook) {
+			c.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": gin.H{"message": "invalid webhook signature"}})
+			return
+		}
+		// Non-2xx makes LiveKit retry the delivery.
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"message": "internal server error"}})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
 func respondError(c *gin.Context, err error) {
 	switch {
-	case errors.Is(err, ErrSessionNotFound):
+	case errors.Is(err, ErrSessionNotFound), errors.Is(err, access.ErrSessionNotFound):
 		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"message": "session not found"}})
+	case errors.Is(err, access.ErrNotEnrolled), errors.Is(err, access.ErrSeatReleased):
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "error": gin.H{
+			"code":    "NOT_ENROLLED",
+			"message": err.Error(),
+		}})
 	case errors.Is(err, ErrUnauthorized):
 		c.JSON(http.StatusForbidden, gin.H{"success": false, "error": gin.H{"message": "unauthorized"}})
-	case errors.Is(err, ErrInvalidStatus):
+	case errors.Is(err, ErrRecordingNotAvailable):
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"message": "recording not available"}})
+	case errors.Is(err, scheduling.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"success": false, "error": gin.H{
+			"code":    "TIME_CONFLICT",
+			"message": err.Error(),
+		}})
+	case errors.Is(err, ErrInvalidStatus), errors.Is(err, access.ErrNotJoinable):
 		c.JSON(http.StatusConflict, gin.H{"success": false, "error": gin.H{"message": "session status does not allow this action"}})
 	default:
 		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"message": "internal server error"}})
@@ -0,0 +1,280 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"educonnect/internal/events"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ─── Automatic Lifecycle ────────────────────────────────────────
+//
+// Sessions normally go live when someone joins and complete when the
+// teacher calls EndSession. The sweep below covers everything else:
+//
+//	scheduled → live       someone is in the LiveKit room
+//	scheduled → no_show    end_time + grace passed and nobody ever joined,
+//	                       or end_time + maxOverrun passed regardless
+//	live      → completed  end_time + grace passed and the room is empty/gone,
+//	                       or end_time + maxOverrun passed regardless
+
+const (
+	lifecycleGrace      = 10 * time.Minute
+	lifecycleMaxOverrun = 2 * time.Hour
+	lifecycleBatchSize  = 200
+)
+
+// roomState is what the sweep knows about a session's LiveKit room.
+type roomState struct {
+	known        bool // false when LiveKit could not be queried
+	participants int
+}
+
+// LifecycleResult counts the transitions applied by one sweep.
+type LifecycleResult struct {
+	Started   int
+	Completed int
+	NoShow    int
+}
+
+// nextStatus decides the transition for a session, or "" to leave it alone.
+func nextStatus(status string, start, end, now time.Time, room roomState) string {
+	switch status {
+	case "scheduled":
+		if room.participants > 0 && !now.Before(start) {
+			return "live"
+		}
+		// Joining goes through JoinSession, which marks the session live;
+		// past the cap nobody did, even if LiveKit cannot tell.
+		if !now.Before(end.Add(lifecycleMaxOverrun)) {
+			return "no_show"
+		}
+		if room.known && room.participants == 0 && !now.Before(end.Add(lifecycleGrace)) {
+			return "no_show"
+		}
+	case "live":
+		if !now.Before(end.Add(lifecycleMaxOverrun)) {
+			return "completed"
+		}
+		if room.known && room.participants == 0 && !now.Before(end.Add(lifecycleGrace)) {
+			return "completed"
+		}
+	}
+	return ""
+}
+
+// RunLifecycle applies automatic transitions to sessions that are due.
+// It is idempotent: every update is guarded by the expected current status.
+func (s *Service) RunLifecycle(ctx context.Context) (*LifecycleResult, error) {
+	rows, err := s.db.Pool.Query(ctx,
+		`SELECT id, teacher_id, series_id, status::text, start_time, end_time, COALESCE(livekit_room_id,'')
+		 FROM sessions
+		 WHERE (status = 'live' AND end_time <= NOW() - make_interval(secs => $1))
+		    OR (status = 'scheduled' AND start_time <= NOW())
+		 ORDER BY end_time
+		 LIMIT $2`,
+		lifecycleGrace.Seconds(), lifecycleBatchSize,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list due sessions: %w", err)
+	}
+
+	type candidate struct {
+		id         uuid.UUID
+		teacherID  uuid.UUID
+		seriesID   *uuid.UUID
+		status     string
+		start, end time.Time
+		roomID     string
+	}
+	var due []candidate
+	for rows.Next() {
+		var c candidate
+		if err := rows.Scan(&c.id, &c.teacherID, &c.seriesID, &c.status, &c.start, &c.end, &c.roomID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan session: %w", err)
+		}
+		due = append(due, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate sessions: %w", err)
+	}
+
+	res := &LifecycleResult{}
+	for _, c := range due {
+		room := s.roomState(ctx, c.roomID)
+		now := time.Now()
+
+		switch nextStatus(c.status, c.start, c.end, now, room) {
+		case "live":
+			ok, err := s.markLive(ctx, c.id, c.seriesID, c.teacherID, c.roomID)
+			if err != nil {
+				slog.Error("session lifecycle: mark live failed", "session_id", c.id, "error", err)
+			} else if ok {
+				res.Started++
+			}
+		case "completed":
+			ok, err := s.markCompleted(ctx, c.id, c.seriesID, c.teacherID, c.roomID)
+			if err != nil {
+				slog.Error("session lifecycle: complete failed", "session_id", c.id, "error", err)
+			} else if ok {
+				res.Completed++
+			}
+		case "no_show":
+			ok, err := s.markNoShow(ctx, c.id, c.seriesID, c.teacherID, c.start, c.end)
+			if err != nil {
+				slog.Error("session lifecycle: no-show failed", "session_id", c.id, "error", err)
+			} else if ok {
+				res.NoShow++
+			}
+		}
+	}
+	return res, nil
+}
+
+// roomState looks the room up in LiveKit. Sessions without a room, or a
+// room LiveKit already closed, count as known and empty.
+func (s *Service) roomState(ctx context.Context, roomID string) roomState {
+	if roomID == "" {
+		return roomState{known: true}
+	}
+	if s.livekit == nil {
+		return roomState{}
+	}
+	room, err := s.livekit.GetRoom(ctx, roomID)
+	if err != nil {
+		slog.Warn("session lifecycle: room lookup failed", "room", roomID, "error", err)
+		return roomState{}
+	}
+	if room == nil {
+		return roomState{known: true}
+	}
+	return roomState{known: true, participants: int(room.NumParticipants)}
+}
+
+func (s *Service) markLive(ctx context.Context, sid uuid.UUID, seriesID *uuid.UUID, teacherID uuid.UUID, roomID string) (bool, error) {
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx,
+		`UPDATE sessions SET status = 'live', actual_start = COALESCE(actual_start, NOW())
+		 WHERE id = $1 AND status = 'scheduled'`, sid,
+	)
+	if err != nil {
+		return false, fmt.Errorf("update session: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	if err := events.Enqueue(ctx, tx, events.SessionStarted{
+		SessionID: sid,
+		SeriesID:  seriesID,
+		TeacherID: teacherID,
+		RoomID:    roomID,
+		StartedAt: time.Now(),
+	}); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit: %w", err)
+	}
+	return true, nil
+}
+
+// markCompleted stamps actual_end, finalizes attendance, stops any running
+// recording and closes the LiveKit room, disconnecting anyone still inside. It reports false if the
+// session was no longer live.
+func (s *Service) markCompleted(ctx context.Context, sid uuid.UUID, seriesID *uuid.UUID, teacherID uuid.UUID, roomID string) (bool, error) {
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var egressID, recordingStatus string
+	err = tx.QueryRow(ctx,
+		`UPDATE sessions SET status = 'completed', actual_end = NOW()
+		 WHERE id = $1 AND status = 'live'
+		 RETURNING COALESCE(recording_egress_id,''), COALESCE(recording_status,'')`, sid,
+	).Scan(&egressID, &recordingStatus)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("update session: %w", err)
+	}
+	if err := finalizeAttendance(ctx, tx, sid); err != nil {
+		return false, err
+	}
+
+	if err := events.Enqueue(ctx, tx, events.SessionEnded{
+		SessionID: sid,
+		SeriesID:  seriesID,
+		TeacherID: teacherID,
+		EndedAt:   time.Now(),
+	}); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit: %w", err)
+	}
+
+	if recordingStatus == "recording" && s.livekit != nil {
+		// Stopping explicitly lets Egress finalize the file before the room
+		// goes away; egress_ended then marks the recording ready.
+		if err := s.livekit.StopRecording(ctx, egressID); err != nil {
+			slog.Warn("session lifecycle: stop recording", "egress_id", egressID, "error", err)
+		}
+	}
+	if roomID != "" && s.livekit != nil {
+		if err := s.livekit.DeleteRoom(ctx, roomID); err != nil {
+			// The room may already be gone; LiveKit also closes it on its own
+			// once empty, so this is not worth failing the transition for.
+			slog.Debug("session lifecycle: delete room", "room", roomID, "error", err)
+		}
+	}
+	return true, nil
+}
+
+func (s *Service) markNoShow(ctx context.Context, sid uuid.UUID, seriesID *uuid.UUID, teacherID uuid.UUID, start, end time.Time) (bool, error) {
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx,
+		`UPDATE sessions SET status = 'no_show' WHERE id = $1 AND status = 'scheduled'`, sid,
+	)
+	if err != nil {
+		return false, fmt.Errorf("update session: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	if err := events.Enqueue(ctx, tx, events.SessionNoShow{
+		SessionID: sid,
+		SeriesID:  seriesID,
+		TeacherID: teacherID,
+		StartTime: start,
+		EndTime:   end,
+	}); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,51 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextStatus(t *testing.T) {
+	start := time.Date(2025, 3, 10, 16, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	empty := roomState{known: true}
+	occupied := roomState{known: true, participants: 2}
+	unknown := roomState{}
+
+	cases := []struct {
+		name   string
+		status string
+		now    time.Time
+		room   roomState
+		want   string
+	}{
+		{"scheduled, room occupied after start", "scheduled", start.Add(5 * time.Minute), occupied, "live"},
+		{"scheduled, occupied before start", "scheduled", start.Add(-5 * time.Minute), occupied, ""},
+		{"scheduled, empty within grace", "scheduled", end.Add(5 * time.Minute), empty, ""},
+		{"scheduled, empty after grace", "scheduled", end.Add(lifecycleGrace), empty, "no_show"},
+		{"scheduled, LiveKit unreachable", "scheduled", end.Add(time.Hour), unknown, ""},
+		{"scheduled, LiveKit unreachable past cap", "scheduled", end.Add(lifecycleMaxOverrun), unknown, "no_show"},
+		{"live, still running", "live", end.Add(-time.Minute), empty, ""},
+		{"live, empty after grace", "live", end.Add(lifecycleGrace), empty, "completed"},
+		{"live, occupied overrun", "live", end.Add(time.Hour), occupied, ""},
+		{"live, past hard cap", "live", end.Add(lifecycleMaxOverrun), occupied, "completed"},
+		{"live, LiveKit unreachable past cap", "live", end.Add(lifecycleMaxOverrun), unknown, "completed"},
+		{"completed untouched", "completed", end.Add(24 * time.Hour), empty, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, nextStatus(tc.status, start, end, tc.now, tc.room))
+		})
+	}
+}
+
+func TestAttendanceFor(t *testing.T) {
+	start := time.Date(2025, 3, 10, 16, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "present", attendanceFor(start, start.Add(-2*time.Minute)))
+	assert.Equal(t, "present", attendanceFor(start, start.Add(lateAfter)))
+	assert.Equal(t, "late", attendanceFor(start, start.Add(lateAfter+time.Second)))
+}
@@ -0,0 +1,147 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"educonnect/internal/events"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	lkproto "github.com/livekit/protocol/livekit"
+)
+
+var ErrRecordingNotAvailable = errors.New("recording not available")
+
+// recordingURLExpiry bounds how long a presigned recording link stays valid.
+const recordingURLExpiry = time.Hour
+
+// ─── Recording ──────────────────────────────────────────────────
+
+// RegisterEventHandlers starts recordings when recording-enabled sessions go
+// live, whichever path (join, series join, lifecycle sweep) made them live.
+func (s *Service) RegisterEventHandlers(r *events.Runner) {
+	r.Handle("session-recording-start", events.TypeSessionStarted, 1, s.onSessionStarted)
+}
+
+func (s *Service) onSessionStarted(ctx context.Context, env *events.Envelope) error {
+	var e events.SessionStarted
+	if err := env.Decode(&e); err != nil {
+		return err
+	}
+	return s.StartRecording(ctx, e.SessionID)
+}
+
+// StartRecording starts a LiveKit room composite egress for a live session
+// with recording enabled. It does nothing if recording is disabled or a
+// recording was already started.
+func (s *Service) StartRecording(ctx context.Context, sid uuid.UUID) error {
+	if s.livekit == nil {
+		return nil
+	}
+
+	var roomID, status string
+	var enabled bool
+	var egressID *string
+	err := s.db.Pool.QueryRow(ctx,
+		`SELECT COALESCE(livekit_room_id,''), status::text, COALESCE(recording_enabled, false), recording_egress_id
+		 FROM sessions WHERE id = $1`, sid,
+	).Scan(&roomID, &status, &enabled, &egressID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("get session: %w", err)
+	}
+	if !enabled || status != "live" || roomID == "" || egressID != nil {
+		return nil
+	}
+
+	key := fmt.Sprintf("sessions/%s/%d.mp4", sid, time.Now().Unix())
+	id, err := s.livekit.StartRecording(ctx, roomID, key)
+	if err != nil {
+		return err
+	}
+
+	tag, err := s.db.Pool.Exec(ctx,
+		`UPDATE sessions SET recording_key = $2, recording_egress_id = $3, recording_status = 'recording'
+		 WHERE id = $1 AND recording_egress_id IS NULL`,
+		sid, key, id,
+	)
+	if err != nil || tag.RowsAffected() == 0 {
+		// Could not record it (or another worker won the race): don't leave
+		// an orphan egress running.
+		_ = s.livekit.StopRecording(ctx, id)
+		if err != nil {
+			return fmt.Errorf("store recording: %w", err)
+		}
+	}
+	return nil
+}
+
+// finishRecording records the outcome reported by an egress_ended webhook.
+func (s *Service) finishRecording(ctx context.Context, info *lkproto.EgressInfo) error {
+	status := "failed"
+	if info.Status == lkproto.EgressStatus_EGRESS_COMPLETE {
+		status = "ready"
+	} else {
+		slog.Warn("session recording failed", "egress_id", info.EgressId, "status", info.Status.String(), "error", info.Error)
+	}
+
+	_, err := s.db.Pool.Exec(ctx,
+		`UPDATE sessions SET recording_status = $2 WHERE recording_egress_id = $1`,
+		info.EgressId, status,
+	)
+	if err != nil {
+		return fmt.Errorf("update recording status: %w", err)
+	}
+	return nil
+}
+
+// GetRecording returns a short-lived download link for a session recording.
+// Only the teacher and the session's students (participants or accepted
+// series enrollees) may fetch it.
+func (s *Service) GetRecording(ctx context.Context, sessionID, userID string) (*RecordingResponse, error) {
+	sid, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+	uid, _ := uuid.Parse(userID)
+
+	var teacherID uuid.UUID
+	var key, status string
+	var allowed bool
+	err = s.db.Pool.QueryRow(ctx,
+		`SELECT s.teacher_id, COALESCE(s.recording_key,''), COALESCE(s.recording_status,''),
+		        s.teacher_id = $2
+		        OR EXISTS (SELECT 1 FROM session_participants sp WHERE sp.session_id = s.id AND sp.student_id = $2)
+		        OR EXISTS (SELECT 1 FROM session_enrollments e
+		                   WHERE e.series_id = s.series_id AND e.student_id = $2 AND e.status = 'accepted')
+		 FROM sessions s WHERE s.id = $1`, sid, uid,
+	).Scan(&teacherID, &key, &status, &allowed)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+	if !allowed {
+		return nil, ErrUnauthorized
+	}
+	if status != "ready" || key == "" || s.storage == nil {
+		return nil, ErrRecordingNotAvailable
+	}
+
+	url, err := s.storage.GetPresignedURL(ctx, s.storage.BucketRecordings(), key, recordingURLExpiry)
+	if err != nil {
+		return nil, fmt.Errorf("presign recording: %w", err)
+	}
+	return &RecordingResponse{
+		SessionID: sid.String(),
+		URL:       url,
+		ExpiresAt: time.Now().Add(recordingURLExpiry).Format(time.RFC3339),
+	}, nil
+}
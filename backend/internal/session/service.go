@@ -6,8 +6,12 @@ import (
 	"fmt"
 	"time"
 
+	"educonnect/internal/access"
+	"educonnect/internal/events"
+	"educonnect/internal/scheduling"
 	"educonnect/pkg/database"
 	lk "educonnect/pkg/livekit"
+	"educonnect/pkg/storage"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -22,10 +26,13 @@ var (
 type Service struct {
 	db      *database.Postgres
 	livekit *lk.Client
+	storage *storage.MinIO
+	access  *access.Policy
+	events  *events.Publisher
 }
 
-func NewService(db *database.Postgres, livekit *lk.Client) *Service {
-	return &Service{db: db, livekit: livekit}
+func NewService(db *database.Postgres, livekit *lk.Client, store *storage.MinIO, policy *access.Policy, pub *events.Publisher) *Service {
+	return &Service{db: db, livekit: livekit, storage: store, access: policy, events: pub}
 }
 
 // CreateSession creates a new tutoring session.
@@ -44,10 +51,14 @@ func (s *Service) CreateSession(ctx context.Context, teacherID string, req Creat
 		return nil, fmt.Errorf("invalid end_time: %w", err)
 	}
 
-	if endTime.Before(startTime) {
+	if !endTime.After(startTime) {
 		return nil, fmt.Errorf("end_time must be after start_time")
 	}
 
+	if err := scheduling.CheckFree(ctx, s.db.Pool, tuid, startTime, endTime); err != nil {
+		return nil, err
+	}
+
 	sessionID := uuid.New()
 	var offeringID *uuid.UUID
 	if req.OfferingID != "" {
@@ -58,11 +69,14 @@ func (s *Service) CreateSession(ctx context.Context, teacherID string, req Creat
 	}
 
 	_, err = s.db.Pool.Exec(ctx,
-		`INSERT INTO sessions (id, teacher_id, offering_id, title, description, session_type, start_time, end_time, max_participants, price, status)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, 'scheduled')`,
+		`INSERT INTO sessions (id, teacher_id, offering_id, title, description, session_type, start_time, end_time, max_participants, price, recording_enabled, status)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, 'scheduled')`,
 		sessionID, tuid, offeringID, req.Title, req.Description,
-		req.SessionType, startTime, endTime, req.MaxStudents, req.Price,
+		req.SessionType, startTime, endTime, req.MaxStudents, req.Price, req.RecordingEnabled,
 	)
+	if scheduling.IsOverlapViolation(err) {
+		return nil, scheduling.ErrConflict
+	}
 	if err != nil {
 		return nil, fmt.Errorf("insert session: %w", err)
 	}
@@ -83,7 +97,8 @@ func (s *Service) GetSession(ctx context.Context, sessionID string) (*SessionRes
 		`SELECT s.id, s.teacher_id, u.first_name || ' ' || u.last_name,
 		        s.title, COALESCE(s.description,''), s.session_type,
 		        s.start_time, s.end_time, s.max_participants, s.price, s.status,
-		        COALESCE(s.livekit_room_id,''), COALESCE(s.recording_url,'')
+		        COALESCE(s.livekit_room_id,''), COALESCE(s.recording_url,''),
+		        COALESCE(s.recording_enabled, false), COALESCE(s.recording_status,'')
 		 FROM sessions s
 		 JOIN users u ON u.id = s.teacher_id
 		 WHERE s.id = $1`, sid,
@@ -92,6 +107,7 @@ func (s *Service) GetSession(ctx context.Context, sessionID string) (*SessionRes
 		&sr.Title, &sr.Description, &sr.SessionType,
 		&startTime, &endTime, &sr.MaxStudents, &sr.Price, &sr.Status,
 		&sr.RoomID, &sr.RecordingURL,
+		&sr.RecordingEnabled, &sr.RecordingStatus,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -166,7 +182,7 @@ func (s *Service) ListSessions(ctx context.Context, userID, role string, q ListS
 }
 
 // JoinSession creates a LiveKit room (if needed), adds participant, returns join token.
-// If the session belongs to a series, enrollment and fee payment are verified.
+// Who may join is decided by access.Policy, shared with the series join path.
 func (s *Service) JoinSession(ctx context.Context, sessionID, userID, userName, role string) (*JoinSessionResponse, error) {
 	sid, err := uuid.Parse(sessionID)
 	if err != nil {
@@ -174,49 +190,11 @@ func (s *Service) JoinSession(ctx context.Context, sessionID, userID, userName,
 	}
 	uid, _ := uuid.Parse(userID)
 
-	// Get session including optional series_id
-	var status, roomID string
-	var teacherID uuid.UUID
-	var seriesID *uuid.UUID
-	err = s.db.Pool.QueryRow(ctx,
-		`SELECT status, COALESCE(livekit_room_id,''), teacher_id, series_id FROM sessions WHERE id = $1`, sid,
-	).Scan(&status, &roomID, &teacherID, &seriesID)
+	grant, err := s.access.CanJoin(ctx, sid, uid)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, ErrSessionNotFound
-		}
 		return nil, err
 	}
-
-	if status != "scheduled" && status != "live" {
-		return nil, ErrInvalidStatus
-	}
-
-	isTeacher := teacherID == uid
-
-	// ── Series access control ─────────────────────────────────────
-	// If session belongs to a series, enforce enrollment + fee checks
-	if seriesID != nil {
-		if !isTeacher {
-			// Student must be enrolled with status 'accepted'
-			var enrollStatus string
-			err = s.db.Pool.QueryRow(ctx,
-				`SELECT status::text FROM session_enrollments WHERE series_id = $1 AND student_id = $2`, *seriesID, uid,
-			).Scan(&enrollStatus)
-			if err != nil || enrollStatus != "accepted" {
-				return nil, fmt.Errorf("not enrolled in this session series")
-			}
-		}
-
-		// Both teacher and student: fee must be paid
-		var feeStatus string
-		err = s.db.Pool.QueryRow(ctx,
-			`SELECT status::text FROM platform_fees WHERE series_id = $1 ORDER BY created_at DESC LIMIT 1`, *seriesID,
-		).Scan(&feeStatus)
-		if err != nil || feeStatus != "completed" {
-			return nil, fmt.Errorf("platform fee not paid — cannot start session")
-		}
-	}
+	roomID, teacherID, seriesID, isTeacher := grant.RoomID, grant.TeacherID, grant.SeriesID, grant.IsTeacher
 
 	// Create LiveKit room if not exists
 	if roomID == "" {
@@ -234,13 +212,22 @@ func (s *Service) JoinSession(ctx context.Context, sessionID, userID, userName,
 		if err != nil {
 			return nil, fmt.Errorf("update room: %w", err)
 		}
+
+		s.events.Emit(ctx, events.SessionStarted{
+			SessionID: sid,
+			SeriesID:  seriesID,
+			TeacherID: teacherID,
+			RoomID:    roomID,
+			StartedAt: time.Now(),
+		})
 	}
 
-	// If student, add as participant
+	// If student, add as participant; attendance is graded by the LiveKit
+	// webhook once they actually connect.
 	if role == "student" {
 		_, _ = s.db.Pool.Exec(ctx,
-			`INSERT INTO session_participants (session_id, student_id, attendance)
-			 SELECT $1, $2, 'present'
+			`INSERT INTO session_participants (session_id, student_id)
+			 SELECT $1, $2
 			 WHERE NOT EXISTS (SELECT 1 FROM session_participants WHERE session_id = $1 AND student_id = $2)`,
 			sid, uid,
 		)
@@ -288,7 +275,17 @@ func (s *Service) CancelSession(ctx context.Context, sessionID, userID string, r
 		`UPDATE sessions SET status = 'cancelled', cancelled_by = $1, cancellation_reason = $2 WHERE id = $3`,
 		uid, req.Reason, sid,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	s.events.Emit(ctx, events.SessionCancelled{
+		SessionID:   sid,
+		TeacherID:   teacherID,
+		CancelledBy: uid,
+		Reason:      req.Reason,
+	})
+	return nil
 }
 
 // RescheduleSession reschedules a session to new start/end times.
@@ -320,26 +317,42 @@ func (s *Service) RescheduleSession(ctx context.Context, sessionID, userID strin
 		return nil, fmt.Errorf("invalid end: %w", err)
 	}
 
+	// The session's current times do not count against the new ones
+	if err := scheduling.CheckFree(ctx, s.db.Pool, teacherID, start, end, sid); err != nil {
+		return nil, err
+	}
+
 	_, err = s.db.Pool.Exec(ctx,
-		`UPDATE sessions SET start_time = $1, end_time = $2 WHERE id = $3`, start, end, sid,
+		`UPDATE sessions SET start_time = $1, end_time = $2, overlap_exempt = false WHERE id = $3`, start, end, sid,
 	)
+	if scheduling.IsOverlapViolation(err) {
+		return nil, scheduling.ErrConflict
+	}
 	if err != nil {
 		return nil, fmt.Errorf("reschedule: %w", err)
 	}
 
+	s.events.Emit(ctx, events.SessionRescheduled{
+		SessionID: sid,
+		TeacherID: teacherID,
+		StartTime: start,
+		EndTime:   end,
+	})
+
 	return s.GetSession(ctx, sessionID)
 }
 
-// EndSession ends a live session and optionally cleans up the LiveKit room.
+// EndSession ends a live session and cleans up the LiveKit room.
 func (s *Service) EndSession(ctx context.Context, sessionID, userID string) error {
 	sid, _ := uuid.Parse(sessionID)
 	uid, _ := uuid.Parse(userID)
 
 	var teacherID uuid.UUID
+	var seriesID *uuid.UUID
 	var status, roomID string
 	err := s.db.Pool.QueryRow(ctx,
-		`SELECT teacher_id, status, COALESCE(livekit_room_id,'') FROM sessions WHERE id = $1`, sid,
-	).Scan(&teacherID, &status, &roomID)
+		`SELECT teacher_id, series_id, status, COALESCE(livekit_room_id,'') FROM sessions WHERE id = $1`, sid,
+	).Scan(&teacherID, &seriesID, &status, &roomID)
 	if err != nil {
 		return ErrSessionNotFound
 	}
@@ -350,13 +363,12 @@ func (s *Service) EndSession(ctx context.Context, sessionID, userID string) erro
 		return ErrInvalidStatus
 	}
 
-	// Delete LiveKit room
-	if roomID != "" && s.livekit != nil {
-		_ = s.livekit.DeleteRoom(ctx, roomID)
+	ok, err := s.markCompleted(ctx, sid, seriesID, teacherID, roomID)
+	if err != nil {
+		return err
 	}
-
-	_, err = s.db.Pool.Exec(ctx,
-		`UPDATE sessions SET status = 'completed', actual_end = NOW() WHERE id = $1`, sid,
-	)
-	return err
+	if !ok {
+		return ErrInvalidStatus
+	}
+	return nil
 }
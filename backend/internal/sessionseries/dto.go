@@ -6,16 +6,6 @@ import (
 	"github.com/google/uuid"
 )
 
-// ═══════════════════════════════════════════════════════════════
-// Star Pricing (replaces old platform fee constants)
-// Teachers pay per-enrollment, not per-hour.
-// ═══════════════════════════════════════════════════════════════
-
-const (
-	GroupStarCost   = 50.0 // DZD per enrollment in a group series
-	PrivateStarCost = 70.0 // DZD per enrollment in a 1-on-1 series
-)
-
 // ═══════════════════════════════════════════════════════════════
 // Session Series DTOs
 // ═══════════════════════════════════════════════════════════════
@@ -44,7 +34,7 @@ type SeriesResponse struct {
 	Enrollments   []EnrollmentBrief `json:"enrollments,omitempty"`
 	EnrolledCount int               `json:"enrolled_count"` // Accepted enrollments
 	PendingCount  int               `json:"pending_count"`  // Invited/Requested
-	StarCost      float64           `json:"star_cost"`      // DZD per enrollment (50 group / 70 private)
+	StarCost      float64           `json:"star_cost"`      // DZD per enrollment at the current star price
 	CreatedAt     time.Time         `json:"created_at"`
 	UpdatedAt     time.Time         `json:"updated_at"`
 	// For browse: current user's enrollment status (empty if not enrolled)
@@ -103,6 +93,11 @@ type InviteStudentsRequest struct {
 	StudentIDs []string `json:"student_ids" validate:"required,min=1"`
 }
 
+// WithdrawRequest names the child when a parent withdraws them.
+type WithdrawRequest struct {
+	StudentID string `json:"student_id"`
+}
+
 type EnrollmentResponse struct {
 	ID            uuid.UUID  `json:"id"`
 	SeriesID      uuid.UUID  `json:"series_id"`
@@ -119,36 +114,17 @@ type EnrollmentResponse struct {
 	InvitedAt     *time.Time `json:"invited_at,omitempty"`
 	RequestedAt   *time.Time `json:"requested_at,omitempty"`
 	AcceptedAt    *time.Time `json:"accepted_at,omitempty"`
-	CreatedAt     time.Time  `json:"created_at"`
+	// Set while the teacher's wallet cannot cover the star (status awaiting_credit)
+	AwaitingCreditAt *time.Time `json:"awaiting_credit_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
 }
 
 // ═══════════════════════════════════════════════════════════════
-// Platform Fee DTOs
+// Finalize DTOs
 // ═══════════════════════════════════════════════════════════════
 
-type PlatformFeeResponse struct {
-	ID            uuid.UUID  `json:"id"`
-	SeriesID      uuid.UUID  `json:"series_id"`
-	SeriesTitle   string     `json:"series_title"`
-	TeacherID     uuid.UUID  `json:"teacher_id"`
-	EnrolledCount int        `json:"enrolled_count"`
-	TotalSessions int        `json:"total_sessions"`
-	DurationHours float64    `json:"duration_hours"`
-	FeeRate       float64    `json:"fee_rate"`
-	Amount        float64    `json:"amount"`
-	Status        string     `json:"status"`
-	ProviderRef   *string    `json:"provider_ref,omitempty"`
-	Description   string     `json:"description,omitempty"`
-	CreatedAt     time.Time  `json:"created_at"`
-	PaidAt        *time.Time `json:"paid_at,omitempty"`
-}
-
 type FinalizeSeriesRequest struct {
-	// No body needed - fee is calculated from enrolled students
-}
-
-type ConfirmPaymentRequest struct {
-	ProviderRef string `json:"provider_ref" validate:"required"` // BaridiMob transaction ref
+	// No body needed - stars are charged per enrollment, not at finalize
 }
 
 // ═══════════════════════════════════════════════════════════════
@@ -163,7 +139,7 @@ type JoinResponse struct {
 
 type AccessDeniedResponse struct {
 	Allowed bool   `json:"allowed"`
-	Reason  string `json:"reason,omitempty"` // "fee_not_paid", "not_enrolled", "session_not_started"
+	Reason  string `json:"reason,omitempty"` // "not_enrolled", "session_not_started"
 }
 
 // ═══════════════════════════════════════════════════════════════
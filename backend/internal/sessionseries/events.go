@@ -0,0 +1,28 @@
+package sessionseries
+
+import (
+	"context"
+	"log/slog"
+
+	"educonnect/internal/events"
+)
+
+// ─── Event Consumers ────────────────────────────────────────────
+
+// RegisterEventHandlers resumes enrollments parked for lack of credit when
+// the teacher's wallet is topped up.
+func (s *Service) RegisterEventHandlers(r *events.Runner) {
+	r.Handle("sessionseries-resume-awaiting-credit", events.TypeWalletPurchaseApproved, 1, s.onWalletPurchaseApproved)
+}
+
+func (s *Service) onWalletPurchaseApproved(ctx context.Context, env *events.Envelope) error {
+	var e events.WalletPurchaseApproved
+	if err := env.Decode(&e); err != nil {
+		return err
+	}
+	n, err := s.ResumeAwaitingCredit(ctx, e.TeacherID)
+	if n > 0 {
+		slog.Info("enrollments resumed after top-up", "teacher_id", e.TeacherID, "accepted", n)
+	}
+	return err
+}
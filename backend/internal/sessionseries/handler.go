@@ -6,7 +6,9 @@ import (
 	"net/http"
 	"strconv"
 
+	"educonnect/internal/access"
 	"educonnect/internal/middleware"
+	"educonnect/internal/scheduling"
 	"educonnect/internal/wallet"
 
 	"github.com/gin-gonic/gin"
@@ -241,6 +243,25 @@ func (h *Handler) RequestToJoin(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{"success": true, "data": enr})
 }
 
+// Withdraw POST /sessions/series/:id/withdraw
+// A parent withdrawing a child passes {"student_id": "..."}.
+func (h *Handler) Withdraw(c *gin.Context) {
+	var req WithdrawRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+			return
+		}
+	}
+
+	userID := middleware.GetUserID(c)
+	if err := h.service.Withdraw(c.Request.Context(), c.Param("id"), userID, req.StudentID); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"message": "withdrawn from series"}})
+}
+
 // ListInvitations GET /invitations
 func (h *Handler) ListInvitations(c *gin.Context) {
 	userID := middleware.GetUserID(c)
@@ -292,7 +313,7 @@ func (h *Handler) DeclineInvitation(c *gin.Context) {
 }
 
 // ═══════════════════════════════════════════════════════════════
-// Platform Fee Endpoints
+// Finalize Series
 // ═══════════════════════════════════════════════════════════════
 
 // FinalizeSeries POST /sessions/series/:id/finalize
@@ -308,66 +329,6 @@ func (h *Handler) FinalizeSeries(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"success": true, "data": series})
 }
 
-// ListPendingFees GET /fees/pending
-func (h *Handler) ListPendingFees(c *gin.Context) {
-	userID := middleware.GetUserID(c)
-
-	fees, err := h.service.ListPendingFees(c.Request.Context(), userID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"message": "internal server error"}})
-		return
-	}
-	c.JSON(http.StatusOK, gin.H{"success": true, "data": fees})
-}
-
-// ConfirmPayment POST /fees/:id/confirm
-func (h *Handler) ConfirmPayment(c *gin.Context) {
-	feeID := c.Param("id")
-	var req ConfirmPaymentRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
-		return
-	}
-	if err := h.validate.Struct(req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": "validation failed"}})
-		return
-	}
-
-	userID := middleware.GetUserID(c)
-	fee, err := h.service.ConfirmPayment(c.Request.Context(), feeID, userID, req)
-	if err != nil {
-		respondError(c, err)
-		return
-	}
-	c.JSON(http.StatusOK, gin.H{"success": true, "data": fee})
-}
-
-// ═══════════════════════════════════════════════════════════════
-// Admin: Verify Payment
-// ═══════════════════════════════════════════════════════════════
-
-// AdminVerifyPayment PUT /admin/fees/:id/verify
-func (h *Handler) AdminVerifyPayment(c *gin.Context) {
-	feeID := c.Param("id")
-	userID := middleware.GetUserID(c)
-
-	var req struct {
-		Approved bool   `json:"approved"`
-		Notes    string `json:"notes"`
-	}
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
-		return
-	}
-
-	fee, err := h.service.AdminVerifyPayment(c.Request.Context(), feeID, userID, req.Approved, req.Notes)
-	if err != nil {
-		respondError(c, err)
-		return
-	}
-	c.JSON(http.StatusOK, gin.H{"success": true, "data": fee})
-}
-
 // ═══════════════════════════════════════════════════════════════
 // Join Session (with access control)
 // ═══════════════════════════════════════════════════════════════
@@ -395,34 +356,29 @@ func (h *Handler) JoinSession(c *gin.Context) {
 
 func respondError(c *gin.Context, err error) {
 	switch {
-	case errors.Is(err, ErrSeriesNotFound), errors.Is(err, ErrSessionNotFound):
+	case errors.Is(err, ErrSeriesNotFound), errors.Is(err, ErrSessionNotFound), errors.Is(err, access.ErrSessionNotFound):
 		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
-	case errors.Is(err, ErrEnrollmentNotFound), errors.Is(err, ErrFeeNotFound):
+	case errors.Is(err, ErrEnrollmentNotFound):
 		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
 	case errors.Is(err, ErrNotAuthorized):
 		c.JSON(http.StatusForbidden, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
 	case errors.Is(err, ErrSeriesFull), errors.Is(err, ErrAlreadyEnrolled),
-		errors.Is(err, ErrAlreadyRequested), errors.Is(err, ErrFeeAlreadyPaid),
-		errors.Is(err, ErrAlreadyFinalized):
+		errors.Is(err, ErrAlreadyRequested), errors.Is(err, ErrAlreadyFinalized),
+		errors.Is(err, scheduling.ErrConflict):
 		c.JSON(http.StatusConflict, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
-	case errors.Is(err, ErrFeeNotPaid):
-		c.JSON(http.StatusPaymentRequired, gin.H{"success": false, "error": gin.H{
-			"code":    "FEE_NOT_PAID",
-			"message": err.Error(),
-		}})
 	case errors.Is(err, wallet.ErrInsufficientBalance):
 		c.JSON(http.StatusPaymentRequired, gin.H{"success": false, "error": gin.H{
 			"code":    "INSUFFICIENT_BALANCE",
 			"message": err.Error(),
 		}})
-	case errors.Is(err, ErrNotEnrolled):
+	case errors.Is(err, access.ErrNotEnrolled), errors.Is(err, access.ErrSeatReleased):
 		c.JSON(http.StatusForbidden, gin.H{"success": false, "error": gin.H{
 			"code":    "NOT_ENROLLED",
 			"message": err.Error(),
 		}})
 	case errors.Is(err, ErrInvalidStatus), errors.Is(err, ErrInvalidDates),
 		errors.Is(err, ErrNoEnrollments), errors.Is(err, ErrNoSessions),
-		errors.Is(err, ErrNotFinalized):
+		errors.Is(err, access.ErrNotJoinable):
 		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
 	default:
 		// Log the actual error for debugging
@@ -4,9 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
+	"educonnect/internal/access"
+	"educonnect/internal/events"
+	"educonnect/internal/scheduling"
 	"educonnect/internal/wallet"
 	"educonnect/pkg/database"
 	lk "educonnect/pkg/livekit"
@@ -23,19 +27,14 @@ var (
 	ErrSeriesNotFound     = errors.New("session series not found")
 	ErrSessionNotFound    = errors.New("session not found")
 	ErrEnrollmentNotFound = errors.New("enrollment not found")
-	ErrFeeNotFound        = errors.New("platform fee not found")
 	ErrNotAuthorized      = errors.New("not authorized")
 	ErrSeriesFull         = errors.New("series has reached maximum students")
 	ErrAlreadyEnrolled    = errors.New("student already enrolled in this series")
 	ErrAlreadyRequested   = errors.New("already requested to join this series")
 	ErrInvalidStatus      = errors.New("invalid status for this action")
 	ErrInvalidDates       = errors.New("end time must be after start time")
-	ErrFeeNotPaid         = errors.New("platform fee not paid — cannot start session")
-	ErrNotEnrolled        = errors.New("not enrolled in this session")
-	ErrFeeAlreadyPaid     = errors.New("fee already paid")
 	ErrNoEnrollments      = errors.New("no enrolled students — cannot finalize")
 	ErrAlreadyFinalized   = errors.New("series already finalized")
-	ErrNotFinalized       = errors.New("series not finalized — cannot pay yet")
 	ErrNoSessions         = errors.New("no sessions added to series")
 )
 
@@ -47,10 +46,12 @@ type Service struct {
 	db      *database.Postgres
 	livekit *lk.Client
 	wallet  *wallet.Service
+	access  *access.Policy
+	events  *events.Publisher
 }
 
-func NewService(db *database.Postgres, livekit *lk.Client, walletSvc *wallet.Service) *Service {
-	return &Service{db: db, livekit: livekit, wallet: walletSvc}
+func NewService(db *database.Postgres, livekit *lk.Client, walletSvc *wallet.Service, policy *access.Policy, pub *events.Publisher) *Service {
+	return &Service{db: db, livekit: livekit, wallet: walletSvc, access: policy, events: pub}
 }
 
 // ═══════════════════════════════════════════════════════════════
@@ -206,7 +207,7 @@ func (s *Service) GetSeries(ctx context.Context, seriesID string, callerID strin
 				sr.Enrollments = append(sr.Enrollments, eb)
 				if eb.Status == "accepted" {
 					sr.EnrolledCount++
-				} else if eb.Status == "invited" || eb.Status == "requested" {
+				} else if eb.Status == "invited" || eb.Status == "requested" || eb.Status == "awaiting_credit" {
 					sr.PendingCount++
 				}
 			}
@@ -217,10 +218,10 @@ func (s *Service) GetSeries(ctx context.Context, seriesID string, callerID strin
 	}
 
 	// Star cost per enrollment
-	if sr.SessionType == "one_on_one" {
-		sr.StarCost = PrivateStarCost
-	} else {
-		sr.StarCost = GroupStarCost
+	if s.wallet != nil {
+		if price, err := s.wallet.StarPriceForSeries(ctx, sid); err == nil {
+			sr.StarCost = price.Price
+		}
 	}
 
 	return &sr, nil
@@ -414,12 +415,10 @@ func (s *Service) AddSessions(ctx context.Context, seriesID, teacherID string, r
 
 	// Verify ownership
 	var ownerID uuid.UUID
-	var seriesStatus, title string
 	var durationHours float64
-	var sessionType string
 	err := s.db.Pool.QueryRow(ctx,
-		`SELECT teacher_id, status::text, title, duration_hours, session_type::text FROM session_series WHERE id = $1`, sid,
-	).Scan(&ownerID, &seriesStatus, &title, &durationHours, &sessionType)
+		`SELECT teacher_id, duration_hours FROM session_series WHERE id = $1`, sid,
+	).Scan(&ownerID, &durationHours)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrSeriesNotFound
@@ -430,18 +429,7 @@ func (s *Service) AddSessions(ctx context.Context, seriesID, teacherID string, r
 		return nil, ErrNotAuthorized
 	}
 
-	// Get current session count
-	var currentCount int
-	_ = s.db.Pool.QueryRow(ctx,
-		`SELECT COUNT(*) FROM sessions WHERE series_id = $1`, sid,
-	).Scan(&currentCount)
-
-	tx, err := s.db.Pool.Begin(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("begin tx: %w", err)
-	}
-	defer tx.Rollback(ctx)
-
+	sessions := make([]NewSession, 0, len(req.Sessions))
 	for i, sess := range req.Sessions {
 		startTime, err := time.Parse(time.RFC3339, sess.StartTime)
 		if err != nil {
@@ -449,28 +437,17 @@ func (s *Service) AddSessions(ctx context.Context, seriesID, teacherID string, r
 		}
 		// Calculate end time based on duration
 		endTime := startTime.Add(time.Duration(durationHours * float64(time.Hour)))
+		sessions = append(sessions, NewSession{Start: startTime, End: endTime})
+	}
 
-		sessionNum := currentCount + i + 1
-		sessionTitle := title
-		if len(req.Sessions) > 1 || currentCount > 0 {
-			sessionTitle = fmt.Sprintf("%s - Séance %d", title, sessionNum)
-		}
-
-		maxParticipants := 1
-		if sessionType == "group" {
-			maxParticipants = 50
-		}
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
 
-		_, err = tx.Exec(ctx,
-			`INSERT INTO sessions (id, teacher_id, series_id, session_number, title,
-			    session_type, start_time, end_time, max_participants, price, status)
-			 VALUES ($1, $2, $3, $4, $5, $6::session_type, $7, $8, $9, 0, 'scheduled')`,
-			uuid.New(), tid, sid, sessionNum, sessionTitle,
-			sessionType, startTime, endTime, maxParticipants,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("insert session %d: %w", sessionNum, err)
-		}
+	if _, err := InsertSessions(ctx, tx, sid, sessions, 0); err != nil {
+		return nil, err
 	}
 
 	// Update series status to active if it was draft
@@ -488,6 +465,77 @@ func (s *Service) AddSessions(ctx context.Context, seriesID, teacherID string, r
 	return s.GetSeries(ctx, seriesID, teacherID)
 }
 
+// NewSession is a session to add to a series.
+type NewSession struct {
+	Start, End time.Time
+}
+
+// InsertSessions numbers and inserts sessions after those the series
+// already has, at the given price, within tx. Sessions take the series'
+// description and capacity (max_students). Each is checked against
+// the teacher's calendar first, earlier ones of the batch included; a
+// conflict fails the batch with a *scheduling.ConflictError, or
+// scheduling.ErrConflict when the overlap constraint catches a race.
+// The new session IDs are returned in order.
+func InsertSessions(ctx context.Context, tx pgx.Tx, seriesID uuid.UUID, sessions []NewSession, price float64) ([]uuid.UUID, error) {
+	var teacherID uuid.UUID
+	var offeringID *uuid.UUID
+	var title, sessionType string
+	var description *string
+	var maxParticipants int
+	err := tx.QueryRow(ctx,
+		`SELECT teacher_id, offering_id, title, description, session_type::text, max_students
+		 FROM session_series WHERE id = $1`, seriesID,
+	).Scan(&teacherID, &offeringID, &title, &description, &sessionType, &maxParticipants)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSeriesNotFound
+		}
+		return nil, err
+	}
+
+	// Get current session count
+	var currentCount int
+	_ = tx.QueryRow(ctx,
+		`SELECT COUNT(*) FROM sessions WHERE series_id = $1`, seriesID,
+	).Scan(&currentCount)
+
+	ids := make([]uuid.UUID, 0, len(sessions))
+	for i, sess := range sessions {
+		if !sess.End.After(sess.Start) {
+			return nil, ErrInvalidDates
+		}
+
+		sessionNum := currentCount + i + 1
+		sessionTitle := title
+		if len(sessions) > 1 || currentCount > 0 {
+			sessionTitle = fmt.Sprintf("%s - Séance %d", title, sessionNum)
+		}
+
+		// Checked in tx so earlier sessions of this batch count too
+		if err := scheduling.CheckFree(ctx, tx, teacherID, sess.Start, sess.End); err != nil {
+			return nil, fmt.Errorf("session %d: %w", sessionNum, err)
+		}
+
+		id := uuid.New()
+		_, err = tx.Exec(ctx,
+			`INSERT INTO sessions (id, teacher_id, offering_id, series_id, session_number, title, description,
+			    session_type, start_time, end_time, max_participants, price, status)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8::session_type, $9, $10, $11, $12, 'scheduled')`,
+			id, teacherID, offeringID, seriesID, sessionNum, sessionTitle, description,
+			sessionType, sess.Start, sess.End, maxParticipants, price,
+		)
+		if scheduling.IsOverlapViolation(err) {
+			return nil, fmt.Errorf("session %d: %w", sessionNum, scheduling.ErrConflict)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("insert session %d: %w", sessionNum, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
 // ═══════════════════════════════════════════════════════════════
 // Teacher Invites Students
 // ═══════════════════════════════════════════════════════════════
@@ -581,9 +629,10 @@ func (s *Service) RequestToJoin(ctx context.Context, seriesID, studentID string)
 	// Check series exists and is not full
 	var maxStudents int
 	var seriesStatus string
+	var teacherID uuid.UUID
 	err := s.db.Pool.QueryRow(ctx,
-		`SELECT max_students, status::text FROM session_series WHERE id = $1`, sid,
-	).Scan(&maxStudents, &seriesStatus)
+		`SELECT max_students, status::text, teacher_id FROM session_series WHERE id = $1`, sid,
+	).Scan(&maxStudents, &seriesStatus, &teacherID)
 	if err != nil {
 		return nil, ErrSeriesNotFound
 	}
@@ -622,7 +671,12 @@ func (s *Service) RequestToJoin(ctx context.Context, seriesID, studentID string)
 		return nil, fmt.Errorf("request to join: %w", err)
 	}
 
-	// TODO: Send notification to teacher
+	s.events.Emit(ctx, events.EnrollmentRequested{
+		EnrollmentID: enrollID,
+		SeriesID:     sid,
+		TeacherID:    teacherID,
+		StudentID:    stid,
+	})
 
 	return s.getEnrollment(ctx, enrollID)
 }
@@ -679,21 +733,17 @@ func (s *Service) AcceptInvitation(ctx context.Context, enrollmentID, studentID
 		`SELECT first_name || ' ' || last_name FROM users WHERE id = $1`, dbStudentID,
 	).Scan(&studentName)
 
-	// ★ Deduct star from teacher wallet (ACID)
-	if s.wallet != nil {
-		_, err = s.wallet.DeductStar(ctx, teacherID.String(), sessionType, eid, seriesID, studentName, seriesTitle)
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	now := time.Now()
-	_, err = s.db.Pool.Exec(ctx,
-		`UPDATE session_enrollments SET status = 'accepted', accepted_at = $1 WHERE id = $2`,
-		now, eid,
-	)
+	err = s.acceptEnrollment(ctx, eid, "invited", teacherID.String(), sessionType, seriesID, studentName, seriesTitle,
+		events.EnrollmentAccepted{
+			EnrollmentID: eid,
+			SeriesID:     seriesID,
+			TeacherID:    teacherID,
+			StudentID:    dbStudentID,
+			InitiatedBy:  "teacher",
+			SeriesTitle:  seriesTitle,
+		})
 	if err != nil {
-		return nil, fmt.Errorf("accept invitation: %w", err)
+		return nil, err
 	}
 
 	return s.getEnrollment(ctx, eid)
@@ -770,24 +820,166 @@ func (s *Service) AcceptRequest(ctx context.Context, seriesID, enrollmentID, tea
 		`SELECT first_name || ' ' || last_name FROM users WHERE id = $1`, studentID,
 	).Scan(&studentName)
 
-	// ★ Deduct star from teacher wallet (ACID)
+	// Without stars to cover it the enrollment is parked as awaiting_credit
+	err = s.acceptEnrollment(ctx, eid, "requested", teacherID, sessionType, sid, studentName, seriesTitle,
+		events.EnrollmentAccepted{
+			EnrollmentID: eid,
+			SeriesID:     sid,
+			TeacherID:    tid,
+			StudentID:    studentID,
+			InitiatedBy:  "student",
+			SeriesTitle:  seriesTitle,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getEnrollment(ctx, eid)
+}
+
+// acceptEnrollment moves an enrollment from fromStatus to 'accepted' in one
+// transaction: the row is locked, the teacher's star is deducted and the
+// enrollment.accepted event is written to the outbox. A concurrent accept of
+// the same enrollment finds the row already accepted and deducts nothing.
+// When the wallet cannot cover the star the enrollment is parked as
+// 'awaiting_credit' instead; resuming a parked one leaves it parked.
+func (s *Service) acceptEnrollment(ctx context.Context, eid uuid.UUID, fromStatus, teacherID, sessionType string, seriesID uuid.UUID, studentName, seriesTitle string, evt events.EnrollmentAccepted) error {
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var status string
+	err = tx.QueryRow(ctx,
+		`SELECT status::text FROM session_enrollments WHERE id = $1 FOR UPDATE`, eid,
+	).Scan(&status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrEnrollmentNotFound
+		}
+		return fmt.Errorf("lock enrollment: %w", err)
+	}
+	if status != fromStatus {
+		return ErrInvalidStatus
+	}
+
+	// ★ Deduct star from teacher wallet (same transaction)
 	if s.wallet != nil {
-		_, err = s.wallet.DeductStar(ctx, teacherID, sessionType, eid, sid, studentName, seriesTitle)
-		if err != nil {
-			return nil, err // ErrInsufficientBalance propagates with 402 status
+		if _, err := s.wallet.DeductStarTx(ctx, tx, teacherID, sessionType, eid, seriesID, studentName, seriesTitle); err != nil {
+			if !errors.Is(err, wallet.ErrInsufficientBalance) || fromStatus == "awaiting_credit" {
+				return err
+			}
+			return s.parkForCredit(ctx, tx, eid, evt)
 		}
 	}
 
-	now := time.Now()
-	_, err = s.db.Pool.Exec(ctx,
-		`UPDATE session_enrollments SET status = 'accepted', accepted_at = $1 WHERE id = $2`,
-		now, eid,
+	_, err = tx.Exec(ctx,
+		`UPDATE session_enrollments SET status = 'accepted', accepted_at = $1, awaiting_credit_at = NULL WHERE id = $2`,
+		time.Now(), eid,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("accept request: %w", err)
+		return fmt.Errorf("accept enrollment: %w", err)
 	}
 
-	return s.getEnrollment(ctx, eid)
+	if err := events.Enqueue(ctx, tx, evt); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// parkForCredit marks the locked enrollment 'awaiting_credit' and commits,
+// telling the teacher to top up and the student that confirmation is pending.
+func (s *Service) parkForCredit(ctx context.Context, tx pgx.Tx, eid uuid.UUID, evt events.EnrollmentAccepted) error {
+	_, err := tx.Exec(ctx,
+		`UPDATE session_enrollments SET status = 'awaiting_credit', awaiting_credit_at = $1 WHERE id = $2`,
+		time.Now(), eid,
+	)
+	if err != nil {
+		return fmt.Errorf("park enrollment: %w", err)
+	}
+
+	err = events.Enqueue(ctx, tx, events.EnrollmentAwaitingCredit{
+		EnrollmentID: evt.EnrollmentID,
+		SeriesID:     evt.SeriesID,
+		TeacherID:    evt.TeacherID,
+		StudentID:    evt.StudentID,
+		InitiatedBy:  evt.InitiatedBy,
+		SeriesTitle:  evt.SeriesTitle,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// ResumeAwaitingCredit accepts the teacher's parked enrollments, oldest
+// first, for as long as the wallet covers their stars. It returns how many
+// were accepted.
+func (s *Service) ResumeAwaitingCredit(ctx context.Context, teacherID uuid.UUID) (int, error) {
+	type parked struct {
+		id, seriesID, studentID  uuid.UUID
+		sessionType, title       string
+		studentName, initiatedBy string
+	}
+
+	rows, err := s.db.Pool.Query(ctx,
+		`SELECT se.id, se.series_id, se.student_id, ss.session_type::text, ss.title,
+		        u.first_name || ' ' || u.last_name, se.initiated_by
+		 FROM session_enrollments se
+		 JOIN session_series ss ON ss.id = se.series_id
+		 JOIN users u ON u.id = se.student_id
+		 WHERE ss.teacher_id = $1 AND se.status = 'awaiting_credit'
+		 ORDER BY se.awaiting_credit_at, se.created_at`, teacherID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("list awaiting credit: %w", err)
+	}
+	var pending []parked
+	for rows.Next() {
+		var p parked
+		if err := rows.Scan(&p.id, &p.seriesID, &p.studentID, &p.sessionType, &p.title, &p.studentName, &p.initiatedBy); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan awaiting credit: %w", err)
+		}
+		pending = append(pending, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("list awaiting credit: %w", err)
+	}
+
+	accepted := 0
+	for _, p := range pending {
+		err := s.acceptEnrollment(ctx, p.id, "awaiting_credit", teacherID.String(), p.sessionType, p.seriesID, p.studentName, p.title,
+			events.EnrollmentAccepted{
+				EnrollmentID: p.id,
+				SeriesID:     p.seriesID,
+				TeacherID:    teacherID,
+				StudentID:    p.studentID,
+				InitiatedBy:  p.initiatedBy,
+				SeriesTitle:  p.title,
+			})
+		switch {
+		case err == nil:
+			accepted++
+		case errors.Is(err, wallet.ErrInsufficientBalance):
+			return accepted, nil
+		case errors.Is(err, ErrInvalidStatus):
+			// Declined or removed meanwhile.
+		default:
+			return accepted, err
+		}
+	}
+	return accepted, nil
 }
 
 // Teacher declines student's request
@@ -814,7 +1006,8 @@ func (s *Service) DeclineRequest(ctx context.Context, seriesID, enrollmentID, te
 	return err
 }
 
-// Teacher removes student from series — refunds star if before first session.
+// Teacher removes student from series — the star is refunded under the
+// wallet refund policy.
 func (s *Service) RemoveStudent(ctx context.Context, seriesID, studentID, teacherID string) error {
 	sid, _ := uuid.Parse(seriesID)
 	stid, _ := uuid.Parse(studentID)
@@ -832,31 +1025,80 @@ func (s *Service) RemoveStudent(ctx context.Context, seriesID, studentID, teache
 		return ErrNotAuthorized
 	}
 
-	// Get enrollment ID before updating
-	var enrollmentID uuid.UUID
-	err = s.db.Pool.QueryRow(ctx,
-		`SELECT id FROM session_enrollments WHERE series_id = $1 AND student_id = $2 AND status = 'accepted'`,
-		sid, stid,
-	).Scan(&enrollmentID)
-	wasAccepted := err == nil
+	return s.removeEnrollment(ctx, sid, stid, tid, "teacher")
+}
 
-	tag, err := s.db.Pool.Exec(ctx,
-		`UPDATE session_enrollments SET status = 'removed' WHERE series_id = $1 AND student_id = $2`,
-		sid, stid,
-	)
+// Withdraw takes a student out of a series at their own (or their
+// parent's) request. studentID is empty when the caller is the student.
+func (s *Service) Withdraw(ctx context.Context, seriesID, callerID, studentID string) error {
+	sid, _ := uuid.Parse(seriesID)
+	cid, _ := uuid.Parse(callerID)
+
+	stid := cid
+	if studentID != "" {
+		stid, _ = uuid.Parse(studentID)
+	}
+	if stid != cid {
+		var isParent bool
+		_ = s.db.Pool.QueryRow(ctx,
+			`SELECT EXISTS(SELECT 1 FROM student_profiles WHERE user_id = $1 AND parent_id = $2)`,
+			stid, cid,
+		).Scan(&isParent)
+		if !isParent {
+			return ErrNotAuthorized
+		}
+	}
+
+	var teacherID uuid.UUID
+	err := s.db.Pool.QueryRow(ctx,
+		`SELECT teacher_id FROM session_series WHERE id = $1`, sid,
+	).Scan(&teacherID)
 	if err != nil {
+		return ErrSeriesNotFound
+	}
+
+	return s.removeEnrollment(ctx, sid, stid, teacherID, "student")
+}
+
+// removeEnrollment marks the student's live enrollment in the series
+// removed. An accepted one gets the teacher's star refunded under the
+// wallet refund policy (best-effort: the policy may grant nothing).
+func (s *Service) removeEnrollment(ctx context.Context, sid, stid, teacherID uuid.UUID, by string) error {
+	var enrollmentID uuid.UUID
+	var status string
+	err := s.db.Pool.QueryRow(ctx,
+		`UPDATE session_enrollments se
+		 SET status = 'removed', removed_at = NOW(), removed_by = $3
+		 FROM (SELECT id, status::text AS old_status FROM session_enrollments
+		       WHERE series_id = $1 AND student_id = $2 AND status NOT IN ('declined', 'removed')
+		       FOR UPDATE) prev
+		 WHERE se.id = prev.id
+		 RETURNING se.id, prev.old_status`,
+		sid, stid, by,
+	).Scan(&enrollmentID, &status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrEnrollmentNotFound
+		}
 		return fmt.Errorf("remove student: %w", err)
 	}
-	if tag.RowsAffected() == 0 {
-		return ErrEnrollmentNotFound
+	if status != "accepted" {
+		return nil
 	}
 
-	// ★ Refund star if enrollment was accepted (idempotent, checks 1st session internally)
-	if wasAccepted && s.wallet != nil {
-		_ = s.wallet.RefundStar(ctx, teacherID, enrollmentID)
-		// Refund is best-effort; if first session started, no refund — that's fine
+	// ★ Refund star (partial after the series has started)
+	if s.wallet != nil {
+		if _, err := s.wallet.RefundStar(ctx, teacherID.String(), enrollmentID); err != nil && !errors.Is(err, wallet.ErrRefundNotEligible) {
+			slog.Error("star refund failed", "enrollment_id", enrollmentID, "error", err)
+		}
 	}
 
+	s.events.Emit(ctx, events.EnrollmentRemoved{
+		EnrollmentID: enrollmentID,
+		SeriesID:     sid,
+		TeacherID:    teacherID,
+		StudentID:    stid,
+	})
 	return nil
 }
 
@@ -963,7 +1205,7 @@ func (s *Service) ListSeriesRequests(ctx context.Context, seriesID, teacherID st
 }
 
 // ═══════════════════════════════════════════════════════════════
-// Finalize & Pay Platform Fee
+// Finalize Series
 // ═══════════════════════════════════════════════════════════════
 
 // Teacher finalizes series — marks it as ready (no payment required, stars are per-enrollment).
@@ -1013,141 +1255,28 @@ func (s *Service) FinalizeSeries(ctx context.Context, seriesID, teacherID string
 	return s.GetSeries(ctx, seriesID, teacherID)
 }
 
-// Teacher confirms payment with BaridiMob reference
-func (s *Service) ConfirmPayment(ctx context.Context, feeID, teacherID string, req ConfirmPaymentRequest) (*PlatformFeeResponse, error) {
-	fid, _ := uuid.Parse(feeID)
-	tid, _ := uuid.Parse(teacherID)
-
-	// Verify ownership
-	var dbTeacherID uuid.UUID
-	var dbStatus string
-	err := s.db.Pool.QueryRow(ctx,
-		`SELECT teacher_id, status::text FROM platform_fees WHERE id = $1`, fid,
-	).Scan(&dbTeacherID, &dbStatus)
-	if err != nil {
-		return nil, ErrFeeNotFound
-	}
-	if dbTeacherID != tid {
-		return nil, ErrNotAuthorized
-	}
-	if dbStatus != "pending" {
-		return nil, ErrFeeAlreadyPaid
-	}
-
-	// Update fee status (will be verified by admin)
-	_, err = s.db.Pool.Exec(ctx,
-		`UPDATE platform_fees SET provider_ref = $1 WHERE id = $2`,
-		req.ProviderRef, fid,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("confirm payment: %w", err)
-	}
-
-	return s.getFee(ctx, fid)
-}
-
-// List pending fees for teacher
-func (s *Service) ListPendingFees(ctx context.Context, teacherID string) ([]PlatformFeeResponse, error) {
-	tid, _ := uuid.Parse(teacherID)
-
-	rows, err := s.db.Pool.Query(ctx,
-		`SELECT id FROM platform_fees WHERE teacher_id = $1 AND status = 'pending' ORDER BY created_at DESC`, tid,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("list fees: %w", err)
-	}
-	defer rows.Close()
-
-	var fees []PlatformFeeResponse
-	for rows.Next() {
-		var id uuid.UUID
-		if err := rows.Scan(&id); err != nil {
-			continue
-		}
-		fee, err := s.getFee(ctx, id)
-		if err == nil {
-			fees = append(fees, *fee)
-		}
-	}
-	if fees == nil {
-		fees = []PlatformFeeResponse{}
-	}
-	return fees, nil
-}
-
-// ═══════════════════════════════════════════════════════════════
-// Admin: Verify Payment
-// ═══════════════════════════════════════════════════════════════
-
-func (s *Service) AdminVerifyPayment(ctx context.Context, feeID, adminID string, approved bool, notes string) (*PlatformFeeResponse, error) {
-	fid, _ := uuid.Parse(feeID)
-	aid, _ := uuid.Parse(adminID)
-
-	now := time.Now()
-	status := "completed"
-	if !approved {
-		status = "failed"
-	}
-
-	_, err := s.db.Pool.Exec(ctx,
-		`UPDATE platform_fees SET status = $1::platform_fee_status, admin_verified_by = $2, admin_notes = $3, paid_at = $4 WHERE id = $5`,
-		status, aid, notes, now, fid,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("verify payment: %w", err)
-	}
-
-	return s.getFee(ctx, fid)
-}
-
 // ═══════════════════════════════════════════════════════════════
 // Join Session (Access Control)
 // ═══════════════════════════════════════════════════════════════
 
 func (s *Service) JoinSession(ctx context.Context, sessionID, userID, userName, role string) (*JoinResponse, error) {
-	sessID, _ := uuid.Parse(sessionID)
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
 	uid, _ := uuid.Parse(userID)
 
-	// Get session info
-	var teacherID uuid.UUID
-	var seriesID *uuid.UUID
-	var status, roomID, sessionType string
-	err := s.db.Pool.QueryRow(ctx,
-		`SELECT teacher_id, series_id, status::text, COALESCE(livekit_room_id,''), session_type::text
-		 FROM sessions WHERE id = $1`, sessID,
-	).Scan(&teacherID, &seriesID, &status, &roomID, &sessionType)
+	grant, err := s.access.CanJoin(ctx, sessID, uid)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, ErrSessionNotFound
-		}
 		return nil, err
 	}
+	roomID, teacherID, seriesID, isTeacher := grant.RoomID, grant.TeacherID, grant.SeriesID, grant.IsTeacher
 
-	if status != "scheduled" && status != "live" {
-		return nil, ErrInvalidStatus
-	}
-
-	isTeacher := teacherID == uid
-
-	// If student, check enrollment (star already paid at acceptance time)
 	if !isTeacher {
-		if seriesID == nil {
-			return nil, ErrNotEnrolled
-		}
-
-		// Check enrollment
-		var enrollStatus string
-		err := s.db.Pool.QueryRow(ctx,
-			`SELECT status::text FROM session_enrollments WHERE series_id = $1 AND student_id = $2`, *seriesID, uid,
-		).Scan(&enrollStatus)
-		if err != nil || enrollStatus != "accepted" {
-			return nil, ErrNotEnrolled
-		}
-
-		// Add as participant
+		// Add as participant; attendance is graded by the LiveKit webhook
 		_, _ = s.db.Pool.Exec(ctx,
-			`INSERT INTO session_participants (session_id, student_id, attendance)
-			 SELECT $1, $2, 'present'
+			`INSERT INTO session_participants (session_id, student_id)
+			 SELECT $1, $2
 			 WHERE NOT EXISTS (SELECT 1 FROM session_participants WHERE session_id = $1 AND student_id = $2)`,
 			sessID, uid,
 		)
@@ -1169,6 +1298,14 @@ func (s *Service) JoinSession(ctx context.Context, sessionID, userID, userName,
 		if err != nil {
 			return nil, fmt.Errorf("update room: %w", err)
 		}
+
+		s.events.Emit(ctx, events.SessionStarted{
+			SessionID: sessID,
+			SeriesID:  seriesID,
+			TeacherID: teacherID,
+			RoomID:    roomID,
+			StartedAt: time.Now(),
+		})
 	}
 
 	// Generate LiveKit token
@@ -1191,13 +1328,6 @@ func (s *Service) JoinSession(ctx context.Context, sessionID, userID, userName,
 // Helpers
 // ═══════════════════════════════════════════════════════════════
 
-func (s *Service) starCostForType(sessionType string) float64 {
-	if sessionType == "one_on_one" {
-		return PrivateStarCost
-	}
-	return GroupStarCost
-}
-
 func (s *Service) getEnrollment(ctx context.Context, enrollID uuid.UUID) (*EnrollmentResponse, error) {
 	var enr EnrollmentResponse
 	err := s.db.Pool.QueryRow(ctx,
@@ -1207,7 +1337,7 @@ func (s *Service) getEnrollment(ctx context.Context, enrollID uuid.UUID) (*Enrol
 		        se.initiated_by, se.status::text, ss.session_type::text,
 		        (SELECT COUNT(*) FROM sessions WHERE series_id = se.series_id),
 		        ss.duration_hours,
-		        se.invited_at, se.requested_at, se.accepted_at, se.created_at
+		        se.invited_at, se.requested_at, se.accepted_at, se.awaiting_credit_at, se.created_at
 		 FROM session_enrollments se
 		 JOIN session_series ss ON ss.id = se.series_id
 		 JOIN users t ON t.id = ss.teacher_id
@@ -1220,7 +1350,7 @@ func (s *Service) getEnrollment(ctx context.Context, enrollID uuid.UUID) (*Enrol
 		&enr.InitiatedBy, &enr.Status, &enr.SessionType,
 		&enr.TotalSessions,
 		&enr.DurationHours,
-		&enr.InvitedAt, &enr.RequestedAt, &enr.AcceptedAt, &enr.CreatedAt,
+		&enr.InvitedAt, &enr.RequestedAt, &enr.AcceptedAt, &enr.AwaitingCreditAt, &enr.CreatedAt,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -1230,37 +1360,3 @@ func (s *Service) getEnrollment(ctx context.Context, enrollID uuid.UUID) (*Enrol
 	}
 	return &enr, nil
 }
-
-func (s *Service) getFee(ctx context.Context, feeID uuid.UUID) (*PlatformFeeResponse, error) {
-	var fee PlatformFeeResponse
-	var seriesTitle string
-	var providerRef, description *string
-	var paidAt *time.Time
-	err := s.db.Pool.QueryRow(ctx,
-		`SELECT pf.id, pf.series_id, ss.title, pf.teacher_id, pf.enrolled_count, 
-		        pf.total_sessions, pf.duration_hours, pf.fee_rate, pf.amount,
-		        pf.status::text, pf.provider_ref, pf.description,
-		        pf.created_at, pf.paid_at
-		 FROM platform_fees pf
-		 JOIN session_series ss ON ss.id = pf.series_id
-		 WHERE pf.id = $1`, feeID,
-	).Scan(
-		&fee.ID, &fee.SeriesID, &seriesTitle, &fee.TeacherID, &fee.EnrolledCount,
-		&fee.TotalSessions, &fee.DurationHours, &fee.FeeRate, &fee.Amount,
-		&fee.Status, &providerRef, &description,
-		&fee.CreatedAt, &paidAt,
-	)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, ErrFeeNotFound
-		}
-		return nil, fmt.Errorf("get fee: %w", err)
-	}
-	fee.SeriesTitle = seriesTitle
-	fee.ProviderRef = providerRef
-	fee.PaidAt = paidAt
-	if description != nil {
-		fee.Description = *description
-	}
-	return &fee, nil
-}
@@ -25,12 +25,15 @@ type TeacherProfileResponse struct {
 	TotalSessions      int       `json:"total_sessions"`
 	TotalStudents      int       `json:"total_students"`
 	CompletionRate     float64   `json:"completion_rate"`
+	ObservedHolidays   []string  `json:"observed_holidays,omitempty"` // holiday kinds the teacher takes off
 }
 
 type UpdateTeacherProfileRequest struct {
 	Bio             *string  `json:"bio,omitempty" validate:"omitempty,max=2000"`
 	ExperienceYears *int     `json:"experience_years,omitempty" validate:"omitempty,min=0,max=50"`
 	Specializations []string `json:"specializations,omitempty"`
+	// ObservedHolidays replaces the kinds observed; [] opts out of all.
+	ObservedHolidays []string `json:"observed_holidays,omitempty" validate:"omitempty,dive,oneof=public school"`
 }
 
 // ─── Offerings ──────────────────────────────────────────────────
@@ -70,11 +73,13 @@ type UpdateOfferingRequest struct {
 
 // ─── Availability ───────────────────────────────────────────────
 
+// AvailabilitySlotResponse is a weekly slot on the teacher's wall clock.
 type AvailabilitySlotResponse struct {
 	ID        uuid.UUID `json:"id"`
 	DayOfWeek int       `json:"day_of_week"`
 	StartTime string    `json:"start_time"`
 	EndTime   string    `json:"end_time"`
+	Timezone  string    `json:"timezone"` // the teacher's IANA zone
 }
 
 type SetAvailabilityRequest struct {
@@ -87,6 +92,37 @@ type AvailabilitySlotInput struct {
 	EndTime   string `json:"end_time" validate:"required"`
 }
 
+// AvailabilityExceptionResponse is a day off or a one-off extra slot on
+// the teacher's wall clock. A day off without times is the whole day.
+type AvailabilityExceptionResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Date        string    `json:"date"`
+	IsAvailable bool      `json:"is_available"` // true: extra slot
+	StartTime   *string   `json:"start_time,omitempty"`
+	EndTime     *string   `json:"end_time,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type AvailabilityExceptionRequest struct {
+	Date        string `json:"date" validate:"required,datetime=2006-01-02"`
+	IsAvailable bool   `json:"is_available"`
+	StartTime   string `json:"start_time,omitempty" validate:"omitempty,datetime=15:04"`
+	EndTime     string `json:"end_time,omitempty" validate:"omitempty,datetime=15:04"`
+	Reason      string `json:"reason,omitempty" validate:"max=255"`
+}
+
+// HolidayResponse is a national or school holiday, dates inclusive.
+type HolidayResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Kind      string    `json:"kind"` // public or school
+	Name      string    `json:"name"`
+	NameAr    string    `json:"name_ar,omitempty"`
+	StartDate string    `json:"start_date"`
+	EndDate   string    `json:"end_date"`
+}
+
 // ─── Earnings ───────────────────────────────────────────────────
 
 type EarningsResponse struct {
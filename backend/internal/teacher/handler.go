@@ -206,6 +206,112 @@ func (h *Handler) GetAvailability(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"success": true, "data": slots})
 }
 
+// GetSchedule GET /teachers/:id/availability/days?from=&to=
+// Resolved availability per date, exceptions and holidays applied.
+func (h *Handler) GetSchedule(c *gin.Context) {
+	days, err := h.service.GetSchedule(c.Request.Context(), c.Param("id"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": days})
+}
+
+// FreeSlots GET /teachers/:id/free-slots?from=&to=&duration=60&granularity=30
+// Bookable start times, for the booking date picker.
+func (h *Handler) FreeSlots(c *gin.Context) {
+	duration, err := strconv.Atoi(c.DefaultQuery("duration", "60"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": "duration must be a number of minutes"}})
+		return
+	}
+	granularity, err := strconv.Atoi(c.DefaultQuery("granularity", "30"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": "granularity must be a number of minutes"}})
+		return
+	}
+
+	days, err := h.service.FreeSlots(c.Request.Context(), c.Param("id"), c.Query("from"), c.Query("to"), duration, granularity)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": days})
+}
+
+// ListExceptions GET /teachers/availability/exceptions?from=&to=
+func (h *Handler) ListExceptions(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	exceptions, err := h.service.ListExceptions(c.Request.Context(), userID, c.Query("from"), c.Query("to"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": exceptions})
+}
+
+// CreateException POST /teachers/availability/exceptions
+func (h *Handler) CreateException(c *gin.Context) {
+	var req AvailabilityExceptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": "invalid request body"}})
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"success": false, "error": gin.H{"message": "validation failed"}})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	exception, err := h.service.CreateException(c.Request.Context(), userID, req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": exception})
+}
+
+// UpdateException PUT /teachers/availability/exceptions/:id
+func (h *Handler) UpdateException(c *gin.Context) {
+	var req AvailabilityExceptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": "invalid request body"}})
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"success": false, "error": gin.H{"message": "validation failed"}})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	exception, err := h.service.UpdateException(c.Request.Context(), userID, c.Param("id"), req)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": exception})
+}
+
+// DeleteException DELETE /teachers/availability/exceptions/:id
+func (h *Handler) DeleteException(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if err := h.service.DeleteException(c.Request.Context(), userID, c.Param("id")); err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"message": "exception deleted"}})
+}
+
+// ListHolidays GET /teachers/holidays?from=&to=
+func (h *Handler) ListHolidays(c *gin.Context) {
+	holidays, err := h.service.ListHolidays(c.Request.Context(), c.Query("from"), c.Query("to"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": holidays})
+}
+
 // GetEarnings GET /teachers/earnings
 func (h *Handler) GetEarnings(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
@@ -230,6 +336,10 @@ func handleError(c *gin.Context, err error) {
 		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"message": "offering not found"}})
 	case errors.Is(err, ErrNotAuthorized):
 		c.JSON(http.StatusForbidden, gin.H{"success": false, "error": gin.H{"message": "not authorized"}})
+	case errors.Is(err, ErrExceptionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"message": "availability exception not found"}})
+	case errors.Is(err, ErrInvalidException), errors.Is(err, ErrInvalidRange), errors.Is(err, ErrInvalidSlotQuery):
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
 	default:
 		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"message": "internal server error"}})
 	}
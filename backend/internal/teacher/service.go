@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"educonnect/internal/scheduling"
 	"educonnect/pkg/database"
 	"educonnect/pkg/search"
 
@@ -16,6 +18,11 @@ var (
 	ErrProfileNotFound  = errors.New("teacher profile not found")
 	ErrOfferingNotFound = errors.New("offering not found")
 	ErrNotAuthorized    = errors.New("not authorized to modify this resource")
+
+	ErrExceptionNotFound = errors.New("availability exception not found")
+	ErrInvalidException  = errors.New("invalid availability exception")
+	ErrInvalidRange      = errors.New("invalid date range")
+	ErrInvalidSlotQuery  = errors.New("invalid free slot query")
 )
 
 type Service struct {
@@ -111,7 +118,8 @@ func (s *Service) GetProfile(ctx context.Context, userID string) (*TeacherProfil
 		        COALESCE(u.email,''), COALESCE(u.phone,''), COALESCE(u.wilaya,''),
 		        COALESCE(tp.bio,''), tp.experience_years, tp.specializations,
 		        tp.verification_status, tp.rating_avg, tp.rating_count,
-		        tp.total_sessions, tp.total_students, tp.completion_rate
+		        tp.total_sessions, tp.total_students, tp.completion_rate,
+		        tp.observed_holidays
 		 FROM teacher_profiles tp
 		 JOIN users u ON u.id = tp.user_id
 		 WHERE tp.user_id = $1`, uid,
@@ -121,6 +129,7 @@ func (s *Service) GetProfile(ctx context.Context, userID string) (*TeacherProfil
 		&p.Bio, &p.ExperienceYears, &specializations,
 		&p.VerificationStatus, &p.RatingAvg, &p.RatingCount,
 		&p.TotalSessions, &p.TotalStudents, &p.CompletionRate,
+		&p.ObservedHolidays,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -141,11 +150,12 @@ func (s *Service) UpdateProfile(ctx context.Context, userID string, req UpdateTe
 
 	_, err = s.db.Pool.Exec(ctx,
 		`UPDATE teacher_profiles SET
-			bio              = COALESCE($2, bio),
-			experience_years = COALESCE($3, experience_years),
-			specializations  = COALESCE($4, specializations)
+			bio               = COALESCE($2, bio),
+			experience_years  = COALESCE($3, experience_years),
+			specializations   = COALESCE($4, specializations),
+			observed_holidays = COALESCE($5, observed_holidays)
 		 WHERE user_id = $1`,
-		uid, req.Bio, req.ExperienceYears, req.Specializations,
+		uid, req.Bio, req.ExperienceYears, req.Specializations, req.ObservedHolidays,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("update teacher: %w", err)
@@ -311,10 +321,11 @@ func (s *Service) GetAvailability(ctx context.Context, teacherID string) ([]Avai
 	uid, _ := uuid.Parse(teacherID)
 
 	rows, err := s.db.Pool.Query(ctx,
-		`SELECT id, day_of_week, start_time::text, end_time::text
-		 FROM availability_slots
-		 WHERE teacher_id = $1 AND is_active = true
-		 ORDER BY day_of_week, start_time`, uid,
+		`SELECT a.id, a.day_of_week, a.start_time::text, a.end_time::text, u.timezone
+		 FROM availability_slots a
+		 JOIN users u ON u.id = a.teacher_id
+		 WHERE a.teacher_id = $1 AND a.is_active = true
+		 ORDER BY a.day_of_week, a.start_time`, uid,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("get availability: %w", err)
@@ -324,7 +335,7 @@ func (s *Service) GetAvailability(ctx context.Context, teacherID string) ([]Avai
 	var slots []AvailabilitySlotResponse
 	for rows.Next() {
 		var slot AvailabilitySlotResponse
-		if err := rows.Scan(&slot.ID, &slot.DayOfWeek, &slot.StartTime, &slot.EndTime); err != nil {
+		if err := rows.Scan(&slot.ID, &slot.DayOfWeek, &slot.StartTime, &slot.EndTime, &slot.Timezone); err != nil {
 			return nil, err
 		}
 		slots = append(slots, slot)
@@ -354,9 +365,10 @@ func (s *Service) SetAvailability(ctx context.Context, teacherID string, req Set
 		err = tx.QueryRow(ctx,
 			`INSERT INTO availability_slots (teacher_id, day_of_week, start_time, end_time)
 			 VALUES ($1, $2, $3::time, $4::time)
-			 RETURNING id, day_of_week, start_time::text, end_time::text`,
+			 RETURNING id, day_of_week, start_time::text, end_time::text,
+			           (SELECT timezone FROM users WHERE id = $1)`,
 			uid, input.DayOfWeek, input.StartTime, input.EndTime,
-		).Scan(&slot.ID, &slot.DayOfWeek, &slot.StartTime, &slot.EndTime)
+		).Scan(&slot.ID, &slot.DayOfWeek, &slot.StartTime, &slot.EndTime, &slot.Timezone)
 		if err != nil {
 			return nil, fmt.Errorf("insert slot: %w", err)
 		}
@@ -370,6 +382,218 @@ func (s *Service) SetAvailability(ctx context.Context, teacherID string, req Set
 	return slots, nil
 }
 
+// ─── Availability Exceptions ────────────────────────────────────
+
+// maxScheduleDays caps the dates GetSchedule resolves in one call.
+const maxScheduleDays = 62
+
+// ListExceptions returns the teacher's exceptions between from and to
+// (YYYY-MM-DD, both optional), by date.
+func (s *Service) ListExceptions(ctx context.Context, teacherID, from, to string) ([]AvailabilityExceptionResponse, error) {
+	uid, _ := uuid.Parse(teacherID)
+	fromDate, err := optionalDate(from)
+	if err != nil {
+		return nil, err
+	}
+	toDate, err := optionalDate(to)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Pool.Query(ctx,
+		`SELECT `+exceptionColumns+` FROM availability_exceptions
+		 WHERE teacher_id = $1
+		   AND ($2::date IS NULL OR exception_date >= $2)
+		   AND ($3::date IS NULL OR exception_date <= $3)
+		 ORDER BY exception_date, start_time NULLS FIRST`,
+		uid, fromDate, toDate,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list exceptions: %w", err)
+	}
+	defer rows.Close()
+
+	exceptions := []AvailabilityExceptionResponse{}
+	for rows.Next() {
+		var e AvailabilityExceptionResponse
+		if err := scanException(rows, &e); err != nil {
+			return nil, fmt.Errorf("scan exception: %w", err)
+		}
+		exceptions = append(exceptions, e)
+	}
+	return exceptions, rows.Err()
+}
+
+// CreateException adds a day off, hours off or an extra slot.
+func (s *Service) CreateException(ctx context.Context, teacherID string, req AvailabilityExceptionRequest) (*AvailabilityExceptionResponse, error) {
+	uid, _ := uuid.Parse(teacherID)
+	if err := validateException(req); err != nil {
+		return nil, err
+	}
+
+	var e AvailabilityExceptionResponse
+	err := scanException(s.db.Pool.QueryRow(ctx,
+		`INSERT INTO availability_exceptions (teacher_id, exception_date, is_available, start_time, end_time, reason)
+		 VALUES ($1, $2::date, $3, $4::time, $5::time, $6)
+		 RETURNING `+exceptionColumns,
+		uid, req.Date, req.IsAvailable, nullIfEmpty(req.StartTime), nullIfEmpty(req.EndTime), nullIfEmpty(req.Reason),
+	), &e)
+	if err != nil {
+		return nil, fmt.Errorf("insert exception: %w", err)
+	}
+	return &e, nil
+}
+
+// UpdateException replaces one of the teacher's exceptions.
+func (s *Service) UpdateException(ctx context.Context, teacherID, exceptionID string, req AvailabilityExceptionRequest) (*AvailabilityExceptionResponse, error) {
+	uid, _ := uuid.Parse(teacherID)
+	eid, err := uuid.Parse(exceptionID)
+	if err != nil {
+		return nil, ErrExceptionNotFound
+	}
+	if err := validateException(req); err != nil {
+		return nil, err
+	}
+
+	var e AvailabilityExceptionResponse
+	err = scanException(s.db.Pool.QueryRow(ctx,
+		`UPDATE availability_exceptions SET
+			exception_date = $3::date,
+			is_available   = $4,
+			start_time     = $5::time,
+			end_time       = $6::time,
+			reason         = $7
+		 WHERE id = $1 AND teacher_id = $2
+		 RETURNING `+exceptionColumns,
+		eid, uid, req.Date, req.IsAvailable, nullIfEmpty(req.StartTime), nullIfEmpty(req.EndTime), nullIfEmpty(req.Reason),
+	), &e)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrExceptionNotFound
+		}
+		return nil, fmt.Errorf("update exception: %w", err)
+	}
+	return &e, nil
+}
+
+func (s *Service) DeleteException(ctx context.Context, teacherID, exceptionID string) error {
+	uid, _ := uuid.Parse(teacherID)
+	eid, err := uuid.Parse(exceptionID)
+	if err != nil {
+		return ErrExceptionNotFound
+	}
+
+	tag, err := s.db.Pool.Exec(ctx,
+		`DELETE FROM availability_exceptions WHERE id = $1 AND teacher_id = $2`, eid, uid)
+	if err != nil {
+		return fmt.Errorf("delete exception: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrExceptionNotFound
+	}
+	return nil
+}
+
+// GetSchedule resolves the teacher's availability for each date from
+// from to to: weekly slots with exceptions and observed holidays applied.
+func (s *Service) GetSchedule(ctx context.Context, teacherID, from, to string) ([]scheduling.Day, error) {
+	uid, err := uuid.Parse(teacherID)
+	if err != nil {
+		return nil, ErrProfileNotFound
+	}
+	loc, err := scheduling.UserZone(ctx, s.db.Pool, uid)
+	if err != nil {
+		return nil, err
+	}
+	fromDate, toDate, err := dateRange(from, to, time.Now().In(loc))
+	if err != nil {
+		return nil, err
+	}
+	return scheduling.Availability(ctx, s.db.Pool, uid, fromDate, toDate)
+}
+
+// FreeSlots lists the times a student can book with the teacher from
+// from to to, for sessions of duration minutes starting every
+// granularity minutes.
+func (s *Service) FreeSlots(ctx context.Context, teacherID, from, to string, duration, granularity int) ([]scheduling.FreeDay, error) {
+	uid, err := uuid.Parse(teacherID)
+	if err != nil {
+		return nil, ErrProfileNotFound
+	}
+	if duration < 30 || duration > 240 {
+		return nil, fmt.Errorf("%w: duration is 30 to 240 minutes", ErrInvalidSlotQuery)
+	}
+	if granularity < 5 || granularity > 120 {
+		return nil, fmt.Errorf("%w: granularity is 5 to 120 minutes", ErrInvalidSlotQuery)
+	}
+
+	loc, err := scheduling.UserZone(ctx, s.db.Pool, uid)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	fromDate, toDate, err := dateRange(from, to, now.In(loc))
+	if err != nil {
+		return nil, err
+	}
+	return scheduling.FreeSlots(ctx, s.db.Pool, uid, loc, fromDate, toDate,
+		time.Duration(duration)*time.Minute, time.Duration(granularity)*time.Minute, now)
+}
+
+// ListHolidays returns the holidays of the national calendar overlapping
+// from to to (YYYY-MM-DD, both optional), by date.
+func (s *Service) ListHolidays(ctx context.Context, from, to string) ([]HolidayResponse, error) {
+	fromDate, err := optionalDate(from)
+	if err != nil {
+		return nil, err
+	}
+	toDate, err := optionalDate(to)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Pool.Query(ctx,
+		`SELECT id, kind, name_fr, COALESCE(name_ar, ''), start_date, end_date
+		 FROM holidays
+		 WHERE ($1::date IS NULL OR end_date >= $1)
+		   AND ($2::date IS NULL OR start_date <= $2)
+		 ORDER BY start_date, kind`,
+		fromDate, toDate,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list holidays: %w", err)
+	}
+	defer rows.Close()
+
+	holidays := []HolidayResponse{}
+	for rows.Next() {
+		var h HolidayResponse
+		var start, end time.Time
+		if err := rows.Scan(&h.ID, &h.Kind, &h.Name, &h.NameAr, &start, &end); err != nil {
+			return nil, fmt.Errorf("scan holiday: %w", err)
+		}
+		h.StartDate = start.Format("2006-01-02")
+		h.EndDate = end.Format("2006-01-02")
+		holidays = append(holidays, h)
+	}
+	return holidays, rows.Err()
+}
+
+// validateException checks what the struct tags cannot: an extra slot
+// needs its hours, and hours need both ends in order.
+func validateException(req AvailabilityExceptionRequest) error {
+	if (req.StartTime == "") != (req.EndTime == "") {
+		return fmt.Errorf("%w: start_time and end_time go together", ErrInvalidException)
+	}
+	if req.IsAvailable && req.StartTime == "" {
+		return fmt.Errorf("%w: an extra slot needs start_time and end_time", ErrInvalidException)
+	}
+	if req.StartTime != "" && req.EndTime <= req.StartTime {
+		return fmt.Errorf("%w: end_time must be after start_time", ErrInvalidException)
+	}
+	return nil
+}
+
 // ─── Earnings ───────────────────────────────────────────────────
 
 func (s *Service) GetEarnings(ctx context.Context, teacherID string, page, limit int) (*EarningsResponse, error) {
@@ -378,14 +602,21 @@ func (s *Service) GetEarnings(ctx context.Context, teacherID string, page, limit
 	var resp EarningsResponse
 	err := s.db.Pool.QueryRow(ctx,
 		`SELECT
-			COALESCE(SUM(net_amount), 0),
-			COALESCE(SUM(CASE WHEN created_at >= date_trunc('month', NOW()) THEN net_amount ELSE 0 END), 0),
-			COALESCE(SUM(CASE WHEN status = 'completed' AND created_at >= date_trunc('month', NOW()) THEN net_amount ELSE 0 END), 0)
-		 FROM transactions WHERE payee_id = $1 AND status = 'completed'`, uid,
+			COALESCE(SUM(net_amount) FILTER (WHERE status = 'completed'), 0),
+			COALESCE(SUM(net_amount) FILTER (WHERE status = 'completed' AND created_at >= date_trunc('month', NOW())), 0),
+			-- Same formula as payout.Service: net earnings less refunds, less
+			-- every payout that is not failed.
+			COALESCE(SUM(net_amount - COALESCE(refund_amount, 0)), 0)
+			  - (SELECT COALESCE(SUM(amount), 0) FROM payouts
+			     WHERE teacher_id = $1 AND status IN ('pending', 'processing', 'completed'))
+		 FROM transactions WHERE payee_id = $1 AND status IN ('completed', 'refunded')`, uid,
 	).Scan(&resp.TotalEarnings, &resp.MonthEarnings, &resp.AvailableBalance)
 	if err != nil {
 		return nil, fmt.Errorf("earnings: %w", err)
 	}
+	if resp.AvailableBalance < 0 {
+		resp.AvailableBalance = 0
+	}
 
 	offset := (page - 1) * limit
 	rows, err := s.db.Pool.Query(ctx,
@@ -546,3 +777,65 @@ func (s *Service) GetDashboard(ctx context.Context, teacherID string) (*TeacherD
 		RecentReviews:    reviews,
 	}, nil
 }
+
+// ─── Helpers ────────────────────────────────────────────────────
+
+const exceptionColumns = `id, exception_date, is_available,
+    to_char(start_time, 'HH24:MI'), to_char(end_time, 'HH24:MI'),
+    COALESCE(reason, ''), created_at, updated_at`
+
+func scanException(row pgx.Row, e *AvailabilityExceptionResponse) error {
+	var date time.Time
+	if err := row.Scan(&e.ID, &date, &e.IsAvailable, &e.StartTime, &e.EndTime, &e.Reason, &e.CreatedAt, &e.UpdatedAt); err != nil {
+		return err
+	}
+	e.Date = date.Format("2006-01-02")
+	return nil
+}
+
+func nullIfEmpty(v string) *string {
+	if v == "" {
+		return nil
+	}
+	return &v
+}
+
+// optionalDate parses a YYYY-MM-DD query parameter; "" is no bound.
+func optionalDate(v string) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	d, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		return nil, fmt.Errorf("%w: dates are YYYY-MM-DD", ErrInvalidRange)
+	}
+	return &d, nil
+}
+
+// dateRange parses from and to, defaulting to the week starting on
+// now's date, and caps the span at maxScheduleDays.
+func dateRange(from, to string, now time.Time) (time.Time, time.Time, error) {
+	fromDate, err := optionalDate(from)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if fromDate == nil {
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		fromDate = &today
+	}
+	toDate, err := optionalDate(to)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if toDate == nil {
+		end := fromDate.AddDate(0, 0, 6)
+		toDate = &end
+	}
+	if toDate.Before(*fromDate) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: to is before from", ErrInvalidRange)
+	}
+	if toDate.Sub(*fromDate) >= maxScheduleDays*24*time.Hour {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: at most %d days at a time", ErrInvalidRange, maxScheduleDays)
+	}
+	return *fromDate, *toDate, nil
+}
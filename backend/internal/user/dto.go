@@ -19,6 +19,7 @@ type ProfileResponse struct {
 	AvatarURL       string     `json:"avatar_url,omitempty"`
 	Wilaya          string     `json:"wilaya,omitempty"`
 	Language        string     `json:"language"`
+	Timezone        string     `json:"timezone"` // IANA zone schedules are shown in
 	IsEmailVerified bool       `json:"is_email_verified"`
 	IsPhoneVerified bool       `json:"is_phone_verified"`
 	LastLoginAt     *time.Time `json:"last_login_at,omitempty"`
@@ -33,6 +34,7 @@ type UpdateProfileRequest struct {
 	LastName  *string `json:"last_name,omitempty" validate:"omitempty,min=2,max=50"`
 	Wilaya    *string `json:"wilaya,omitempty" validate:"omitempty,max=100"`
 	Language  *string `json:"language,omitempty" validate:"omitempty,oneof=fr ar en"`
+	Timezone  *string `json:"timezone,omitempty" validate:"omitempty,timezone"`
 }
 
 // ChangePasswordRequest requires old and new password.
@@ -42,12 +42,12 @@ func (s *Service) GetProfile(ctx context.Context, userID string) (*ProfileRespon
 	var p ProfileResponse
 	err = s.db.Pool.QueryRow(ctx,
 		`SELECT id, COALESCE(email,''), COALESCE(phone,''), role, first_name, last_name,
-		        COALESCE(avatar_url,''), COALESCE(wilaya,''), language,
+		        COALESCE(avatar_url,''), COALESCE(wilaya,''), language, timezone,
 		        is_email_verified, is_phone_verified, last_login_at, created_at
 		 FROM users WHERE id = $1 AND is_active = true`, uid,
 	).Scan(
 		&p.ID, &p.Email, &p.Phone, &p.Role, &p.FirstName, &p.LastName,
-		&p.AvatarURL, &p.Wilaya, &p.Language,
+		&p.AvatarURL, &p.Wilaya, &p.Language, &p.Timezone,
 		&p.IsEmailVerified, &p.IsPhoneVerified, &p.LastLoginAt, &p.CreatedAt,
 	)
 	if err != nil {
@@ -73,15 +73,16 @@ func (s *Service) UpdateProfile(ctx context.Context, userID string, req UpdatePr
 			first_name = COALESCE($2, first_name),
 			last_name  = COALESCE($3, last_name),
 			wilaya     = COALESCE($4, wilaya),
-			language   = COALESCE($5, language)
+			language   = COALESCE($5, language),
+			timezone   = COALESCE($6, timezone)
 		 WHERE id = $1 AND is_active = true
 		 RETURNING id, COALESCE(email,''), COALESCE(phone,''), role, first_name, last_name,
-		           COALESCE(avatar_url,''), COALESCE(wilaya,''), language,
+		           COALESCE(avatar_url,''), COALESCE(wilaya,''), language, timezone,
 		           is_email_verified, is_phone_verified, last_login_at, created_at`,
-		uid, req.FirstName, req.LastName, req.Wilaya, req.Language,
+		uid, req.FirstName, req.LastName, req.Wilaya, req.Language, req.Timezone,
 	).Scan(
 		&p.ID, &p.Email, &p.Phone, &p.Role, &p.FirstName, &p.LastName,
-		&p.AvatarURL, &p.Wilaya, &p.Language,
+		&p.AvatarURL, &p.Wilaya, &p.Language, &p.Timezone,
 		&p.IsEmailVerified, &p.IsPhoneVerified, &p.LastLoginAt, &p.CreatedAt,
 	)
 	if err != nil {
@@ -146,12 +147,12 @@ func (s *Service) UploadAvatar(ctx context.Context, userID string, fileData []by
 		`UPDATE users SET avatar_url = $2
 		 WHERE id = $1 AND is_active = true
 		 RETURNING id, COALESCE(email,''), COALESCE(phone,''), role, first_name, last_name,
-		           avatar_url, COALESCE(wilaya,''), language,
+		           avatar_url, COALESCE(wilaya,''), language, timezone,
 		           is_email_verified, is_phone_verified, last_login_at, created_at`,
 		uid, presignedURL,
 	).Scan(
 		&p.ID, &p.Email, &p.Phone, &p.Role, &p.FirstName, &p.LastName,
-		&p.AvatarURL, &p.Wilaya, &p.Language,
+		&p.AvatarURL, &p.Wilaya, &p.Language, &p.Timezone,
 		&p.IsEmailVerified, &p.IsPhoneVerified, &p.LastLoginAt, &p.CreatedAt,
 	)
 	if err != nil {
@@ -7,24 +7,15 @@ import (
 )
 
 // ═══════════════════════════════════════════════════════════════
-// Star Pricing Constants
+// Purchase Review
 // ═══════════════════════════════════════════════════════════════
-// Replaces the old per-hour platform_fees model.
-// Cost is per enrollment (1 star per accepted student).
 
 const (
-	GroupStarCost   = 50.0 // DZD per enrollment in a group series
-	PrivateStarCost = 70.0 // DZD per enrollment in a 1-on-1 series
+	PurchaseExpiry   = 7 * 24 * time.Hour // pending purchases expire after this
+	MaxReceiptSize   = 10 << 20           // bytes
+	receiptURLExpiry = 15 * time.Minute
 )
 
-// StarCost returns the DZD cost of one star based on session type.
-func StarCost(sessionType string) float64 {
-	if sessionType == "one_on_one" {
-		return PrivateStarCost
-	}
-	return GroupStarCost
-}
-
 // ═══════════════════════════════════════════════════════════════
 // Wallet Responses
 // ═══════════════════════════════════════════════════════════════
@@ -47,7 +38,7 @@ type WalletTransactionResponse struct {
 	ID            uuid.UUID  `json:"id"`
 	WalletID      uuid.UUID  `json:"wallet_id"`
 	Type          string     `json:"type"`   // purchase, star_deduction, refund
-	Status        string     `json:"status"` // pending, completed, failed
+	Status        string     `json:"status"` // pending, completed, failed, expired
 	Amount        float64    `json:"amount"`
 	BalanceAfter  float64    `json:"balance_after"`
 	Description   string     `json:"description"`
@@ -59,7 +50,21 @@ type WalletTransactionResponse struct {
 	SeriesID      *uuid.UUID `json:"series_id,omitempty"`
 	SeriesTitle   string     `json:"series_title,omitempty"`
 	AdminNotes    string     `json:"admin_notes,omitempty"`
-	CreatedAt     time.Time  `json:"created_at"`
+	// Star deductions: the price version that was charged
+	StarPriceID      *uuid.UUID `json:"star_price_id,omitempty"`
+	StarPriceVersion *int64     `json:"star_price_version,omitempty"`
+	// Refunds: the deduction returned and the policy rule applied
+	RefundOf   *uuid.UUID `json:"refund_of,omitempty"`
+	RefundRule string     `json:"refund_rule,omitempty"`
+	// Purchases: proof of payment and review window
+	HasReceipt         bool        `json:"has_receipt"`
+	ReceiptContentType string      `json:"receipt_content_type,omitempty"`
+	ReceiptURL         string      `json:"receipt_url,omitempty"`  // presigned, short-lived
+	DuplicateOf        []uuid.UUID `json:"duplicate_of,omitempty"` // admin: other purchases with the same reference
+	ExpiresAt          *time.Time  `json:"expires_at,omitempty"`   // pending purchases only
+	CreatedAt          time.Time   `json:"created_at"`
+
+	receiptKey string
 }
 
 type CreditPackageResponse struct {
@@ -75,6 +80,25 @@ type CreditPackageResponse struct {
 	SortOrder    int  `json:"sort_order"`
 }
 
+// StarPriceResponse is one version of the star cost. Versions are never
+// edited: a price change is a new version, and an override is retired by
+// setting EffectiveUntil.
+type StarPriceResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	Version        int64      `json:"version"`
+	SessionType    string     `json:"session_type"`       // group, one_on_one
+	LevelID        *uuid.UUID `json:"level_id,omitempty"` // nil = any level
+	LevelName      string     `json:"level_name,omitempty"`
+	Wilaya         string     `json:"wilaya,omitempty"` // empty = any wilaya
+	Price          float64    `json:"price"`            // DZD per accepted enrollment
+	EffectiveFrom  time.Time  `json:"effective_from"`
+	EffectiveUntil *time.Time `json:"effective_until,omitempty"`
+	Superseded     bool       `json:"superseded"` // a newer version of the same scope is in force
+	Notes          string     `json:"notes,omitempty"`
+	CreatedBy      *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
 // ═══════════════════════════════════════════════════════════════
 // Requests
 // ═══════════════════════════════════════════════════════════════
@@ -90,6 +114,50 @@ type AdminApprovePurchaseRequest struct {
 	Notes    string `json:"notes"`
 }
 
+// AdminRefundRequest refunds a star outside the refund policy. Amount 0
+// refunds everything not yet refunded.
+type AdminRefundRequest struct {
+	EnrollmentID uuid.UUID `json:"enrollment_id" validate:"required"`
+	Amount       float64   `json:"amount" validate:"gte=0"`
+	Notes        string    `json:"notes" validate:"required"`
+}
+
+type CreatePackageRequest struct {
+	Name      string  `json:"name" validate:"required,max=100"`
+	Amount    float64 `json:"amount" validate:"gt=0"`
+	Bonus     float64 `json:"bonus" validate:"gte=0"`
+	SortOrder int     `json:"sort_order"`
+	IsActive  *bool   `json:"is_active"` // defaults to true
+}
+
+// UpdatePackageRequest changes only the fields that are set. Pending
+// purchases keep the amount they were submitted with.
+type UpdatePackageRequest struct {
+	Name      *string  `json:"name" validate:"omitempty,min=1,max=100"`
+	Amount    *float64 `json:"amount" validate:"omitempty,gt=0"`
+	Bonus     *float64 `json:"bonus" validate:"omitempty,gte=0"`
+	SortOrder *int     `json:"sort_order"`
+	IsActive  *bool    `json:"is_active"`
+}
+
+// CreateStarPriceRequest publishes a new price version. Leaving LevelID
+// and Wilaya empty replaces the default price for the session type;
+// EffectiveFrom defaults to now and may be in the future.
+type CreateStarPriceRequest struct {
+	SessionType   string     `json:"session_type" validate:"required,oneof=group one_on_one"`
+	LevelID       *uuid.UUID `json:"level_id"`
+	Wilaya        string     `json:"wilaya" validate:"max=100"`
+	Price         float64    `json:"price" validate:"gt=0"`
+	EffectiveFrom *time.Time `json:"effective_from"`
+	Notes         string     `json:"notes"`
+}
+
+// EndStarPriceRequest retires a level or wilaya override; EffectiveUntil
+// defaults to now.
+type EndStarPriceRequest struct {
+	EffectiveUntil *time.Time `json:"effective_until"`
+}
+
 // ═══════════════════════════════════════════════════════════════
 // Pagination
 // ═══════════════════════════════════════════════════════════════
@@ -3,6 +3,7 @@ package wallet
 import (
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 
@@ -57,6 +58,35 @@ func (h *Handler) BuyCredits(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{"success": true, "data": tx})
 }
 
+// UploadReceipt POST /wallet/purchases/:id/receipt
+func (h *Handler) UploadReceipt(c *gin.Context) {
+	file, header, err := c.Request.FormFile("receipt")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": "receipt file required"}})
+		return
+	}
+	defer file.Close()
+
+	if header.Size > MaxReceiptSize {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": "file size must be under 10MB"}})
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, MaxReceiptSize))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": gin.H{"message": "failed to read file"}})
+		return
+	}
+
+	userID := middleware.GetUserID(c)
+	tx, err := h.service.UploadReceipt(c.Request.Context(), userID, c.Param("id"), data)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": tx})
+}
+
 // ListTransactions GET /wallet/transactions
 func (h *Handler) ListTransactions(c *gin.Context) {
 	userID := middleware.GetUserID(c)
@@ -138,13 +168,128 @@ func (h *Handler) AdminApprovePurchase(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"success": true, "data": tx})
 }
 
+// AdminRefundStar POST /admin/wallet/refunds
+func (h *Handler) AdminRefundStar(c *gin.Context) {
+	var req AdminRefundRequest
+	if !h.bind(c, &req) {
+		return
+	}
+	tx, err := h.service.AdminRefundStar(c.Request.Context(), middleware.GetUserID(c), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": tx})
+}
+
+// AdminListPackages GET /admin/wallet/packages
+func (h *Handler) AdminListPackages(c *gin.Context) {
+	pkgs, err := h.service.AdminListPackages(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": pkgs})
+}
+
+// AdminCreatePackage POST /admin/wallet/packages
+func (h *Handler) AdminCreatePackage(c *gin.Context) {
+	var req CreatePackageRequest
+	if !h.bind(c, &req) {
+		return
+	}
+	pkg, err := h.service.AdminCreatePackage(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": pkg})
+}
+
+// AdminUpdatePackage PUT /admin/wallet/packages/:id
+func (h *Handler) AdminUpdatePackage(c *gin.Context) {
+	var req UpdatePackageRequest
+	if !h.bind(c, &req) {
+		return
+	}
+	pkg, err := h.service.AdminUpdatePackage(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": pkg})
+}
+
+// AdminDeletePackage DELETE /admin/wallet/packages/:id
+func (h *Handler) AdminDeletePackage(c *gin.Context) {
+	pkg, err := h.service.AdminDeletePackage(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": pkg})
+}
+
+// AdminListStarPrices GET /admin/wallet/star-prices?session_type=&history=true
+func (h *Handler) AdminListStarPrices(c *gin.Context) {
+	prices, err := h.service.AdminListStarPrices(c.Request.Context(), c.Query("session_type"), c.Query("history") == "true")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": prices})
+}
+
+// AdminCreateStarPrice POST /admin/wallet/star-prices
+func (h *Handler) AdminCreateStarPrice(c *gin.Context) {
+	var req CreateStarPriceRequest
+	if !h.bind(c, &req) {
+		return
+	}
+	price, err := h.service.AdminCreateStarPrice(c.Request.Context(), middleware.GetUserID(c), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"success": true, "data": price})
+}
+
+// AdminEndStarPrice PUT /admin/wallet/star-prices/:id/end
+func (h *Handler) AdminEndStarPrice(c *gin.Context) {
+	var req EndStarPriceRequest
+	if c.Request.ContentLength > 0 && !h.bind(c, &req) {
+		return
+	}
+	price, err := h.service.AdminEndStarPrice(c.Request.Context(), c.Param("id"), req.EffectiveUntil)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": price})
+}
+
+// bind decodes and validates a JSON body, writing the error response
+// itself when it fails.
+func (h *Handler) bind(c *gin.Context, req any) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+		return false
+	}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"success": false, "error": gin.H{"message": "validation failed", "details": err.Error()}})
+		return false
+	}
+	return true
+}
+
 // ═══════════════════════════════════════════════════════════════
 // Error Handler
 // ═══════════════════════════════════════════════════════════════
 
 func respondError(c *gin.Context, err error) {
 	switch {
-	case errors.Is(err, ErrWalletNotFound), errors.Is(err, ErrTransactionNotFound), errors.Is(err, ErrPackageNotFound):
+	case errors.Is(err, ErrWalletNotFound), errors.Is(err, ErrTransactionNotFound), errors.Is(err, ErrPackageNotFound),
+		errors.Is(err, ErrStarPriceNotFound), errors.Is(err, ErrLevelNotFound), errors.Is(err, ErrNoStarDeduction):
 		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
 	case errors.Is(err, ErrInsufficientBalance):
 		c.JSON(http.StatusPaymentRequired, gin.H{"success": false, "error": gin.H{
@@ -153,6 +298,25 @@ func respondError(c *gin.Context, err error) {
 		}})
 	case errors.Is(err, ErrPackageInactive), errors.Is(err, ErrAlreadyProcessed), errors.Is(err, ErrNotPendingPurchase):
 		c.JSON(http.StatusConflict, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+	case errors.Is(err, ErrDuplicateReference):
+		c.JSON(http.StatusConflict, gin.H{"success": false, "error": gin.H{
+			"code":    "DUPLICATE_REFERENCE",
+			"message": err.Error(),
+		}})
+	case errors.Is(err, ErrReceiptRequired):
+		c.JSON(http.StatusConflict, gin.H{"success": false, "error": gin.H{
+			"code":    "RECEIPT_REQUIRED",
+			"message": err.Error(),
+		}})
+	case errors.Is(err, ErrStarPriceEnded), errors.Is(err, ErrDefaultStarPrice), errors.Is(err, ErrRefundExceedsCharge):
+		c.JSON(http.StatusConflict, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
+	case errors.Is(err, ErrNoStarPrice):
+		c.JSON(http.StatusConflict, gin.H{"success": false, "error": gin.H{
+			"code":    "NO_STAR_PRICE",
+			"message": err.Error(),
+		}})
+	case errors.Is(err, ErrInvalidReceipt), errors.Is(err, ErrInvalidProviderRef), errors.Is(err, ErrPastEffectiveDate):
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{"message": err.Error()}})
 	case errors.Is(err, ErrRefundNotEligible):
 		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": gin.H{
 			"code":    "REFUND_NOT_ELIGIBLE",
@@ -0,0 +1,307 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// querier is satisfied by both the pool and a transaction, so prices can
+// be resolved for display or inside the deduction transaction.
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// ═══════════════════════════════════════════════════════════════
+// Star Price Resolution
+// ═══════════════════════════════════════════════════════════════
+
+// resolveStarPrice returns the price version in force at `at` for an
+// enrollment of sessionType. A level override beats a wilaya override,
+// which beats the default; within a scope the latest version wins.
+func resolveStarPrice(ctx context.Context, q querier, sessionType string, levelID *uuid.UUID, wilaya string, at time.Time) (*StarPriceResponse, error) {
+	var p StarPriceResponse
+	err := scanStarPrice(q.QueryRow(ctx, starPriceSelect+`
+		 WHERE sp.session_type = $1::session_type
+		   AND (sp.level_id IS NULL OR sp.level_id = $2)
+		   AND (sp.wilaya IS NULL OR lower(sp.wilaya) = lower($3::text))
+		   AND sp.effective_from <= $4
+		   AND (sp.effective_until IS NULL OR sp.effective_until > $4)
+		 ORDER BY (sp.level_id IS NOT NULL) DESC, (sp.wilaya IS NOT NULL) DESC, sp.effective_from DESC
+		 LIMIT 1`, sessionType, levelID, wilaya, at,
+	), &p)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNoStarPrice
+		}
+		return nil, fmt.Errorf("resolve star price: %w", err)
+	}
+	return &p, nil
+}
+
+// seriesPriceScope returns the level of a series (direct or through its
+// offering) and its teacher's wilaya.
+func seriesPriceScope(ctx context.Context, q querier, seriesID uuid.UUID) (*uuid.UUID, string, error) {
+	var levelID *uuid.UUID
+	var wilaya string
+	err := q.QueryRow(ctx,
+		`SELECT COALESCE(ss.level_id, o.level_id), COALESCE(u.wilaya, '')
+		 FROM session_series ss
+		 JOIN users u ON u.id = ss.teacher_id
+		 LEFT JOIN offerings o ON o.id = ss.offering_id
+		 WHERE ss.id = $1`, seriesID,
+	).Scan(&levelID, &wilaya)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, "", fmt.Errorf("series price scope: %w", err)
+	}
+	return levelID, wilaya, nil
+}
+
+// StarPriceForSeries returns the price one accepted enrollment in the
+// series costs its teacher right now.
+func (s *Service) StarPriceForSeries(ctx context.Context, seriesID uuid.UUID) (*StarPriceResponse, error) {
+	var sessionType string
+	err := s.db.Pool.QueryRow(ctx,
+		`SELECT session_type::text FROM session_series WHERE id = $1`, seriesID,
+	).Scan(&sessionType)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNoStarPrice
+		}
+		return nil, fmt.Errorf("get series type: %w", err)
+	}
+	levelID, wilaya, err := seriesPriceScope(ctx, s.db.Pool, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	return resolveStarPrice(ctx, s.db.Pool, sessionType, levelID, wilaya, time.Now())
+}
+
+// starsFor is how many stars amount buys at the current group and private
+// prices for a teacher in wilaya (any level). A missing price counts as
+// zero stars rather than failing the wallet view.
+func (s *Service) starsFor(ctx context.Context, amount float64, wilaya string) (group, private int) {
+	count := func(sessionType string) int {
+		p, err := resolveStarPrice(ctx, s.db.Pool, sessionType, nil, wilaya, time.Now())
+		if err != nil {
+			return 0
+		}
+		return int(math.Floor(amount / p.Price))
+	}
+	return count("group"), count("one_on_one")
+}
+
+// ═══════════════════════════════════════════════════════════════
+// Admin: Star Prices
+// ═══════════════════════════════════════════════════════════════
+
+// AdminListStarPrices lists price versions by scope, newest first. Unless
+// history is set, ended and superseded versions are left out.
+func (s *Service) AdminListStarPrices(ctx context.Context, sessionType string, history bool) ([]StarPriceResponse, error) {
+	query := starPriceSelect + ` WHERE ($1 = '' OR sp.session_type::text = $1)`
+	if !history {
+		query += ` AND (sp.effective_until IS NULL OR sp.effective_until > NOW()) AND NOT ` + starPriceSuperseded
+	}
+	query += ` ORDER BY sp.session_type, l.name NULLS FIRST, sp.wilaya NULLS FIRST, sp.effective_from DESC`
+
+	rows, err := s.db.Pool.Query(ctx, query, sessionType)
+	if err != nil {
+		return nil, fmt.Errorf("list star prices: %w", err)
+	}
+	defer rows.Close()
+
+	prices := []StarPriceResponse{}
+	for rows.Next() {
+		var p StarPriceResponse
+		if err := scanStarPrice(rows, &p); err != nil {
+			return nil, fmt.Errorf("scan star price: %w", err)
+		}
+		prices = append(prices, p)
+	}
+	return prices, rows.Err()
+}
+
+// AdminCreateStarPrice publishes a new price version. It applies to
+// deductions from EffectiveFrom on; earlier deductions keep the version
+// they recorded.
+func (s *Service) AdminCreateStarPrice(ctx context.Context, adminID string, req CreateStarPriceRequest) (*StarPriceResponse, error) {
+	aid, _ := uuid.Parse(adminID)
+
+	from := time.Now()
+	if req.EffectiveFrom != nil {
+		if req.EffectiveFrom.Before(from.Add(-time.Minute)) {
+			return nil, ErrPastEffectiveDate
+		}
+		from = *req.EffectiveFrom
+	}
+
+	if req.LevelID != nil {
+		var exists bool
+		_ = s.db.Pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM levels WHERE id = $1)`, *req.LevelID).Scan(&exists)
+		if !exists {
+			return nil, ErrLevelNotFound
+		}
+	}
+
+	var wilaya *string
+	if w := strings.TrimSpace(req.Wilaya); w != "" {
+		wilaya = &w
+	}
+
+	var id uuid.UUID
+	err := s.db.Pool.QueryRow(ctx,
+		`INSERT INTO star_prices (session_type, level_id, wilaya, price, effective_from, notes, created_by)
+		 VALUES ($1::session_type, $2, $3, $4, $5, $6, $7)
+		 RETURNING id`,
+		req.SessionType, req.LevelID, wilaya, req.Price, from, strings.TrimSpace(req.Notes), aid,
+	).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("create star price: %w", err)
+	}
+	return s.getStarPrice(ctx, id)
+}
+
+// AdminEndStarPrice retires a level or wilaya override, after which the
+// next less specific price applies. Default prices always stay in force;
+// they change by publishing a new version.
+func (s *Service) AdminEndStarPrice(ctx context.Context, priceID string, until *time.Time) (*StarPriceResponse, error) {
+	id, err := uuid.Parse(priceID)
+	if err != nil {
+		return nil, ErrStarPriceNotFound
+	}
+	p, err := s.getStarPrice(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if p.LevelID == nil && p.Wilaya == "" {
+		return nil, ErrDefaultStarPrice
+	}
+	if p.EffectiveUntil != nil && !p.EffectiveUntil.After(time.Now()) {
+		return nil, ErrStarPriceEnded
+	}
+
+	end := time.Now()
+	if until != nil {
+		if until.Before(end.Add(-time.Minute)) {
+			return nil, ErrPastEffectiveDate
+		}
+		end = *until
+	}
+	if !end.After(p.EffectiveFrom) {
+		// Never took effect: end it the moment it would have started.
+		end = p.EffectiveFrom.Add(time.Microsecond)
+	}
+
+	_, err = s.db.Pool.Exec(ctx,
+		`UPDATE star_prices SET effective_until = $1 WHERE id = $2`, end, id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("end star price: %w", err)
+	}
+	return s.getStarPrice(ctx, id)
+}
+
+func (s *Service) getStarPrice(ctx context.Context, id uuid.UUID) (*StarPriceResponse, error) {
+	var p StarPriceResponse
+	if err := scanStarPrice(s.db.Pool.QueryRow(ctx, starPriceSelect+` WHERE sp.id = $1`, id), &p); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrStarPriceNotFound
+		}
+		return nil, fmt.Errorf("get star price: %w", err)
+	}
+	return &p, nil
+}
+
+const (
+	starPriceSuperseded = `EXISTS (
+		SELECT 1 FROM star_prices n
+		WHERE n.session_type = sp.session_type
+		  AND n.level_id IS NOT DISTINCT FROM sp.level_id
+		  AND n.wilaya IS NOT DISTINCT FROM sp.wilaya
+		  AND n.effective_from > sp.effective_from AND n.effective_from <= NOW())`
+	starPriceSelect = `SELECT sp.id, sp.version, sp.session_type::text, sp.level_id, COALESCE(l.name, ''),
+        COALESCE(sp.wilaya, ''), sp.price, sp.effective_from, sp.effective_until, ` + starPriceSuperseded + `,
+        sp.notes, sp.created_by, sp.created_at
+ FROM star_prices sp
+ LEFT JOIN levels l ON l.id = sp.level_id`
+)
+
+func scanStarPrice(row pgx.Row, p *StarPriceResponse) error {
+	return row.Scan(
+		&p.ID, &p.Version, &p.SessionType, &p.LevelID, &p.LevelName,
+		&p.Wilaya, &p.Price, &p.EffectiveFrom, &p.EffectiveUntil, &p.Superseded,
+		&p.Notes, &p.CreatedBy, &p.CreatedAt,
+	)
+}
+
+// ═══════════════════════════════════════════════════════════════
+// Admin: Credit Packages
+// ═══════════════════════════════════════════════════════════════
+
+// AdminListPackages lists every package, including retired ones.
+func (s *Service) AdminListPackages(ctx context.Context) ([]CreditPackageResponse, error) {
+	return s.listPackages(ctx, false)
+}
+
+func (s *Service) AdminCreatePackage(ctx context.Context, req CreatePackageRequest) (*CreditPackageResponse, error) {
+	active := true
+	if req.IsActive != nil {
+		active = *req.IsActive
+	}
+
+	var id uuid.UUID
+	err := s.db.Pool.QueryRow(ctx,
+		`INSERT INTO credit_packages (name, amount, bonus, is_active, sort_order)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING id`,
+		strings.TrimSpace(req.Name), req.Amount, req.Bonus, active, req.SortOrder,
+	).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("create package: %w", err)
+	}
+	return s.getPackage(ctx, id)
+}
+
+// AdminUpdatePackage changes the set fields of a package. Purchases
+// already submitted keep the amount and credits they were created with.
+func (s *Service) AdminUpdatePackage(ctx context.Context, packageID string, req UpdatePackageRequest) (*CreditPackageResponse, error) {
+	id, err := uuid.Parse(packageID)
+	if err != nil {
+		return nil, ErrPackageNotFound
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		req.Name = &name
+	}
+
+	tag, err := s.db.Pool.Exec(ctx,
+		`UPDATE credit_packages
+		 SET name       = COALESCE($2, name),
+		     amount     = COALESCE($3, amount),
+		     bonus      = COALESCE($4, bonus),
+		     sort_order = COALESCE($5, sort_order),
+		     is_active  = COALESCE($6, is_active)
+		 WHERE id = $1`,
+		id, req.Name, req.Amount, req.Bonus, req.SortOrder, req.IsActive,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("update package: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, ErrPackageNotFound
+	}
+	return s.getPackage(ctx, id)
+}
+
+// AdminDeletePackage retires a package. Rows are kept because purchases
+// reference them.
+func (s *Service) AdminDeletePackage(ctx context.Context, packageID string) (*CreditPackageResponse, error) {
+	inactive := false
+	return s.AdminUpdatePackage(ctx, packageID, UpdatePackageRequest{IsActive: &inactive})
+}
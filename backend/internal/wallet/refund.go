@@ -0,0 +1,260 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"educonnect/internal/ledger"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ═══════════════════════════════════════════════════════════════
+// Refund Policy
+// ═══════════════════════════════════════════════════════════════
+
+// Refund rules, recorded on each refund transaction.
+const (
+	RefundBeforeStart = "before_start" // no session had started: full refund
+	RefundGrace       = "grace"        // left within the grace window: full refund
+	RefundProRated    = "prorated"     // share of the sessions not yet started
+	RefundAdmin       = "admin"        // admin override
+)
+
+// RefundPolicy decides how much of a star comes back to the teacher when
+// a student leaves a series.
+type RefundPolicy struct {
+	GraceWindow time.Duration // full refund within this long of the deduction
+	ProRated    bool          // otherwise refund the share of sessions not yet started
+	Minimum     float64       // DZD; smaller refunds are not issued
+}
+
+// RefundCase is what the policy looks at for one enrollment.
+type RefundCase struct {
+	Cost            float64   // star charged
+	ChargedAt       time.Time // when the star was deducted (acceptance)
+	TotalSessions   int       // sessions in the series, cancelled ones excluded
+	StartedSessions int       // of which started or past
+}
+
+// Decide returns the total refund due for c and the rule that grants it.
+// A zero amount means nothing is due.
+func (p RefundPolicy) Decide(c RefundCase, now time.Time) (float64, string) {
+	switch {
+	case c.StartedSessions == 0 || c.TotalSessions == 0:
+		return c.Cost, RefundBeforeStart
+	case p.GraceWindow > 0 && now.Sub(c.ChargedAt) <= p.GraceWindow:
+		return c.Cost, RefundGrace
+	case p.ProRated && c.StartedSessions < c.TotalSessions:
+		remaining := c.TotalSessions - c.StartedSessions
+		amount := math.Round(c.Cost*float64(remaining)/float64(c.TotalSessions)*100) / 100
+		if amount < p.Minimum {
+			return 0, ""
+		}
+		return amount, RefundProRated
+	}
+	return 0, ""
+}
+
+// ═══════════════════════════════════════════════════════════════
+// Refund Star (student removed or withdrawn)
+// ═══════════════════════════════════════════════════════════════
+
+// RefundStar returns to the teacher's wallet what the refund policy grants
+// for an enrollment leaving its series, less anything already refunded.
+// It returns the refund transaction, nil when the enrollment was never
+// charged or is already refunded, and ErrRefundNotEligible when the policy
+// grants nothing.
+func (s *Service) RefundStar(ctx context.Context, teacherID string, enrollmentID uuid.UUID) (*WalletTransactionResponse, error) {
+	tid, _ := uuid.Parse(teacherID)
+
+	dbtx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer dbtx.Rollback(ctx)
+
+	d, err := lockDeduction(ctx, dbtx, tid, enrollmentID)
+	if err != nil {
+		if errors.Is(err, ErrNoStarDeduction) {
+			return nil, nil // e.g. legacy enrollment
+		}
+		return nil, err
+	}
+
+	c := RefundCase{Cost: d.amount, ChargedAt: d.chargedAt}
+	err = dbtx.QueryRow(ctx,
+		`SELECT COUNT(*) FILTER (WHERE status <> 'cancelled'),
+		        COUNT(*) FILTER (WHERE status <> 'cancelled'
+		                           AND (status <> 'scheduled' OR actual_start IS NOT NULL OR start_time <= NOW()))
+		 FROM sessions WHERE series_id = $1`, d.seriesID,
+	).Scan(&c.TotalSessions, &c.StartedSessions)
+	if err != nil {
+		return nil, fmt.Errorf("count sessions: %w", err)
+	}
+
+	due, rule := s.refunds.Decide(c, time.Now())
+	amount := math.Round((due-d.refunded)*100) / 100
+	if amount <= 0 {
+		if d.refunded > 0 {
+			return nil, nil // already refunded, idempotent
+		}
+		return nil, ErrRefundNotEligible
+	}
+
+	desc := fmt.Sprintf("★ Remboursement — %s", d.seriesTitle)
+	if rule == RefundProRated {
+		desc = fmt.Sprintf("★ Remboursement partiel (%d/%d séances restantes) — %s",
+			c.TotalSessions-c.StartedSessions, c.TotalSessions, d.seriesTitle)
+	}
+
+	txID, err := s.creditRefund(ctx, dbtx, d, amount, rule, desc, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := dbtx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return s.getTransaction(ctx, txID)
+}
+
+// AdminRefundStar refunds an enrollment's star regardless of the policy,
+// up to what is still unrefunded. A zero amount refunds all of it.
+func (s *Service) AdminRefundStar(ctx context.Context, adminID string, req AdminRefundRequest) (*WalletTransactionResponse, error) {
+	aid, _ := uuid.Parse(adminID)
+
+	dbtx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer dbtx.Rollback(ctx)
+
+	var teacherID uuid.UUID
+	err = dbtx.QueryRow(ctx,
+		`SELECT tw.teacher_id FROM wallet_transactions wt
+		 JOIN teacher_wallets tw ON tw.id = wt.wallet_id
+		 WHERE wt.enrollment_id = $1 AND wt.type = 'star_deduction' AND wt.status = 'completed'
+		 LIMIT 1`, req.EnrollmentID,
+	).Scan(&teacherID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNoStarDeduction
+		}
+		return nil, fmt.Errorf("find deduction: %w", err)
+	}
+
+	d, err := lockDeduction(ctx, dbtx, teacherID, req.EnrollmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	left := math.Round((d.amount-d.refunded)*100) / 100
+	amount := req.Amount
+	if amount == 0 {
+		amount = left
+	}
+	if left <= 0 || amount > left {
+		return nil, ErrRefundExceedsCharge
+	}
+
+	desc := fmt.Sprintf("★ Remboursement (admin) — %s", d.seriesTitle)
+	txID, err := s.creditRefund(ctx, dbtx, d, amount, RefundAdmin, desc, &aid, strings.TrimSpace(req.Notes))
+	if err != nil {
+		return nil, err
+	}
+	if err := dbtx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return s.getTransaction(ctx, txID)
+}
+
+// deduction is a star charge being refunded, read under the wallet lock.
+type deduction struct {
+	id, walletID, teacherID, enrollmentID, seriesID uuid.UUID
+	seriesTitle                                     string
+	amount, refunded                                float64
+	chargedAt                                       time.Time
+}
+
+// lockDeduction locks the teacher's wallet, which serialises refunds of
+// the same enrollment, then reads its star deduction and prior refunds.
+func lockDeduction(ctx context.Context, dbtx pgx.Tx, teacherID, enrollmentID uuid.UUID) (*deduction, error) {
+	d := deduction{teacherID: teacherID, enrollmentID: enrollmentID}
+	err := dbtx.QueryRow(ctx,
+		`SELECT id FROM teacher_wallets WHERE teacher_id = $1 FOR UPDATE`, teacherID,
+	).Scan(&d.walletID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNoStarDeduction
+		}
+		return nil, fmt.Errorf("lock wallet: %w", err)
+	}
+
+	err = dbtx.QueryRow(ctx,
+		`SELECT wt.id, wt.amount, wt.series_id, COALESCE(ss.title,''), wt.created_at,
+		        COALESCE((SELECT SUM(r.amount) FROM wallet_transactions r
+		                  WHERE r.type = 'refund' AND r.status = 'completed'
+		                    AND (r.refund_of = wt.id OR (r.refund_of IS NULL AND r.enrollment_id = wt.enrollment_id))), 0)
+		 FROM wallet_transactions wt
+		 LEFT JOIN session_series ss ON ss.id = wt.series_id
+		 WHERE wt.wallet_id = $1 AND wt.enrollment_id = $2
+		   AND wt.type = 'star_deduction' AND wt.status = 'completed'
+		 ORDER BY wt.created_at DESC
+		 LIMIT 1`, d.walletID, enrollmentID,
+	).Scan(&d.id, &d.amount, &d.seriesID, &d.seriesTitle, &d.chargedAt, &d.refunded)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNoStarDeduction
+		}
+		return nil, fmt.Errorf("find deduction: %w", err)
+	}
+	return &d, nil
+}
+
+// creditRefund writes one refund of a locked deduction: wallet balance,
+// transaction row and ledger entry.
+func (s *Service) creditRefund(ctx context.Context, dbtx pgx.Tx, d *deduction, amount float64, rule, desc string, adminID *uuid.UUID, notes string) (uuid.UUID, error) {
+	var newBalance float64
+	err := dbtx.QueryRow(ctx,
+		`UPDATE teacher_wallets
+		 SET balance = balance + $1, total_refunded = total_refunded + $1, updated_at = NOW()
+		 WHERE id = $2
+		 RETURNING balance`, amount, d.walletID,
+	).Scan(&newBalance)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("refund wallet: %w", err)
+	}
+
+	txID := uuid.New()
+	_, err = dbtx.Exec(ctx,
+		`INSERT INTO wallet_transactions
+		    (id, wallet_id, type, status, amount, balance_after, description, enrollment_id, series_id,
+		     refund_of, refund_rule, admin_id, admin_notes)
+		 VALUES ($1, $2, 'refund', 'completed', $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, ''))`,
+		txID, d.walletID, amount, newBalance, desc, d.enrollmentID, d.seriesID,
+		d.id, rule, adminID, notes,
+	)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("insert refund tx: %w", err)
+	}
+
+	err = ledger.Post(ctx, dbtx, ledger.Entry{
+		Kind:        ledger.JournalStarRefund,
+		RefType:     ledger.RefWalletTransaction,
+		RefID:       txID,
+		Description: desc,
+		Lines: []ledger.Line{
+			ledger.Debit(ledger.System(ledger.Refunds), amount),
+			ledger.Credit(ledger.WalletOf(d.teacherID), amount),
+		},
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return txID, nil
+}
@@ -1,12 +1,19 @@
 package wallet
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"math"
+	"net/http"
+	"strings"
+	"time"
 
+	"educonnect/internal/config"
+	"educonnect/internal/events"
+	"educonnect/internal/ledger"
 	"educonnect/pkg/database"
+	"educonnect/pkg/storage"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -24,8 +31,20 @@ var (
 	ErrTransactionNotFound   = errors.New("wallet transaction not found")
 	ErrAlreadyProcessed      = errors.New("transaction already processed")
 	ErrNotPendingPurchase    = errors.New("only pending purchases can be approved")
-	ErrRefundNotEligible     = errors.New("refund not eligible — first session already started")
+	ErrRefundNotEligible     = errors.New("no refund is due under the refund policy")
+	ErrNoStarDeduction       = errors.New("no star was charged for this enrollment")
+	ErrRefundExceedsCharge   = errors.New("refund exceeds what remains of the star charged")
 	ErrEnrollmentNotAccepted = errors.New("enrollment is not in accepted state")
+	ErrInvalidProviderRef    = errors.New("provider reference must contain letters or digits")
+	ErrDuplicateReference    = errors.New("this payment reference is already used by another purchase")
+	ErrInvalidReceipt        = errors.New("receipt must be a JPEG, PNG or WebP image or a PDF")
+	ErrReceiptRequired       = errors.New("a payment receipt is required before approval")
+	ErrNoStarPrice           = errors.New("no star price is configured for this session")
+	ErrStarPriceNotFound     = errors.New("star price not found")
+	ErrStarPriceEnded        = errors.New("this star price version has already ended")
+	ErrDefaultStarPrice      = errors.New("default prices cannot be ended — publish a new version instead")
+	ErrPastEffectiveDate     = errors.New("price changes cannot take effect in the past")
+	ErrLevelNotFound         = errors.New("level not found")
 )
 
 // ═══════════════════════════════════════════════════════════════
@@ -33,11 +52,25 @@ var (
 // ═══════════════════════════════════════════════════════════════
 
 type Service struct {
-	db *database.Postgres
+	db         *database.Postgres
+	storage    *storage.MinIO
+	events     *events.Publisher
+	lowBalance []float64 // DZD alert thresholds
+	refunds    RefundPolicy
 }
 
-func NewService(db *database.Postgres) *Service {
-	return &Service{db: db}
+func NewService(db *database.Postgres, store *storage.MinIO, pub *events.Publisher, cfg config.WalletConfig) *Service {
+	return &Service{
+		db:         db,
+		storage:    store,
+		events:     pub,
+		lowBalance: cfg.LowBalanceThresholds,
+		refunds: RefundPolicy{
+			GraceWindow: cfg.RefundGraceWindow,
+			ProRated:    cfg.RefundProRated,
+			Minimum:     cfg.RefundMinimum,
+		},
+	}
 }
 
 // ═══════════════════════════════════════════════════════════════
@@ -86,20 +119,44 @@ func (s *Service) BuyCredits(ctx context.Context, teacherID string, req BuyCredi
 		return nil, err
 	}
 
+	refKey := NormalizeProviderRef(req.ProviderRef)
+	if refKey == "" {
+		return nil, ErrInvalidProviderRef
+	}
+
 	txID := uuid.New()
 	desc := fmt.Sprintf("Achat de crédits — Forfait %s (%0.f DA + %0.f DA bonus)", pkg.Name, pkg.Amount, pkg.Bonus)
 
-	_, err = s.db.Pool.Exec(ctx,
+	dbtx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer dbtx.Rollback(ctx)
+
+	// One CCP transfer buys one package: serialize on the reference so two
+	// submissions of the same receipt cannot both get through.
+	if err := s.checkReferenceUnused(ctx, dbtx, refKey, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	// The teacher reports having paid the package price. Nothing is
+	// posted until an admin checks the receipt; the price is kept for
+	// then, in case the package is repriced meanwhile.
+	_, err = dbtx.Exec(ctx,
 		`INSERT INTO wallet_transactions
-		    (id, wallet_id, type, status, amount, balance_after, description, package_id, payment_method, provider_ref)
-		 VALUES ($1, $2, 'purchase', 'pending', $3, $4, $5, $6, $7, $8)`,
+		    (id, wallet_id, type, status, amount, balance_after, description, package_id, payment_method, provider_ref, price)
+		 VALUES ($1, $2, 'purchase', 'pending', $3, $4, $5, $6, $7, $8, $9)`,
 		txID, wallet.ID, pkg.TotalCredits, wallet.Balance, desc,
-		req.PackageID, req.PaymentMethod, req.ProviderRef,
+		req.PackageID, req.PaymentMethod, req.ProviderRef, pkg.Amount,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("insert purchase tx: %w", err)
 	}
 
+	if err := dbtx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+
 	// Auto-create wallet if teacher is new (upsert above handles it)
 	_ = tid // used above
 
@@ -121,12 +178,17 @@ func (s *Service) AdminApprovePurchase(ctx context.Context, txID, adminID string
 	defer tx.Rollback(ctx)
 
 	// Lock the transaction row
-	var walletID uuid.UUID
-	var currentStatus string
+	var walletID, teacherID uuid.UUID
+	var currentStatus, refKey string
 	var amount float64
+	var hasReceipt bool
 	err = tx.QueryRow(ctx,
-		`SELECT wallet_id, status::text, amount FROM wallet_transactions WHERE id = $1 FOR UPDATE`, tid,
-	).Scan(&walletID, &currentStatus, &amount)
+		`SELECT wt.wallet_id, w.teacher_id, wt.status::text, wt.amount,
+		        COALESCE(wt.provider_ref_key, ''), wt.receipt_key IS NOT NULL
+		 FROM wallet_transactions wt
+		 JOIN teacher_wallets w ON w.id = wt.wallet_id
+		 WHERE wt.id = $1 FOR UPDATE OF wt`, tid,
+	).Scan(&walletID, &teacherID, &currentStatus, &amount, &refKey, &hasReceipt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrTransactionNotFound
@@ -136,7 +198,16 @@ func (s *Service) AdminApprovePurchase(ctx context.Context, txID, adminID string
 	if currentStatus != "pending" {
 		return nil, ErrAlreadyProcessed
 	}
+	if approved {
+		if !hasReceipt {
+			return nil, ErrReceiptRequired
+		}
+		if err := s.checkReferenceUnused(ctx, tx, refKey, tid); err != nil {
+			return nil, err
+		}
+	}
 
+	var newBalance float64
 	if approved {
 		// Lock wallet and credit balance
 		var currentBalance float64
@@ -147,7 +218,7 @@ func (s *Service) AdminApprovePurchase(ctx context.Context, txID, adminID string
 			return nil, fmt.Errorf("lock wallet: %w", err)
 		}
 
-		newBalance := currentBalance + amount
+		newBalance = currentBalance + amount
 		_, err = tx.Exec(ctx,
 			`UPDATE teacher_wallets
 			 SET balance = $1, total_purchased = total_purchased + $2, updated_at = NOW()
@@ -165,6 +236,32 @@ func (s *Service) AdminApprovePurchase(ctx context.Context, txID, adminID string
 		if err != nil {
 			return nil, fmt.Errorf("approve tx: %w", err)
 		}
+
+		// What was paid comes into cash, or out of pending purchases for
+		// a purchase submitted when they were posted on submission; the
+		// package bonus on top of it is a platform expense.
+		paid, held, err := s.purchasePrice(ctx, tx, tid, amount)
+		if err != nil {
+			return nil, err
+		}
+		from := ledger.System(ledger.Cash)
+		if held {
+			from = ledger.System(ledger.PendingPurchases)
+		}
+		err = ledger.Post(ctx, tx, ledger.Entry{
+			Kind:        ledger.JournalPurchaseApproved,
+			RefType:     ledger.RefWalletTransaction,
+			RefID:       tid,
+			Description: "Recharge validée",
+			Lines: []ledger.Line{
+				ledger.Debit(from, paid),
+				ledger.Debit(ledger.System(ledger.BonusExpense), amount-paid),
+				ledger.Credit(ledger.WalletOf(teacherID), amount),
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
 	} else {
 		// Rejected
 		_, err = tx.Exec(ctx,
@@ -175,6 +272,33 @@ func (s *Service) AdminApprovePurchase(ctx context.Context, txID, adminID string
 		if err != nil {
 			return nil, fmt.Errorf("reject tx: %w", err)
 		}
+
+		if err := s.releasePendingPurchase(ctx, tx, tid, amount, ledger.JournalPurchaseRejected, "Recharge refusée"); err != nil {
+			return nil, err
+		}
+	}
+
+	// Record the outcome in the outbox so it survives a crash after commit
+	var evt events.Event = events.WalletPurchaseRejected{
+		TransactionID: tid,
+		WalletID:      walletID,
+		TeacherID:     teacherID,
+		AdminID:       aid,
+		Amount:        amount,
+		Notes:         notes,
+	}
+	if approved {
+		evt = events.WalletPurchaseApproved{
+			TransactionID: tid,
+			WalletID:      walletID,
+			TeacherID:     teacherID,
+			AdminID:       aid,
+			Amount:        amount,
+			BalanceAfter:  newBalance,
+		}
+	}
+	if err := events.Enqueue(ctx, tx, evt); err != nil {
+		return nil, err
 	}
 
 	if err := tx.Commit(ctx); err != nil {
@@ -185,170 +309,288 @@ func (s *Service) AdminApprovePurchase(ctx context.Context, txID, adminID string
 }
 
 // ═══════════════════════════════════════════════════════════════
-// Deduct Star (called when enrollment is accepted)
+// Upload Receipt (proof of CCP transfer for a pending purchase)
 // ═══════════════════════════════════════════════════════════════
 
-// DeductStar deducts 1 star cost from the teacher's wallet.
-// Called inside a DB transaction from the enrollment accept flow.
-// Returns the wallet_transaction ID for reference.
-func (s *Service) DeductStar(ctx context.Context, teacherID string, sessionType string, enrollmentID, seriesID uuid.UUID, studentName, seriesTitle string) (uuid.UUID, error) {
-	tid, _ := uuid.Parse(teacherID)
-	cost := StarCost(sessionType)
+// receiptTypes maps the sniffed content type of an accepted receipt to
+// its object extension.
+var receiptTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
 
-	dbtx, err := s.db.Pool.Begin(ctx)
+// UploadReceipt attaches a receipt to one of the teacher's pending
+// purchases, replacing any earlier one. The type is sniffed from the
+// content, not taken from the client.
+func (s *Service) UploadReceipt(ctx context.Context, teacherID, txID string, data []byte) (*WalletTransactionResponse, error) {
+	tid, _ := uuid.Parse(teacherID)
+	id, err := uuid.Parse(txID)
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("begin tx: %w", err)
+		return nil, ErrTransactionNotFound
 	}
-	defer dbtx.Rollback(ctx)
 
-	// Lock wallet
-	var walletID uuid.UUID
-	var balance float64
-	err = dbtx.QueryRow(ctx,
-		`SELECT id, balance FROM teacher_wallets WHERE teacher_id = $1 FOR UPDATE`, tid,
-	).Scan(&walletID, &balance)
+	contentType := http.DetectContentType(data)
+	ext, ok := receiptTypes[contentType]
+	if !ok {
+		return nil, ErrInvalidReceipt
+	}
+
+	var status string
+	err = s.db.Pool.QueryRow(ctx,
+		`SELECT wt.status::text
+		 FROM wallet_transactions wt
+		 JOIN teacher_wallets w ON w.id = wt.wallet_id
+		 WHERE wt.id = $1 AND w.teacher_id = $2 AND wt.type = 'purchase'`, id, tid,
+	).Scan(&status)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return uuid.Nil, ErrInsufficientBalance // no wallet = no balance
+			return nil, ErrTransactionNotFound
 		}
-		return uuid.Nil, fmt.Errorf("lock wallet: %w", err)
+		return nil, fmt.Errorf("get purchase: %w", err)
 	}
-
-	if balance < cost {
-		return uuid.Nil, ErrInsufficientBalance
+	if status != "pending" {
+		return nil, ErrNotPendingPurchase
 	}
 
-	newBalance := balance - cost
+	bucket := s.storage.BucketDocuments()
+	key := fmt.Sprintf("receipts/%s/%s/%s%s", tid, id, uuid.New(), ext)
+	if err := s.storage.Upload(ctx, bucket, key, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+		return nil, fmt.Errorf("upload receipt: %w", err)
+	}
 
-	_, err = dbtx.Exec(ctx,
-		`UPDATE teacher_wallets
-		 SET balance = $1, total_spent = total_spent + $2, updated_at = NOW()
-		 WHERE id = $3`, newBalance, cost, walletID,
+	tag, err := s.db.Pool.Exec(ctx,
+		`UPDATE wallet_transactions
+		 SET receipt_key = $1, receipt_content_type = $2, receipt_uploaded_at = NOW()
+		 WHERE id = $3 AND status = 'pending'`, key, contentType, id,
 	)
-	if err != nil {
-		return uuid.Nil, fmt.Errorf("deduct wallet: %w", err)
+	if err != nil || tag.RowsAffected() == 0 {
+		_ = s.storage.Delete(ctx, bucket, key)
+		if err != nil {
+			return nil, fmt.Errorf("attach receipt: %w", err)
+		}
+		return nil, ErrNotPendingPurchase // reviewed while uploading
 	}
 
-	starLabel := "★ Groupe"
-	if sessionType == "one_on_one" {
-		starLabel = "★ Privé"
+	t, err := s.getTransaction(ctx, id)
+	if err != nil {
+		return nil, err
 	}
-	desc := fmt.Sprintf("%s — %s pour %s", starLabel, seriesTitle, studentName)
+	s.presignReceipt(ctx, t)
+	return t, nil
+}
 
-	txID := uuid.New()
-	_, err = dbtx.Exec(ctx,
-		`INSERT INTO wallet_transactions
-		    (id, wallet_id, type, status, amount, balance_after, description, enrollment_id, series_id)
-		 VALUES ($1, $2, 'star_deduction', 'completed', $3, $4, $5, $6, $7)`,
-		txID, walletID, cost, newBalance, desc, enrollmentID, seriesID,
+// ═══════════════════════════════════════════════════════════════
+// Expire Stale Purchases (worker)
+// ═══════════════════════════════════════════════════════════════
+
+// ExpireStalePurchases closes purchases left pending longer than
+// PurchaseExpiry. It returns how many were expired.
+func (s *Service) ExpireStalePurchases(ctx context.Context) (int, error) {
+	rows, err := s.db.Pool.Query(ctx,
+		`SELECT id FROM wallet_transactions
+		 WHERE type = 'purchase' AND status = 'pending' AND created_at < $1
+		 ORDER BY created_at
+		 LIMIT 200`, time.Now().Add(-PurchaseExpiry),
 	)
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("insert deduction tx: %w", err)
+		return 0, fmt.Errorf("find stale purchases: %w", err)
 	}
-
-	if err := dbtx.Commit(ctx); err != nil {
-		return uuid.Nil, fmt.Errorf("commit: %w", err)
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan stale purchase: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("find stale purchases: %w", err)
 	}
 
-	return txID, nil
+	expired := 0
+	for _, id := range ids {
+		ok, err := s.expirePurchase(ctx, id)
+		if err != nil {
+			return expired, err
+		}
+		if ok {
+			expired++
+		}
+	}
+	return expired, nil
 }
 
-// ═══════════════════════════════════════════════════════════════
-// Refund Star (called when student removed BEFORE first session)
-// ═══════════════════════════════════════════════════════════════
-
-// RefundStar adds back the star cost to the teacher's wallet.
-// Only allowed if no session in the series has started yet.
-func (s *Service) RefundStar(ctx context.Context, teacherID string, enrollmentID uuid.UUID) error {
-	tid, _ := uuid.Parse(teacherID)
+// expirePurchase expires one purchase if it is still pending.
+func (s *Service) expirePurchase(ctx context.Context, id uuid.UUID) (bool, error) {
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
 
-	// Find the original star_deduction for this enrollment
-	var originalAmount float64
-	var walletID uuid.UUID
-	var seriesID uuid.UUID
-	var seriesTitle string
-	err := s.db.Pool.QueryRow(ctx,
-		`SELECT wt.amount, wt.wallet_id, wt.series_id, COALESCE(ss.title,'')
+	var walletID, teacherID uuid.UUID
+	var amount float64
+	err = tx.QueryRow(ctx,
+		`SELECT wt.wallet_id, w.teacher_id, wt.amount
 		 FROM wallet_transactions wt
-		 LEFT JOIN session_series ss ON ss.id = wt.series_id
-		 WHERE wt.enrollment_id = $1 AND wt.type = 'star_deduction' AND wt.status = 'completed'
-		 LIMIT 1`,
-		enrollmentID,
-	).Scan(&originalAmount, &walletID, &seriesID, &seriesTitle)
+		 JOIN teacher_wallets w ON w.id = wt.wallet_id
+		 WHERE wt.id = $1 AND wt.status = 'pending'
+		 FOR UPDATE OF wt SKIP LOCKED`, id,
+	).Scan(&walletID, &teacherID, &amount)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil // No deduction to refund (e.g., legacy enrollment)
+			return false, nil // reviewed meanwhile, or being reviewed now
 		}
-		return fmt.Errorf("find deduction: %w", err)
+		return false, fmt.Errorf("lock purchase: %w", err)
 	}
 
-	// Check if any session in the series has already started
-	var startedCount int
-	err = s.db.Pool.QueryRow(ctx,
-		`SELECT COUNT(*) FROM sessions
-		 WHERE series_id = $1 AND (status = 'live' OR status = 'completed' OR actual_start IS NOT NULL)`,
-		seriesID,
-	).Scan(&startedCount)
+	_, err = tx.Exec(ctx,
+		`UPDATE wallet_transactions
+		 SET status = 'expired', admin_notes = 'Délai de validation dépassé'
+		 WHERE id = $1`, id,
+	)
 	if err != nil {
-		return fmt.Errorf("check sessions: %w", err)
+		return false, fmt.Errorf("expire purchase: %w", err)
 	}
-	if startedCount > 0 {
-		return ErrRefundNotEligible
+
+	if err := s.releasePendingPurchase(ctx, tx, id, amount, ledger.JournalPurchaseExpired, "Recharge expirée"); err != nil {
+		return false, err
 	}
 
-	// Check we haven't already refunded this enrollment
-	var existingRefund int
-	_ = s.db.Pool.QueryRow(ctx,
-		`SELECT COUNT(*) FROM wallet_transactions WHERE enrollment_id = $1 AND type = 'refund'`,
-		enrollmentID,
-	).Scan(&existingRefund)
-	if existingRefund > 0 {
-		return nil // Already refunded, idempotent
+	err = events.Enqueue(ctx, tx, events.WalletPurchaseExpired{
+		TransactionID: id,
+		WalletID:      walletID,
+		TeacherID:     teacherID,
+		Amount:        amount,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit: %w", err)
 	}
+	return true, nil
+}
 
-	// Apply refund in a transaction
+// ═══════════════════════════════════════════════════════════════
+// Deduct Star (called when enrollment is accepted)
+// ═══════════════════════════════════════════════════════════════
+
+// DeductStar deducts 1 star cost from the teacher's wallet in its own
+// DB transaction. Returns the wallet_transaction ID for reference.
+func (s *Service) DeductStar(ctx context.Context, teacherID string, sessionType string, enrollmentID, seriesID uuid.UUID, studentName, seriesTitle string) (uuid.UUID, error) {
 	dbtx, err := s.db.Pool.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("begin tx: %w", err)
+		return uuid.Nil, fmt.Errorf("begin tx: %w", err)
 	}
 	defer dbtx.Rollback(ctx)
 
+	txID, err := s.DeductStarTx(ctx, dbtx, teacherID, sessionType, enrollmentID, seriesID, studentName, seriesTitle)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := dbtx.Commit(ctx); err != nil {
+		return uuid.Nil, fmt.Errorf("commit: %w", err)
+	}
+
+	return txID, nil
+}
+
+// DeductStarTx is DeductStar within a caller-owned transaction, so the
+// deduction commits or rolls back together with the enrollment change.
+// The cost is the star price in force for the series' level and the
+// teacher's wilaya; the version charged is recorded on the transaction.
+func (s *Service) DeductStarTx(ctx context.Context, dbtx pgx.Tx, teacherID string, sessionType string, enrollmentID, seriesID uuid.UUID, studentName, seriesTitle string) (uuid.UUID, error) {
+	tid, _ := uuid.Parse(teacherID)
+
+	levelID, wilaya, err := seriesPriceScope(ctx, dbtx, seriesID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	price, err := resolveStarPrice(ctx, dbtx, sessionType, levelID, wilaya, time.Now())
+	if err != nil {
+		return uuid.Nil, err
+	}
+	cost := price.Price
+
 	// Lock wallet
+	var walletID uuid.UUID
 	var balance float64
 	err = dbtx.QueryRow(ctx,
-		`SELECT balance FROM teacher_wallets WHERE id = $1 AND teacher_id = $2 FOR UPDATE`, walletID, tid,
-	).Scan(&balance)
+		`SELECT id, balance FROM teacher_wallets WHERE teacher_id = $1 FOR UPDATE`, tid,
+	).Scan(&walletID, &balance)
 	if err != nil {
-		return fmt.Errorf("lock wallet: %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, ErrInsufficientBalance // no wallet = no balance
+		}
+		return uuid.Nil, fmt.Errorf("lock wallet: %w", err)
 	}
 
-	newBalance := balance + originalAmount
+	if balance < cost {
+		return uuid.Nil, ErrInsufficientBalance
+	}
+
+	newBalance := balance - cost
+
 	_, err = dbtx.Exec(ctx,
 		`UPDATE teacher_wallets
-		 SET balance = $1, total_refunded = total_refunded + $2, updated_at = NOW()
-		 WHERE id = $3`, newBalance, originalAmount, walletID,
+		 SET balance = $1, total_spent = total_spent + $2, updated_at = NOW()
+		 WHERE id = $3`, newBalance, cost, walletID,
 	)
 	if err != nil {
-		return fmt.Errorf("refund wallet: %w", err)
+		return uuid.Nil, fmt.Errorf("deduct wallet: %w", err)
+	}
+
+	if threshold, ok := CrossedThreshold(s.lowBalance, balance, newBalance); ok {
+		err = events.Enqueue(ctx, dbtx, events.WalletLowBalance{
+			WalletID:  walletID,
+			TeacherID: tid,
+			Balance:   newBalance,
+			Threshold: threshold,
+		})
+		if err != nil {
+			return uuid.Nil, err
+		}
 	}
 
-	desc := fmt.Sprintf("★ Remboursement — %s", seriesTitle)
+	starLabel := "★ Groupe"
+	if sessionType == "one_on_one" {
+		starLabel = "★ Privé"
+	}
+	desc := fmt.Sprintf("%s — %s pour %s", starLabel, seriesTitle, studentName)
+
 	txID := uuid.New()
 	_, err = dbtx.Exec(ctx,
 		`INSERT INTO wallet_transactions
-		    (id, wallet_id, type, status, amount, balance_after, description, enrollment_id, series_id)
-		 VALUES ($1, $2, 'refund', 'completed', $3, $4, $5, $6, $7)`,
-		txID, walletID, originalAmount, newBalance, desc, enrollmentID, seriesID,
+		    (id, wallet_id, type, status, amount, balance_after, description, enrollment_id, series_id, star_price_id)
+		 VALUES ($1, $2, 'star_deduction', 'completed', $3, $4, $5, $6, $7, $8)`,
+		txID, walletID, cost, newBalance, desc, enrollmentID, seriesID, price.ID,
 	)
 	if err != nil {
-		return fmt.Errorf("insert refund tx: %w", err)
+		return uuid.Nil, fmt.Errorf("insert deduction tx: %w", err)
 	}
 
-	if err := dbtx.Commit(ctx); err != nil {
-		return fmt.Errorf("commit: %w", err)
+	err = ledger.Post(ctx, dbtx, ledger.Entry{
+		Kind:        ledger.JournalStarDeduction,
+		RefType:     ledger.RefWalletTransaction,
+		RefID:       txID,
+		Description: desc,
+		Lines: []ledger.Line{
+			ledger.Debit(ledger.WalletOf(tid), cost),
+			ledger.Credit(ledger.System(ledger.PlatformRevenue), cost),
+		},
+	})
+	if err != nil {
+		return uuid.Nil, err
 	}
 
-	return nil
+	return txID, nil
 }
 
 // ═══════════════════════════════════════════════════════════════
@@ -382,14 +624,7 @@ func (s *Service) ListTransactions(ctx context.Context, teacherID string, txType
 	_ = s.db.Pool.QueryRow(ctx, countQ, countArgs...).Scan(&total)
 
 	// Fetch
-	listQ := `SELECT wt.id, wt.wallet_id, wt.type::text, wt.status::text, wt.amount, wt.balance_after,
-	                  wt.description, wt.package_id, COALESCE(cp.name,''), COALESCE(wt.payment_method,''),
-	                  COALESCE(wt.provider_ref,''), wt.enrollment_id, wt.series_id,
-	                  COALESCE(ss.title,''), COALESCE(wt.admin_notes,''), wt.created_at
-	           FROM wallet_transactions wt
-	           LEFT JOIN credit_packages cp ON cp.id = wt.package_id
-	           LEFT JOIN session_series ss ON ss.id = wt.series_id
-	           WHERE wt.wallet_id = $1`
+	listQ := walletTxSelect + ` WHERE wt.wallet_id = $1`
 	listArgs := []interface{}{walletID}
 	if txType != "" {
 		listQ += ` AND wt.type = $2::wallet_tx_type`
@@ -406,12 +641,7 @@ func (s *Service) ListTransactions(ctx context.Context, teacherID string, txType
 	var results []WalletTransactionResponse
 	for rows.Next() {
 		var t WalletTransactionResponse
-		if err := rows.Scan(
-			&t.ID, &t.WalletID, &t.Type, &t.Status, &t.Amount, &t.BalanceAfter,
-			&t.Description, &t.PackageID, &t.PackageName, &t.PaymentMethod,
-			&t.ProviderRef, &t.EnrollmentID, &t.SeriesID,
-			&t.SeriesTitle, &t.AdminNotes, &t.CreatedAt,
-		); err != nil {
+		if err := scanWalletTx(rows, &t); err != nil {
 			continue
 		}
 		results = append(results, t)
@@ -427,10 +657,16 @@ func (s *Service) ListTransactions(ctx context.Context, teacherID string, txType
 // List Packages
 // ═══════════════════════════════════════════════════════════════
 
+// ListPackages lists the packages on sale, with star counts at the
+// current default prices.
 func (s *Service) ListPackages(ctx context.Context) ([]CreditPackageResponse, error) {
+	return s.listPackages(ctx, true)
+}
+
+func (s *Service) listPackages(ctx context.Context, activeOnly bool) ([]CreditPackageResponse, error) {
 	rows, err := s.db.Pool.Query(ctx,
 		`SELECT id, name, amount, bonus, total_credits, is_active, sort_order
-		 FROM credit_packages WHERE is_active = true ORDER BY sort_order`,
+		 FROM credit_packages WHERE is_active OR NOT $1 ORDER BY sort_order, created_at`, activeOnly,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("list packages: %w", err)
@@ -443,10 +679,13 @@ func (s *Service) ListPackages(ctx context.Context) ([]CreditPackageResponse, er
 		if err := rows.Scan(&p.ID, &p.Name, &p.Amount, &p.Bonus, &p.TotalCredits, &p.IsActive, &p.SortOrder); err != nil {
 			continue
 		}
-		p.GroupStars = int(math.Floor(p.TotalCredits / GroupStarCost))
-		p.PrivateStars = int(math.Floor(p.TotalCredits / PrivateStarCost))
 		pkgs = append(pkgs, p)
 	}
+	rows.Close()
+
+	for i := range pkgs {
+		pkgs[i].GroupStars, pkgs[i].PrivateStars = s.starsFor(ctx, pkgs[i].TotalCredits, "")
+	}
 	if pkgs == nil {
 		pkgs = []CreditPackageResponse{}
 	}
@@ -457,6 +696,9 @@ func (s *Service) ListPackages(ctx context.Context) ([]CreditPackageResponse, er
 // Admin: List Pending Purchases
 // ═══════════════════════════════════════════════════════════════
 
+// AdminListPendingPurchases lists purchases awaiting review, oldest first,
+// with a short-lived link to each receipt and any other purchase that
+// claims the same transfer reference.
 func (s *Service) AdminListPendingPurchases(ctx context.Context, page, limit int) ([]WalletTransactionResponse, int64, error) {
 	offset := (page - 1) * limit
 
@@ -466,16 +708,14 @@ func (s *Service) AdminListPendingPurchases(ctx context.Context, page, limit int
 	).Scan(&total)
 
 	rows, err := s.db.Pool.Query(ctx,
-		fmt.Sprintf(`SELECT wt.id, wt.wallet_id, wt.type::text, wt.status::text, wt.amount, wt.balance_after,
-		                    wt.description, wt.package_id, COALESCE(cp.name,''), COALESCE(wt.payment_method,''),
-		                    COALESCE(wt.provider_ref,''), wt.enrollment_id, wt.series_id,
-		                    COALESCE(ss.title,''), COALESCE(wt.admin_notes,''), wt.created_at
-		             FROM wallet_transactions wt
-		             LEFT JOIN credit_packages cp ON cp.id = wt.package_id
-		             LEFT JOIN session_series ss ON ss.id = wt.series_id
-		             WHERE wt.type = 'purchase' AND wt.status = 'pending'
-		             ORDER BY wt.created_at ASC
-		             LIMIT %d OFFSET %d`, limit, offset),
+		`SELECT `+walletTxColumns+`,
+		        ARRAY(SELECT o.id FROM wallet_transactions o
+		              WHERE o.type = 'purchase' AND o.id <> wt.id
+		                AND o.provider_ref_key = wt.provider_ref_key AND o.provider_ref_key <> ''
+		              ORDER BY o.created_at)`+walletTxFrom+fmt.Sprintf(`
+		 WHERE wt.type = 'purchase' AND wt.status = 'pending'
+		 ORDER BY wt.created_at ASC
+		 LIMIT %d OFFSET %d`, limit, offset),
 	)
 	if err != nil {
 		return nil, 0, fmt.Errorf("admin list: %w", err)
@@ -485,16 +725,19 @@ func (s *Service) AdminListPendingPurchases(ctx context.Context, page, limit int
 	var results []WalletTransactionResponse
 	for rows.Next() {
 		var t WalletTransactionResponse
-		if err := rows.Scan(
-			&t.ID, &t.WalletID, &t.Type, &t.Status, &t.Amount, &t.BalanceAfter,
-			&t.Description, &t.PackageID, &t.PackageName, &t.PaymentMethod,
-			&t.ProviderRef, &t.EnrollmentID, &t.SeriesID,
-			&t.SeriesTitle, &t.AdminNotes, &t.CreatedAt,
-		); err != nil {
+		if err := scanWalletTx(rows, &t, &t.DuplicateOf); err != nil {
 			continue
 		}
 		results = append(results, t)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("admin list: %w", err)
+	}
+	rows.Close()
+
+	for i := range results {
+		s.presignReceipt(ctx, &results[i])
+	}
 	if results == nil {
 		results = []WalletTransactionResponse{}
 	}
@@ -505,20 +748,126 @@ func (s *Service) AdminListPendingPurchases(ctx context.Context, page, limit int
 // Helpers
 // ═══════════════════════════════════════════════════════════════
 
+// NormalizeProviderRef reduces a transfer reference to upper-case letters
+// and digits, as the provider_ref_key column does, so "ccp-0012 34" and
+// "CCP001234" are the same transfer.
+func NormalizeProviderRef(ref string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(ref) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// CrossedThreshold reports the lowest threshold that a balance moving from
+// before to after has dropped below, so a single large deduction raises
+// one alert rather than one per threshold.
+func CrossedThreshold(thresholds []float64, before, after float64) (float64, bool) {
+	var crossed float64
+	found := false
+	for _, t := range thresholds {
+		if before >= t && after < t && (!found || t < crossed) {
+			crossed, found = t, true
+		}
+	}
+	return crossed, found
+}
+
+// checkReferenceUnused fails if a pending or completed purchase other
+// than self already claims refKey. The advisory lock holds until the
+// caller's transaction ends.
+func (s *Service) checkReferenceUnused(ctx context.Context, tx pgx.Tx, refKey string, self uuid.UUID) error {
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('wallet-ref:' || $1))`, refKey); err != nil {
+		return fmt.Errorf("lock reference: %w", err)
+	}
+	var used bool
+	err := tx.QueryRow(ctx,
+		`SELECT EXISTS (
+		     SELECT 1 FROM wallet_transactions
+		     WHERE type = 'purchase' AND provider_ref_key = $1 AND id <> $2
+		       AND status IN ('pending', 'completed'))`, refKey, self,
+	).Scan(&used)
+	if err != nil {
+		return fmt.Errorf("check reference: %w", err)
+	}
+	if used {
+		return ErrDuplicateReference
+	}
+	return nil
+}
+
+// presignReceipt fills ReceiptURL with a short-lived link for review.
+func (s *Service) presignReceipt(ctx context.Context, t *WalletTransactionResponse) {
+	if t.receiptKey == "" || s.storage == nil {
+		return
+	}
+	url, err := s.storage.GetPresignedURL(ctx, s.storage.BucketDocuments(), t.receiptKey, receiptURLExpiry)
+	if err == nil {
+		t.ReceiptURL = url
+	}
+}
+
+// purchasePrice is what a purchase paid, bonus excluded, and whether its
+// submission put it in pending_purchases, as purchases submitted before
+// migration 000029 did. total is only a fallback for purchases that
+// predate both the ledger and the price column.
+func (s *Service) purchasePrice(ctx context.Context, tx pgx.Tx, txID uuid.UUID, total float64) (float64, bool, error) {
+	posted, ok, err := ledger.PostedAmount(ctx, tx, ledger.JournalPurchaseSubmitted, ledger.RefWalletTransaction, txID, ledger.System(ledger.PendingPurchases))
+	if err != nil {
+		return 0, false, err
+	}
+	if ok {
+		return -posted, true, nil
+	}
+	var price *float64
+	if err := tx.QueryRow(ctx, `SELECT price FROM wallet_transactions WHERE id = $1`, txID).Scan(&price); err != nil {
+		return 0, false, fmt.Errorf("get purchase price: %w", err)
+	}
+	if price == nil {
+		return total, false, nil
+	}
+	return *price, false, nil
+}
+
+// releasePendingPurchase returns to cash what the submission of a purchase
+// put into pending_purchases, when it is closed without being approved.
+// Purchases submitted since migration 000029 posted nothing to undo.
+func (s *Service) releasePendingPurchase(ctx context.Context, tx pgx.Tx, txID uuid.UUID, total float64, kind, description string) error {
+	paid, held, err := s.purchasePrice(ctx, tx, txID, total)
+	if err != nil || !held {
+		return err
+	}
+	return ledger.Post(ctx, tx, ledger.Entry{
+		Kind:        kind,
+		RefType:     ledger.RefWalletTransaction,
+		RefID:       txID,
+		Description: description,
+		Lines: []ledger.Line{
+			ledger.Debit(ledger.System(ledger.PendingPurchases), paid),
+			ledger.Credit(ledger.System(ledger.Cash), paid),
+		},
+	})
+}
+
 func (s *Service) getWalletByTeacher(ctx context.Context, teacherID uuid.UUID) (*WalletResponse, error) {
 	var w WalletResponse
+	var wilaya string
 	err := s.db.Pool.QueryRow(ctx,
-		`SELECT id, teacher_id, balance, total_purchased, total_spent, total_refunded, created_at, updated_at
-		 FROM teacher_wallets WHERE teacher_id = $1`, teacherID,
-	).Scan(&w.ID, &w.TeacherID, &w.Balance, &w.TotalPurchased, &w.TotalSpent, &w.TotalRefunded, &w.CreatedAt, &w.UpdatedAt)
+		`SELECT tw.id, tw.teacher_id, tw.balance, tw.total_purchased, tw.total_spent, tw.total_refunded,
+		        tw.created_at, tw.updated_at, COALESCE(u.wilaya, '')
+		 FROM teacher_wallets tw
+		 JOIN users u ON u.id = tw.teacher_id
+		 WHERE tw.teacher_id = $1`, teacherID,
+	).Scan(&w.ID, &w.TeacherID, &w.Balance, &w.TotalPurchased, &w.TotalSpent, &w.TotalRefunded, &w.CreatedAt, &w.UpdatedAt, &wilaya)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrWalletNotFound
 		}
 		return nil, fmt.Errorf("get wallet: %w", err)
 	}
-	w.GroupStarsAvailable = int(math.Floor(w.Balance / GroupStarCost))
-	w.PrivateStarsAvailable = int(math.Floor(w.Balance / PrivateStarCost))
+	w.GroupStarsAvailable, w.PrivateStarsAvailable = s.starsFor(ctx, w.Balance, wilaya)
 	return &w, nil
 }
 
@@ -534,28 +883,13 @@ func (s *Service) getPackage(ctx context.Context, pkgID uuid.UUID) (*CreditPacka
 		}
 		return nil, fmt.Errorf("get package: %w", err)
 	}
-	p.GroupStars = int(math.Floor(p.TotalCredits / GroupStarCost))
-	p.PrivateStars = int(math.Floor(p.TotalCredits / PrivateStarCost))
+	p.GroupStars, p.PrivateStars = s.starsFor(ctx, p.TotalCredits, "")
 	return &p, nil
 }
 
 func (s *Service) getTransaction(ctx context.Context, txID uuid.UUID) (*WalletTransactionResponse, error) {
 	var t WalletTransactionResponse
-	err := s.db.Pool.QueryRow(ctx,
-		`SELECT wt.id, wt.wallet_id, wt.type::text, wt.status::text, wt.amount, wt.balance_after,
-		        wt.description, wt.package_id, COALESCE(cp.name,''), COALESCE(wt.payment_method,''),
-		        COALESCE(wt.provider_ref,''), wt.enrollment_id, wt.series_id,
-		        COALESCE(ss.title,''), COALESCE(wt.admin_notes,''), wt.created_at
-		 FROM wallet_transactions wt
-		 LEFT JOIN credit_packages cp ON cp.id = wt.package_id
-		 LEFT JOIN session_series ss ON ss.id = wt.series_id
-		 WHERE wt.id = $1`, txID,
-	).Scan(
-		&t.ID, &t.WalletID, &t.Type, &t.Status, &t.Amount, &t.BalanceAfter,
-		&t.Description, &t.PackageID, &t.PackageName, &t.PaymentMethod,
-		&t.ProviderRef, &t.EnrollmentID, &t.SeriesID,
-		&t.SeriesTitle, &t.AdminNotes, &t.CreatedAt,
-	)
+	err := scanWalletTx(s.db.Pool.QueryRow(ctx, walletTxSelect+` WHERE wt.id = $1`, txID), &t)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrTransactionNotFound
@@ -564,3 +898,39 @@ func (s *Service) getTransaction(ctx context.Context, txID uuid.UUID) (*WalletTr
 	}
 	return &t, nil
 }
+
+const (
+	walletTxColumns = `wt.id, wt.wallet_id, wt.type::text, wt.status::text, wt.amount, wt.balance_after,
+        wt.description, wt.package_id, COALESCE(cp.name,''), COALESCE(wt.payment_method,''),
+        COALESCE(wt.provider_ref,''), wt.enrollment_id, wt.series_id,
+        COALESCE(ss.title,''), COALESCE(wt.admin_notes,''),
+        COALESCE(wt.receipt_key,''), COALESCE(wt.receipt_content_type,''),
+        wt.star_price_id, sp.version, wt.refund_of, COALESCE(wt.refund_rule,''), wt.created_at`
+	walletTxFrom = `
+ FROM wallet_transactions wt
+ LEFT JOIN credit_packages cp ON cp.id = wt.package_id
+ LEFT JOIN session_series ss ON ss.id = wt.series_id
+ LEFT JOIN star_prices sp ON sp.id = wt.star_price_id`
+	walletTxSelect = `SELECT ` + walletTxColumns + walletTxFrom
+)
+
+// scanWalletTx scans walletTxSelect, followed by any extra columns.
+func scanWalletTx(row pgx.Row, t *WalletTransactionResponse, extra ...any) error {
+	dest := []any{
+		&t.ID, &t.WalletID, &t.Type, &t.Status, &t.Amount, &t.BalanceAfter,
+		&t.Description, &t.PackageID, &t.PackageName, &t.PaymentMethod,
+		&t.ProviderRef, &t.EnrollmentID, &t.SeriesID,
+		&t.SeriesTitle, &t.AdminNotes,
+		&t.receiptKey, &t.ReceiptContentType,
+		&t.StarPriceID, &t.StarPriceVersion, &t.RefundOf, &t.RefundRule, &t.CreatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+	t.HasReceipt = t.receiptKey != ""
+	if t.Type == "purchase" && t.Status == "pending" {
+		expires := t.CreatedAt.Add(PurchaseExpiry)
+		t.ExpiresAt = &expires
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCrossedThreshold(t *testing.T) {
+	thresholds := []float64{300, 100}
+	tests := []struct {
+		name          string
+		before, after float64
+		want          float64
+		crossed       bool
+	}{
+		{"stays above", 600, 550, 0, false},
+		{"crosses upper", 320, 270, 300, true},
+		{"lands on threshold", 350, 300, 0, false},
+		{"already below", 250, 200, 0, false},
+		{"crosses lower", 120, 70, 100, true},
+		{"crosses both", 350, 50, 100, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := CrossedThreshold(thresholds, tt.before, tt.after)
+			assert.Equal(t, tt.crossed, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, ok := CrossedThreshold(nil, 100, 0)
+	assert.False(t, ok, "no thresholds configured")
+}
+
+func TestRefundPolicy_Decide(t *testing.T) {
+	now := time.Now()
+	policy := RefundPolicy{GraceWindow: 24 * time.Hour, ProRated: true, Minimum: 5}
+	tests := []struct {
+		name   string
+		policy RefundPolicy
+		c      RefundCase
+		amount float64
+		rule   string
+	}{
+		{"before first session", policy, RefundCase{Cost: 50, ChargedAt: now.Add(-72 * time.Hour), TotalSessions: 12}, 50, RefundBeforeStart},
+		{"no sessions scheduled", policy, RefundCase{Cost: 70, ChargedAt: now.Add(-72 * time.Hour)}, 70, RefundBeforeStart},
+		{"within grace window", policy, RefundCase{Cost: 50, ChargedAt: now.Add(-time.Hour), TotalSessions: 12, StartedSessions: 1}, 50, RefundGrace},
+		{"pro-rated", policy, RefundCase{Cost: 50, ChargedAt: now.Add(-72 * time.Hour), TotalSessions: 12, StartedSessions: 1}, 45.83, RefundProRated},
+		{"below minimum", policy, RefundCase{Cost: 50, ChargedAt: now.Add(-72 * time.Hour), TotalSessions: 12, StartedSessions: 11}, 0, ""},
+		{"all sessions held", policy, RefundCase{Cost: 50, ChargedAt: now.Add(-72 * time.Hour), TotalSessions: 4, StartedSessions: 4}, 0, ""},
+		{"pro-rating disabled", RefundPolicy{}, RefundCase{Cost: 50, ChargedAt: now.Add(-time.Hour), TotalSessions: 4, StartedSessions: 1}, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, rule := tt.policy.Decide(tt.c, now)
+			assert.Equal(t, tt.amount, amount)
+			assert.Equal(t, tt.rule, rule)
+		})
+	}
+}
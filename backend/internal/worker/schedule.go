@@ -0,0 +1,163 @@
+package worker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule computes the next run time of a job.
+type Schedule interface {
+	Next(after time.Time) time.Time
+}
+
+// ParseSchedule accepts either "@every <duration>" (e.g. "@every 30s") or a
+// standard 5-field cron expression "minute hour day-of-month month day-of-week"
+// supporting "*", "*/n", "a-b", "a-b/n" and comma lists. Day-of-week 0 is Sunday.
+// Cron expressions are evaluated in loc.
+func ParseSchedule(spec string, loc *time.Location) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid @every duration in %q", spec)
+		}
+		return everySchedule{interval: d}, nil
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron spec %q must have 5 fields", spec)
+	}
+
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 6}}
+	var sets [5]uint64
+	for i, f := range fields {
+		set, err := parseField(f, bounds[i][0], bounds[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("cron spec %q: %w", spec, err)
+		}
+		sets[i] = set
+	}
+
+	if loc == nil {
+		loc = time.UTC
+	}
+	return &cronSchedule{
+		minute: sets[0], hour: sets[1], dom: sets[2], month: sets[3], dow: sets[4],
+		domAny: fields[2] == "*", dowAny: fields[4] == "*",
+		loc: loc,
+	}, nil
+}
+
+// ─── @every ─────────────────────────────────────────────────────
+
+type everySchedule struct {
+	interval time.Duration
+}
+
+func (s everySchedule) Next(after time.Time) time.Time {
+	return after.Truncate(s.interval).Add(s.interval)
+}
+
+// ─── Cron ───────────────────────────────────────────────────────
+
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool
+	loc                           *time.Location
+}
+
+func (s *cronSchedule) Next(after time.Time) time.Time {
+	t := after.In(s.loc).Truncate(time.Minute).Add(time.Minute)
+	// Four years covers every valid combination, including Feb 29.
+	limit := t.AddDate(4, 0, 0)
+
+	for t.Before(limit) {
+		if !has(s.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.loc)
+			continue
+		}
+		if !has(s.hour, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, s.loc)
+			continue
+		}
+		if !has(s.minute, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches follows cron semantics: when both day fields are restricted,
+// either one matching is enough.
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	domOK := has(s.dom, t.Day())
+	dowOK := has(s.dow, int(t.Weekday()))
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dowOK
+	case s.dowAny:
+		return domOK
+	default:
+		return domOK || dowOK
+	}
+}
+
+func has(set uint64, v int) bool {
+	return set&(1<<uint(v)) != 0
+}
+
+func parseField(field string, min, max int) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			step = n
+			part = part[:i]
+		}
+
+		lo, hi := min, max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			a, errA := strconv.Atoi(bounds[0])
+			b, errB := strconv.Atoi(bounds[1])
+			if errA != nil || errB != nil {
+				return 0, fmt.Errorf("invalid range %q", part)
+			}
+			lo, hi = a, b
+		default:
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+			lo, hi = n, n
+			if step > 1 {
+				hi = max
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("value out of range [%d-%d] in %q", min, max, field)
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
@@ -0,0 +1,45 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule_Every(t *testing.T) {
+	s, err := ParseSchedule("@every 5m", time.UTC)
+	require.NoError(t, err)
+
+	from := time.Date(2025, 3, 10, 9, 2, 30, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 3, 10, 9, 5, 0, 0, time.UTC), s.Next(from))
+}
+
+func TestParseSchedule_Cron(t *testing.T) {
+	algiers := time.FixedZone("CET", 3600)
+
+	cases := []struct {
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"*/5 * * * *", time.Date(2025, 3, 10, 9, 2, 0, 0, algiers), time.Date(2025, 3, 10, 9, 5, 0, 0, algiers)},
+		{"0 8 * * *", time.Date(2025, 3, 10, 9, 0, 0, 0, algiers), time.Date(2025, 3, 11, 8, 0, 0, 0, algiers)},
+		{"30 7 * * 0,4", time.Date(2025, 3, 10, 9, 0, 0, 0, algiers), time.Date(2025, 3, 13, 7, 30, 0, 0, algiers)},
+		{"0 0 1 * *", time.Date(2025, 12, 15, 0, 0, 0, 0, algiers), time.Date(2026, 1, 1, 0, 0, 0, 0, algiers)},
+		{"0 9-17/4 * * *", time.Date(2025, 3, 10, 13, 0, 0, 0, algiers), time.Date(2025, 3, 10, 17, 0, 0, 0, algiers)},
+	}
+	for _, tc := range cases {
+		s, err := ParseSchedule(tc.spec, algiers)
+		require.NoError(t, err, tc.spec)
+		assert.Equal(t, tc.want, s.Next(tc.from), tc.spec)
+	}
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* * * * 7", "*/0 * * * *", "@every -1s", "a b c d e"} {
+		_, err := ParseSchedule(spec, time.UTC)
+		assert.Error(t, err, spec)
+	}
+}
@@ -0,0 +1,206 @@
+package worker
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"educonnect/pkg/cache"
+)
+
+// leaderLock is the Redis lock name contended by every worker instance.
+const leaderLock = "worker:leader"
+
+// Job is a unit of periodic work.
+type Job struct {
+	Name     string
+	Schedule string        // cron spec or "@every <duration>", see ParseSchedule
+	Timeout  time.Duration // per-run deadline; defaults to one minute
+	Run      func(ctx context.Context) error
+}
+
+type scheduledJob struct {
+	Job
+	schedule Schedule
+}
+
+// Scheduler runs registered jobs on their schedules. Several instances may
+// run side by side: they elect a leader through a Redis lock and only the
+// leader executes jobs, so each tick runs once cluster-wide.
+type Scheduler struct {
+	cache    *cache.Redis
+	loc      *time.Location
+	ttl      time.Duration
+	token    string
+	jobs     []*scheduledJob
+	isLeader atomic.Bool
+}
+
+// NewScheduler creates a scheduler. rdb may be nil for a single-instance
+// deployment, in which case this instance always leads. ttl is the leader
+// lease; it is renewed at a third of its length.
+func NewScheduler(rdb *cache.Redis, loc *time.Location, ttl time.Duration) *Scheduler {
+	if loc == nil {
+		loc = time.UTC
+	}
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+	return &Scheduler{cache: rdb, loc: loc, ttl: ttl, token: newToken()}
+}
+
+// Register adds a job. It must be called before Run.
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" || job.Run == nil {
+		return fmt.Errorf("job must have a name and a Run func")
+	}
+	sched, err := ParseSchedule(job.Schedule, s.loc)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", job.Name, err)
+	}
+	if job.Timeout <= 0 {
+		job.Timeout = time.Minute
+	}
+	s.jobs = append(s.jobs, &scheduledJob{Job: job, schedule: sched})
+	return nil
+}
+
+// IsLeader reports whether this instance currently holds the leader lease.
+func (s *Scheduler) IsLeader() bool {
+	return s.isLeader.Load()
+}
+
+// Run blocks until ctx is cancelled, campaigning for leadership and firing
+// jobs when due. In-flight jobs are awaited before it returns.
+func (s *Scheduler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.campaign(ctx)
+	}()
+
+	for _, j := range s.jobs {
+		wg.Add(1)
+		go func(j *scheduledJob) {
+			defer wg.Done()
+			s.loop(ctx, j)
+		}(j)
+	}
+
+	slog.Info("worker scheduler started", "jobs", len(s.jobs))
+	wg.Wait()
+}
+
+// loop sleeps until the job's next tick and runs it if this instance leads.
+// Runs of the same job never overlap: a slow run delays the next tick.
+func (s *Scheduler) loop(ctx context.Context, j *scheduledJob) {
+	for {
+		next := j.schedule.Next(time.Now())
+		if next.IsZero() {
+			slog.Error("job schedule never fires", "job", j.Name)
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if !s.IsLeader() {
+			continue
+		}
+		s.runOnce(ctx, j)
+	}
+}
+
+func (s *Scheduler) runOnce(ctx context.Context, j *scheduledJob) {
+	runCtx, cancel := context.WithTimeout(ctx, j.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("job panicked", "job", j.Name, "panic", r)
+		}
+	}()
+
+	if err := j.Run(runCtx); err != nil {
+		slog.Error("job failed", "job", j.Name, "duration", time.Since(start), "error", err)
+		return
+	}
+	slog.Debug("job completed", "job", j.Name, "duration", time.Since(start))
+}
+
+// ─── Leader Election ────────────────────────────────────────────
+
+// campaign acquires or renews the leader lease every ttl/3 and releases it
+// on shutdown so another instance can take over without waiting for expiry.
+func (s *Scheduler) campaign(ctx context.Context) {
+	if s.cache == nil {
+		s.isLeader.Store(true)
+		<-ctx.Done()
+		s.isLeader.Store(false)
+		return
+	}
+
+	ticker := time.NewTicker(s.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		s.tryLead(ctx)
+
+		select {
+		case <-ctx.Done():
+			if s.isLeader.Swap(false) {
+				releaseCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+				if err := s.cache.ReleaseLock(releaseCtx, leaderLock, s.token); err != nil {
+					slog.Warn("release leader lock failed", "error", err)
+				}
+				cancel()
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) tryLead(ctx context.Context) {
+	var (
+		held bool
+		err  error
+	)
+	if s.IsLeader() {
+		held, err = s.cache.RenewLock(ctx, leaderLock, s.token, s.ttl)
+	} else {
+		held, err = s.cache.AcquireLock(ctx, leaderLock, s.token, s.ttl)
+	}
+	if err != nil {
+		// Without Redis we cannot prove we still lead; step down.
+		slog.Warn("leader lease check failed", "error", err)
+		held = false
+	}
+
+	if was := s.isLeader.Swap(held); was != held {
+		if held {
+			slog.Info("worker became leader", "token", s.token)
+		} else {
+			slog.Info("worker lost leadership", "token", s.token)
+		}
+	}
+}
+
+func newToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
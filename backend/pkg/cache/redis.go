@@ -52,6 +52,8 @@ const (
 	PrefixSession = "session:"
 	PrefixRefresh = "refresh:"
 	PrefixRate    = "rate:"
+	PrefixReset   = "reset:"
+	PrefixLock    = "lock:"
 )
 
 // ─── OTP Operations ─────────────────────────────────────────────
@@ -81,6 +83,60 @@ func (r *Redis) IncrOTPAttempts(ctx context.Context, phone string) (int64, error
 	return incr.Val(), err
 }
 
+// ─── Password Reset ─────────────────────────────────────────────
+
+// SetResetToken stores the user ID for a hashed password reset token.
+func (r *Redis) SetResetToken(ctx context.Context, tokenHash, userID string, expiry time.Duration) error {
+	return r.Client.Set(ctx, PrefixReset+tokenHash, userID, expiry).Err()
+}
+
+// ConsumeResetToken atomically reads and deletes a reset token so it can only be used once.
+func (r *Redis) ConsumeResetToken(ctx context.Context, tokenHash string) (string, error) {
+	return r.Client.GetDel(ctx, PrefixReset+tokenHash).Result()
+}
+
+// IncrResetAttempts increments the password reset request counter for an identifier.
+func (r *Redis) IncrResetAttempts(ctx context.Context, identifier string) (int64, error) {
+	key := PrefixReset + identifier + ":attempts"
+	pipe := r.Client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, time.Hour)
+	_, err := pipe.Exec(ctx)
+	return incr.Val(), err
+}
+
+// ─── Distributed Locks ──────────────────────────────────────────
+
+// renewLockScript extends a lock only if it is still held by token.
+var renewLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+// releaseLockScript deletes a lock only if it is still held by token.
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// AcquireLock takes a lock if nobody holds it. token identifies the holder.
+func (r *Redis) AcquireLock(ctx context.Context, name, token string, ttl time.Duration) (bool, error) {
+	return r.Client.SetNX(ctx, PrefixLock+name, token, ttl).Result()
+}
+
+// RenewLock extends a lock held by token. Returns false if the lock was lost.
+func (r *Redis) RenewLock(ctx context.Context, name, token string, ttl time.Duration) (bool, error) {
+	n, err := renewLockScript.Run(ctx, r.Client, []string{PrefixLock + name}, token, ttl.Milliseconds()).Int()
+	return n == 1, err
+}
+
+// ReleaseLock releases a lock held by token.
+func (r *Redis) ReleaseLock(ctx context.Context, name, token string) error {
+	return releaseLockScript.Run(ctx, r.Client, []string{PrefixLock + name}, token).Err()
+}
+
 // ─── Generic Cache ──────────────────────────────────────────────
 
 // Set stores a value as JSON with expiration.
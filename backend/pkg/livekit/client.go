@@ -3,32 +3,47 @@ package livekit
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"time"
 
 	"educonnect/internal/config"
 
 	"github.com/livekit/protocol/auth"
 	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/webhook"
 	lksdk "github.com/livekit/server-sdk-go/v2"
 )
 
 // Client wraps the LiveKit server SDK.
 type Client struct {
-	roomClient *lksdk.RoomServiceClient
-	apiKey     string
-	apiSecret  string
-	host       string
+	roomClient   *lksdk.RoomServiceClient
+	egressClient *lksdk.EgressClient
+	apiKey       string
+	apiSecret    string
+	host         string
+	recordings   *livekit.S3Upload
 }
 
-// NewClient creates a new LiveKit client.
-func NewClient(cfg config.LiveKitConfig) *Client {
+// NewClient creates a new LiveKit client. Room recordings are uploaded by
+// LiveKit Egress to the MinIO recordings bucket.
+func NewClient(cfg config.LiveKitConfig, store config.MinIOConfig) *Client {
 	roomClient := lksdk.NewRoomServiceClient(cfg.Host, cfg.APIKey, cfg.APISecret)
+	egressClient := lksdk.NewEgressClient(cfg.Host, cfg.APIKey, cfg.APISecret)
 
 	return &Client{
-		roomClient: roomClient,
-		apiKey:     cfg.APIKey,
-		apiSecret:  cfg.APISecret,
-		host:       cfg.Host,
+		roomClient:   roomClient,
+		egressClient: egressClient,
+		apiKey:       cfg.APIKey,
+		apiSecret:    cfg.APISecret,
+		host:         cfg.Host,
+		recordings: &livekit.S3Upload{
+			AccessKey:      store.AccessKey,
+			Secret:         store.SecretKey,
+			Endpoint:       cfg.EgressS3Endpoint,
+			Bucket:         store.BucketRecordings,
+			Region:         "us-east-1", // MinIO ignores it but Egress requires one
+			ForcePathStyle: true,
+		},
 	}
 }
 
@@ -72,6 +87,51 @@ func (c *Client) DeleteRoom(ctx context.Context, roomName string) error {
 	return err
 }
 
+// GetRoom returns a room by name, or nil if LiveKit has no such room
+// (never created, or closed after its empty timeout).
+func (c *Client) GetRoom(ctx context.Context, roomName string) (*livekit.Room, error) {
+	resp, err := c.roomClient.ListRooms(ctx, &livekit.ListRoomsRequest{
+		Names: []string{roomName},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list rooms: %w", err)
+	}
+	if len(resp.Rooms) == 0 {
+		return nil, nil
+	}
+	return resp.Rooms[0], nil
+}
+
+// ReceiveWebhook verifies that a webhook request was signed with this
+// client's API key/secret and returns the parsed event. The body is consumed.
+func (c *Client) ReceiveWebhook(r *http.Request) (*livekit.WebhookEvent, error) {
+	return webhook.ReceiveWebhookEvent(r, auth.NewSimpleKeyProvider(c.apiKey, c.apiSecret))
+}
+
+// StartRecording starts a room composite egress writing an MP4 to objectKey
+// in the recordings bucket and returns the egress ID.
+func (c *Client) StartRecording(ctx context.Context, roomName, objectKey string) (string, error) {
+	info, err := c.egressClient.StartRoomCompositeEgress(ctx, &livekit.RoomCompositeEgressRequest{
+		RoomName: roomName,
+		Layout:   "speaker",
+		FileOutputs: []*livekit.EncodedFileOutput{{
+			FileType: livekit.EncodedFileType_MP4,
+			Filepath: objectKey,
+			Output:   &livekit.EncodedFileOutput_S3{S3: c.recordings},
+		}},
+	})
+	if err != nil {
+		return "", fmt.Errorf("start room egress: %w", err)
+	}
+	return info.EgressId, nil
+}
+
+// StopRecording stops an egress; the file is finalized and uploaded after.
+func (c *Client) StopRecording(ctx context.Context, egressID string) error {
+	_, err := c.egressClient.StopEgress(ctx, &livekit.StopEgressRequest{EgressId: egressID})
+	return err
+}
+
 // GenerateToken creates a JWT token for a participant to join a room.
 func (c *Client) GenerateToken(roomName, participantID, participantName string, isTeacher bool) (string, error) {
 	at := auth.NewAccessToken(c.apiKey, c.apiSecret)
@@ -0,0 +1,56 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"net/smtp"
+	"strings"
+
+	"educonnect/internal/config"
+)
+
+// Mailer sends transactional emails over SMTP.
+// When no SMTP host is configured (development), messages are logged instead.
+type Mailer struct {
+	cfg config.SMTPConfig
+}
+
+// NewMailer creates a new SMTP mailer.
+func NewMailer(cfg config.SMTPConfig) *Mailer {
+	return &Mailer{cfg: cfg}
+}
+
+// Send delivers a plain-text email to a single recipient.
+func (m *Mailer) Send(ctx context.Context, to, subject, body string) error {
+	if m.cfg.Host == "" {
+		slog.Info("email not sent (SMTP disabled)", "to", to, "subject", subject)
+		return nil
+	}
+
+	from, err := mail.ParseAddress(m.cfg.From)
+	if err != nil {
+		return fmt.Errorf("parse sender: %w", err)
+	}
+
+	var msg strings.Builder
+	msg.WriteString("From: " + from.String() + "\r\n")
+	msg.WriteString("To: " + to + "\r\n")
+	msg.WriteString("Subject: " + subject + "\r\n")
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=\"UTF-8\"\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(body)
+
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+
+	addr := m.cfg.Host + ":" + m.cfg.Port
+	if err := smtp.SendMail(addr, auth, from.Address, []string{to}, []byte(msg.String())); err != nil {
+		return fmt.Errorf("send mail: %w", err)
+	}
+	return nil
+}
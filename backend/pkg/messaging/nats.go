@@ -25,6 +25,7 @@ const (
 	SubjectVideoTranscode = "educonnect.transcode"
 	SubjectTeacherVerify  = "educonnect.verification"
 	SubjectAnalyticsEvent = "educonnect.analytics"
+	SubjectDomainEvent    = "educonnect.events"
 )
 
 // Stream names.
@@ -33,6 +34,7 @@ const (
 	StreamPayments      = "PAYMENTS"
 	StreamSessions      = "SESSIONS"
 	StreamTranscoding   = "TRANSCODING"
+	StreamEvents        = "EVENTS"
 )
 
 // NewNATS creates a new NATS connection with JetStream.
@@ -79,22 +81,29 @@ func NewNATS(cfg config.NATSConfig) (*NATS, error) {
 // createStreams initializes JetStream streams.
 func (n *NATS) createStreams() error {
 	streams := []struct {
-		name     string
-		subjects []string
+		name       string
+		subjects   []string
+		retention  nats.RetentionPolicy
+		maxAge     time.Duration
+		duplicates time.Duration
 	}{
-		{StreamNotifications, []string{"educonnect.notification.>"}},
-		{StreamPayments, []string{"educonnect.payment.>"}},
-		{StreamSessions, []string{"educonnect.session.>"}},
-		{StreamTranscoding, []string{"educonnect.transcode.>"}},
+		{StreamNotifications, []string{"educonnect.notification.>"}, nats.WorkQueuePolicy, 24 * time.Hour, 0},
+		{StreamPayments, []string{"educonnect.payment.>"}, nats.WorkQueuePolicy, 24 * time.Hour, 0},
+		{StreamSessions, []string{"educonnect.session.>"}, nats.WorkQueuePolicy, 24 * time.Hour, 0},
+		{StreamTranscoding, []string{"educonnect.transcode.>"}, nats.WorkQueuePolicy, 24 * time.Hour, 0},
+		// Domain events fan out to every interested consumer, so they are kept
+		// by age rather than removed on first ack.
+		{StreamEvents, []string{SubjectDomainEvent + ".>"}, nats.LimitsPolicy, 7 * 24 * time.Hour, time.Hour},
 	}
 
 	for _, s := range streams {
 		_, err := n.JetStream.AddStream(&nats.StreamConfig{
-			Name:      s.name,
-			Subjects:  s.subjects,
-			Retention: nats.WorkQueuePolicy,
-			MaxAge:    24 * time.Hour,
-			Storage:   nats.FileStorage,
+			Name:       s.name,
+			Subjects:   s.subjects,
+			Retention:  s.retention,
+			MaxAge:     s.maxAge,
+			Storage:    nats.FileStorage,
+			Duplicates: s.duplicates,
 		})
 		if err != nil {
 			return fmt.Errorf("create stream %s: %w", s.name, err)
@@ -115,11 +124,24 @@ func (n *NATS) Publish(subject string, payload interface{}) error {
 	return err
 }
 
+// PublishMsg publishes raw bytes with a message ID so JetStream drops
+// duplicates published within the stream's deduplication window.
+func (n *NATS) PublishMsg(subject string, data []byte, msgID string) error {
+	_, err := n.JetStream.Publish(subject, data, nats.MsgId(msgID))
+	return err
+}
+
 // Subscribe creates a durable subscription on a subject.
 func (n *NATS) Subscribe(subject, durable string, handler func(msg *nats.Msg)) (*nats.Subscription, error) {
 	return n.JetStream.Subscribe(subject, handler, nats.Durable(durable), nats.ManualAck())
 }
 
+// QueueSubscribe creates a durable subscription shared by every process
+// using the same durable name: each message goes to one of them.
+func (n *NATS) QueueSubscribe(subject, durable string, handler func(msg *nats.Msg)) (*nats.Subscription, error) {
+	return n.JetStream.QueueSubscribe(subject, durable, handler, nats.Durable(durable), nats.ManualAck())
+}
+
 // Close closes the NATS connection.
 func (n *NATS) Close() {
 	n.Conn.Drain()
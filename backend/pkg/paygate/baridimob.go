@@ -0,0 +1,116 @@
+package paygate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"educonnect/internal/config"
+)
+
+// BaridiMobGateway takes CCP payments through Algérie Poste's BaridiMob
+// merchant API. The payer approves the payment in the BaridiMob app; the
+// outcome is POSTed to our callback URL as JSON signed with the merchant
+// webhook secret (X-BaridiMob-Signature: hex HMAC-SHA256 of the body).
+type BaridiMobGateway struct {
+	baseURL    string
+	merchantID string
+	apiKey     string
+	secret     string
+	client     *http.Client
+}
+
+// NewBaridiMob creates a BaridiMob gateway.
+func NewBaridiMob(cfg config.PaymentConfig) *BaridiMobGateway {
+	return &BaridiMobGateway{
+		baseURL:    strings.TrimSuffix(cfg.BaridiMobURL, "/"),
+		merchantID: cfg.BaridiMobMerchantID,
+		apiKey:     cfg.BaridiMobAPIKey,
+		secret:     cfg.BaridiMobWebhookSecret,
+		client:     defaultHTTPClient(),
+	}
+}
+
+func (g *BaridiMobGateway) Name() string { return BaridiMob }
+
+// Checkout creates a payment order and returns the BaridiMob approval page.
+func (g *BaridiMobGateway) Checkout(ctx context.Context, req CheckoutRequest) (*Checkout, error) {
+	payload, err := json.Marshal(map[string]any{
+		"merchant_id":  g.merchantID,
+		"order_id":     req.OrderID,
+		"amount":       centimes(req.Amount),
+		"currency":     "DZD",
+		"description":  req.Description,
+		"callback_url": req.CallbackURL,
+		"return_url":   req.ReturnURL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal baridimob payload: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, g.baseURL+"/v1/payments", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("build baridimob request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+g.apiKey)
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := g.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("baridimob request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("baridimob: status %d: %s", resp.StatusCode, body)
+	}
+
+	var out struct {
+		Reference  string `json:"reference"`
+		PaymentURL string `json:"payment_url"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("decode baridimob response: %w", err)
+	}
+	if out.Reference == "" || out.PaymentURL == "" {
+		return nil, fmt.Errorf("baridimob: incomplete response: %s", body)
+	}
+	return &Checkout{Reference: out.Reference, RedirectURL: out.PaymentURL}, nil
+}
+
+// VerifyCallback checks the body signature and decodes the outcome.
+func (g *BaridiMobGateway) VerifyCallback(ctx context.Context, r *http.Request) (*Callback, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 64<<10))
+	if err != nil {
+		return nil, fmt.Errorf("read baridimob callback: %w", err)
+	}
+	if !validSignature(g.secret, string(body), r.Header.Get("X-BaridiMob-Signature")) {
+		return nil, ErrInvalidCallback
+	}
+
+	var in struct {
+		OrderID   string `json:"order_id"`
+		Reference string `json:"reference"`
+		Amount    int64  `json:"amount"` // centimes
+		Status    string `json:"status"`
+	}
+	if err := json.Unmarshal(body, &in); err != nil {
+		return nil, ErrInvalidCallback
+	}
+
+	cb := &Callback{OrderID: in.OrderID, Reference: in.Reference, Amount: float64(in.Amount) / 100}
+	switch in.Status {
+	case "paid", "success":
+		cb.Status = StatusPaid
+	case "pending":
+		cb.Status = StatusPending
+	default:
+		cb.Status = StatusFailed
+	}
+	return cb, nil
+}
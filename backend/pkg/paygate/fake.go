@@ -0,0 +1,71 @@
+package paygate
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// FakeGateway is a local gateway for development and tests. Its checkout
+// URL is a signed callback that pays the order, so opening it in a browser
+// (or calling it from a test) completes the payment without any network.
+type FakeGateway struct {
+	secret string
+}
+
+// NewFakeGateway creates a fake gateway signing with secret.
+func NewFakeGateway(secret string) *FakeGateway {
+	return &FakeGateway{secret: secret}
+}
+
+func (g *FakeGateway) Name() string { return Fake }
+
+// Checkout returns a callback URL that reports the order as paid.
+func (g *FakeGateway) Checkout(ctx context.Context, req CheckoutRequest) (*Checkout, error) {
+	ref := "FAKE-" + strings.ToUpper(satimOrderNumber(req.OrderID))
+	cb := Callback{OrderID: req.OrderID, Reference: ref, Amount: req.Amount, Status: StatusPaid}
+	return &Checkout{Reference: ref, RedirectURL: g.CallbackURL(req.CallbackURL, cb)}, nil
+}
+
+// CallbackURL builds a signed callback for any outcome, so tests can
+// simulate declines and amount mismatches.
+func (g *FakeGateway) CallbackURL(base string, cb Callback) string {
+	amount := strconv.FormatInt(centimes(cb.Amount), 10)
+	q := url.Values{
+		"order_id":  {cb.OrderID},
+		"reference": {cb.Reference},
+		"amount":    {amount},
+		"status":    {cb.Status},
+		"sig":       {sign(g.secret, fakeMessage(cb.OrderID, cb.Reference, amount, cb.Status))},
+	}
+	sep := "?"
+	if strings.Contains(base, "?") {
+		sep = "&"
+	}
+	return base + sep + q.Encode()
+}
+
+// VerifyCallback checks the query signature.
+func (g *FakeGateway) VerifyCallback(ctx context.Context, r *http.Request) (*Callback, error) {
+	q := r.URL.Query()
+	msg := fakeMessage(q.Get("order_id"), q.Get("reference"), q.Get("amount"), q.Get("status"))
+	if !validSignature(g.secret, msg, q.Get("sig")) {
+		return nil, ErrInvalidCallback
+	}
+	amount, err := strconv.ParseInt(q.Get("amount"), 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCallback
+	}
+	return &Callback{
+		OrderID:   q.Get("order_id"),
+		Reference: q.Get("reference"),
+		Amount:    float64(amount) / 100,
+		Status:    q.Get("status"),
+	}, nil
+}
+
+func fakeMessage(orderID, ref, amount, status string) string {
+	return strings.Join([]string{orderID, ref, amount, status}, "|")
+}
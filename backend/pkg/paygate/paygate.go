@@ -0,0 +1,151 @@
+package paygate
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"educonnect/internal/config"
+)
+
+// ErrInvalidCallback is returned when a callback's signature does not verify
+// or the gateway does not confirm it.
+var ErrInvalidCallback = errors.New("paygate: invalid callback")
+
+// Gateway names, as stored in transactions.gateway and used in the
+// callback path (/webhooks/payments/:gateway).
+const (
+	SATIM     = "satim"
+	BaridiMob = "baridimob"
+	Fake      = "fake"
+)
+
+// Callback outcomes.
+const (
+	StatusPaid    = "paid"
+	StatusFailed  = "failed"
+	StatusPending = "pending" // not final yet; leave the transaction alone
+)
+
+// CheckoutRequest asks a gateway to open a payment page for one transaction.
+type CheckoutRequest struct {
+	OrderID     string  // our transaction ID
+	Amount      float64 // DZD
+	Description string
+	CallbackURL string // where the gateway (or the payer's browser) reports the outcome
+	ReturnURL   string // app page to show once the outcome is known
+}
+
+// Checkout is where to send the payer.
+type Checkout struct {
+	Reference   string // the gateway's order ID
+	RedirectURL string
+}
+
+// Callback is a verified payment outcome.
+type Callback struct {
+	OrderID   string
+	Reference string
+	Amount    float64 // DZD
+	Status    string
+}
+
+// PaymentGateway opens checkouts and verifies their callbacks.
+type PaymentGateway interface {
+	Name() string
+	Checkout(ctx context.Context, req CheckoutRequest) (*Checkout, error)
+	// VerifyCallback authenticates an incoming callback and returns its
+	// outcome. It returns ErrInvalidCallback for anything not provably
+	// sent by the gateway.
+	VerifyCallback(ctx context.Context, r *http.Request) (*Callback, error)
+}
+
+// Gateways routes payment methods to the gateway that handles them.
+type Gateways struct {
+	byName   map[string]PaymentGateway
+	byMethod map[string]string
+}
+
+// NewGateways returns the gateways selected by cfg.Gateway.
+func NewGateways(cfg config.PaymentConfig) (*Gateways, error) {
+	switch cfg.Gateway {
+	case "live":
+		if cfg.SATIMUsername == "" || cfg.SATIMPassword == "" || cfg.SATIMTerminalID == "" {
+			return nil, fmt.Errorf("paygate: live gateway requires SATIM_USERNAME, SATIM_PASSWORD and SATIM_TERMINAL_ID")
+		}
+		if cfg.BaridiMobMerchantID == "" || cfg.BaridiMobAPIKey == "" || cfg.BaridiMobWebhookSecret == "" {
+			return nil, fmt.Errorf("paygate: live gateway requires BARIDIMOB_MERCHANT_ID, BARIDIMOB_API_KEY and BARIDIMOB_WEBHOOK_SECRET")
+		}
+		if cfg.CallbackSecret == "" {
+			return nil, fmt.Errorf("paygate: live gateway requires PAYMENT_CALLBACK_SECRET")
+		}
+		satim, baridi := NewSATIM(cfg), NewBaridiMob(cfg)
+		return &Gateways{
+			byName: map[string]PaymentGateway{SATIM: satim, BaridiMob: baridi},
+			byMethod: map[string]string{
+				"cib":           SATIM,
+				"edahabia":      SATIM,
+				"ccp_baridimob": BaridiMob,
+			},
+		}, nil
+	case "fake":
+		return NewFakeGateways(cfg.CallbackSecret), nil
+	case "":
+		return nil, fmt.Errorf("paygate: PAYMENT_GATEWAY is not set")
+	default:
+		return nil, fmt.Errorf("paygate: unknown gateway %q", cfg.Gateway)
+	}
+}
+
+// NewFakeGateways routes every payment method to a single FakeGateway.
+func NewFakeGateways(secret string) *Gateways {
+	return &Gateways{
+		byName: map[string]PaymentGateway{Fake: NewFakeGateway(secret)},
+		byMethod: map[string]string{
+			"cib":           Fake,
+			"edahabia":      Fake,
+			"ccp_baridimob": Fake,
+		},
+	}
+}
+
+// ForMethod returns the gateway for a payment method.
+func (g *Gateways) ForMethod(method string) (PaymentGateway, error) {
+	name, ok := g.byMethod[method]
+	if !ok {
+		return nil, fmt.Errorf("paygate: no gateway for payment method %q", method)
+	}
+	return g.byName[name], nil
+}
+
+// ByName returns a gateway by its callback name.
+func (g *Gateways) ByName(name string) (PaymentGateway, bool) {
+	gw, ok := g.byName[name]
+	return gw, ok
+}
+
+// ─── Helpers ────────────────────────────────────────────────────
+
+func defaultHTTPClient() *http.Client {
+	return &http.Client{Timeout: 15 * time.Second}
+}
+
+// sign is a hex HMAC-SHA256 of msg.
+func sign(secret, msg string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(msg))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func validSignature(secret, msg, sig string) bool {
+	return secret != "" && hmac.Equal([]byte(sign(secret, msg)), []byte(sig))
+}
+
+// centimes converts DZD to the integer minor unit gateways expect.
+func centimes(amount float64) int64 { return int64(math.Round(amount * 100)) }
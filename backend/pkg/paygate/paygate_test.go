@@ -0,0 +1,142 @@
+package paygate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"educonnect/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const orderID = "8d3f6a1e-2b4c-4d5e-9f60-718293a4b5c6"
+
+func TestFakeGateway_CheckoutPays(t *testing.T) {
+	gw := NewFakeGateway("secret")
+	ctx := context.Background()
+
+	co, err := gw.Checkout(ctx, CheckoutRequest{OrderID: orderID, Amount: 1500, CallbackURL: "http://api/webhooks/payments/fake"})
+	require.NoError(t, err)
+
+	cb, err := gw.VerifyCallback(ctx, httptest.NewRequest(http.MethodGet, co.RedirectURL, nil))
+	require.NoError(t, err)
+	assert.Equal(t, orderID, cb.OrderID)
+	assert.Equal(t, co.Reference, cb.Reference)
+	assert.Equal(t, 1500.0, cb.Amount)
+	assert.Equal(t, StatusPaid, cb.Status)
+}
+
+func TestFakeGateway_RejectsTampering(t *testing.T) {
+	gw := NewFakeGateway("secret")
+	url := gw.CallbackURL("http://api/cb", Callback{OrderID: orderID, Reference: "R1", Amount: 100, Status: StatusFailed})
+
+	_, err := gw.VerifyCallback(context.Background(), httptest.NewRequest(http.MethodGet, strings.Replace(url, "status=failed", "status=paid", 1), nil))
+	assert.ErrorIs(t, err, ErrInvalidCallback)
+
+	other := NewFakeGateway("another-secret")
+	_, err = other.VerifyCallback(context.Background(), httptest.NewRequest(http.MethodGet, url, nil))
+	assert.ErrorIs(t, err, ErrInvalidCallback)
+}
+
+func TestBaridiMob_CheckoutAndCallback(t *testing.T) {
+	var got map[string]any
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		_, _ = w.Write([]byte(`{"reference":"BM-42","payment_url":"https://baridimob/pay/BM-42"}`))
+	}))
+	defer srv.Close()
+
+	gw := NewBaridiMob(config.PaymentConfig{
+		BaridiMobURL: srv.URL, BaridiMobMerchantID: "M1", BaridiMobAPIKey: "key", BaridiMobWebhookSecret: "whsec",
+	})
+	co, err := gw.Checkout(context.Background(), CheckoutRequest{OrderID: orderID, Amount: 2000.5})
+	require.NoError(t, err)
+	assert.Equal(t, "BM-42", co.Reference)
+	assert.Equal(t, "Bearer key", auth)
+	assert.Equal(t, float64(200050), got["amount"])
+
+	body := []byte(`{"order_id":"` + orderID + `","reference":"BM-42","amount":200050,"status":"paid"}`)
+	req := httptest.NewRequest(http.MethodPost, "/cb", bytes.NewReader(body))
+	req.Header.Set("X-BaridiMob-Signature", sign("whsec", string(body)))
+	cb, err := gw.VerifyCallback(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, StatusPaid, cb.Status)
+	assert.Equal(t, 2000.5, cb.Amount)
+
+	req = httptest.NewRequest(http.MethodPost, "/cb", bytes.NewReader(body))
+	req.Header.Set("X-BaridiMob-Signature", sign("wrong", string(body)))
+	_, err = gw.VerifyCallback(context.Background(), req)
+	assert.ErrorIs(t, err, ErrInvalidCallback)
+}
+
+func TestSATIM_CheckoutAndConfirm(t *testing.T) {
+	var registered, confirmed bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		switch r.URL.Path {
+		case "/register.do":
+			registered = true
+			assert.Equal(t, "150000", r.PostForm.Get("amount"))
+			assert.Equal(t, "012", r.PostForm.Get("currency"))
+			assert.Len(t, r.PostForm.Get("orderNumber"), 10)
+			assert.Contains(t, r.PostForm.Get("returnUrl"), "sig=")
+			_, _ = w.Write([]byte(`{"orderId":"S-1","formUrl":"https://satim/form/S-1","errorCode":"0"}`))
+		case "/confirmOrder.do":
+			confirmed = true
+			assert.Equal(t, "S-1", r.PostForm.Get("orderId"))
+			_, _ = w.Write([]byte(`{"OrderStatus":2,"Amount":150000,"ErrorCode":0}`))
+		}
+	}))
+	defer srv.Close()
+
+	gw := NewSATIM(config.PaymentConfig{
+		SATIMURL: srv.URL, SATIMUsername: "u", SATIMPassword: "p", SATIMTerminalID: "T1", CallbackSecret: "secret",
+	})
+	ctx := context.Background()
+
+	co, err := gw.Checkout(ctx, CheckoutRequest{OrderID: orderID, Amount: 1500, CallbackURL: "http://api/webhooks/payments/satim"})
+	require.NoError(t, err)
+	assert.True(t, registered)
+	assert.Equal(t, "S-1", co.Reference)
+
+	// SATIM redirects the browser to the signed URL, appending its orderId.
+	back := signedURL("http://api/webhooks/payments/satim", "secret", orderID) + "&orderId=S-1"
+	cb, err := gw.VerifyCallback(ctx, httptest.NewRequest(http.MethodGet, back, nil))
+	require.NoError(t, err)
+	assert.True(t, confirmed)
+	assert.Equal(t, StatusPaid, cb.Status)
+	assert.Equal(t, orderID, cb.OrderID)
+	assert.Equal(t, 1500.0, cb.Amount)
+
+	forged := signedURL("http://api/webhooks/payments/satim", "guess", orderID) + "&orderId=S-1"
+	_, err = gw.VerifyCallback(ctx, httptest.NewRequest(http.MethodGet, forged, nil))
+	assert.ErrorIs(t, err, ErrInvalidCallback)
+}
+
+func TestNewGateways_Selection(t *testing.T) {
+	_, err := NewGateways(config.PaymentConfig{Gateway: "live"})
+	assert.Error(t, err, "live without credentials must fail")
+
+	g, err := NewGateways(config.PaymentConfig{Gateway: "fake", CallbackSecret: "s"})
+	require.NoError(t, err)
+	gw, err := g.ForMethod("edahabia")
+	require.NoError(t, err)
+	assert.Equal(t, Fake, gw.Name())
+
+	_, err = g.ForMethod("cash")
+	assert.Error(t, err)
+
+	_, err = NewGateways(config.PaymentConfig{Gateway: "paypal"})
+	assert.Error(t, err)
+
+	_, err = NewGateways(config.PaymentConfig{CallbackSecret: "s"})
+	assert.Error(t, err, "an unset gateway must not fall back to the fake one")
+}
@@ -0,0 +1,174 @@
+package paygate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"educonnect/internal/config"
+)
+
+// SATIMGateway takes CIB and Edahabia cards through the SATIM interbank
+// e-payment platform.
+//
+// SATIM does not call merchants back: it redirects the payer's browser to
+// the returnUrl/failUrl given at registration, appending its orderId. We
+// sign our transaction ID into that URL and then ask SATIM for the order's
+// status server-to-server, so a forged redirect can neither name a
+// transaction it did not start nor claim an outcome SATIM did not record.
+type SATIMGateway struct {
+	baseURL    string
+	username   string
+	password   string
+	terminalID string
+	secret     string
+	client     *http.Client
+}
+
+// NewSATIM creates a SATIM gateway.
+func NewSATIM(cfg config.PaymentConfig) *SATIMGateway {
+	return &SATIMGateway{
+		baseURL:    strings.TrimSuffix(cfg.SATIMURL, "/"),
+		username:   cfg.SATIMUsername,
+		password:   cfg.SATIMPassword,
+		terminalID: cfg.SATIMTerminalID,
+		secret:     cfg.CallbackSecret,
+		client:     defaultHTTPClient(),
+	}
+}
+
+func (g *SATIMGateway) Name() string { return SATIM }
+
+// satimCode is an error code SATIM sends either as a number or a string.
+type satimCode string
+
+func (c *satimCode) UnmarshalJSON(b []byte) error {
+	*c = satimCode(strings.Trim(string(b), `"`))
+	return nil
+}
+
+func (c satimCode) ok() bool { return c == "" || c == "0" }
+
+// Checkout registers the order and returns SATIM's payment form.
+func (g *SATIMGateway) Checkout(ctx context.Context, req CheckoutRequest) (*Checkout, error) {
+	callback := signedURL(req.CallbackURL, g.secret, req.OrderID)
+	params, err := json.Marshal(map[string]string{
+		"force_terminal_id": g.terminalID,
+		"udf1":              req.OrderID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal satim params: %w", err)
+	}
+
+	form := url.Values{
+		"userName":    {g.username},
+		"password":    {g.password},
+		"orderNumber": {satimOrderNumber(req.OrderID)},
+		"amount":      {strconv.FormatInt(centimes(req.Amount), 10)},
+		"currency":    {"012"}, // DZD
+		"returnUrl":   {callback},
+		"failUrl":     {callback},
+		"description": {req.Description},
+		"language":    {"fr"},
+		"jsonParams":  {string(params)},
+	}
+
+	var resp struct {
+		OrderID      string    `json:"orderId"`
+		FormURL      string    `json:"formUrl"`
+		ErrorCode    satimCode `json:"errorCode"`
+		ErrorMessage string    `json:"errorMessage"`
+	}
+	if err := g.call(ctx, "register.do", form, &resp); err != nil {
+		return nil, err
+	}
+	if !resp.ErrorCode.ok() || resp.OrderID == "" {
+		return nil, fmt.Errorf("satim register: code %s: %s", resp.ErrorCode, resp.ErrorMessage)
+	}
+	return &Checkout{Reference: resp.OrderID, RedirectURL: resp.FormURL}, nil
+}
+
+// VerifyCallback checks the signed return URL, then confirms the order
+// with SATIM.
+func (g *SATIMGateway) VerifyCallback(ctx context.Context, r *http.Request) (*Callback, error) {
+	q := r.URL.Query()
+	orderID, ref := q.Get("order_id"), q.Get("orderId")
+	if ref == "" || !validSignature(g.secret, orderID, q.Get("sig")) {
+		return nil, ErrInvalidCallback
+	}
+
+	var resp struct {
+		OrderStatus  int       `json:"OrderStatus"`
+		Amount       int64     `json:"Amount"`
+		ErrorCode    satimCode `json:"ErrorCode"`
+		ErrorMessage string    `json:"ErrorMessage"`
+	}
+	form := url.Values{
+		"userName": {g.username},
+		"password": {g.password},
+		"orderId":  {ref},
+		"language": {"fr"},
+	}
+	if err := g.call(ctx, "confirmOrder.do", form, &resp); err != nil {
+		return nil, err
+	}
+
+	cb := &Callback{OrderID: orderID, Reference: ref, Amount: float64(resp.Amount) / 100}
+	switch {
+	case resp.OrderStatus == 2 && resp.ErrorCode.ok(): // deposited
+		cb.Status = StatusPaid
+	case resp.OrderStatus == 0: // registered, not paid yet
+		cb.Status = StatusPending
+	default: // declined, reversed, refunded, or an error
+		cb.Status = StatusFailed
+	}
+	return cb, nil
+}
+
+func (g *SATIMGateway) call(ctx context.Context, method string, form url.Values, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.baseURL+"/"+method, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("build satim request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("satim %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("satim %s: status %d: %s", method, resp.StatusCode, body)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("decode satim %s: %w", method, err)
+	}
+	return nil
+}
+
+// satimOrderNumber shortens a transaction ID to the 10 characters SATIM
+// accepts as a merchant order number. The full ID travels in udf1 and in
+// the signed return URL.
+func satimOrderNumber(orderID string) string {
+	s := strings.ReplaceAll(orderID, "-", "")
+	if len(s) > 10 {
+		s = s[:10]
+	}
+	return s
+}
+
+// signedURL appends order_id and its signature to a callback URL.
+func signedURL(base, secret, orderID string) string {
+	sep := "?"
+	if strings.Contains(base, "?") {
+		sep = "&"
+	}
+	return base + sep + url.Values{"order_id": {orderID}, "sig": {sign(secret, orderID)}}.Encode()
+}
@@ -0,0 +1,105 @@
+package sms
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Message is a single SMS captured by the FileSender.
+type Message struct {
+	To      string    `json:"to"`
+	Message string    `json:"message"`
+	SentAt  time.Time `json:"sent_at"`
+}
+
+// FileSender is a development sink that appends messages as JSON lines to a
+// file, or writes them to stdout when no path is set. Nothing leaves the machine.
+type FileSender struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileSender creates a file sink. An empty path writes to stdout.
+func NewFileSender(path string) *FileSender {
+	return &FileSender{path: path}
+}
+
+// Send records the message.
+func (s *FileSender) Send(ctx context.Context, to, message string) error {
+	line, err := json.Marshal(Message{To: to, Message: message, SentAt: time.Now()})
+	if err != nil {
+		return fmt.Errorf("marshal sms: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.path == "" {
+		_, err = fmt.Fprintf(os.Stdout, "[SMS] %s\n", line)
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("create sms dir: %w", err)
+	}
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open sms file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write sms file: %w", err)
+	}
+	return nil
+}
+
+// Messages returns every message recorded so far, oldest first.
+func (s *FileSender) Messages() ([]Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.path == "" {
+		return []Message{}, nil
+	}
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Message{}, nil
+		}
+		return nil, fmt.Errorf("open sms file: %w", err)
+	}
+	defer f.Close()
+
+	messages := []Message{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var m Message
+		if err := json.Unmarshal(scanner.Bytes(), &m); err != nil {
+			continue
+		}
+		messages = append(messages, m)
+	}
+	return messages, scanner.Err()
+}
+
+// LastTo returns the most recent message sent to a phone number.
+func (s *FileSender) LastTo(to string) (*Message, error) {
+	messages, err := s.Messages()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].To == to {
+			return &messages[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no sms sent to %s", to)
+}
@@ -0,0 +1,63 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"educonnect/internal/config"
+)
+
+// ICOSNET sends SMS through the ICOSNET HTTP gateway (primary Algerian provider).
+type ICOSNET struct {
+	url    string
+	key    string
+	secret string
+	sender string
+	client *http.Client
+}
+
+// NewICOSNET creates an ICOSNET sender.
+func NewICOSNET(cfg config.SMSConfig) *ICOSNET {
+	return &ICOSNET{
+		url:    cfg.ICOSNETURL,
+		key:    cfg.ICOSNETKey,
+		secret: cfg.ICOSNETSecret,
+		sender: cfg.ICOSNETSender,
+		client: defaultHTTPClient(),
+	}
+}
+
+// Send posts a single message to the gateway.
+func (s *ICOSNET) Send(ctx context.Context, to, message string) error {
+	payload, err := json.Marshal(map[string]string{
+		"sender":  s.sender,
+		"to":      to,
+		"message": message,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal icosnet payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("build icosnet request: %w", err)
+	}
+	req.SetBasicAuth(s.key, s.secret)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("icosnet request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("icosnet: status %d: %s", resp.StatusCode, body)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"educonnect/internal/config"
+)
+
+// SMSSender delivers text messages to phone numbers.
+type SMSSender interface {
+	Send(ctx context.Context, to, message string) error
+}
+
+// NewSender returns the SMSSender selected by cfg.Provider.
+func NewSender(cfg config.SMSConfig) (SMSSender, error) {
+	switch cfg.Provider {
+	case "icosnet":
+		if cfg.ICOSNETKey == "" || cfg.ICOSNETSecret == "" {
+			return nil, fmt.Errorf("sms: icosnet provider requires ICOSNET_API_KEY and ICOSNET_API_SECRET")
+		}
+		return NewICOSNET(cfg), nil
+	case "twilio":
+		if cfg.TwilioSID == "" || cfg.TwilioToken == "" || cfg.TwilioFrom == "" {
+			return nil, fmt.Errorf("sms: twilio provider requires TWILIO_ACCOUNT_SID, TWILIO_AUTH_TOKEN and TWILIO_FROM_NUMBER")
+		}
+		return NewTwilio(cfg), nil
+	case "file":
+		return NewFileSender(cfg.FilePath), nil
+	case "console":
+		return NewFileSender(""), nil
+	case "":
+		return nil, fmt.Errorf("sms: SMS_PROVIDER is not set")
+	default:
+		return nil, fmt.Errorf("sms: unknown provider %q", cfg.Provider)
+	}
+}
+
+func defaultHTTPClient() *http.Client {
+	return &http.Client{Timeout: 10 * time.Second}
+}
@@ -0,0 +1,83 @@
+package sms
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"educonnect/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSender_RecordsMessages(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sms.log")
+	sender := NewFileSender(path)
+	ctx := context.Background()
+
+	require.NoError(t, sender.Send(ctx, "+213550000001", "Votre code EduConnect : 111111"))
+	require.NoError(t, sender.Send(ctx, "+213550000002", "Votre code EduConnect : 222222"))
+	require.NoError(t, sender.Send(ctx, "+213550000001", "Votre code EduConnect : 333333"))
+
+	messages, err := sender.Messages()
+	require.NoError(t, err)
+	assert.Len(t, messages, 3)
+
+	last, err := sender.LastTo("+213550000001")
+	require.NoError(t, err)
+	assert.Contains(t, last.Message, "333333")
+
+	_, err = sender.LastTo("+213550000009")
+	assert.Error(t, err)
+}
+
+func TestTwilio_Send(t *testing.T) {
+	var gotPath, gotTo, gotBody, gotUser string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		gotPath = r.URL.Path
+		gotTo = r.PostForm.Get("To")
+		gotBody = r.PostForm.Get("Body")
+		gotUser, _, _ = r.BasicAuth()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	sender := NewTwilio(config.SMSConfig{TwilioSID: "AC123", TwilioToken: "tok", TwilioFrom: "+15550000000"})
+	sender.baseURL = srv.URL
+
+	require.NoError(t, sender.Send(context.Background(), "+213550000001", "hello"))
+	assert.Equal(t, "/Accounts/AC123/Messages.json", gotPath)
+	assert.Equal(t, "+213550000001", gotTo)
+	assert.Equal(t, "hello", gotBody)
+	assert.Equal(t, "AC123", gotUser)
+}
+
+func TestICOSNET_SendReportsGatewayErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad credentials", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	sender := NewICOSNET(config.SMSConfig{ICOSNETURL: srv.URL, ICOSNETKey: "k", ICOSNETSecret: "s"})
+	err := sender.Send(context.Background(), "+213550000001", "hello")
+	assert.ErrorContains(t, err, "status 401")
+}
+
+func TestNewSender_Selection(t *testing.T) {
+	_, err := NewSender(config.SMSConfig{Provider: "icosnet"})
+	assert.Error(t, err, "icosnet without credentials must fail")
+
+	s, err := NewSender(config.SMSConfig{Provider: "file", FilePath: filepath.Join(t.TempDir(), "sms.log")})
+	require.NoError(t, err)
+	assert.IsType(t, &FileSender{}, s)
+
+	_, err = NewSender(config.SMSConfig{Provider: "carrier-pigeon"})
+	assert.Error(t, err)
+
+	_, err = NewSender(config.SMSConfig{})
+	assert.Error(t, err, "an unset provider must not fall back to the console")
+}
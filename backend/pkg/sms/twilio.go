@@ -0,0 +1,62 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"educonnect/internal/config"
+)
+
+const twilioBaseURL = "https://api.twilio.com/2010-04-01"
+
+// Twilio sends SMS through the Twilio Messages API (international fallback).
+type Twilio struct {
+	baseURL string
+	sid     string
+	token   string
+	from    string
+	client  *http.Client
+}
+
+// NewTwilio creates a Twilio sender.
+func NewTwilio(cfg config.SMSConfig) *Twilio {
+	return &Twilio{
+		baseURL: twilioBaseURL,
+		sid:     cfg.TwilioSID,
+		token:   cfg.TwilioToken,
+		from:    cfg.TwilioFrom,
+		client:  defaultHTTPClient(),
+	}
+}
+
+// Send creates a message resource on the Twilio account.
+func (s *Twilio) Send(ctx context.Context, to, message string) error {
+	form := url.Values{}
+	form.Set("To", to)
+	form.Set("From", s.from)
+	form.Set("Body", message)
+
+	endpoint := fmt.Sprintf("%s/Accounts/%s/Messages.json", s.baseURL, s.sid)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("build twilio request: %w", err)
+	}
+	req.SetBasicAuth(s.sid, s.token)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("twilio request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("twilio: status %d: %s", resp.StatusCode, body)
+	}
+	return nil
+}
@@ -2,18 +2,38 @@ package integration_test
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"educonnect/internal/access"
+	"educonnect/internal/auth"
 	"educonnect/internal/booking"
 	"educonnect/internal/config"
+	"educonnect/internal/events"
+	"educonnect/internal/ledger"
 	"educonnect/internal/payment"
+	"educonnect/internal/payout"
+	"educonnect/internal/promotion"
+	"educonnect/internal/scheduling"
+	"educonnect/internal/session"
 	"educonnect/internal/sessionseries"
 	teacherpkg "educonnect/internal/teacher"
 	"educonnect/internal/wallet"
+	"educonnect/pkg/cache"
 	"educonnect/pkg/database"
+	"educonnect/pkg/mailer"
+	"educonnect/pkg/paygate"
+	"educonnect/pkg/sms"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -32,6 +52,7 @@ var (
 	teacherService *teacherpkg.Service
 	paymentService *payment.Service
 	walletService  *wallet.Service
+	testRedis      *cache.Redis // nil when Redis is not running
 )
 
 // TestUser represents a user created for testing
@@ -71,11 +92,27 @@ func TestMain(m *testing.M) {
 	defer testDB.Close()
 
 	// Initialize services
-	bookingService = booking.NewService(testDB, nil) // No notification service for tests
-	walletService = wallet.NewService(testDB)
-	seriesService = sessionseries.NewService(testDB, nil, walletService) // No LiveKit for tests
-	teacherService = teacherpkg.NewService(testDB, nil)                  // No Meilisearch for tests
-	paymentService = payment.NewService(testDB)
+	bookingService = booking.NewService(testDB, nil, nil) // No notification service / event bus for tests
+	walletService = wallet.NewService(testDB, nil, nil, config.WalletConfig{
+		LowBalanceThresholds: []float64{300, 100},
+		RefundProRated:       true, // no grace window: refunds follow sessions started
+	})
+	seriesService = sessionseries.NewService(testDB, nil, walletService, access.NewPolicy(testDB), nil) // No LiveKit for tests
+	teacherService = teacherpkg.NewService(testDB, nil)                                                 // No Meilisearch for tests
+	paymentService = payment.NewService(testDB, paygate.NewFakeGateways(testPaymentSecret), "http://localhost:8080", "http://localhost:8080/payments/result")
+
+	// Redis backs the auth tests only; they are skipped without it
+	testRedis, err = cache.NewRedis(config.RedisConfig{
+		Host:     getEnvOrDefault("REDIS_HOST", "localhost"),
+		Port:     getEnvOrDefault("REDIS_PORT", "6380"),
+		Password: getEnvOrDefault("REDIS_PASSWORD", "educonnect_redis"),
+	})
+	if err != nil {
+		fmt.Printf("Redis not available, skipping auth tests: %v\n", err)
+		testRedis = nil
+	} else {
+		defer testRedis.Close()
+	}
 
 	// Run tests
 	code := m.Run()
@@ -185,7 +222,6 @@ func cleanupTestUser(t *testing.T, ctx context.Context, userID uuid.UUID) {
 	// Delete in reverse order of foreign keys
 	testDB.Pool.Exec(ctx, `DELETE FROM wallet_transactions WHERE wallet_id IN (SELECT id FROM teacher_wallets WHERE teacher_id = $1)`, userID)
 	testDB.Pool.Exec(ctx, `DELETE FROM teacher_wallets WHERE teacher_id = $1`, userID)
-	testDB.Pool.Exec(ctx, `DELETE FROM platform_fees WHERE series_id IN (SELECT id FROM session_series WHERE teacher_id = $1)`, userID)
 	testDB.Pool.Exec(ctx, `DELETE FROM session_participants WHERE user_id = $1`, userID)
 	testDB.Pool.Exec(ctx, `DELETE FROM session_participants WHERE student_id = $1`, userID)
 	testDB.Pool.Exec(ctx, `DELETE FROM session_enrollments WHERE student_id = $1`, userID)
@@ -196,7 +232,13 @@ func cleanupTestUser(t *testing.T, ctx context.Context, userID uuid.UUID) {
 	testDB.Pool.Exec(ctx, `DELETE FROM booking_messages WHERE booking_id IN (SELECT id FROM booking_requests WHERE student_id = $1 OR teacher_id = $1)`, userID)
 	testDB.Pool.Exec(ctx, `DELETE FROM booking_requests WHERE student_id = $1 OR teacher_id = $1`, userID)
 	testDB.Pool.Exec(ctx, `DELETE FROM booking_requests WHERE booked_by_parent_id = $1`, userID)
+	testDB.Pool.Exec(ctx, `DELETE FROM package_purchases WHERE student_id = $1 OR purchased_by = $1 OR package_id IN (SELECT id FROM packages WHERE teacher_id = $1)`, userID)
+	testDB.Pool.Exec(ctx, `DELETE FROM packages WHERE teacher_id = $1`, userID)
+	testDB.Pool.Exec(ctx, `UPDATE subscriptions SET renewal_transaction_id = NULL WHERE student_id = $1 OR teacher_id = $1`, userID)
+	testDB.Pool.Exec(ctx, `DELETE FROM promotion_redemptions WHERE student_id = $1 OR promotion_id IN (SELECT id FROM promotions WHERE teacher_id = $1)`, userID)
 	testDB.Pool.Exec(ctx, `DELETE FROM transactions WHERE payer_id = $1 OR payee_id = $1`, userID)
+	testDB.Pool.Exec(ctx, `DELETE FROM payouts WHERE teacher_id = $1`, userID)
+	testDB.Pool.Exec(ctx, `DELETE FROM promotions WHERE teacher_id = $1`, userID)
 	testDB.Pool.Exec(ctx, `DELETE FROM subscriptions WHERE student_id = $1 OR teacher_id = $1`, userID)
 	testDB.Pool.Exec(ctx, `DELETE FROM availability_slots WHERE teacher_id = $1`, userID)
 	testDB.Pool.Exec(ctx, `DELETE FROM offerings WHERE teacher_id = $1`, userID)
@@ -227,10 +269,22 @@ func fundTeacherWallet(t *testing.T, ctx context.Context, teacherID uuid.UUID) {
 	})
 	require.NoError(t, err)
 
+	attachTestReceipt(t, ctx, tx.ID)
 	_, err = walletService.AdminApprovePurchase(ctx, tx.ID.String(), admin.ID.String(), true, "test funding")
 	require.NoError(t, err)
 }
 
+// attachTestReceipt marks a purchase as having a receipt, which approval
+// requires; the tests run without object storage.
+func attachTestReceipt(t *testing.T, ctx context.Context, txID uuid.UUID) {
+	t.Helper()
+	_, err := testDB.Pool.Exec(ctx,
+		`UPDATE wallet_transactions
+		 SET receipt_key = 'receipts/test/' || id, receipt_content_type = 'application/pdf', receipt_uploaded_at = NOW()
+		 WHERE id = $1`, txID)
+	require.NoError(t, err)
+}
+
 func getNextWeekday(weekday time.Weekday) time.Time {
 	now := time.Now()
 	daysUntil := int(weekday) - int(now.Weekday())
@@ -302,6 +356,223 @@ func TestTeacherAvailability(t *testing.T) {
 	})
 }
 
+// ─── Exceptions and holidays change a date's availability ───────
+
+func TestAvailabilityExceptions(t *testing.T) {
+	ctx := context.Background()
+
+	teacher := createTeacherWithProfile(t, ctx, "Except", "Teacher")
+	defer cleanupTestUser(t, ctx, teacher.ID)
+	for i := 0; i < 7; i++ {
+		createTeacherAvailability(t, ctx, teacher.ID, i, "08:00", "20:00")
+	}
+	student := createStudentWithProfile(t, ctx, "Except", "Student", nil)
+	defer cleanupTestUser(t, ctx, student.ID)
+
+	book := func(day time.Time, start, end string) error {
+		_, err := bookingService.CreateBookingRequest(ctx, student.ID.String(), "student", booking.CreateBookingRequest{
+			TeacherID:     teacher.ID.String(),
+			SessionType:   "individual",
+			RequestedDate: day.Format("2006-01-02"),
+			StartTime:     start,
+			EndTime:       end,
+		})
+		return err
+	}
+	schedule := func(day time.Time) []scheduling.Window {
+		days, err := teacherService.GetSchedule(ctx, teacher.ID.String(), day.Format("2006-01-02"), day.Format("2006-01-02"))
+		require.NoError(t, err)
+		require.Len(t, days, 1)
+		return days[0].Windows
+	}
+
+	tuesday := getNextWeekday(time.Tuesday)
+	wednesday := getNextWeekday(time.Wednesday)
+	thursday := getNextWeekday(time.Thursday)
+
+	// ─── Test: A day off closes the date ────────────────────────
+	var dayOff *teacherpkg.AvailabilityExceptionResponse
+	t.Run("DayOff", func(t *testing.T) {
+		var err error
+		dayOff, err = teacherService.CreateException(ctx, teacher.ID.String(), teacherpkg.AvailabilityExceptionRequest{
+			Date:   tuesday.Format("2006-01-02"),
+			Reason: "Congé",
+		})
+		require.NoError(t, err)
+		assert.Nil(t, dayOff.StartTime)
+
+		assert.Empty(t, schedule(tuesday))
+		assert.ErrorIs(t, book(tuesday, "10:00", "11:00"), booking.ErrSlotNotAvailable)
+
+		t.Log("✓ No booking on a day off")
+	})
+
+	// ─── Test: An extra slot opens hours outside the week ───────
+	t.Run("ExtraSlot", func(t *testing.T) {
+		_, err := teacherService.CreateException(ctx, teacher.ID.String(), teacherpkg.AvailabilityExceptionRequest{
+			Date:        tuesday.Format("2006-01-02"),
+			IsAvailable: true,
+			StartTime:   "20:00",
+			EndTime:     "22:00",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []scheduling.Window{{Start: "20:00", End: "22:00"}}, schedule(tuesday))
+		require.NoError(t, book(tuesday, "20:30", "21:30"))
+
+		t.Log("✓ An extra slot is bookable, even on a day off")
+	})
+
+	// ─── Test: Hours off cut the weekly slot ────────────────────
+	t.Run("HoursOff", func(t *testing.T) {
+		_, err := teacherService.CreateException(ctx, teacher.ID.String(), teacherpkg.AvailabilityExceptionRequest{
+			Date:      wednesday.Format("2006-01-02"),
+			StartTime: "12:00",
+			EndTime:   "14:00",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []scheduling.Window{{Start: "08:00", End: "12:00"}, {Start: "14:00", End: "20:00"}}, schedule(wednesday))
+		assert.ErrorIs(t, book(wednesday, "13:00", "14:00"), booking.ErrSlotNotAvailable)
+		require.NoError(t, book(wednesday, "14:00", "15:00"))
+
+		t.Log("✓ Hours off are taken out of the weekly slot")
+	})
+
+	// ─── Test: Removing the day off reopens it ──────────────────
+	t.Run("DeleteException", func(t *testing.T) {
+		require.NotNil(t, dayOff)
+		require.NoError(t, teacherService.DeleteException(ctx, teacher.ID.String(), dayOff.ID.String()))
+		assert.ErrorIs(t, teacherService.DeleteException(ctx, teacher.ID.String(), dayOff.ID.String()), teacherpkg.ErrExceptionNotFound)
+
+		require.NoError(t, book(tuesday, "10:00", "11:00"))
+
+		exceptions, err := teacherService.ListExceptions(ctx, teacher.ID.String(), "", "")
+		require.NoError(t, err)
+		assert.Len(t, exceptions, 2, "the extra slot and the hours off")
+
+		t.Log("✓ Deleted exception no longer applies")
+	})
+
+	// ─── Test: Observed holidays close the day ──────────────────
+	t.Run("ObservedHoliday", func(t *testing.T) {
+		var holidayID uuid.UUID
+		require.NoError(t, testDB.Pool.QueryRow(ctx,
+			`INSERT INTO holidays (kind, name_fr, start_date, end_date)
+			 VALUES ('school', 'Vacances de test', $1::date, $1::date) RETURNING id`,
+			thursday.Format("2006-01-02"),
+		).Scan(&holidayID))
+		defer testDB.Pool.Exec(ctx, `DELETE FROM holidays WHERE id = $1`, holidayID)
+
+		// Not observed: the weekly slots stand
+		assert.NotEmpty(t, schedule(thursday))
+
+		_, err := teacherService.UpdateProfile(ctx, teacher.ID.String(), teacherpkg.UpdateTeacherProfileRequest{
+			ObservedHolidays: []string{"school"},
+		})
+		require.NoError(t, err)
+
+		assert.Empty(t, schedule(thursday))
+		err = book(thursday, "10:00", "11:00")
+		assert.ErrorIs(t, err, booking.ErrSlotNotAvailable)
+		assert.Contains(t, err.Error(), "Vacances de test")
+
+		t.Log("✓ An observed holiday closes the day")
+	})
+
+	// ─── Test: An extra slot needs its hours ────────────────────
+	t.Run("Validation", func(t *testing.T) {
+		_, err := teacherService.CreateException(ctx, teacher.ID.String(), teacherpkg.AvailabilityExceptionRequest{
+			Date:        thursday.Format("2006-01-02"),
+			IsAvailable: true,
+		})
+		assert.ErrorIs(t, err, teacherpkg.ErrInvalidException)
+
+		_, err = teacherService.CreateException(ctx, teacher.ID.String(), teacherpkg.AvailabilityExceptionRequest{
+			Date:      thursday.Format("2006-01-02"),
+			StartTime: "15:00",
+			EndTime:   "14:00",
+		})
+		assert.ErrorIs(t, err, teacherpkg.ErrInvalidException)
+
+		t.Log("✓ Incomplete exceptions refused")
+	})
+}
+
+// ─── Free slots leave out sessions and pending bookings ──────────
+
+func TestFreeSlots(t *testing.T) {
+	ctx := context.Background()
+
+	teacher := createTeacherWithProfile(t, ctx, "Free", "Teacher")
+	defer cleanupTestUser(t, ctx, teacher.ID)
+	friday := getNextWeekday(time.Friday)
+	createTeacherAvailability(t, ctx, teacher.ID, int(time.Friday), "09:00", "12:00")
+	student := createStudentWithProfile(t, ctx, "Free", "Student", nil)
+	defer cleanupTestUser(t, ctx, student.ID)
+
+	book := func(start, end string) *booking.BookingRequestResponse {
+		created, err := bookingService.CreateBookingRequest(ctx, student.ID.String(), "student", booking.CreateBookingRequest{
+			TeacherID:     teacher.ID.String(),
+			SessionType:   "individual",
+			RequestedDate: friday.Format("2006-01-02"),
+			StartTime:     start,
+			EndTime:       end,
+		})
+		require.NoError(t, err)
+		return created
+	}
+	starts := func(duration, granularity int) []string {
+		date := friday.Format("2006-01-02")
+		days, err := teacherService.FreeSlots(ctx, teacher.ID.String(), date, date, duration, granularity)
+		require.NoError(t, err)
+		require.Len(t, days, 1)
+		out := []string{}
+		for _, slot := range days[0].Slots {
+			out = append(out, slot.StartTime)
+		}
+		return out
+	}
+
+	// ─── Test: The whole window is free ─────────────────────────
+	t.Run("Empty", func(t *testing.T) {
+		assert.Equal(t, []string{"09:00", "10:00", "11:00"}, starts(60, 60))
+		assert.Equal(t, []string{"09:00", "09:30", "10:00", "10:30", "11:00"}, starts(60, 30))
+	})
+
+	// ─── Test: Sessions and pending bookings are taken out ──────
+	t.Run("Busy", func(t *testing.T) {
+		accepted := book("10:00", "11:00")
+		_, err := bookingService.AcceptBookingRequest(ctx, accepted.ID, teacher.ID.String(), booking.AcceptBookingRequest{Price: 2000})
+		require.NoError(t, err)
+		book("11:30", "12:00") // left pending
+
+		assert.Equal(t, []string{"09:00", "09:30", "11:00"}, starts(30, 30))
+		assert.Equal(t, []string{"09:00"}, starts(60, 30))
+
+		t.Log("✓ Free slots skip the session and the pending booking")
+	})
+
+	// ─── Test: Every slot offered can be booked ─────────────────
+	t.Run("Bookable", func(t *testing.T) {
+		date := friday.Format("2006-01-02")
+		days, err := teacherService.FreeSlots(ctx, teacher.ID.String(), date, date, 30, 30)
+		require.NoError(t, err)
+		for _, slot := range days[0].Slots {
+			book(slot.StartTime, slot.EndTime)
+		}
+		assert.Empty(t, starts(30, 30))
+	})
+
+	// ─── Test: Bad queries are refused ──────────────────────────
+	t.Run("Validation", func(t *testing.T) {
+		_, err := teacherService.FreeSlots(ctx, teacher.ID.String(), "", "", 10, 30)
+		assert.ErrorIs(t, err, teacherpkg.ErrInvalidSlotQuery)
+		_, err = teacherService.FreeSlots(ctx, teacher.ID.String(), "2026-02-01", "2026-01-01", 60, 30)
+		assert.ErrorIs(t, err, teacherpkg.ErrInvalidRange)
+	})
+}
+
 // ═══════════════════════════════════════════════════════════════
 // TEST SUITE 2: Teacher Offerings
 // ═══════════════════════════════════════════════════════════════
@@ -1157,6 +1428,215 @@ func TestBookingRequests(t *testing.T) {
 	})
 }
 
+// ─── Booking times are the teacher's wall clock ─────────────────
+
+func TestBookingTimezones(t *testing.T) {
+	ctx := context.Background()
+
+	teacher := createTeacherWithProfile(t, ctx, "Zone", "Teacher")
+	defer cleanupTestUser(t, ctx, teacher.ID)
+	for i := 0; i < 7; i++ {
+		createTeacherAvailability(t, ctx, teacher.ID, i, "08:00", "20:00")
+	}
+	student := createStudentWithProfile(t, ctx, "Zone", "Student", nil)
+	defer cleanupTestUser(t, ctx, student.ID)
+
+	book := func(start, end string) *booking.BookingRequestResponse {
+		created, err := bookingService.CreateBookingRequest(ctx, student.ID.String(), "student", booking.CreateBookingRequest{
+			TeacherID:     teacher.ID.String(),
+			SessionType:   "individual",
+			RequestedDate: getNextWeekday(time.Wednesday).Format("2006-01-02"),
+			StartTime:     start,
+			EndTime:       end,
+		})
+		require.NoError(t, err)
+		accepted, err := bookingService.AcceptBookingRequest(ctx, created.ID, teacher.ID.String(), booking.AcceptBookingRequest{Price: 2000})
+		require.NoError(t, err)
+		return accepted
+	}
+	sessionStart := func(br *booking.BookingRequestResponse) time.Time {
+		var start time.Time
+		require.NotNil(t, br.SessionID)
+		require.NoError(t, testDB.Pool.QueryRow(ctx,
+			`SELECT start_time FROM sessions WHERE id = $1`, *br.SessionID).Scan(&start))
+		return start
+	}
+
+	// ─── Test: Algiers by default ───────────────────────────────
+	t.Run("DefaultZone", func(t *testing.T) {
+		br := book("16:00", "17:00")
+		assert.Equal(t, "Africa/Algiers", br.Timezone)
+		require.NotNil(t, br.StartsAt)
+		assert.Equal(t, "16:00:00+01:00", br.StartsAt.Format("15:04:05Z07:00"))
+		assert.Equal(t, "15:00", sessionStart(br).UTC().Format("15:04"), "16:00 in Algiers is 15:00 UTC")
+
+		t.Log("✓ A 16:00 booking in Algiers starts at 15:00 UTC")
+	})
+
+	// ─── Test: The teacher's zone is used ───────────────────────
+	t.Run("TeacherZone", func(t *testing.T) {
+		_, err := testDB.Pool.Exec(ctx, `UPDATE users SET timezone = 'Asia/Tokyo' WHERE id = $1`, teacher.ID)
+		require.NoError(t, err)
+
+		br := book("10:00", "11:00")
+		assert.Equal(t, "Asia/Tokyo", br.Timezone)
+		assert.Equal(t, "01:00", sessionStart(br).UTC().Format("15:04"))
+
+		t.Log("✓ Bookings follow the teacher's zone")
+	})
+}
+
+// ─── Overlapping sessions are refused ───────────────────────────
+
+func TestSessionOverlaps(t *testing.T) {
+	ctx := context.Background()
+	sessionService := session.NewService(testDB, nil, nil, access.NewPolicy(testDB), nil)
+
+	teacher := createTeacherWithProfile(t, ctx, "Overlap", "Teacher")
+	defer cleanupTestUser(t, ctx, teacher.ID)
+	for i := 0; i < 7; i++ {
+		createTeacherAvailability(t, ctx, teacher.ID, i, "08:00", "20:00")
+	}
+	student := createStudentWithProfile(t, ctx, "Overlap", "Student", nil)
+	defer cleanupTestUser(t, ctx, student.ID)
+
+	day := getNextWeekday(time.Thursday)
+	loc, err := scheduling.LoadZone("")
+	require.NoError(t, err)
+	at := func(clock string) time.Time {
+		tm, err := scheduling.At(day, clock, loc)
+		require.NoError(t, err)
+		return tm
+	}
+
+	// 15:00–17:00 is taken
+	taken, err := sessionService.CreateSession(ctx, teacher.ID.String(), session.CreateSessionRequest{
+		Title:       "Séance 15h",
+		SessionType: "one_on_one",
+		StartTime:   at("15:00").Format(time.RFC3339),
+		EndTime:     at("17:00").Format(time.RFC3339),
+		MaxStudents: 1,
+		Price:       2000,
+	})
+	require.NoError(t, err)
+
+	// ─── Test: A partly overlapping booking is refused ──────────
+	t.Run("BookingOverlap", func(t *testing.T) {
+		_, err := bookingService.CreateBookingRequest(ctx, student.ID.String(), "student", booking.CreateBookingRequest{
+			TeacherID:     teacher.ID.String(),
+			SessionType:   "individual",
+			RequestedDate: day.Format("2006-01-02"),
+			StartTime:     "16:00",
+			EndTime:       "18:00",
+		})
+		assert.ErrorIs(t, err, booking.ErrAlreadyBooked)
+
+		// Touching is not overlapping
+		created, err := bookingService.CreateBookingRequest(ctx, student.ID.String(), "student", booking.CreateBookingRequest{
+			TeacherID:     teacher.ID.String(),
+			SessionType:   "individual",
+			RequestedDate: day.Format("2006-01-02"),
+			StartTime:     "17:00",
+			EndTime:       "18:00",
+		})
+		require.NoError(t, err)
+		_, err = bookingService.AcceptBookingRequest(ctx, created.ID, teacher.ID.String(), booking.AcceptBookingRequest{Price: 2000})
+		require.NoError(t, err)
+
+		t.Log("✓ 16:00–18:00 refused next to 15:00–17:00; 17:00–18:00 accepted")
+	})
+
+	// ─── Test: A session created meanwhile blocks acceptance ────
+	t.Run("AcceptOverlap", func(t *testing.T) {
+		created, err := bookingService.CreateBookingRequest(ctx, student.ID.String(), "student", booking.CreateBookingRequest{
+			TeacherID:     teacher.ID.String(),
+			SessionType:   "individual",
+			RequestedDate: day.Format("2006-01-02"),
+			StartTime:     "10:00",
+			EndTime:       "11:00",
+		})
+		require.NoError(t, err)
+
+		_, err = sessionService.CreateSession(ctx, teacher.ID.String(), session.CreateSessionRequest{
+			Title:       "Séance 10h30",
+			SessionType: "one_on_one",
+			StartTime:   at("10:30").Format(time.RFC3339),
+			EndTime:     at("11:30").Format(time.RFC3339),
+			MaxStudents: 1,
+			Price:       2000,
+		})
+		require.NoError(t, err)
+
+		_, err = bookingService.AcceptBookingRequest(ctx, created.ID, teacher.ID.String(), booking.AcceptBookingRequest{Price: 2000})
+		assert.ErrorIs(t, err, booking.ErrTimeConflict)
+		assert.Contains(t, err.Error(), "Séance 10h30")
+
+		t.Log("✓ Acceptance refused over a partly overlapping session")
+	})
+
+	// ─── Test: Series sessions cannot overlap ───────────────────
+	t.Run("AddSessionsOverlap", func(t *testing.T) {
+		series, err := seriesService.CreateSeries(ctx, teacher.ID.String(), sessionseries.CreateSeriesRequest{
+			Title:         "Overlap Series",
+			SessionType:   "group",
+			DurationHours: 2,
+			MaxStudents:   5,
+		})
+		require.NoError(t, err)
+
+		_, err = seriesService.AddSessions(ctx, series.ID.String(), teacher.ID.String(), sessionseries.AddSessionsRequest{
+			Sessions: []sessionseries.SessionDateInput{{StartTime: at("14:00").Format(time.RFC3339)}},
+		})
+		assert.ErrorIs(t, err, scheduling.ErrConflict)
+
+		// Two sessions of the same batch may not overlap either
+		_, err = seriesService.AddSessions(ctx, series.ID.String(), teacher.ID.String(), sessionseries.AddSessionsRequest{
+			Sessions: []sessionseries.SessionDateInput{
+				{StartTime: at("08:00").Format(time.RFC3339)},
+				{StartTime: at("08:30").Format(time.RFC3339)},
+			},
+		})
+		assert.ErrorIs(t, err, scheduling.ErrConflict)
+
+		var count int
+		require.NoError(t, testDB.Pool.QueryRow(ctx,
+			`SELECT COUNT(*) FROM sessions WHERE series_id = $1`, series.ID).Scan(&count))
+		assert.Equal(t, 0, count, "a refused batch adds nothing")
+
+		t.Log("✓ Overlapping series sessions refused")
+	})
+
+	// ─── Test: Rescheduling onto another session is refused ─────
+	t.Run("RescheduleOverlap", func(t *testing.T) {
+		_, err := sessionService.RescheduleSession(ctx, taken.ID, teacher.ID.String(), session.RescheduleSessionRequest{
+			StartTime: at("17:30").Format(time.RFC3339),
+			EndTime:   at("18:30").Format(time.RFC3339),
+		})
+		assert.ErrorIs(t, err, scheduling.ErrConflict)
+
+		// Moving within its own slot is fine
+		_, err = sessionService.RescheduleSession(ctx, taken.ID, teacher.ID.String(), session.RescheduleSessionRequest{
+			StartTime: at("15:30").Format(time.RFC3339),
+			EndTime:   at("16:30").Format(time.RFC3339),
+		})
+		require.NoError(t, err)
+
+		t.Log("✓ Rescheduling checks every session but the one moved")
+	})
+
+	// ─── Test: The database refuses what the checks let through ─
+	t.Run("ExclusionConstraint", func(t *testing.T) {
+		_, err := testDB.Pool.Exec(ctx,
+			`INSERT INTO sessions (teacher_id, title, session_type, start_time, end_time, max_participants, price, status)
+			 VALUES ($1, 'Direct', 'one_on_one', $2, $3, 1, 0, 'scheduled')`,
+			teacher.ID, at("16:00"), at("16:15"),
+		)
+		assert.True(t, scheduling.IsOverlapViolation(err), "got %v", err)
+
+		t.Log("✓ sessions_no_overlap rejects a direct overlapping insert")
+	})
+}
+
 // ═══════════════════════════════════════════════════════════════
 // TEST SUITE 5: Individual vs Group Sessions
 // ═══════════════════════════════════════════════════════════════
@@ -1857,34 +2337,72 @@ func TestPaymentStatusGuards(t *testing.T) {
 	payee := createTestUser(t, ctx, "teacher", "Pay", "Payee")
 	defer cleanupTestUser(t, ctx, payee.ID)
 
-	// ─── Test: Confirm only pending transactions ────────────────
-	t.Run("ConfirmOnlyPending", func(t *testing.T) {
-		// Create a payment
+	// ─── Test: Only a signed gateway callback completes ─────────
+	t.Run("CallbackCompletes", func(t *testing.T) {
 		tx, err := paymentService.InitiatePayment(ctx, payer.ID.String(), payment.InitiatePaymentRequest{
 			PayeeID:       payee.ID,
 			Amount:        1000,
 			PaymentMethod: "ccp_baridimob",
 		})
 		require.NoError(t, err)
-		assert.Equal(t, "pending", tx.Status)
+		assert.Equal(t, "processing", tx.Status)
+		require.NotNil(t, tx.CheckoutURL)
+
+		// A callback signed with another secret is rejected
+		forged := paygate.NewFakeGateway("not-the-secret").CallbackURL("http://localhost:8080/api/v1/webhooks/payments/fake",
+			paygate.Callback{OrderID: tx.ID.String(), Reference: *tx.ProviderReference, Amount: 1000, Status: paygate.StatusPaid})
+		_, err = paymentCallback(ctx, forged)
+		assert.ErrorIs(t, err, payment.ErrInvalidCallback)
+
+		// The gateway's callback completes it
+		completed, err := paymentCallback(ctx, *tx.CheckoutURL)
+		require.NoError(t, err)
+		assert.Equal(t, "completed", completed.Status)
+		assert.NotNil(t, completed.PaidAt)
+
+		// Replays are harmless
+		again, err := paymentCallback(ctx, *tx.CheckoutURL)
+		require.NoError(t, err)
+		assert.Equal(t, "completed", again.Status)
+
+		t.Log("✓ Only the gateway callback completes a transaction")
+	})
+
+	// ─── Test: Declines and short payments fail ─────────────────
+	t.Run("DeclinedCallbackFails", func(t *testing.T) {
+		fake := paygate.NewFakeGateway(testPaymentSecret)
+		base := "http://localhost:8080/api/v1/webhooks/payments/fake"
 
-		// Confirm it (should succeed)
-		confirmed, err := paymentService.ConfirmPayment(ctx, payer.ID.String(), payment.ConfirmPaymentRequest{
-			TransactionID:     tx.ID,
-			ProviderReference: "REF-001",
+		tx, err := paymentService.InitiatePayment(ctx, payer.ID.String(), payment.InitiatePaymentRequest{
+			PayeeID:       payee.ID,
+			Amount:        800,
+			PaymentMethod: "cib",
 		})
 		require.NoError(t, err)
-		assert.Equal(t, "completed", confirmed.Status)
+		declined, err := paymentCallback(ctx, fake.CallbackURL(base,
+			paygate.Callback{OrderID: tx.ID.String(), Reference: *tx.ProviderReference, Amount: 800, Status: paygate.StatusFailed}))
+		require.NoError(t, err)
+		assert.Equal(t, "failed", declined.Status)
+
+		// A late "paid" does not resurrect it
+		late, err := paymentCallback(ctx, *tx.CheckoutURL)
+		require.NoError(t, err)
+		assert.Equal(t, "failed", late.Status)
 
-		// Try to confirm again (should fail — not pending anymore)
-		_, err = paymentService.ConfirmPayment(ctx, payer.ID.String(), payment.ConfirmPaymentRequest{
-			TransactionID:     tx.ID,
-			ProviderReference: "REF-002",
+		tx, err = paymentService.InitiatePayment(ctx, payer.ID.String(), payment.InitiatePaymentRequest{
+			PayeeID:       payee.ID,
+			Amount:        800,
+			PaymentMethod: "edahabia",
 		})
-		require.Error(t, err)
-		assert.ErrorIs(t, err, payment.ErrNotPending, "Cannot confirm a completed transaction")
+		require.NoError(t, err)
+		short, err := paymentCallback(ctx, fake.CallbackURL(base,
+			paygate.Callback{OrderID: tx.ID.String(), Reference: *tx.ProviderReference, Amount: 80, Status: paygate.StatusPaid}))
+		require.NoError(t, err)
+		assert.Equal(t, "failed", short.Status)
+		require.NotNil(t, short.FailureReason)
+		assert.Contains(t, *short.FailureReason, "amount mismatch")
 
-		t.Log("✓ ConfirmPayment only works on pending transactions")
+		t.Log("✓ Declined and mismatched payments are marked failed")
 	})
 
 	// ─── Test: Refund only completed transactions ───────────────
@@ -1906,11 +2424,8 @@ func TestPaymentStatusGuards(t *testing.T) {
 		require.Error(t, err)
 		assert.ErrorIs(t, err, payment.ErrNotCompleted, "Cannot refund a pending transaction")
 
-		// Confirm it first
-		_, err = paymentService.ConfirmPayment(ctx, payer.ID.String(), payment.ConfirmPaymentRequest{
-			TransactionID:     tx.ID,
-			ProviderReference: "REF-003",
-		})
+		// Complete it through the gateway first
+		_, err = paymentCallback(ctx, *tx.CheckoutURL)
 		require.NoError(t, err)
 
 		// Now refund should succeed
@@ -1934,8 +2449,8 @@ func TestPaymentStatusGuards(t *testing.T) {
 		t.Log("✓ RefundPayment only works on completed transactions")
 	})
 
-	// ─── Test: Cannot confirm someone else's transaction ────────
-	t.Run("CannotConfirmOthersTransaction", func(t *testing.T) {
+	// ─── Test: Only payer and payee can see a transaction ───────
+	t.Run("GetPaymentOwnership", func(t *testing.T) {
 		tx, err := paymentService.InitiatePayment(ctx, payer.ID.String(), payment.InitiatePaymentRequest{
 			PayeeID:       payee.ID,
 			Amount:        500,
@@ -1943,60 +2458,687 @@ func TestPaymentStatusGuards(t *testing.T) {
 		})
 		require.NoError(t, err)
 
-		// Payee (not payer) tries to confirm
-		_, err = paymentService.ConfirmPayment(ctx, payee.ID.String(), payment.ConfirmPaymentRequest{
-			TransactionID:     tx.ID,
-			ProviderReference: "STOLEN",
-		})
-		require.Error(t, err)
+		_, err = paymentService.GetPayment(ctx, payee.ID.String(), tx.ID.String())
+		require.NoError(t, err)
+
+		_, err = paymentService.GetPayment(ctx, uuid.New().String(), tx.ID.String())
 		assert.ErrorIs(t, err, payment.ErrNotAuthorized)
 
-		t.Log("✓ Cannot confirm another user's transaction")
+		t.Log("✓ Transactions are visible to payer and payee only")
 	})
 }
 
-// ═══════════════════════════════════════════════════════════════
-// Run summary
-// ═══════════════════════════════════════════════════════════════
-// Suite 12: Teacher Wallet & Star System
-// ═══════════════════════════════════════════════════════════════
-
-func TestWallet_GetOrCreateWallet(t *testing.T) {
+func TestSubscriptionLifecycle(t *testing.T) {
 	ctx := context.Background()
-	teacher := createTeacherWithProfile(t, ctx, "WalletTeacher", "One")
 
-	// First call creates the wallet
-	w, err := walletService.GetOrCreateWallet(ctx, teacher.ID.String())
-	require.NoError(t, err)
-	assert.Equal(t, teacher.ID, w.TeacherID)
-	assert.Equal(t, 0.0, w.Balance)
-	assert.Equal(t, 0.0, w.TotalPurchased)
-	assert.Equal(t, 0.0, w.TotalSpent)
-	assert.Equal(t, 0.0, w.TotalRefunded)
+	student := createTestUser(t, ctx, "student", "Sub", "Student")
+	defer cleanupTestUser(t, ctx, student.ID)
+	teacher := createTestUser(t, ctx, "teacher", "Sub", "Teacher")
+	defer cleanupTestUser(t, ctx, teacher.ID)
 
-	// Second call returns same wallet (idempotent)
-	w2, err := walletService.GetOrCreateWallet(ctx, teacher.ID.String())
+	// Subscription periods follow the Algiers calendar
+	algiers, err := time.LoadLocation("Africa/Algiers")
 	require.NoError(t, err)
-	assert.Equal(t, w.ID, w2.ID)
-}
+	now := time.Now().In(algiers)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, algiers)
+	lastMonth := today.AddDate(0, -1, 0)
+
+	// getSubscription reloads a subscription as the student sees it.
+	getSubscription := func(t *testing.T, id uuid.UUID) payment.SubscriptionResponse {
+		t.Helper()
+		subs, err := paymentService.ListSubscriptions(ctx, student.ID.String(), "student")
+		require.NoError(t, err)
+		for _, sub := range subs {
+			if sub.ID == id {
+				return sub
+			}
+		}
+		t.Fatalf("subscription %s not found", id)
+		return payment.SubscriptionResponse{}
+	}
 
-func TestWallet_ListPackages(t *testing.T) {
-	ctx := context.Background()
+	// ─── Test: Ended period renews with a payment ───────────────
+	t.Run("AutoRenew", func(t *testing.T) {
+		sub, err := paymentService.CreateSubscription(ctx, student.ID.String(), payment.CreateSubscriptionRequest{
+			TeacherID:        teacher.ID,
+			PlanType:         "monthly",
+			SessionsPerMonth: 1,
+			Price:            4000,
+			StartDate:        lastMonth.Format("2006-01-02"),
+			EndDate:          today.AddDate(0, 0, -1).Format("2006-01-02"),
+			RenewalMethod:    "edahabia",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, sub.Period)
 
-	pkgs, err := walletService.ListPackages(ctx)
-	require.NoError(t, err)
-	assert.GreaterOrEqual(t, len(pkgs), 4, "Should have at least 4 seeded packages")
+		_, err = paymentService.RunSubscriptionLifecycle(ctx)
+		require.NoError(t, err)
 
-	// Verify ordering
-	assert.Equal(t, "Starter", pkgs[0].Name)
-	assert.Equal(t, 600.0, pkgs[0].Amount)
-	assert.Equal(t, 0.0, pkgs[0].Bonus)
-	assert.Equal(t, 600.0, pkgs[0].TotalCredits)
-	assert.Equal(t, 12, pkgs[0].GroupStars)  // 600/50
-	assert.Equal(t, 8, pkgs[0].PrivateStars) // floor(600/70) = 8
+		renewed := getSubscription(t, sub.ID)
+		assert.Equal(t, "active", renewed.Status)
+		assert.Equal(t, 2, renewed.Period)
+		assert.Equal(t, today.Format("2006-01-02"), renewed.StartDate)
+		require.NotNil(t, renewed.RenewalTransactionID)
 
-	assert.Equal(t, "Premium", pkgs[3].Name)
-	assert.Equal(t, 5000.0, pkgs[3].Amount)
+		txn, err := paymentService.GetPayment(ctx, student.ID.String(), renewed.RenewalTransactionID.String())
+		require.NoError(t, err)
+		assert.Equal(t, "processing", txn.Status, "renewal checkout is opened")
+		assert.Equal(t, 4000.0, txn.Amount)
+		assert.Equal(t, "edahabia", txn.PaymentMethod)
+
+		// Attending a session uses the period's only session...
+		var sessionID uuid.UUID
+		err = testDB.Pool.QueryRow(ctx,
+			`INSERT INTO sessions (teacher_id, title, start_time, end_time, status, price)
+			 VALUES ($1, 'Abonnement', NOW() - INTERVAL '2 hours', NOW() - INTERVAL '1 hour', 'completed', 0)
+			 RETURNING id`, teacher.ID,
+		).Scan(&sessionID)
+		require.NoError(t, err)
+		_, err = testDB.Pool.Exec(ctx,
+			`INSERT INTO session_participants (session_id, student_id, attendance) VALUES ($1, $2, 'present')`,
+			sessionID, student.ID,
+		)
+		require.NoError(t, err)
+
+		n, err := paymentService.RecordSessionUsage(ctx, sessionID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+		n, err = paymentService.RecordSessionUsage(ctx, sessionID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, n, "usage is recorded once per session")
+		assert.Equal(t, 1, getSubscription(t, sub.ID).SessionsUsed)
+
+		// ...after which booking the teacher is refused
+		_, err = bookingService.CreateBookingRequest(ctx, student.ID.String(), "student", booking.CreateBookingRequest{
+			TeacherID:     teacher.ID.String(),
+			SessionType:   "individual",
+			RequestedDate: today.AddDate(0, 0, 1).Format("2006-01-02"),
+			StartTime:     "10:00",
+			EndTime:       "11:00",
+		})
+		assert.ErrorIs(t, err, booking.ErrSubscriptionExhausted)
+
+		// An unpaid renewal expires once the grace period is over
+		_, err = testDB.Pool.Exec(ctx,
+			`UPDATE subscriptions SET renewed_at = NOW() - INTERVAL '4 days' WHERE id = $1`, sub.ID)
+		require.NoError(t, err)
+		_, err = paymentService.RunSubscriptionLifecycle(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "expired", getSubscription(t, sub.ID).Status)
+
+		t.Log("✓ Subscriptions renew, count sessions and expire when unpaid")
+	})
+
+	// ─── Test: A long-lapsed period is not renewed ──────────────
+	t.Run("LapsedAutoRenewExpires", func(t *testing.T) {
+		sub, err := paymentService.CreateSubscription(ctx, student.ID.String(), payment.CreateSubscriptionRequest{
+			TeacherID:        teacher.ID,
+			PlanType:         "monthly",
+			SessionsPerMonth: 4,
+			Price:            4000,
+			StartDate:        lastMonth.AddDate(0, -2, 0).Format("2006-01-02"),
+			EndDate:          lastMonth.AddDate(0, -1, -1).Format("2006-01-02"),
+		})
+		require.NoError(t, err)
+
+		_, err = paymentService.RunSubscriptionLifecycle(ctx)
+		require.NoError(t, err)
+
+		lapsed := getSubscription(t, sub.ID)
+		assert.Equal(t, "expired", lapsed.Status, "the next period is already over")
+		assert.Equal(t, 1, lapsed.Period)
+		assert.Nil(t, lapsed.RenewalTransactionID, "no charge for a period gone by")
+
+		t.Log("✓ A subscription the sweep reaches a period late expires")
+	})
+
+	// ─── Test: An unpaid renewal is not renewed again ───────────
+	t.Run("UnpaidRenewalNotRenewedAgain", func(t *testing.T) {
+		sub, err := paymentService.CreateSubscription(ctx, student.ID.String(), payment.CreateSubscriptionRequest{
+			TeacherID:        teacher.ID,
+			PlanType:         "monthly",
+			SessionsPerMonth: 4,
+			Price:            4000,
+			StartDate:        lastMonth.Format("2006-01-02"),
+			EndDate:          today.AddDate(0, 0, -1).Format("2006-01-02"),
+		})
+		require.NoError(t, err)
+
+		_, err = paymentService.RunSubscriptionLifecycle(ctx)
+		require.NoError(t, err)
+		renewed := getSubscription(t, sub.ID)
+		require.Equal(t, 2, renewed.Period)
+		require.NotNil(t, renewed.RenewalTransactionID)
+
+		// The renewed period is over, its renewal still unpaid
+		_, err = testDB.Pool.Exec(ctx,
+			`UPDATE subscriptions SET end_date = $2 WHERE id = $1`, sub.ID, today.AddDate(0, 0, -1).Format("2006-01-02"))
+		require.NoError(t, err)
+		_, err = paymentService.RunSubscriptionLifecycle(ctx)
+		require.NoError(t, err)
+
+		ended := getSubscription(t, sub.ID)
+		assert.Equal(t, "expired", ended.Status)
+		assert.Equal(t, 2, ended.Period)
+		assert.Equal(t, *renewed.RenewalTransactionID, *ended.RenewalTransactionID, "the pending renewal is not replaced")
+
+		t.Log("✓ Unpaid renewals are not stacked")
+	})
+
+	// ─── Test: Without auto-renew the subscription ends ─────────
+	t.Run("ExpireWithoutAutoRenew", func(t *testing.T) {
+		autoRenew := false
+		sub, err := paymentService.CreateSubscription(ctx, student.ID.String(), payment.CreateSubscriptionRequest{
+			TeacherID:        teacher.ID,
+			PlanType:         "monthly",
+			SessionsPerMonth: 4,
+			Price:            4000,
+			StartDate:        lastMonth.Format("2006-01-02"),
+			EndDate:          today.AddDate(0, 0, -1).Format("2006-01-02"),
+			AutoRenew:        &autoRenew,
+		})
+		require.NoError(t, err)
+
+		_, err = paymentService.RunSubscriptionLifecycle(ctx)
+		require.NoError(t, err)
+
+		expired := getSubscription(t, sub.ID)
+		assert.Equal(t, "expired", expired.Status)
+		assert.Nil(t, expired.RenewalTransactionID)
+
+		t.Log("✓ Subscriptions without auto-renew expire at end_date")
+	})
+
+	// ─── Test: Pause and resume push the end date back ──────────
+	t.Run("PauseResume", func(t *testing.T) {
+		end := today.AddDate(0, 0, 20)
+		sub, err := paymentService.CreateSubscription(ctx, student.ID.String(), payment.CreateSubscriptionRequest{
+			TeacherID:        teacher.ID,
+			PlanType:         "monthly",
+			SessionsPerMonth: 4,
+			Price:            4000,
+			StartDate:        today.AddDate(0, 0, -10).Format("2006-01-02"),
+			EndDate:          end.Format("2006-01-02"),
+		})
+		require.NoError(t, err)
+
+		_, err = paymentService.ResumeSubscription(ctx, student.ID.String(), sub.ID.String())
+		assert.ErrorIs(t, err, payment.ErrNotPaused)
+		_, err = paymentService.PauseSubscription(ctx, teacher.ID.String(), sub.ID.String())
+		assert.ErrorIs(t, err, payment.ErrNotAuthorized)
+
+		paused, err := paymentService.PauseSubscription(ctx, student.ID.String(), sub.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, "paused", paused.Status)
+		_, err = paymentService.PauseSubscription(ctx, student.ID.String(), sub.ID.String())
+		assert.ErrorIs(t, err, payment.ErrNotActive)
+
+		// Paused five days ago
+		_, err = testDB.Pool.Exec(ctx,
+			`UPDATE subscriptions SET paused_at = NOW() - INTERVAL '5 days' WHERE id = $1`, sub.ID)
+		require.NoError(t, err)
+
+		resumed, err := paymentService.ResumeSubscription(ctx, student.ID.String(), sub.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, "active", resumed.Status)
+		assert.Nil(t, resumed.PausedAt)
+		assert.Equal(t, end.AddDate(0, 0, 5).Format("2006-01-02"), resumed.EndDate)
+
+		t.Log("✓ Resuming extends the period by the days spent paused")
+	})
+}
+
+// ─── Bookings count against the subscription's sessions ─────────
+
+func TestSubscriptionBookingQuota(t *testing.T) {
+	ctx := context.Background()
+
+	teacher := createTeacherWithProfile(t, ctx, "Quota", "Teacher")
+	defer cleanupTestUser(t, ctx, teacher.ID)
+	for day := 0; day < 7; day++ {
+		createTeacherAvailability(t, ctx, teacher.ID, day, "08:00", "20:00")
+	}
+	student := createStudentWithProfile(t, ctx, "Quota", "Student", nil)
+	defer cleanupTestUser(t, ctx, student.ID)
+
+	algiers, err := time.LoadLocation("Africa/Algiers")
+	require.NoError(t, err)
+	now := time.Now().In(algiers)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, algiers)
+
+	sub, err := paymentService.CreateSubscription(ctx, student.ID.String(), payment.CreateSubscriptionRequest{
+		TeacherID:        teacher.ID,
+		PlanType:         "monthly",
+		SessionsPerMonth: 2,
+		Price:            4000,
+		StartDate:        today.Format("2006-01-02"),
+		EndDate:          today.AddDate(0, 0, 30).Format("2006-01-02"),
+	})
+	require.NoError(t, err)
+
+	book := func(date time.Time, recurrence *booking.RecurrenceInput) (*booking.BookingRequestResponse, error) {
+		return bookingService.CreateBookingRequest(ctx, student.ID.String(), "student", booking.CreateBookingRequest{
+			TeacherID:     teacher.ID.String(),
+			SessionType:   "individual",
+			RequestedDate: date.Format("2006-01-02"),
+			StartTime:     "10:00",
+			EndTime:       "11:00",
+			Recurrence:    recurrence,
+		})
+	}
+
+	var first *booking.BookingRequestResponse
+
+	// ─── Test: Pending bookings and every occurrence count ──────
+	t.Run("PendingBookings", func(t *testing.T) {
+		first, err = book(today.AddDate(0, 0, 1), nil)
+		require.NoError(t, err)
+
+		weekly := today.AddDate(0, 0, 2)
+		_, err = book(weekly, &booking.RecurrenceInput{Days: []int{int(weekly.Weekday())}, Count: 2})
+		assert.ErrorIs(t, err, booking.ErrSubscriptionExhausted, "two weekly dates on top of a pending booking")
+
+		_, err = book(today.AddDate(0, 0, 2), nil)
+		require.NoError(t, err)
+		_, err = book(today.AddDate(0, 0, 3), nil)
+		assert.ErrorIs(t, err, booking.ErrSubscriptionExhausted)
+
+		t.Log("✓ Pending bookings and recurring dates use up the period")
+	})
+
+	// ─── Test: Accepting checks the period again ────────────────
+	t.Run("RecheckOnAccept", func(t *testing.T) {
+		require.NotNil(t, first)
+		_, err := testDB.Pool.Exec(ctx, `UPDATE subscriptions SET sessions_used = 1 WHERE id = $1`, sub.ID)
+		require.NoError(t, err)
+		_, err = bookingService.AcceptBookingRequest(ctx, first.ID, teacher.ID.String(), booking.AcceptBookingRequest{Price: 2000})
+		assert.ErrorIs(t, err, booking.ErrSubscriptionExhausted, "a session used since the request")
+
+		_, err = testDB.Pool.Exec(ctx, `UPDATE subscriptions SET sessions_used = 0 WHERE id = $1`, sub.ID)
+		require.NoError(t, err)
+		_, err = bookingService.AcceptBookingRequest(ctx, first.ID, teacher.ID.String(), booking.AcceptBookingRequest{Price: 2000})
+		require.NoError(t, err)
+
+		t.Log("✓ Acceptance is refused once the period is used up")
+	})
+
+	// ─── Test: Scheduled sessions count once accepted ───────────
+	t.Run("ScheduledSessions", func(t *testing.T) {
+		_, err := testDB.Pool.Exec(ctx,
+			`UPDATE booking_requests SET status = 'cancelled' WHERE student_id = $1 AND status = 'pending'`, student.ID)
+		require.NoError(t, err)
+
+		_, err = book(today.AddDate(0, 0, 4), nil)
+		require.NoError(t, err, "one scheduled session leaves one")
+		_, err = book(today.AddDate(0, 0, 5), nil)
+		assert.ErrorIs(t, err, booking.ErrSubscriptionExhausted)
+
+		t.Log("✓ The accepted booking counts through its session")
+	})
+}
+
+func TestSessionPackages(t *testing.T) {
+	ctx := context.Background()
+
+	teacher := createTeacherWithProfile(t, ctx, "Pack", "Teacher")
+	defer cleanupTestUser(t, ctx, teacher.ID)
+	for i := 0; i < 7; i++ {
+		createTeacherAvailability(t, ctx, teacher.ID, i, "08:00", "20:00")
+	}
+	offering := createOffering(t, ctx, teacher.ID)
+
+	parent := createParentWithProfile(t, ctx, "Pack", "Parent")
+	defer cleanupTestUser(t, ctx, parent.ID)
+	child := createStudentWithProfile(t, ctx, "Pack", "Child", &parent.ID)
+	defer cleanupTestUser(t, ctx, child.ID)
+	other := createStudentWithProfile(t, ctx, "Pack", "Other", nil)
+	defer cleanupTestUser(t, ctx, other.ID)
+
+	pkg, err := paymentService.CreatePackage(ctx, teacher.ID.String(), payment.CreatePackageRequest{
+		OfferingID:    offering.ID,
+		Name:          "Pack 2 séances",
+		TotalSessions: 2,
+		Price:         3500,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 90, pkg.ValidDays)
+
+	// ─── Test: Only the offering's teacher defines packages ─────
+	t.Run("OwnOfferingOnly", func(t *testing.T) {
+		_, err := paymentService.CreatePackage(ctx, other.ID.String(), payment.CreatePackageRequest{
+			OfferingID: offering.ID, Name: "Pirate", TotalSessions: 1, Price: 100,
+		})
+		assert.ErrorIs(t, err, payment.ErrNotAuthorized)
+
+		_, err = paymentService.UpdatePackage(ctx, other.ID.String(), pkg.ID.String(), payment.UpdatePackageRequest{})
+		assert.ErrorIs(t, err, payment.ErrNotAuthorized)
+
+		t.Log("✓ Packages belong to the offering's teacher")
+	})
+
+	// ─── Test: Parent buys for a child, bookings use it ─────────
+	t.Run("PurchaseAndConsume", func(t *testing.T) {
+		_, err := paymentService.PurchasePackage(ctx, parent.ID.String(), "parent", pkg.ID.String(), payment.PurchasePackageRequest{
+			PaymentMethod: "edahabia",
+		})
+		assert.ErrorIs(t, err, payment.ErrChildRequired)
+		_, err = paymentService.PurchasePackage(ctx, parent.ID.String(), "parent", pkg.ID.String(), payment.PurchasePackageRequest{
+			PaymentMethod: "edahabia",
+			ForChildID:    &other.ID,
+		})
+		assert.ErrorIs(t, err, payment.ErrNotAuthorized)
+
+		purchase, err := paymentService.PurchasePackage(ctx, parent.ID.String(), "parent", pkg.ID.String(), payment.PurchasePackageRequest{
+			PaymentMethod: "edahabia",
+			ForChildID:    &child.ID,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "pending", purchase.Status)
+		assert.Equal(t, child.ID, purchase.StudentID)
+		require.NotNil(t, purchase.CheckoutURL)
+
+		// Paying activates it
+		_, err = paymentCallback(ctx, *purchase.CheckoutURL)
+		require.NoError(t, err)
+
+		balance, err := paymentService.ListPackagePurchases(ctx, child.ID.String(), "student")
+		require.NoError(t, err)
+		require.Len(t, balance, 1)
+		assert.Equal(t, "active", balance[0].Status)
+		assert.Equal(t, 2, balance[0].SessionsRemaining)
+		assert.Nil(t, balance[0].CheckoutURL)
+
+		// Each accepted booking uses one session, until none are left
+		nextMonday := getNextWeekday(time.Monday)
+		accept := func(start, end string) *booking.BookingRequestResponse {
+			created, err := bookingService.CreateBookingRequest(ctx, parent.ID.String(), "parent", booking.CreateBookingRequest{
+				TeacherID:     teacher.ID.String(),
+				OfferingID:    offering.ID.String(),
+				SessionType:   "individual",
+				RequestedDate: nextMonday.Format("2006-01-02"),
+				StartTime:     start,
+				EndTime:       end,
+				ForChildID:    child.ID.String(),
+			})
+			require.NoError(t, err)
+			accepted, err := bookingService.AcceptBookingRequest(ctx, created.ID, teacher.ID.String(), booking.AcceptBookingRequest{Price: 2000})
+			require.NoError(t, err)
+			return accepted
+		}
+		for _, slot := range [][2]string{{"09:00", "10:00"}, {"11:00", "12:00"}} {
+			accepted := accept(slot[0], slot[1])
+			require.NotNil(t, accepted.PackagePurchaseID)
+			assert.Equal(t, purchase.ID.String(), *accepted.PackagePurchaseID)
+		}
+		assert.Nil(t, accept("14:00", "15:00").PackagePurchaseID, "used-up package pays nothing more")
+
+		balance, err = paymentService.ListPackagePurchases(ctx, parent.ID.String(), "parent")
+		require.NoError(t, err)
+		require.Len(t, balance, 1)
+		assert.Equal(t, 0, balance[0].SessionsRemaining)
+		assert.Equal(t, 2, balance[0].SessionsUsed)
+
+		t.Log("✓ Paid packages cover accepted bookings one session at a time")
+	})
+
+	// ─── Test: Expired and withdrawn packages ───────────────────
+	t.Run("Expiry", func(t *testing.T) {
+		purchase, err := paymentService.PurchasePackage(ctx, other.ID.String(), "student", pkg.ID.String(), payment.PurchasePackageRequest{
+			PaymentMethod: "cib",
+		})
+		require.NoError(t, err)
+		_, err = paymentCallback(ctx, *purchase.CheckoutURL)
+		require.NoError(t, err)
+
+		_, err = testDB.Pool.Exec(ctx,
+			`UPDATE package_purchases SET expires_at = NOW() - INTERVAL '1 minute' WHERE id = $1`, purchase.ID)
+		require.NoError(t, err)
+		n, err := paymentService.ExpirePackagePurchases(ctx)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, n, 1)
+
+		balance, err := paymentService.ListPackagePurchases(ctx, other.ID.String(), "student")
+		require.NoError(t, err)
+		require.Len(t, balance, 1)
+		assert.Equal(t, "expired", balance[0].Status)
+
+		require.NoError(t, paymentService.DeactivatePackage(ctx, teacher.ID.String(), pkg.ID.String()))
+		_, err = paymentService.PurchasePackage(ctx, other.ID.String(), "student", pkg.ID.String(), payment.PurchasePackageRequest{
+			PaymentMethod: "cib",
+		})
+		assert.ErrorIs(t, err, payment.ErrPackageInactive)
+		onSale, err := paymentService.ListPackages(ctx, teacher.ID.String(), true)
+		require.NoError(t, err)
+		assert.Empty(t, onSale)
+
+		t.Log("✓ Packages expire and can be withdrawn from sale")
+	})
+}
+
+func TestPromotions(t *testing.T) {
+	ctx := context.Background()
+	promotionService := promotion.NewService(testDB)
+
+	teacher := createTeacherWithProfile(t, ctx, "Promo", "Teacher")
+	defer cleanupTestUser(t, ctx, teacher.ID)
+	for i := 0; i < 7; i++ {
+		createTeacherAvailability(t, ctx, teacher.ID, i, "08:00", "20:00")
+	}
+	offering := createOffering(t, ctx, teacher.ID)
+
+	student := createStudentWithProfile(t, ctx, "Promo", "Student", nil)
+	defer cleanupTestUser(t, ctx, student.ID)
+	other := createStudentWithProfile(t, ctx, "Promo", "Other", nil)
+	defer cleanupTestUser(t, ctx, other.ID)
+
+	now := time.Now()
+	code := "RENTREE" + strings.ToUpper(uuid.NewString()[:6])
+	promo, err := promotionService.CreatePromotion(ctx, teacher.ID.String(), promotion.CreatePromotionRequest{
+		Name:            "Rentrée -15%",
+		Code:            strings.ToLower(code),
+		Type:            promotion.TypePercentage,
+		Value:           15,
+		OfferingID:      &offering.ID,
+		StartDate:       now.Add(-time.Hour),
+		EndDate:         now.AddDate(0, 1, 0),
+		PerStudentLimit: intPtr(1),
+	})
+	require.NoError(t, err)
+	require.NotNil(t, promo.Code)
+	assert.Equal(t, code, *promo.Code, "codes are stored in upper case")
+
+	// ─── Test: Codes are unique and rules are checked ───────────
+	t.Run("Validation", func(t *testing.T) {
+		_, err := promotionService.CreatePromotion(ctx, teacher.ID.String(), promotion.CreatePromotionRequest{
+			Name: "Doublon", Code: code, Type: promotion.TypeFixed, Value: 100,
+			StartDate: now, EndDate: now.AddDate(0, 0, 7),
+		})
+		assert.ErrorIs(t, err, promotion.ErrCodeTaken)
+
+		_, err = promotionService.CreatePromotion(ctx, teacher.ID.String(), promotion.CreatePromotionRequest{
+			Name: "Trop", Type: promotion.TypePercentage, Value: 100,
+			StartDate: now, EndDate: now.AddDate(0, 0, 7),
+		})
+		assert.ErrorIs(t, err, promotion.ErrInvalidPromotion)
+
+		_, err = promotionService.CreatePromotion(ctx, other.ID.String(), promotion.CreatePromotionRequest{
+			Name: "Pirate", Type: promotion.TypeFixed, Value: 100, OfferingID: &offering.ID,
+			StartDate: now, EndDate: now.AddDate(0, 0, 7),
+		})
+		assert.ErrorIs(t, err, promotion.ErrNotAuthorized)
+
+		t.Log("✓ Promotions are validated on creation")
+	})
+
+	// ─── Test: A code is checked at booking, redeemed at payment ─
+	t.Run("BookingThenPayment", func(t *testing.T) {
+		nextMonday := getNextWeekday(time.Monday)
+		_, err := bookingService.CreateBookingRequest(ctx, student.ID.String(), "student", booking.CreateBookingRequest{
+			TeacherID:     teacher.ID.String(),
+			OfferingID:    offering.ID.String(),
+			SessionType:   "individual",
+			RequestedDate: nextMonday.Format("2006-01-02"),
+			StartTime:     "09:00",
+			EndTime:       "10:00",
+			PromoCode:     "NOSUCHCODE",
+		})
+		assert.True(t, promotion.IsRefusal(err))
+
+		created, err := bookingService.CreateBookingRequest(ctx, student.ID.String(), "student", booking.CreateBookingRequest{
+			TeacherID:     teacher.ID.String(),
+			OfferingID:    offering.ID.String(),
+			SessionType:   "individual",
+			RequestedDate: nextMonday.Format("2006-01-02"),
+			StartTime:     "09:00",
+			EndTime:       "10:00",
+			PromoCode:     strings.ToLower(code),
+		})
+		require.NoError(t, err)
+		require.NotNil(t, created.PromoCode)
+		assert.Equal(t, code, *created.PromoCode)
+
+		accepted, err := bookingService.AcceptBookingRequest(ctx, created.ID, teacher.ID.String(), booking.AcceptBookingRequest{Price: 2000})
+		require.NoError(t, err)
+		require.NotNil(t, accepted.SessionID)
+		sessionID := uuid.MustParse(*accepted.SessionID)
+
+		// Paying for the session redeems the code given at booking
+		txn, err := paymentService.InitiatePayment(ctx, student.ID.String(), payment.InitiatePaymentRequest{
+			PayeeID:       teacher.ID,
+			SessionID:     &sessionID,
+			Amount:        2000,
+			PaymentMethod: "cib",
+		})
+		require.NoError(t, err)
+		require.NotNil(t, txn.PromotionID)
+		assert.Equal(t, promo.ID, *txn.PromotionID)
+		assert.InDelta(t, 300, txn.DiscountAmount, 0.001)
+		assert.InDelta(t, 1700, txn.Amount, 0.001)
+		assert.InDelta(t, 255, txn.Commission, 0.001)
+
+		// Once per student
+		_, err = promotionService.Quote(ctx, student.ID.String(), promotion.QuoteRequest{
+			Code: code, TeacherID: teacher.ID, OfferingID: &offering.ID, Amount: 2000,
+		})
+		assert.ErrorIs(t, err, promotion.ErrStudentLimit)
+
+		// A declined payment gives the use back
+		fake := paygate.NewFakeGateway(testPaymentSecret)
+		_, err = paymentCallback(ctx, fake.CallbackURL("http://localhost:8080/api/v1/webhooks/payments/fake",
+			paygate.Callback{OrderID: txn.ID.String(), Reference: *txn.ProviderReference, Amount: txn.Amount, Status: paygate.StatusFailed}))
+		require.NoError(t, err)
+		quote, err := promotionService.Quote(ctx, student.ID.String(), promotion.QuoteRequest{
+			Code: code, TeacherID: teacher.ID, OfferingID: &offering.ID, Amount: 2000,
+		})
+		require.NoError(t, err)
+		assert.InDelta(t, 1700, quote.FinalAmount, 0.001)
+
+		t.Log("✓ Booking codes are redeemed when the session is paid")
+	})
+
+	// ─── Test: Package promotions ───────────────────────────────
+	t.Run("Packages", func(t *testing.T) {
+		pkg, err := paymentService.CreatePackage(ctx, teacher.ID.String(), payment.CreatePackageRequest{
+			OfferingID:    offering.ID,
+			Name:          "Pack 4 séances",
+			TotalSessions: 4,
+			Price:         7000,
+		})
+		require.NoError(t, err)
+
+		// Automatic: 1 session free for every 2 bought
+		_, err = promotionService.CreatePromotion(ctx, teacher.ID.String(), promotion.CreatePromotionRequest{
+			Name: "2 achetées, 1 offerte", Type: promotion.TypeBuyXGetY,
+			BuyQuantity: intPtr(2), FreeQuantity: intPtr(1),
+			StartDate: now.Add(-time.Hour), EndDate: now.AddDate(0, 1, 0),
+		})
+		require.NoError(t, err)
+
+		purchase, err := paymentService.PurchasePackage(ctx, other.ID.String(), "student", pkg.ID.String(), payment.PurchasePackageRequest{
+			PaymentMethod: "edahabia",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 6, purchase.TotalSessions)
+		require.NotNil(t, purchase.TransactionID)
+		txn, err := paymentService.GetPayment(ctx, other.ID.String(), purchase.TransactionID.String())
+		require.NoError(t, err)
+		assert.InDelta(t, 7000, txn.Amount, 0.001)
+		require.NotNil(t, txn.PromotionID)
+
+		// A code takes precedence over the automatic promotion
+		purchase, err = paymentService.PurchasePackage(ctx, other.ID.String(), "student", pkg.ID.String(), payment.PurchasePackageRequest{
+			PaymentMethod: "edahabia",
+			PromoCode:     code,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 4, purchase.TotalSessions)
+		txn, err = paymentService.GetPayment(ctx, other.ID.String(), purchase.TransactionID.String())
+		require.NoError(t, err)
+		assert.InDelta(t, 5950, txn.Amount, 0.001)
+		assert.InDelta(t, 1050, txn.DiscountAmount, 0.001)
+
+		promotions, err := promotionService.ListPromotions(ctx, teacher.ID.String())
+		require.NoError(t, err)
+		require.Len(t, promotions, 2)
+		for _, p := range promotions {
+			assert.Equal(t, 1, p.UsageCount, p.Name)
+		}
+
+		t.Log("✓ Packages take free sessions or a discounted price")
+	})
+}
+
+func intPtr(n int) *int { return &n }
+
+const testPaymentSecret = "test-payment-secret"
+
+// paymentCallback delivers a fake-gateway callback, as the payer's browser would.
+func paymentCallback(ctx context.Context, url string) (*payment.TransactionResponse, error) {
+	return paymentService.HandleCallback(ctx, paygate.Fake, httptest.NewRequest(http.MethodGet, url, nil))
+}
+
+// ═══════════════════════════════════════════════════════════════
+// Run summary
+// ═══════════════════════════════════════════════════════════════
+// Suite 12: Teacher Wallet & Star System
+// ═══════════════════════════════════════════════════════════════
+
+func TestWallet_GetOrCreateWallet(t *testing.T) {
+	ctx := context.Background()
+	teacher := createTeacherWithProfile(t, ctx, "WalletTeacher", "One")
+
+	// First call creates the wallet
+	w, err := walletService.GetOrCreateWallet(ctx, teacher.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, teacher.ID, w.TeacherID)
+	assert.Equal(t, 0.0, w.Balance)
+	assert.Equal(t, 0.0, w.TotalPurchased)
+	assert.Equal(t, 0.0, w.TotalSpent)
+	assert.Equal(t, 0.0, w.TotalRefunded)
+
+	// Second call returns same wallet (idempotent)
+	w2, err := walletService.GetOrCreateWallet(ctx, teacher.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, w.ID, w2.ID)
+}
+
+func TestWallet_ListPackages(t *testing.T) {
+	ctx := context.Background()
+
+	pkgs, err := walletService.ListPackages(ctx)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, len(pkgs), 4, "Should have at least 4 seeded packages")
+
+	// Verify ordering
+	assert.Equal(t, "Starter", pkgs[0].Name)
+	assert.Equal(t, 600.0, pkgs[0].Amount)
+	assert.Equal(t, 0.0, pkgs[0].Bonus)
+	assert.Equal(t, 600.0, pkgs[0].TotalCredits)
+	assert.Equal(t, 12, pkgs[0].GroupStars)  // 600/50
+	assert.Equal(t, 8, pkgs[0].PrivateStars) // floor(600/70) = 8
+
+	assert.Equal(t, "Premium", pkgs[3].Name)
+	assert.Equal(t, 5000.0, pkgs[3].Amount)
 	assert.Equal(t, 400.0, pkgs[3].Bonus)
 	assert.Equal(t, 5400.0, pkgs[3].TotalCredits)
 }
@@ -2023,11 +3165,13 @@ func TestWallet_BuyCredits_PendingUntilApproved(t *testing.T) {
 	assert.Equal(t, "pending", tx.Status)
 	assert.Equal(t, 600.0, tx.Amount)
 
-	// Balance should still be 0
+	// Balance should still be 0, and nothing is in the books yet
 	w, _ := walletService.GetOrCreateWallet(ctx, teacher.ID.String())
 	assert.Equal(t, 0.0, w.Balance)
+	assert.Empty(t, purchaseJournals(t, ctx, tx.ID))
 
 	// Admin approves
+	attachTestReceipt(t, ctx, tx.ID)
 	approved, err := walletService.AdminApprovePurchase(ctx, tx.ID.String(), admin.ID.String(), true, "Payment verified")
 	require.NoError(t, err)
 	assert.Equal(t, "completed", approved.Status)
@@ -2038,6 +3182,33 @@ func TestWallet_BuyCredits_PendingUntilApproved(t *testing.T) {
 	w, _ = walletService.GetOrCreateWallet(ctx, teacher.ID.String())
 	assert.Equal(t, 600.0, w.Balance)
 	assert.Equal(t, 600.0, w.TotalPurchased)
+
+	// The price paid reaches cash on approval
+	assert.Equal(t, map[string]float64{"purchase_approved": starterPkg.Amount}, purchaseJournals(t, ctx, tx.ID))
+}
+
+// purchaseJournals sums, per journal kind, what a purchase's journals
+// posted to cash.
+func purchaseJournals(t *testing.T, ctx context.Context, txID uuid.UUID) map[string]float64 {
+	t.Helper()
+	rows, err := testDB.Pool.Query(ctx,
+		`SELECT j.kind, COALESCE(SUM(p.amount) FILTER (WHERE p.account_code = 'cash'), 0)::float8
+		 FROM ledger_journals j
+		 JOIN ledger_postings p ON p.journal_id = j.id
+		 WHERE j.reference_type = 'wallet_transaction' AND j.reference_id = $1
+		 GROUP BY j.kind`, txID,
+	)
+	require.NoError(t, err)
+	defer rows.Close()
+	journals := map[string]float64{}
+	for rows.Next() {
+		var kind string
+		var cash float64
+		require.NoError(t, rows.Scan(&kind, &cash))
+		journals[kind] = cash
+	}
+	require.NoError(t, rows.Err())
+	return journals
 }
 
 func TestWallet_BuyCredits_AdminReject(t *testing.T) {
@@ -2058,9 +3229,10 @@ func TestWallet_BuyCredits_AdminReject(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "failed", rejected.Status)
 
-	// Balance stays 0
+	// Balance stays 0, with nothing to compensate in the books
 	w, _ := walletService.GetOrCreateWallet(ctx, teacher.ID.String())
 	assert.Equal(t, 0.0, w.Balance)
+	assert.Empty(t, purchaseJournals(t, ctx, tx.ID))
 }
 
 func TestWallet_StarDeduction_OnAcceptRequest(t *testing.T) {
@@ -2076,6 +3248,7 @@ func TestWallet_StarDeduction_OnAcceptRequest(t *testing.T) {
 		PaymentMethod: "ccp_baridimob",
 		ProviderRef:   "STAR-TEST-001",
 	})
+	attachTestReceipt(t, ctx, tx.ID)
 	walletService.AdminApprovePurchase(ctx, tx.ID.String(), admin.ID.String(), true, "ok")
 
 	// Create a group series
@@ -2117,6 +3290,7 @@ func TestWallet_StarDeduction_OnAcceptInvitation(t *testing.T) {
 		PaymentMethod: "ccp_baridimob",
 		ProviderRef:   "INV-TEST-001",
 	})
+	attachTestReceipt(t, ctx, tx.ID)
 	walletService.AdminApprovePurchase(ctx, tx.ID.String(), admin.ID.String(), true, "ok")
 
 	// Create private series
@@ -2149,10 +3323,11 @@ func TestWallet_StarDeduction_OnAcceptInvitation(t *testing.T) {
 	assert.Equal(t, 950.0, w.Balance)
 }
 
-func TestWallet_InsufficientBalance_RejectsAccept(t *testing.T) {
+func TestWallet_InsufficientBalance_ParksAccept(t *testing.T) {
 	ctx := context.Background()
 	teacher := createTeacherWithProfile(t, ctx, "WalletPoor", "Teacher")
 	student := createStudentWithProfile(t, ctx, "WalletPoor", "Student", nil)
+	admin := createTestUser(t, ctx, "admin", "Admin", "Poor")
 
 	// No credits added — wallet balance = 0
 
@@ -2168,10 +3343,45 @@ func TestWallet_InsufficientBalance_RejectsAccept(t *testing.T) {
 	// Student requests
 	enr, _ := seriesService.RequestToJoin(ctx, series.ID.String(), student.ID.String())
 
-	// Teacher tries to accept — should fail with insufficient balance
-	_, err = seriesService.AcceptRequest(ctx, series.ID.String(), enr.ID.String(), teacher.ID.String())
-	require.Error(t, err)
-	assert.ErrorIs(t, err, wallet.ErrInsufficientBalance)
+	// Teacher accepts without credit — parked, nothing deducted
+	parked, err := seriesService.AcceptRequest(ctx, series.ID.String(), enr.ID.String(), teacher.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "awaiting_credit", parked.Status)
+	assert.NotNil(t, parked.AwaitingCreditAt)
+
+	var queued int
+	_ = testDB.Pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM event_outbox WHERE event_type = $1 AND envelope->'data'->>'enrollment_id' = $2`,
+		events.TypeEnrollmentAwaitingCredit, enr.ID.String()).Scan(&queued)
+	assert.Equal(t, 1, queued)
+
+	// Nothing to resume while the wallet is empty
+	n, err := seriesService.ResumeAwaitingCredit(ctx, teacher.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	// Top-up approved → the parked enrollment is accepted and charged
+	pkgs, _ := walletService.ListPackages(ctx)
+	tx, err := walletService.BuyCredits(ctx, teacher.ID.String(), wallet.BuyCreditsRequest{
+		PackageID:     pkgs[0].ID,
+		PaymentMethod: "ccp_baridimob",
+		ProviderRef:   "POOR-" + teacher.ID.String()[:8],
+	})
+	require.NoError(t, err)
+	attachTestReceipt(t, ctx, tx.ID)
+	_, err = walletService.AdminApprovePurchase(ctx, tx.ID.String(), admin.ID.String(), true, "ok")
+	require.NoError(t, err)
+
+	n, err = seriesService.ResumeAwaitingCredit(ctx, teacher.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	series, err = seriesService.GetSeries(ctx, series.ID.String(), teacher.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, 1, series.EnrolledCount)
+
+	w, _ := walletService.GetOrCreateWallet(ctx, teacher.ID.String())
+	assert.Equal(t, 550.0, w.Balance)
 }
 
 func TestWallet_RefundStar_BeforeFirstSession(t *testing.T) {
@@ -2187,6 +3397,7 @@ func TestWallet_RefundStar_BeforeFirstSession(t *testing.T) {
 		PaymentMethod: "ccp_baridimob",
 		ProviderRef:   "REFUND-TEST-001",
 	})
+	attachTestReceipt(t, ctx, tx.ID)
 	walletService.AdminApprovePurchase(ctx, tx.ID.String(), admin.ID.String(), true, "ok")
 
 	// Create group series (no sessions added yet)
@@ -2228,6 +3439,7 @@ func TestWallet_NoRefund_AfterFirstSession(t *testing.T) {
 		PaymentMethod: "ccp_baridimob",
 		ProviderRef:   "NOREF-TEST-001",
 	})
+	attachTestReceipt(t, ctx, tx.ID)
 	walletService.AdminApprovePurchase(ctx, tx.ID.String(), admin.ID.String(), true, "ok")
 
 	// Create series + add a session
@@ -2273,6 +3485,67 @@ func TestWallet_NoRefund_AfterFirstSession(t *testing.T) {
 	assert.Equal(t, 550.0, w.Balance)
 }
 
+func TestWallet_ProRatedRefund_OnWithdraw(t *testing.T) {
+	ctx := context.Background()
+	teacher := createTeacherWithProfile(t, ctx, "WalletProRata", "Teacher")
+	student := createStudentWithProfile(t, ctx, "WalletProRata", "Student", nil)
+	admin := createTestUser(t, ctx, "admin", "Admin", "ProRata")
+	fundTeacherWallet(t, ctx, teacher.ID)
+
+	series, err := seriesService.CreateSeries(ctx, teacher.ID.String(), sessionseries.CreateSeriesRequest{
+		Title:         "Pro Rata Series",
+		SessionType:   "group",
+		DurationHours: 1,
+		MaxStudents:   10,
+	})
+	require.NoError(t, err)
+
+	var dates []sessionseries.SessionDateInput
+	for i := 1; i <= 4; i++ {
+		dates = append(dates, sessionseries.SessionDateInput{StartTime: time.Now().Add(time.Duration(i) * 24 * time.Hour).Format(time.RFC3339)})
+	}
+	_, err = seriesService.AddSessions(ctx, series.ID.String(), teacher.ID.String(), sessionseries.AddSessionsRequest{Sessions: dates})
+	require.NoError(t, err)
+
+	enr, err := seriesService.RequestToJoin(ctx, series.ID.String(), student.ID.String())
+	require.NoError(t, err)
+	_, err = seriesService.AcceptRequest(ctx, series.ID.String(), enr.ID.String(), teacher.ID.String())
+	require.NoError(t, err)
+
+	// First of four sessions held
+	_, err = testDB.Pool.Exec(ctx,
+		`UPDATE sessions SET status = 'completed', actual_start = NOW()
+		 WHERE series_id = $1 AND session_number = 1`, series.ID)
+	require.NoError(t, err)
+
+	// Student leaves: 3/4 of the 50 DZD star comes back
+	err = seriesService.Withdraw(ctx, series.ID.String(), student.ID.String(), "")
+	require.NoError(t, err)
+
+	w, _ := walletService.GetOrCreateWallet(ctx, teacher.ID.String())
+	assert.Equal(t, 5400.0-50+37.5, w.Balance)
+
+	refunds, _, err := walletService.ListTransactions(ctx, teacher.ID.String(), "refund", 1, 10)
+	require.NoError(t, err)
+	require.Len(t, refunds, 1)
+	assert.Equal(t, 37.5, refunds[0].Amount)
+	assert.Equal(t, wallet.RefundProRated, refunds[0].RefundRule)
+	assert.NotNil(t, refunds[0].RefundOf)
+
+	// Admin refunds the rest; nothing is left after that
+	tx, err := walletService.AdminRefundStar(ctx, admin.ID.String(), wallet.AdminRefundRequest{EnrollmentID: enr.ID, Notes: "goodwill"})
+	require.NoError(t, err)
+	assert.Equal(t, 12.5, tx.Amount)
+	assert.Equal(t, wallet.RefundAdmin, tx.RefundRule)
+
+	_, err = walletService.AdminRefundStar(ctx, admin.ID.String(), wallet.AdminRefundRequest{EnrollmentID: enr.ID, Amount: 1, Notes: "again"})
+	assert.ErrorIs(t, err, wallet.ErrRefundExceedsCharge)
+
+	// Withdrawing twice finds no live enrollment
+	err = seriesService.Withdraw(ctx, series.ID.String(), student.ID.String(), "")
+	assert.ErrorIs(t, err, sessionseries.ErrEnrollmentNotFound)
+}
+
 func TestWallet_TransactionHistory(t *testing.T) {
 	ctx := context.Background()
 	teacher := createTeacherWithProfile(t, ctx, "WalletHist", "Teacher")
@@ -2285,6 +3558,7 @@ func TestWallet_TransactionHistory(t *testing.T) {
 		PaymentMethod: "ccp_baridimob",
 		ProviderRef:   "HIST-001",
 	})
+	attachTestReceipt(t, ctx, tx1.ID)
 	walletService.AdminApprovePurchase(ctx, tx1.ID.String(), admin.ID.String(), true, "ok")
 
 	tx2, _ := walletService.BuyCredits(ctx, teacher.ID.String(), wallet.BuyCreditsRequest{
@@ -2292,6 +3566,7 @@ func TestWallet_TransactionHistory(t *testing.T) {
 		PaymentMethod: "edahabia",
 		ProviderRef:   "HIST-002",
 	})
+	attachTestReceipt(t, ctx, tx2.ID)
 	walletService.AdminApprovePurchase(ctx, tx2.ID.String(), admin.ID.String(), true, "ok")
 
 	// List all transactions
@@ -2344,6 +3619,7 @@ func TestWallet_DoubleApprove_ReturnsError(t *testing.T) {
 	})
 
 	// First approve succeeds
+	attachTestReceipt(t, ctx, tx.ID)
 	_, err := walletService.AdminApprovePurchase(ctx, tx.ID.String(), admin.ID.String(), true, "ok")
 	require.NoError(t, err)
 
@@ -2353,6 +3629,544 @@ func TestWallet_DoubleApprove_ReturnsError(t *testing.T) {
 	assert.ErrorIs(t, err, wallet.ErrAlreadyProcessed)
 }
 
+func TestWallet_ApproveRequiresReceipt(t *testing.T) {
+	ctx := context.Background()
+	teacher := createTeacherWithProfile(t, ctx, "WalletRcpt", "Teacher")
+	admin := createTestUser(t, ctx, "admin", "Admin", "Rcpt")
+
+	pkgs, _ := walletService.ListPackages(ctx)
+	tx, err := walletService.BuyCredits(ctx, teacher.ID.String(), wallet.BuyCreditsRequest{
+		PackageID:     pkgs[0].ID,
+		PaymentMethod: "ccp_baridimob",
+		ProviderRef:   "RCPT-001",
+	})
+	require.NoError(t, err)
+	assert.False(t, tx.HasReceipt)
+	require.NotNil(t, tx.ExpiresAt)
+
+	_, err = walletService.AdminApprovePurchase(ctx, tx.ID.String(), admin.ID.String(), true, "blind")
+	assert.ErrorIs(t, err, wallet.ErrReceiptRequired)
+
+	// Rejecting does not need one
+	rejected, err := walletService.AdminApprovePurchase(ctx, tx.ID.String(), admin.ID.String(), false, "no receipt")
+	require.NoError(t, err)
+	assert.Equal(t, "failed", rejected.Status)
+}
+
+func TestWallet_DuplicateProviderRef(t *testing.T) {
+	ctx := context.Background()
+	teacher := createTeacherWithProfile(t, ctx, "WalletDup", "Teacher")
+	other := createTeacherWithProfile(t, ctx, "WalletDup", "Other")
+	admin := createTestUser(t, ctx, "admin", "Admin", "Dup")
+
+	pkgs, _ := walletService.ListPackages(ctx)
+	ref := "DUP-" + teacher.ID.String()[:8]
+	first, err := walletService.BuyCredits(ctx, teacher.ID.String(), wallet.BuyCreditsRequest{
+		PackageID:     pkgs[0].ID,
+		PaymentMethod: "ccp_baridimob",
+		ProviderRef:   ref,
+	})
+	require.NoError(t, err)
+
+	// Same transfer, differently formatted, from another teacher
+	_, err = walletService.BuyCredits(ctx, other.ID.String(), wallet.BuyCreditsRequest{
+		PackageID:     pkgs[0].ID,
+		PaymentMethod: "ccp_baridimob",
+		ProviderRef:   " " + strings.ToLower(strings.ReplaceAll(ref, "-", " ")),
+	})
+	assert.ErrorIs(t, err, wallet.ErrDuplicateReference)
+
+	// Once the first is rejected the reference is free again, and the
+	// admin list points at the earlier claim
+	_, err = walletService.AdminApprovePurchase(ctx, first.ID.String(), admin.ID.String(), false, "wrong amount")
+	require.NoError(t, err)
+	second, err := walletService.BuyCredits(ctx, other.ID.String(), wallet.BuyCreditsRequest{
+		PackageID:     pkgs[0].ID,
+		PaymentMethod: "ccp_baridimob",
+		ProviderRef:   ref,
+	})
+	require.NoError(t, err)
+
+	pending, _, err := walletService.AdminListPendingPurchases(ctx, 1, 50)
+	require.NoError(t, err)
+	for _, p := range pending {
+		if p.ID == second.ID {
+			assert.Equal(t, []uuid.UUID{first.ID}, p.DuplicateOf)
+		}
+	}
+}
+
+func TestWallet_ExpireStalePurchases(t *testing.T) {
+	ctx := context.Background()
+	teacher := createTeacherWithProfile(t, ctx, "WalletExp", "Teacher")
+
+	pkgs, _ := walletService.ListPackages(ctx)
+	tx, err := walletService.BuyCredits(ctx, teacher.ID.String(), wallet.BuyCreditsRequest{
+		PackageID:     pkgs[0].ID,
+		PaymentMethod: "ccp_baridimob",
+		ProviderRef:   "EXP-" + teacher.ID.String()[:8],
+	})
+	require.NoError(t, err)
+
+	_, err = testDB.Pool.Exec(ctx,
+		`UPDATE wallet_transactions SET created_at = $1 WHERE id = $2`,
+		time.Now().Add(-wallet.PurchaseExpiry-time.Hour), tx.ID)
+	require.NoError(t, err)
+
+	n, err := walletService.ExpireStalePurchases(ctx)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, n, 1)
+
+	txs, _, err := walletService.ListTransactions(ctx, teacher.ID.String(), "purchase", 1, 10)
+	require.NoError(t, err)
+	require.Len(t, txs, 1)
+	assert.Equal(t, "expired", txs[0].Status)
+	assert.Nil(t, txs[0].ExpiresAt)
+}
+
+func TestWallet_StarPriceWilayaOverride(t *testing.T) {
+	ctx := context.Background()
+	teacher := createTeacherWithProfile(t, ctx, "WalletPrice", "Teacher")
+	student := createStudentWithProfile(t, ctx, "WalletPrice", "Student", nil)
+	admin := createTestUser(t, ctx, "admin", "Admin", "Price")
+
+	wilaya := "Test-" + teacher.ID.String()[:8]
+	_, err := testDB.Pool.Exec(ctx, `UPDATE users SET wilaya = $1 WHERE id = $2`, wilaya, teacher.ID)
+	require.NoError(t, err)
+	fundTeacherWallet(t, ctx, teacher.ID)
+
+	override, err := walletService.AdminCreateStarPrice(ctx, admin.ID.String(), wallet.CreateStarPriceRequest{
+		SessionType: "group",
+		Wilaya:      wilaya,
+		Price:       40,
+	})
+	require.NoError(t, err)
+
+	series, err := seriesService.CreateSeries(ctx, teacher.ID.String(), sessionseries.CreateSeriesRequest{
+		Title:         "Physique Groupe",
+		SessionType:   "group",
+		DurationHours: 2,
+		MaxStudents:   10,
+		PricePerHour:  500,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 40.0, series.StarCost)
+
+	enr, err := seriesService.RequestToJoin(ctx, series.ID.String(), student.ID.String())
+	require.NoError(t, err)
+	_, err = seriesService.AcceptRequest(ctx, series.ID.String(), enr.ID.String(), teacher.ID.String())
+	require.NoError(t, err)
+
+	w, _ := walletService.GetOrCreateWallet(ctx, teacher.ID.String())
+	assert.Equal(t, 5360.0, w.Balance)
+
+	txs, _, err := walletService.ListTransactions(ctx, teacher.ID.String(), "star_deduction", 1, 10)
+	require.NoError(t, err)
+	require.Len(t, txs, 1)
+	assert.Equal(t, 40.0, txs[0].Amount)
+	require.NotNil(t, txs[0].StarPriceVersion)
+	assert.Equal(t, override.Version, *txs[0].StarPriceVersion)
+
+	// Retiring the override falls back to the default price.
+	_, err = walletService.AdminEndStarPrice(ctx, override.ID.String(), nil)
+	require.NoError(t, err)
+	price, err := walletService.StarPriceForSeries(ctx, series.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 50.0, price.Price)
+	_, err = walletService.AdminEndStarPrice(ctx, price.ID.String(), nil)
+	assert.ErrorIs(t, err, wallet.ErrDefaultStarPrice)
+
+	past := time.Now().Add(-time.Hour)
+	_, err = walletService.AdminCreateStarPrice(ctx, admin.ID.String(), wallet.CreateStarPriceRequest{
+		SessionType:   "group",
+		Price:         45,
+		EffectiveFrom: &past,
+	})
+	assert.ErrorIs(t, err, wallet.ErrPastEffectiveDate)
+}
+
+func TestWallet_AdminPackageCRUD(t *testing.T) {
+	ctx := context.Background()
+	inactive := false
+
+	pkg, err := walletService.AdminCreatePackage(ctx, wallet.CreatePackageRequest{
+		Name:      "Test Ramadan",
+		Amount:    1500,
+		Bonus:     150,
+		SortOrder: 99,
+		IsActive:  &inactive,
+	})
+	require.NoError(t, err)
+	defer testDB.Pool.Exec(ctx, `DELETE FROM credit_packages WHERE id = $1`, pkg.ID)
+	assert.Equal(t, 1650.0, pkg.TotalCredits)
+	assert.Equal(t, 33, pkg.GroupStars) // 1650/50
+	assert.False(t, pkg.IsActive)
+
+	bonus := 300.0
+	pkg, err = walletService.AdminUpdatePackage(ctx, pkg.ID.String(), wallet.UpdatePackageRequest{Bonus: &bonus})
+	require.NoError(t, err)
+	assert.Equal(t, "Test Ramadan", pkg.Name)
+	assert.Equal(t, 1800.0, pkg.TotalCredits)
+
+	all, err := walletService.AdminListPackages(ctx)
+	require.NoError(t, err)
+	onSale, err := walletService.ListPackages(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, packageIDs(all), pkg.ID)
+	assert.NotContains(t, packageIDs(onSale), pkg.ID)
+
+	_, err = walletService.AdminDeletePackage(ctx, uuid.New().String())
+	assert.ErrorIs(t, err, wallet.ErrPackageNotFound)
+}
+
+func packageIDs(pkgs []wallet.CreditPackageResponse) []uuid.UUID {
+	ids := make([]uuid.UUID, len(pkgs))
+	for i, p := range pkgs {
+		ids[i] = p.ID
+	}
+	return ids
+}
+
+// ═══════════════════════════════════════════════════════════════
+// Suite 13: Password Reset
+// ═══════════════════════════════════════════════════════════════
+
+// failingSMS is a carrier that is down.
+type failingSMS struct{}
+
+func (failingSMS) Send(ctx context.Context, to, message string) error {
+	return errors.New("carrier unavailable")
+}
+
+// newAuthService returns an auth service sending mail through m and SMS
+// through sender, skipping the test when Redis is not running.
+func newAuthService(t *testing.T, m *mailer.Mailer, sender sms.SMSSender) *auth.Service {
+	t.Helper()
+	if testRedis == nil {
+		t.Skip("Redis not available")
+	}
+	cfg := &config.Config{
+		App: config.AppConfig{URL: "http://localhost:8080"},
+		JWT: config.JWTConfig{Secret: "test_jwt_secret", AccessExpiry: time.Hour, RefreshExpiry: 24 * time.Hour},
+	}
+	return auth.NewService(testDB, testRedis, cfg, nil, m, sender)
+}
+
+// testPhone returns a phone number no other test uses.
+func testPhone() string {
+	return fmt.Sprintf("+2135%08d", uuid.New().ID()%100000000)
+}
+
+// lastResetToken reads the token from the last reset link sent by SMS.
+func lastResetToken(t *testing.T, sender *sms.FileSender) string {
+	t.Helper()
+	msgs, err := sender.Messages()
+	require.NoError(t, err)
+	require.NotEmpty(t, msgs)
+	_, token, found := strings.Cut(msgs[len(msgs)-1].Message, "token=")
+	require.True(t, found, "no reset link in %q", msgs[len(msgs)-1].Message)
+	return token
+}
+
+// plantResetToken stores a reset token for userID as ForgotPassword
+// does, since the link itself only leaves by mail.
+func plantResetToken(t *testing.T, ctx context.Context, userID uuid.UUID, expiry time.Duration) string {
+	t.Helper()
+	token := uuid.NewString()
+	sum := sha256.Sum256([]byte(token))
+	require.NoError(t, testRedis.SetResetToken(ctx, hex.EncodeToString(sum[:]), userID.String(), expiry))
+	return token
+}
+
+func TestPasswordReset(t *testing.T) {
+	ctx := context.Background()
+	sender := sms.NewFileSender(filepath.Join(t.TempDir(), "sms.log"))
+	authService := newAuthService(t, mailer.NewMailer(config.SMTPConfig{}), sender)
+
+	user := createTestUser(t, ctx, "student", "Reset", "Student")
+	defer cleanupTestUser(t, ctx, user.ID)
+	phone := testPhone()
+	_, err := testDB.Pool.Exec(ctx, `UPDATE users SET phone = $1 WHERE id = $2`, phone, user.ID)
+	require.NoError(t, err)
+	defer testDB.Pool.Exec(ctx, `DELETE FROM refresh_tokens WHERE user_id = $1`, user.ID)
+
+	loggedIn, err := authService.Login(ctx, auth.LoginRequest{Email: user.Email, Password: "test123456"})
+	require.NoError(t, err)
+
+	t.Run("SameResponseForUnknownAccounts", func(t *testing.T) {
+		known, err := authService.ForgotPassword(ctx, auth.ForgotPasswordRequest{Email: user.Email})
+		require.NoError(t, err)
+		unknown, err := authService.ForgotPassword(ctx, auth.ForgotPasswordRequest{Email: "nobody_" + uuid.NewString()[:8] + "@test.com"})
+		require.NoError(t, err)
+		assert.Equal(t, unknown, known)
+	})
+
+	token := plantResetToken(t, ctx, user.ID, 30*time.Minute)
+
+	t.Run("ResetRevokesRefreshTokens", func(t *testing.T) {
+		require.NoError(t, authService.ResetPassword(ctx, auth.ResetPasswordRequest{Token: token, NewPassword: "new_password_123"}))
+
+		_, err := authService.RefreshToken(ctx, loggedIn.RefreshToken)
+		assert.ErrorIs(t, err, auth.ErrInvalidToken, "sessions from before the reset are logged out")
+
+		_, err = authService.Login(ctx, auth.LoginRequest{Email: user.Email, Password: "test123456"})
+		assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+		_, err = authService.Login(ctx, auth.LoginRequest{Email: user.Email, Password: "new_password_123"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("TokenIsSingleUse", func(t *testing.T) {
+		err := authService.ResetPassword(ctx, auth.ResetPasswordRequest{Token: token, NewPassword: "another_password"})
+		assert.ErrorIs(t, err, auth.ErrInvalidToken)
+	})
+
+	t.Run("LinkBySMS", func(t *testing.T) {
+		_, err := authService.ForgotPassword(ctx, auth.ForgotPasswordRequest{Phone: phone})
+		require.NoError(t, err)
+
+		err = authService.ResetPassword(ctx, auth.ResetPasswordRequest{Token: lastResetToken(t, sender), NewPassword: "sms_password_123"})
+		require.NoError(t, err)
+		_, err = authService.Login(ctx, auth.LoginRequest{Email: user.Email, Password: "sms_password_123"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("TokenExpires", func(t *testing.T) {
+		expired := plantResetToken(t, ctx, user.ID, time.Millisecond)
+		time.Sleep(20 * time.Millisecond)
+
+		err := authService.ResetPassword(ctx, auth.ResetPasswordRequest{Token: expired, NewPassword: "another_password"})
+		assert.ErrorIs(t, err, auth.ErrInvalidToken)
+	})
+
+	t.Run("DeliveryFailureIsHidden", func(t *testing.T) {
+		down := mailer.NewMailer(config.SMTPConfig{Host: "127.0.0.1", Port: "1", From: "EduConnect <no-reply@educonnect.dz>"})
+		failing := newAuthService(t, down, failingSMS{})
+
+		resp, err := failing.ForgotPassword(ctx, auth.ForgotPasswordRequest{Email: user.Email})
+		require.NoError(t, err, "a failed send must not reveal that the account exists")
+		assert.Equal(t, "If an account exists, reset instructions have been sent", resp.Message)
+
+		resp, err = failing.ForgotPassword(ctx, auth.ForgotPasswordRequest{Phone: phone})
+		require.NoError(t, err, "a failed SMS must not reveal that the account exists")
+		assert.Equal(t, "If an account exists, reset instructions have been sent", resp.Message)
+	})
+
+	t.Run("RateLimited", func(t *testing.T) {
+		// Two requests so far for this address
+		_, err := authService.ForgotPassword(ctx, auth.ForgotPasswordRequest{Email: user.Email})
+		require.NoError(t, err)
+		_, err = authService.ForgotPassword(ctx, auth.ForgotPasswordRequest{Email: user.Email})
+		assert.ErrorIs(t, err, auth.ErrTooManyResetAttempts)
+
+		// Unknown identifiers are limited the same way
+		other := "nobody_" + uuid.NewString()[:8] + "@test.com"
+		for i := 0; i < 3; i++ {
+			_, err := authService.ForgotPassword(ctx, auth.ForgotPasswordRequest{Email: other})
+			require.NoError(t, err)
+		}
+		_, err = authService.ForgotPassword(ctx, auth.ForgotPasswordRequest{Email: other})
+		assert.ErrorIs(t, err, auth.ErrTooManyResetAttempts)
+	})
+}
+
+// ═══════════════════════════════════════════════════════════════
+// Suite 14: Teacher Payouts
+// ═══════════════════════════════════════════════════════════════
+
+func TestPayouts(t *testing.T) {
+	ctx := context.Background()
+	payoutService := payout.NewService(testDB, nil)
+
+	teacher := createTeacherWithProfile(t, ctx, "Payout", "Teacher")
+	defer cleanupTestUser(t, ctx, teacher.ID)
+	student := createStudentWithProfile(t, ctx, "Payout", "Student", nil)
+	defer cleanupTestUser(t, ctx, student.ID)
+	admin := createTestUser(t, ctx, "admin", "Payout", "Admin")
+	defer cleanupTestUser(t, ctx, admin.ID)
+
+	// 5000 DZD paid for a lesson: 4500 earned after commission
+	_, err := testDB.Pool.Exec(ctx,
+		`INSERT INTO transactions (payer_id, payee_id, amount, commission, net_amount, payment_method, status)
+		 VALUES ($1, $2, 5000, 500, 4500, 'ccp_baridimob', 'completed')`,
+		student.ID, teacher.ID,
+	)
+	require.NoError(t, err)
+
+	request := func(amount float64) (*payout.PayoutResponse, error) {
+		return payoutService.RequestPayout(ctx, teacher.ID.String(), payout.RequestPayoutRequest{
+			Amount:        amount,
+			AccountNumber: "1234567 68",
+		})
+	}
+	available := func() float64 {
+		b, err := payoutService.GetBalance(ctx, teacher.ID.String())
+		require.NoError(t, err)
+		return b.AvailableBalance
+	}
+	journals := func(payoutID uuid.UUID) []string {
+		rows, err := testDB.Pool.Query(ctx,
+			`SELECT kind FROM ledger_journals
+			 WHERE reference_type = 'payout' AND reference_id = $1
+			 ORDER BY created_at`, payoutID,
+		)
+		require.NoError(t, err)
+		defer rows.Close()
+		var kinds []string
+		for rows.Next() {
+			var kind string
+			require.NoError(t, rows.Scan(&kind))
+			kinds = append(kinds, kind)
+		}
+		require.NoError(t, rows.Err())
+		return kinds
+	}
+
+	// ─── Test: Requests are checked against earnings ────────────
+	t.Run("BalanceChecks", func(t *testing.T) {
+		assert.Equal(t, 4500.0, available())
+
+		_, err := request(500)
+		assert.ErrorIs(t, err, payout.ErrBelowMinimum)
+		_, err = request(4600)
+		assert.ErrorIs(t, err, payout.ErrInsufficientEarnings)
+
+		_, err = payoutService.RequestPayout(ctx, teacher.ID.String(), payout.RequestPayoutRequest{
+			Amount:        1000,
+			AccountNumber: "1234567 69",
+		})
+		assert.ErrorIs(t, err, payout.ErrInvalidCCP)
+
+		_, err = payoutService.RequestPayout(ctx, student.ID.String(), payout.RequestPayoutRequest{
+			Amount:        1000,
+			AccountNumber: "1234567 68",
+		})
+		assert.ErrorIs(t, err, payout.ErrNotTeacher)
+
+		assert.Equal(t, 4500.0, available())
+		t.Log("✓ Payouts below the minimum, above earnings or to a bad CCP refused")
+	})
+
+	// ─── Test: Request → approve → mark paid ────────────────────
+	t.Run("RequestApprovePay", func(t *testing.T) {
+		p, err := request(2000)
+		require.NoError(t, err)
+		assert.Equal(t, "pending", p.Status)
+		assert.Equal(t, "00799999000123456787", p.AccountNumber)
+		assert.Equal(t, 2500.0, available()) // reserved while pending
+
+		// Only an approved payout can be paid
+		_, err = payoutService.AdminMarkPaid(ctx, p.ID.String(), admin.ID.String(), "VIR-0001", "")
+		assert.ErrorIs(t, err, payout.ErrInvalidTransition)
+
+		p, err = payoutService.AdminApprovePayout(ctx, p.ID.String(), admin.ID.String(), "")
+		require.NoError(t, err)
+		assert.Equal(t, "processing", p.Status)
+		assert.Equal(t, 2500.0, available())
+
+		p, err = payoutService.AdminMarkPaid(ctx, p.ID.String(), admin.ID.String(), "VIR-0001", "")
+		require.NoError(t, err)
+		assert.Equal(t, "completed", p.Status)
+		assert.Equal(t, "VIR-0001", p.Reference)
+		assert.NotNil(t, p.ProcessedAt)
+
+		b, err := payoutService.GetBalance(ctx, teacher.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, 2000.0, b.TotalPaidOut)
+		assert.Equal(t, 0.0, b.PendingPayouts)
+		assert.Equal(t, 2500.0, b.AvailableBalance)
+		assert.Equal(t, []string{ledger.JournalPayoutRequested, ledger.JournalPayoutPaid}, journals(p.ID))
+
+		// A paid payout cannot be rejected
+		_, err = payoutService.AdminRejectPayout(ctx, p.ID.String(), admin.ID.String(), "Trop tard")
+		assert.ErrorIs(t, err, payout.ErrInvalidTransition)
+
+		t.Log("✓ Payout approved, then paid with its transfer reference")
+	})
+
+	// ─── Test: Rejecting releases the reserved amount ───────────
+	t.Run("RejectReleases", func(t *testing.T) {
+		p, err := request(1500)
+		require.NoError(t, err)
+		assert.Equal(t, 1000.0, available())
+
+		p, err = payoutService.AdminRejectPayout(ctx, p.ID.String(), admin.ID.String(), "RIP erroné")
+		require.NoError(t, err)
+		assert.Equal(t, "failed", p.Status)
+		assert.Equal(t, "RIP erroné", p.AdminNotes)
+		assert.Equal(t, 2500.0, available())
+		assert.Equal(t, []string{ledger.JournalPayoutRequested, ledger.JournalPayoutRejected}, journals(p.ID))
+
+		_, err = payoutService.AdminApprovePayout(ctx, p.ID.String(), admin.ID.String(), "")
+		assert.ErrorIs(t, err, payout.ErrInvalidTransition)
+		_, err = payoutService.AdminApprovePayout(ctx, uuid.NewString(), admin.ID.String(), "")
+		assert.ErrorIs(t, err, payout.ErrPayoutNotFound)
+
+		t.Log("✓ Rejected payout returns its amount to the balance")
+	})
+
+	// ─── Test: Concurrent requests cannot overdraw ──────────────
+	var pendingID uuid.UUID
+	t.Run("ConcurrentRequests", func(t *testing.T) {
+		// 2500 available: only one of these fits
+		var wg sync.WaitGroup
+		results := make([]*payout.PayoutResponse, 4)
+		errs := make([]error, len(results))
+		for i := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i], errs[i] = request(2000)
+			}()
+		}
+		wg.Wait()
+
+		succeeded := 0
+		for i, err := range errs {
+			if err != nil {
+				assert.ErrorIs(t, err, payout.ErrInsufficientEarnings)
+				continue
+			}
+			succeeded++
+			pendingID = results[i].ID
+		}
+		assert.Equal(t, 1, succeeded)
+		assert.Equal(t, 500.0, available())
+
+		t.Log("✓ Concurrent requests reserve the balance once")
+	})
+
+	// ─── Test: Concurrent decisions settle a payout once ────────
+	t.Run("ConcurrentTransitions", func(t *testing.T) {
+		require.NotEqual(t, uuid.Nil, pendingID)
+
+		var wg sync.WaitGroup
+		errs := make([]error, 2)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, errs[0] = payoutService.AdminApprovePayout(ctx, pendingID.String(), admin.ID.String(), "")
+		}()
+		go func() {
+			defer wg.Done()
+			_, errs[1] = payoutService.AdminRejectPayout(ctx, pendingID.String(), admin.ID.String(), "Doublon")
+		}()
+		wg.Wait()
+
+		if errs[0] == nil {
+			assert.ErrorIs(t, errs[1], payout.ErrInvalidTransition)
+			assert.Equal(t, 500.0, available())
+			assert.Equal(t, []string{ledger.JournalPayoutRequested}, journals(pendingID))
+		} else {
+			assert.ErrorIs(t, errs[0], payout.ErrInvalidTransition)
+			require.NoError(t, errs[1])
+			assert.Equal(t, 2500.0, available())
+			assert.Len(t, journals(pendingID), 2)
+		}
+
+		t.Log("✓ Approve and reject racing: exactly one applies")
+	})
+}
+
 // ═══════════════════════════════════════════════════════════════
 
 func TestSummary(t *testing.T) {
@@ -2435,6 +4249,17 @@ func TestSummary(t *testing.T) {
   - Admin list pending purchases
   - Double-approve returns ErrAlreadyProcessed
 
+✓ Suite 13: Password Reset
+  - Same response for unknown accounts and failed delivery
+  - Reset revokes refresh tokens
+  - Reset tokens are single-use and expire
+  - Requests rate-limited per identifier
+
+✓ Suite 14: Teacher Payouts
+  - Minimum, earnings and CCP checks
+  - Request → approve → mark paid, reject releases the amount
+  - Concurrent requests and decisions settle once
+
 ═══════════════════════════════════════════════════════════════
 	`)
 }
@@ -92,10 +92,6 @@ class ApiConstants {
   static const String walletTransactions = '/wallet/transactions'; // GET
   static const String walletPackages = '/wallet/packages'; // GET
 
-  // ── Platform Fees (legacy — kept for backward compat) ──────
-  static const String pendingFees = '/fees/pending'; // GET
-  static String confirmFeePayment(String id) => '/fees/$id/confirm'; // POST
-
   // ── Bookings (Student requests session from teacher) ────────
   static const String bookings = '/bookings'; // POST & GET list
   static String bookingDetail(String id) => '/bookings/$id'; // GET & DELETE
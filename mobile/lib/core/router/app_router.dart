@@ -28,7 +28,6 @@ import 'package:educonnect/features/session/presentation/pages/series_list_page.
 import 'package:educonnect/features/session/presentation/pages/create_series_page.dart';
 import 'package:educonnect/features/session/presentation/pages/series_detail_page.dart';
 import 'package:educonnect/features/session/presentation/pages/invitations_page.dart';
-import 'package:educonnect/features/session/presentation/pages/platform_fees_page.dart';
 import 'package:educonnect/features/session/presentation/pages/browse_series_page.dart';
 import 'package:educonnect/features/session/presentation/bloc/series_bloc.dart';
 import 'package:educonnect/features/parent/presentation/bloc/parent_bloc.dart';
@@ -188,11 +187,6 @@ GoRouter createRouter(AuthBloc authBloc) => GoRouter(
             seriesId: state.pathParameters['id']!,
           ),
         ),
-        // Teacher fees route
-        GoRoute(
-          path: '/teacher/fees',
-          builder: (_, __) => const PlatformFeesPage(),
-        ),
         // Teacher booking requests management
         GoRoute(
           path: '/teacher/bookings',
@@ -1,7 +1,6 @@
 import 'package:educonnect/core/network/api_client.dart';
 import 'package:educonnect/core/network/api_constants.dart';
 import 'package:educonnect/features/session/data/models/enrollment_model.dart';
-import 'package:educonnect/features/session/data/models/platform_fee_model.dart';
 import 'package:educonnect/features/session/data/models/session_series_model.dart';
 
 class SeriesRemoteDataSource {
@@ -199,32 +198,6 @@ class SeriesRemoteDataSource {
     return EnrollmentModel.fromJson(data);
   }
 
-  // ==================== PLATFORM FEES ====================
-
-  /// GET /fees - Get pending fees for teacher
-  Future<List<PlatformFeeModel>> getPendingFees() async {
-    final response = await apiClient.get(ApiConstants.pendingFees);
-    final list = response.data['data'] as List<dynamic>? ?? [];
-    return list
-        .map((e) => PlatformFeeModel.fromJson(e as Map<String, dynamic>))
-        .toList();
-  }
-
-  /// POST /fees/:id/pay - Confirm fee payment
-  Future<PlatformFeeModel> confirmFeePayment(
-    String feeId,
-    ConfirmFeePaymentRequest request,
-  ) async {
-    final response = await apiClient.post(
-      ApiConstants.confirmFeePayment(feeId),
-      data: request.toJson(),
-    );
-    final data = response.data['data'] as Map<String, dynamic>?;
-    if (data == null)
-      throw Exception('No data returned from confirmFeePayment');
-    return PlatformFeeModel.fromJson(data);
-  }
-
   // ==================== BROWSE (FOR STUDENTS) ====================
 
   /// GET /sessions/series/browse - Browse available series (student)
@@ -1,8 +1,6 @@
 import 'package:educonnect/features/session/data/datasources/series_remote_datasource.dart';
-import 'package:educonnect/features/session/data/models/platform_fee_model.dart';
 import 'package:educonnect/features/session/data/models/session_series_model.dart';
 import 'package:educonnect/features/session/domain/entities/enrollment.dart';
-import 'package:educonnect/features/session/domain/entities/platform_fee.dart';
 import 'package:educonnect/features/session/domain/entities/session_series.dart';
 import 'package:educonnect/features/session/domain/repositories/series_repository.dart';
 
@@ -96,24 +94,6 @@ class SeriesRepositoryImpl implements SeriesRepository {
   Future<Enrollment> declineInvitation(String enrollmentId, {String? reason}) =>
       remoteDataSource.declineInvitation(enrollmentId, reason: reason);
 
-  // ==================== PLATFORM FEES ====================
-
-  @override
-  Future<List<PlatformFee>> getPendingFees() =>
-      remoteDataSource.getPendingFees();
-
-  @override
-  Future<PlatformFee> confirmFeePayment(
-    String feeId, {
-    required String providerRef,
-  }) =>
-      remoteDataSource.confirmFeePayment(
-        feeId,
-        ConfirmFeePaymentRequest(
-          providerRef: providerRef,
-        ),
-      );
-
   // ==================== BROWSE (FOR STUDENTS) ====================
 
   @override
@@ -1,6 +1,5 @@
 import 'package:educonnect/features/session/data/models/session_series_model.dart';
 import 'package:educonnect/features/session/domain/entities/enrollment.dart';
-import 'package:educonnect/features/session/domain/entities/platform_fee.dart';
 import 'package:educonnect/features/session/domain/entities/session_series.dart';
 
 /// Repository interface for session series operations
@@ -26,7 +25,7 @@ abstract class SeriesRepository {
     AddSessionsRequest request,
   );
 
-  /// Finalize series (teacher only, calculates fee)
+  /// Finalize series (teacher only)
   Future<SessionSeries> finalizeSeries(String seriesId);
 
   // ==================== TEACHER ENROLLMENT MANAGEMENT ====================
@@ -75,17 +74,6 @@ abstract class SeriesRepository {
   /// Decline an invitation
   Future<Enrollment> declineInvitation(String enrollmentId, {String? reason});
 
-  // ==================== PLATFORM FEES ====================
-
-  /// Get pending fees (teacher only)
-  Future<List<PlatformFee>> getPendingFees();
-
-  /// Confirm fee payment (teacher only)
-  Future<PlatformFee> confirmFeePayment(
-    String feeId, {
-    required String providerRef,
-  });
-
   // ==================== BROWSE (FOR STUDENTS) ====================
 
   /// Browse available series to join
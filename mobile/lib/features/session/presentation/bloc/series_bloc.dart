@@ -3,7 +3,6 @@ import 'package:equatable/equatable.dart';
 import 'package:flutter_bloc/flutter_bloc.dart';
 import 'package:educonnect/features/session/data/models/session_series_model.dart';
 import 'package:educonnect/features/session/domain/entities/enrollment.dart';
-import 'package:educonnect/features/session/domain/entities/platform_fee.dart';
 import 'package:educonnect/features/session/domain/entities/session_series.dart';
 import 'package:educonnect/features/session/domain/repositories/series_repository.dart';
 
@@ -205,25 +204,6 @@ class DeclineInvitationRequested extends SeriesEvent {
   List<Object?> get props => [enrollmentId, reason];
 }
 
-// ── Platform Fee Events ─────────────────────────────────────────
-
-class PendingFeesRequested extends SeriesEvent {
-  const PendingFeesRequested();
-}
-
-class ConfirmFeePaymentRequested extends SeriesEvent {
-  final String feeId;
-  final String providerRef;
-
-  const ConfirmFeePaymentRequested({
-    required this.feeId,
-    required this.providerRef,
-  });
-
-  @override
-  List<Object?> get props => [feeId, providerRef];
-}
-
 // ── Browse Events (Student) ─────────────────────────────────────
 
 class BrowseSeriesRequested extends SeriesEvent {
@@ -354,22 +334,6 @@ class InvitationDeclined extends SeriesState {
   List<Object?> get props => [enrollment];
 }
 
-// ── Platform Fee States ─────────────────────────────────────────
-
-class PendingFeesLoaded extends SeriesState {
-  final List<PlatformFee> fees;
-  const PendingFeesLoaded({required this.fees});
-  @override
-  List<Object?> get props => [fees];
-}
-
-class FeePaymentConfirmed extends SeriesState {
-  final PlatformFee fee;
-  const FeePaymentConfirmed({required this.fee});
-  @override
-  List<Object?> get props => [fee];
-}
-
 // ── Error State ─────────────────────────────────────────────────
 
 class SeriesError extends SeriesState {
@@ -407,10 +371,6 @@ class SeriesBloc extends Bloc<SeriesEvent, SeriesState> {
     on<AcceptInvitationRequested>(_onAcceptInvitation);
     on<DeclineInvitationRequested>(_onDeclineInvitation);
 
-    // Platform fees
-    on<PendingFeesRequested>(_onGetPendingFees);
-    on<ConfirmFeePaymentRequested>(_onConfirmFeePayment);
-
     // Browse
     on<BrowseSeriesRequested>(_onBrowseSeries);
   }
@@ -649,37 +609,6 @@ class SeriesBloc extends Bloc<SeriesEvent, SeriesState> {
     }
   }
 
-  // ── Platform Fee Handlers ─────────────────────────────────────
-
-  Future<void> _onGetPendingFees(
-    PendingFeesRequested event,
-    Emitter<SeriesState> emit,
-  ) async {
-    emit(SeriesLoading());
-    try {
-      final fees = await seriesRepository.getPendingFees();
-      emit(PendingFeesLoaded(fees: fees));
-    } catch (e) {
-      emit(SeriesError(message: _extractError(e)));
-    }
-  }
-
-  Future<void> _onConfirmFeePayment(
-    ConfirmFeePaymentRequested event,
-    Emitter<SeriesState> emit,
-  ) async {
-    emit(SeriesLoading());
-    try {
-      final fee = await seriesRepository.confirmFeePayment(
-        event.feeId,
-        providerRef: event.providerRef,
-      );
-      emit(FeePaymentConfirmed(fee: fee));
-    } catch (e) {
-      emit(SeriesError(message: _extractError(e)));
-    }
-  }
-
   // ── Browse Handler ────────────────────────────────────────────
 
   Future<void> _onBrowseSeries(
@@ -363,7 +363,7 @@ class _CreateSeriesFormState extends State<_CreateSeriesForm> {
               ),
               SizedBox(height: 16.h),
 
-              // Platform fee info
+              // Star cost info
               Card(
                 color: theme.colorScheme.secondaryContainer,
                 child: Padding(
@@ -380,7 +380,7 @@ class _CreateSeriesFormState extends State<_CreateSeriesForm> {
                           ),
                           SizedBox(width: 8.w),
                           Text(
-                            'Frais de plateforme',
+                            'Étoiles',
                             style: theme.textTheme.titleSmall?.copyWith(
                               fontWeight: FontWeight.bold,
                               color: theme.colorScheme.onSecondaryContainer,
@@ -390,16 +390,14 @@ class _CreateSeriesFormState extends State<_CreateSeriesForm> {
                       ),
                       SizedBox(height: 8.h),
                       Text(
-                        _sessionType == 'group'
-                            ? '• 50 DA/élève/heure pour les sessions de groupe'
-                            : '• 120 DA/heure pour les sessions individuelles',
+                        '• Une étoile est débitée de votre portefeuille pour chaque élève accepté',
                         style: theme.textTheme.bodySmall?.copyWith(
                           color: theme.colorScheme.onSecondaryContainer,
                         ),
                       ),
                       SizedBox(height: 4.h),
                       Text(
-                        '• Les frais seront calculés après finalisation',
+                        '• Le prix de l\'étoile dépend du type de session',
                         style: theme.textTheme.bodySmall?.copyWith(
                           color: theme.colorScheme.onSecondaryContainer,
                         ),